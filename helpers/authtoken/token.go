@@ -38,6 +38,13 @@ const (
 type EpinioClaims struct {
 	jwt.RegisteredClaims
 	Username string `json:"user"`
+	Scope    *Scope `json:"scope,omitempty"`
+}
+
+// Scope restricts the access granted by the token, for users authenticated with a scoped credential
+type Scope struct {
+	Namespaces []string `json:"namespaces,omitempty"`
+	ReadOnly   bool     `json:"readOnly,omitempty"`
 }
 
 func init() {
@@ -54,6 +61,11 @@ func init() {
 // WARNING: It should only be used to establish the websocket connection once,
 // because we can't revoke and don't check for deleted users.
 func Create(user string, s time.Duration) string {
+	return CreateWithScope(user, nil, s)
+}
+
+// CreateWithScope creates a token whose access is restricted by the scope. A nil scope is unrestricted.
+func CreateWithScope(user string, scope *Scope, s time.Duration) string {
 	// seriously, don't use a long expiry time with this code
 	if s > maxExpiry {
 		return ""
@@ -64,6 +76,7 @@ func Create(user string, s time.Duration) string {
 			Issuer:    "epinio-server",
 		},
 		Username: user,
+		Scope:    scope,
 	}
 
 	token := jwt.NewWithClaims(alg, claims)
@@ -55,6 +55,8 @@ var (
 	EpinioAPISecretLabelKey     = fmt.Sprintf("%s/%s", APISGroupName, "api-user-credentials")
 	EpinioAPISecretLabelValue   = "true"
 	EpinioAPISecretRoleLabelKey = fmt.Sprintf("%s/%s", APISGroupName, "role")
+	EpinioAPITokenLabelKey      = fmt.Sprintf("%s/%s", APISGroupName, "api-token")
+	EpinioAPITokenLabelValue    = "true"
 )
 
 // Memoization of GetCluster
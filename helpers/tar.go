@@ -12,13 +12,39 @@
 package helpers
 
 import (
+	"archive/tar"
+	"bufio"
+	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/mholt/archiver/v3"
 	"github.com/pkg/errors"
 )
 
+// IgnoreFiles are the files holding the gitignore-style patterns of the sources left out
+// by TarSources, in order of preference. Only the first file found in a directory is used.
+var IgnoreFiles = []string{".epinioignore", ".gitignore"}
+
+// TarStats describes the tarball created by TarSources.
+type TarStats struct {
+	// IgnoreFile is the file the ignore patterns of the top directory were read from, if any.
+	IgnoreFile string
+	// Files is the number of files in the tarball.
+	Files int
+	// Ignored is the number of files and directories left out by the ignore patterns.
+	Ignored int
+	// Size is the size of the tarball, in bytes.
+	Size int64
+}
+
+// Tar creates a tarball of the directory, without the git config files at its top. It
+// returns the temp directory holding the tarball, to be removed by the caller, and the
+// path of the tarball.
 func Tar(dir string) (string, string, error) {
 	files, err := os.ReadDir(dir)
 	if err != nil {
@@ -32,7 +58,7 @@ func Tar(dir string) (string, string, error) {
 		// and directories to assemble in the tarball.
 
 		// Ignore git config files in the app sources.
-		if f.Name() == ".git" || f.Name() == ".gitignore" || f.Name() == ".gitmodules" || f.Name() == ".gitconfig" || f.Name() == ".git-credentials" {
+		if skipConfigFile(f.Name()) {
 			continue
 		}
 		sources = append(sources, path.Join(dir, f.Name()))
@@ -52,3 +78,189 @@ func Tar(dir string) (string, string, error) {
 
 	return tmpDir, tarball, nil
 }
+
+// TarSources is like Tar, but leaves out the files and directories matching the patterns
+// of the ignore files. Like with git, the ignore file of a directory applies to everything
+// below that directory. See IgnoreFiles.
+func TarSources(dir string) (string, string, TarStats, error) {
+	stats := TarStats{}
+
+	patterns, ignoreFile, err := readIgnorePatterns(dir, nil)
+	if err != nil {
+		return "", "", stats, err
+	}
+	stats.IgnoreFile = ignoreFile
+	matcher := gitignore.NewMatcher(patterns)
+
+	tmpDir, err := os.MkdirTemp("", "epinio-app")
+	if err != nil {
+		return "", "", stats, errors.Wrap(err, "can't create temp directory")
+	}
+
+	tarball := path.Join(tmpDir, "blob.tar")
+	file, err := os.Create(tarball)
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+
+	err = filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == dir {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+
+		// Ignore git config files and the ignore files in the app sources.
+		if len(parts) == 1 && (skipConfigFile(rel) || isIgnoreFile(rel)) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if matcher.Match(parts, entry.IsDir()) {
+			stats.Ignored++
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() {
+			nested, _, err := readIgnorePatterns(name, parts)
+			if err != nil {
+				return err
+			}
+			if len(nested) > 0 {
+				// Patterns scoped to the directory never match outside of it.
+				patterns = append(patterns, nested...)
+				matcher = gitignore.NewMatcher(patterns)
+			}
+		}
+
+		added, err := addToTar(writer, name, filepath.ToSlash(rel), entry)
+		if added {
+			stats.Files++
+		}
+		return err
+	})
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+
+	if err := writer.Close(); err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return tmpDir, "", stats, errors.Wrap(err, "can't create archive")
+	}
+	stats.Size = info.Size()
+
+	return tmpDir, tarball, stats, nil
+}
+
+// addToTar writes the file system entry to the tarball, under the name. It returns true if
+// the entry is a file, i.e. not a directory.
+func addToTar(writer *tar.Writer, path, name string, entry fs.DirEntry) (bool, error) {
+	info, err := entry.Info()
+	if err != nil {
+		return false, err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err = os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return false, err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+
+	if err := writer.WriteHeader(header); err != nil {
+		return false, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return !info.IsDir(), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	return true, err
+}
+
+// readIgnorePatterns reads the patterns of the first ignore file found in the directory.
+// The domain is the path of the directory relative to the top of the sources, the patterns
+// only apply below it. It returns the patterns, and the name of the file they were read
+// from.
+func readIgnorePatterns(dir string, domain []string) ([]gitignore.Pattern, string, error) {
+	for _, ignoreFile := range IgnoreFiles {
+		file, err := os.Open(path.Join(dir, ignoreFile))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "cannot read %s", ignoreFile)
+		}
+		defer file.Close()
+
+		patterns := []gitignore.Pattern{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+				continue
+			}
+			patterns = append(patterns, gitignore.ParsePattern(line, domain))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, "", errors.Wrapf(err, "cannot read %s", ignoreFile)
+		}
+
+		return patterns, ignoreFile, nil
+	}
+
+	return nil, "", nil
+}
+
+func skipConfigFile(name string) bool {
+	switch name {
+	case ".git", ".gitignore", ".gitmodules", ".gitconfig", ".git-credentials":
+		return true
+	}
+	return false
+}
+
+func isIgnoreFile(name string) bool {
+	for _, ignoreFile := range IgnoreFiles {
+		if name == ignoreFile {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helpers_test
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/epinio/epinio/helpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TarSources", func() {
+	var dir string
+
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	}
+
+	entries := func(tarball string) []string {
+		file, err := os.Open(tarball)
+		Expect(err).ToNot(HaveOccurred())
+		defer file.Close()
+
+		names := []string{}
+		reader := tar.NewReader(file)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+			names = append(names, header.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "epinio-tar")
+		Expect(err).ToNot(HaveOccurred())
+
+		write("main.go", "package main")
+		write("lib/util.go", "package lib")
+		write("node_modules/dep/index.js", "module.exports = {}")
+		write("build/out.bin", "binary")
+		write("debug.log", "log")
+		write(".git/HEAD", "ref: refs/heads/main")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("archives everything but the git files without ignore file", func() {
+		tmpDir, tarball, stats, err := helpers.TarSources(dir)
+		defer os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(entries(tarball)).To(ConsistOf(
+			"main.go", "lib/", "lib/util.go", "node_modules/", "node_modules/dep/",
+			"node_modules/dep/index.js", "build/", "build/out.bin", "debug.log"))
+		Expect(stats.IgnoreFile).To(BeEmpty())
+		Expect(stats.Files).To(Equal(5))
+		Expect(stats.Ignored).To(Equal(0))
+		Expect(stats.Size).To(BeNumerically(">", 0))
+	})
+
+	It("honours the .epinioignore", func() {
+		write(".epinioignore", "# dependencies\nnode_modules/\n\n/build\n*.log\n")
+		write(".gitignore", "lib/\n")
+
+		tmpDir, tarball, stats, err := helpers.TarSources(dir)
+		defer os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(entries(tarball)).To(ConsistOf("main.go", "lib/", "lib/util.go"))
+		Expect(stats.IgnoreFile).To(Equal(".epinioignore"))
+		Expect(stats.Files).To(Equal(2))
+		Expect(stats.Ignored).To(Equal(3))
+	})
+
+	It("falls back to the .gitignore", func() {
+		write(".gitignore", "node_modules\n!lib/*.go\nlib/\n")
+
+		tmpDir, tarball, stats, err := helpers.TarSources(dir)
+		defer os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(entries(tarball)).To(ConsistOf("main.go", "build/", "build/out.bin", "debug.log"))
+		Expect(stats.IgnoreFile).To(Equal(".gitignore"))
+		Expect(stats.Ignored).To(Equal(2))
+	})
+
+	It("honours the ignore files of sub directories", func() {
+		write(".gitignore", "*.log\n")
+		write("lib/.gitignore", "*.bin\n!keep.log\n")
+		write("lib/out.bin", "binary")
+		write("lib/keep.log", "log")
+		write("lib/drop.log", "log")
+
+		tmpDir, tarball, stats, err := helpers.TarSources(dir)
+		defer os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(entries(tarball)).To(ConsistOf(
+			"main.go", "lib/", "lib/.gitignore", "lib/util.go", "lib/keep.log",
+			"node_modules/", "node_modules/dep/", "node_modules/dep/index.js",
+			"build/", "build/out.bin"))
+		Expect(stats.IgnoreFile).To(Equal(".gitignore"))
+		Expect(stats.Ignored).To(Equal(3))
+	})
+})
@@ -0,0 +1,329 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/s3manager"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/h2non/filetype"
+	"github.com/pkg/errors"
+)
+
+// A chunked upload stores the application sources in parts, backed by a multipart upload
+// of the blob store. The state of the upload is kept by the blob store alone. Clients
+// resume an interrupted upload by asking for the stored parts, and sending the missing
+// ones.
+
+// UploadInit handles the API endpoint POST /namespaces/:namespace/applications/:app/uploads
+// It starts a chunked upload of the application sources, and returns its ids and the
+// size of the parts to send.
+func (hc Controller) UploadInit(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+
+	var req models.UploadInitRequest
+	if err := c.BindJSON(&req); err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+	if req.Size <= 0 {
+		return apierror.NewBadRequestError("size of the upload is required")
+	}
+	if _, err := hex.DecodeString(req.SHA256); err != nil || len(req.SHA256) != sha256.Size*2 {
+		return apierror.NewBadRequestError("sha256 checksum of the upload is required")
+	}
+
+	manager, apierr := uploadManager(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	username := requestctx.User(ctx).Username
+	blobUID, uploadID, err := manager.StartUpload(ctx, req.SHA256, map[string]string{
+		"app": name, "namespace": namespace, "username": username,
+		"size": strconv.FormatInt(req.Size, 10),
+	})
+	if err != nil {
+		return apierror.InternalError(err, "starting the upload of the application sources")
+	}
+
+	log.Info("started chunked upload", "namespace", namespace, "app", name, "blobUID", blobUID, "size", req.Size)
+
+	response.OKReturn(c, models.UploadInitResponse{
+		BlobUID:  blobUID,
+		UploadID: uploadID,
+		PartSize: s3manager.UploadPartSize,
+	})
+	return nil
+}
+
+// UploadPart handles the API endpoint PUT /namespaces/:namespace/applications/:app/uploads/:blob/parts/:part
+// It stores a part of a chunked upload. The request body is the part, its sha256
+// checksum is in the UploadChecksumHeader. Parts can be sent more than once, the last
+// stored wins.
+func (hc Controller) UploadPart(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+	blobUID := c.Param("blob")
+	uploadID := c.Query("upload")
+	if uploadID == "" {
+		return apierror.NewBadRequestError("upload id is required")
+	}
+
+	number, err := strconv.Atoi(c.Param("part"))
+	if err != nil || number < 1 {
+		return apierror.NewBadRequestErrorf("bad part number %s", c.Param("part"))
+	}
+
+	checksum := c.GetHeader(models.UploadChecksumHeader)
+	if checksum == "" {
+		return apierror.NewBadRequestErrorf("%s header is required", models.UploadChecksumHeader)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, s3manager.UploadPartSize+1))
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("can't read the part")
+	}
+	if len(data) == 0 {
+		return apierror.NewBadRequestError("part is empty")
+	}
+	if len(data) > s3manager.UploadPartSize {
+		return apierror.NewBadRequestErrorf("part is larger than %d bytes", s3manager.UploadPartSize)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return apierror.NewBadRequestError("checksum mismatch, the part is corrupted")
+	}
+
+	// The archive type is known from the first part. Completing the upload requires it.
+	if number == 1 {
+		kind, _ := filetype.Match(data)
+		if !isValidType(kind.MIME.Value) {
+			return apierror.NewBadRequestErrorf("archive type not supported [%s]", kind.MIME.Value)
+		}
+	}
+
+	manager, apierr := uploadManager(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	if _, apierr := lookupUpload(ctx, manager, namespace, name, blobUID, uploadID); apierr != nil {
+		return apierr
+	}
+
+	part, err := manager.UploadPart(ctx, blobUID, uploadID, number, bytes.NewReader(data), int64(len(data)), checksum)
+	if err != nil {
+		return apierror.InternalError(err, "storing the part")
+	}
+
+	response.OKReturn(c, part)
+	return nil
+}
+
+// UploadStatus handles the API endpoint GET /namespaces/:namespace/applications/:app/uploads/:blob
+// It returns the stored parts of a chunked upload.
+func (hc Controller) UploadStatus(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+	blobUID := c.Param("blob")
+	uploadID := c.Query("upload")
+	if uploadID == "" {
+		return apierror.NewBadRequestError("upload id is required")
+	}
+
+	manager, apierr := uploadManager(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	if _, apierr := lookupUpload(ctx, manager, namespace, name, blobUID, uploadID); apierr != nil {
+		return apierr
+	}
+
+	parts, err := manager.UploadedParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return apierror.InternalError(err, "listing the stored parts")
+	}
+
+	response.OKReturn(c, models.UploadStatusResponse{
+		Parts: parts,
+	})
+	return nil
+}
+
+// UploadComplete handles the API endpoint POST /namespaces/:namespace/applications/:app/uploads/:blob/complete
+// It assembles the application sources from the stored parts, verifies them against the
+// size and checksum given at the start of the upload, and returns the blobUID for staging.
+// The parts have to be numbered from 1 on, without gaps.
+func (hc Controller) UploadComplete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+	blobUID := c.Param("blob")
+	uploadID := c.Query("upload")
+	if uploadID == "" {
+		return apierror.NewBadRequestError("upload id is required")
+	}
+
+	manager, apierr := uploadManager(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	meta, apierr := lookupUpload(ctx, manager, namespace, name, blobUID, uploadID)
+	if apierr != nil {
+		return apierr
+	}
+
+	size, err := strconv.ParseInt(meta["Size"], 10, 64)
+	if err != nil {
+		return apierror.InternalError(err, "reading the size of the upload")
+	}
+
+	parts, err := manager.UploadedParts(ctx, blobUID, uploadID)
+	if err != nil {
+		return apierror.InternalError(err, "listing the stored parts")
+	}
+	if err := checkParts(parts, size); err != nil {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("the upload is incomplete")
+	}
+
+	err = manager.CompleteUpload(ctx, blobUID, uploadID, parts)
+	if errors.Is(err, s3manager.ErrChecksumMismatch) {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("the uploaded sources are corrupted")
+	}
+	if err != nil {
+		return apierror.InternalError(err, "completing the upload of the application sources")
+	}
+
+	log.Info("uploaded app", "namespace", namespace, "app", name, "blobUID", blobUID)
+
+	response.OKReturn(c, models.UploadResponse{
+		BlobUID: blobUID,
+	})
+	return nil
+}
+
+// UploadAbort handles the API endpoint DELETE /namespaces/:namespace/applications/:app/uploads/:blob
+// It cancels a chunked upload, and removes its stored parts.
+func (hc Controller) UploadAbort(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	name := c.Param("app")
+	blobUID := c.Param("blob")
+	uploadID := c.Query("upload")
+	if uploadID == "" {
+		return apierror.NewBadRequestError("upload id is required")
+	}
+
+	manager, apierr := uploadManager(ctx)
+	if apierr != nil {
+		return apierr
+	}
+
+	if _, apierr := lookupUpload(ctx, manager, namespace, name, blobUID, uploadID); apierr != nil {
+		return apierr
+	}
+
+	err := manager.AbortUpload(ctx, blobUID, uploadID)
+	if err != nil {
+		return apierror.InternalError(err, "aborting the upload")
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// lookupUpload returns the metadata of the upload. Uploads of other applications are
+// reported as not found, the same as unknown uploads.
+func lookupUpload(ctx context.Context, manager blobstore.MultipartStore, namespace, name, blobUID, uploadID string) (map[string]string, apierror.APIErrors) {
+	meta, err := manager.UploadMeta(ctx, blobUID, uploadID)
+	if errors.Is(err, s3manager.ErrUploadNotFound) {
+		return nil, apierror.NewNotFoundError("upload", blobUID)
+	}
+	if err != nil {
+		return nil, apierror.InternalError(err, "reading the upload")
+	}
+
+	if meta["App"] != name || meta["Namespace"] != namespace {
+		return nil, apierror.NewNotFoundError("upload", blobUID)
+	}
+
+	return meta, nil
+}
+
+// checkParts checks that the parts, ordered by number, are numbered from 1 on without
+// gaps, and add up to the size of the upload.
+func checkParts(parts []models.UploadPart, size int64) error {
+	total := int64(0)
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return errors.Errorf("part %d is missing", i+1)
+		}
+		total += part.Size
+	}
+
+	if total != size {
+		return errors.Errorf("the parts hold %d bytes, expected %d", total, size)
+	}
+
+	return nil
+}
+
+// uploadManager returns the blob store for chunked uploads. Stores without support for
+// multipart uploads report the chunked upload API as not found. Clients fall back to
+// plain uploads then. It is a variable for the tests.
+var uploadManager = defaultUploadManager
+
+func defaultUploadManager(ctx context.Context) (blobstore.MultipartStore, apierror.APIErrors) {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	store, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return nil, apierror.InternalError(err, "accessing the blob storage")
+	}
+
+	manager, ok := store.(blobstore.MultipartStore)
+	if !ok {
+		return nil, apierror.NewAPIError("chunked uploads are not supported by the blob storage", http.StatusNotFound)
+	}
+
+	return manager, nil
+}
@@ -0,0 +1,230 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/internal/s3manager"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeMultipartStore keeps the uploads in memory.
+type fakeMultipartStore struct {
+	blobstore.Store
+	meta      map[string]map[string]string
+	parts     map[string]map[int][]byte
+	completed map[string][]models.UploadPart
+	aborted   []string
+}
+
+func newFakeMultipartStore() *fakeMultipartStore {
+	return &fakeMultipartStore{
+		meta:      map[string]map[string]string{},
+		parts:     map[string]map[int][]byte{},
+		completed: map[string][]models.UploadPart{},
+	}
+}
+
+func (f *fakeMultipartStore) StartUpload(ctx context.Context, sha256Hex string, metadata map[string]string) (string, string, error) {
+	blobUID := fmt.Sprintf("blob%d", len(f.meta))
+	f.meta[blobUID] = map[string]string{
+		"App":       metadata["app"],
+		"Namespace": metadata["namespace"],
+		"Size":      metadata["size"],
+		"Upload":    "upload-" + blobUID,
+	}
+	f.parts[blobUID] = map[int][]byte{}
+	return blobUID, "upload-" + blobUID, nil
+}
+
+func (f *fakeMultipartStore) UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error) {
+	meta, ok := f.meta[blobUID]
+	if !ok || meta["Upload"] != uploadID {
+		return nil, s3manager.ErrUploadNotFound
+	}
+	return meta, nil
+}
+
+func (f *fakeMultipartStore) UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64, sha256Hex string) (models.UploadPart, error) {
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return models.UploadPart{}, err
+	}
+	f.parts[blobUID][number] = content
+	return models.UploadPart{Number: number, Size: size, ETag: sha256Hex}, nil
+}
+
+func (f *fakeMultipartStore) UploadedParts(ctx context.Context, blobUID, uploadID string) ([]models.UploadPart, error) {
+	parts := []models.UploadPart{}
+	for number, content := range f.parts[blobUID] {
+		parts = append(parts, models.UploadPart{Number: number, Size: int64(len(content))})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+	return parts, nil
+}
+
+func (f *fakeMultipartStore) CompleteUpload(ctx context.Context, blobUID, uploadID string, parts []models.UploadPart) error {
+	f.completed[blobUID] = parts
+	return nil
+}
+
+func (f *fakeMultipartStore) AbortUpload(ctx context.Context, blobUID, uploadID string) error {
+	f.aborted = append(f.aborted, blobUID)
+	return nil
+}
+
+var _ = Describe("Chunked uploads", func() {
+	var store *fakeMultipartStore
+	var blobUID, uploadID string
+	var archive []byte
+
+	request := func(handler func(*gin.Context) apierror.APIErrors, method, namespace, app, blob, part string, body []byte) (*httptest.ResponseRecorder, apierror.APIErrors) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest(method, "/?upload="+uploadID, bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		c.Request.Header.Set(models.UploadChecksumHeader, hex.EncodeToString(sum[:]))
+		c.Params = gin.Params{
+			{Key: "namespace", Value: namespace},
+			{Key: "app", Value: app},
+			{Key: "blob", Value: blob},
+			{Key: "part", Value: part},
+		}
+
+		return w, handler(c)
+	}
+
+	status := func(apierr apierror.APIErrors) int {
+		Expect(apierr).ToNot(BeNil())
+		return apierr.FirstStatus()
+	}
+
+	BeforeEach(func() {
+		store = newFakeMultipartStore()
+		uploadManager = func(ctx context.Context) (blobstore.MultipartStore, apierror.APIErrors) {
+			return store, nil
+		}
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write([]byte("application sources"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(zw.Close()).To(Succeed())
+		archive = buf.Bytes()
+
+		blobUID, uploadID, err = store.StartUpload(context.Background(), "", map[string]string{
+			"app": "app", "namespace": "workspace", "size": fmt.Sprint(len(archive)),
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		uploadManager = defaultUploadManager
+	})
+
+	It("stores the parts of the upload and completes it", func() {
+		_, apierr := request(Controller{}.UploadPart, "PUT", "workspace", "app", blobUID, "1", archive)
+		Expect(apierr).To(BeNil())
+
+		w, apierr := request(Controller{}.UploadStatus, "GET", "workspace", "app", blobUID, "", nil)
+		Expect(apierr).To(BeNil())
+		Expect(w.Body.String()).To(ContainSubstring(`"number":1`))
+
+		_, apierr = request(Controller{}.UploadComplete, "POST", "workspace", "app", blobUID, "", nil)
+		Expect(apierr).To(BeNil())
+		Expect(store.completed).To(HaveKey(blobUID))
+	})
+
+	It("rejects access to the uploads of other applications", func() {
+		for _, ref := range [][]string{{"workspace", "other"}, {"other", "app"}} {
+			_, apierr := request(Controller{}.UploadPart, "PUT", ref[0], ref[1], blobUID, "1", archive)
+			Expect(status(apierr)).To(Equal(http.StatusNotFound))
+
+			_, apierr = request(Controller{}.UploadStatus, "GET", ref[0], ref[1], blobUID, "", nil)
+			Expect(status(apierr)).To(Equal(http.StatusNotFound))
+
+			_, apierr = request(Controller{}.UploadComplete, "POST", ref[0], ref[1], blobUID, "", nil)
+			Expect(status(apierr)).To(Equal(http.StatusNotFound))
+
+			_, apierr = request(Controller{}.UploadAbort, "DELETE", ref[0], ref[1], blobUID, "", nil)
+			Expect(status(apierr)).To(Equal(http.StatusNotFound))
+		}
+
+		Expect(store.parts[blobUID]).To(BeEmpty())
+		Expect(store.completed).To(BeEmpty())
+		Expect(store.aborted).To(BeEmpty())
+	})
+
+	It("rejects unknown upload ids", func() {
+		uploadID = "bogus"
+		_, apierr := request(Controller{}.UploadStatus, "GET", "workspace", "app", blobUID, "", nil)
+		Expect(status(apierr)).To(Equal(http.StatusNotFound))
+	})
+
+	It("rejects a first part which is not an archive", func() {
+		_, apierr := request(Controller{}.UploadPart, "PUT", "workspace", "app", blobUID, "1", []byte("plain text"))
+		Expect(status(apierr)).To(Equal(http.StatusBadRequest))
+	})
+
+	It("refuses to complete an upload without its first part", func() {
+		_, apierr := request(Controller{}.UploadPart, "PUT", "workspace", "app", blobUID, "2", archive)
+		Expect(apierr).To(BeNil())
+
+		_, apierr = request(Controller{}.UploadComplete, "POST", "workspace", "app", blobUID, "", nil)
+		Expect(status(apierr)).To(Equal(http.StatusBadRequest))
+		Expect(store.completed).To(BeEmpty())
+	})
+
+	It("refuses to complete an upload of the wrong size", func() {
+		_, apierr := request(Controller{}.UploadPart, "PUT", "workspace", "app", blobUID, "1", archive[:len(archive)-1])
+		Expect(apierr).To(BeNil())
+
+		_, apierr = request(Controller{}.UploadComplete, "POST", "workspace", "app", blobUID, "", nil)
+		Expect(status(apierr)).To(Equal(http.StatusBadRequest))
+		Expect(store.completed).To(BeEmpty())
+	})
+
+	It("aborts the upload", func() {
+		_, apierr := request(Controller{}.UploadAbort, "DELETE", "workspace", "app", blobUID, "", nil)
+		Expect(apierr).To(BeNil())
+		Expect(store.aborted).To(ConsistOf(blobUID))
+	})
+
+	Describe("checkParts", func() {
+		It("requires contiguous parts from 1 on, adding up to the size", func() {
+			parts := []models.UploadPart{{Number: 1, Size: 8}, {Number: 2, Size: 2}}
+			Expect(checkParts(parts, 10)).To(Succeed())
+			Expect(checkParts(parts, 11)).To(MatchError(ContainSubstring("expected 11")))
+			Expect(checkParts(parts[1:], 2)).To(MatchError(ContainSubstring("part 1 is missing")))
+			Expect(checkParts([]models.UploadPart{{Number: 1, Size: 8}, {Number: 3, Size: 2}}, 10)).
+				To(MatchError(ContainSubstring("part 2 is missing")))
+			Expect(checkParts(nil, 10)).ToNot(Succeed())
+		})
+	})
+})
@@ -21,10 +21,12 @@ import (
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/configurations"
 	"github.com/epinio/epinio/internal/domain"
+	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/internal/routes"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -103,6 +105,26 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierr
 	}
 
+	if createRequest.Configuration.Deployment != nil {
+		apierr := validateDeploymentStrategy(ctx, cluster, *createRequest.Configuration.Deployment)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	if createRequest.Configuration.HealthChecks != nil {
+		if apierr := validateHealthChecks(*createRequest.Configuration.HealthChecks); apierr != nil {
+			return apierr
+		}
+	}
+
+	if createRequest.Configuration.Resources != nil {
+		apierr := validateResources(ctx, cluster, namespace, *createRequest.Configuration.Resources)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
 	// Finalize chart selection (system fallback), and verify existence.
 
 	chart := "standard"
@@ -150,10 +172,94 @@ func (hc Controller) Create(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
+	if createRequest.Configuration.Deployment != nil {
+		err = application.SetDeploymentStrategy(ctx, cluster, appRef,
+			*createRequest.Configuration.Deployment)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	if createRequest.Configuration.HealthChecks != nil {
+		err = application.SetHealthChecks(ctx, cluster, appRef,
+			*createRequest.Configuration.HealthChecks)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	if createRequest.Configuration.Resources != nil {
+		err = application.SetResources(ctx, cluster, appRef,
+			*createRequest.Configuration.Resources)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	response.Created(c)
 	return nil
 }
 
+// validateHealthChecks checks the health checks of an application, and that the server
+// supports them. Removing the health checks is always possible.
+func validateHealthChecks(checks models.AppHealthChecks) apierror.APIErrors {
+	if checks.IsEmpty() {
+		return nil
+	}
+
+	if !application.HealthChecksEnabled() {
+		return apierror.NewBadRequestError("health checks are not enabled on this server").
+			WithDetails("the app chart has to support them, see the server option --app-health-checks")
+	}
+
+	if err := checks.Validate(); err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	return nil
+}
+
+// validateResources checks the compute resources of an application, and that they do not
+// exceed the maximums of its namespace.
+func validateResources(ctx context.Context, cluster *kubernetes.Cluster, namespace string, resources models.AppResources) apierror.APIErrors {
+	if err := resources.Validate(); err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	maxCPU, maxMemory, err := namespaces.AppResourceMaximums(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	if err := application.CheckResourceMaximums(resources, maxCPU, maxMemory); err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	return nil
+}
+
+// validateDeploymentStrategy checks the deployment strategy of an application, and that the
+// ingress controller supports the traffic split of a canary.
+func validateDeploymentStrategy(ctx context.Context, cluster *kubernetes.Cluster, strategy models.AppDeploymentStrategy) apierror.APIErrors {
+	if err := strategy.Validate(); err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	if strategy.Strategy != models.DeploymentStrategyCanary {
+		return nil
+	}
+
+	err := application.CheckCanarySupport(ctx, cluster)
+	if errors.Is(err, application.ErrCanaryUnsupported) {
+		return apierror.NewBadRequestError(err.Error())
+	}
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	return nil
+}
+
 func validateRoutes(ctx context.Context, cluster *kubernetes.Cluster, appName, namespace string, desiredRoutes []string) apierror.APIErrors {
 	desiredRoutesMap := map[string]struct{}{}
 	for _, desiredRoute := range desiredRoutes {
@@ -12,6 +12,8 @@
 package application
 
 import (
+	"context"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -32,7 +34,7 @@ const (
 
 // Deploy handles the API endpoint /namespaces/:namespace/applications/:app/deploy
 // It uses an application chart to create the deployment, configuration and ingress (kube)
-// resources for the app.
+// resources for the app. With the `dryRun` parameter it only renders the chart.
 func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 
@@ -52,45 +54,81 @@ func (hc Controller) Deploy(c *gin.Context) apierror.APIErrors {
 		return apierror.NewBadRequestError("namespace parameter from URL does not match namespace param in body")
 	}
 
+	if c.Query("dryRun") == "true" {
+		result, apierr := dryRunImage(ctx, req, username)
+		if apierr != nil {
+			return apierr
+		}
+
+		response.OKReturn(c, result)
+		return nil
+	}
+
+	routes, apierr := deployImage(ctx, req, username)
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, models.DeployResponse{
+		Routes: routes,
+	})
+	return nil
+}
+
+// deployImage points the app to the image of the request, and creates the deployment,
+// configuration and ingress resources for it. It returns the routes of the app.
+func deployImage(ctx context.Context, req models.DeployRequest, username string) ([]string, apierror.APIErrors) {
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
-		return apierror.InternalError(err, "failed to get access to a kube client")
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
 	}
 
 	applicationCR, err := application.Get(ctx, cluster, req.App)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return apierror.AppIsNotKnown("cannot deploy app, application resource is missing")
+			return nil, apierror.AppIsNotKnown("cannot deploy app, application resource is missing")
 		}
-		return apierror.InternalError(err, "failed to get the application resource")
+		return nil, apierror.InternalError(err, "failed to get the application resource")
+	}
+
+	// Pin images not built by staging to their digest, and record it in the origin.
+	if apierr := pinRequestImage(ctx, cluster, &req); apierr != nil {
+		return nil, apierr
 	}
 
 	err = deploy.UpdateImageURL(ctx, cluster, applicationCR, req.ImageURL)
 	if err != nil {
-		return apierror.InternalError(err, "failed to set application's image url")
+		return nil, apierror.InternalError(err, "failed to set application's image url")
 	}
 
 	desiredRoutes, found, err := unstructured.NestedStringSlice(applicationCR.Object, "spec", "routes")
 	if err != nil {
-		return apierror.InternalError(err, "failed to get the application routes")
+		return nil, apierror.InternalError(err, "failed to get the application routes")
 	}
 	if !found {
 		// [NO-ROUTES] See other places bearing this marker for explanations.
 		desiredRoutes = []string{}
 	}
 
-	apierr := validateRoutes(ctx, cluster, name, namespace, desiredRoutes)
+	apierr := validateRoutes(ctx, cluster, req.App.Name, req.App.Namespace, desiredRoutes)
 	if apierr != nil {
-		return apierr
+		return nil, apierr
 	}
 
-	routes, apierr := deploy.DeployApp(ctx, cluster, req.App, username, req.Stage.ID, &req.Origin, nil)
-	if apierr != nil {
-		return apierr
+	return deploy.DeployApp(ctx, cluster, req.App, username, req.Stage.ID, &req.Origin, nil)
+}
+
+// dryRunImage renders the application chart for the image of the request, without
+// changing the app or deploying anything.
+func dryRunImage(ctx context.Context, req models.DeployRequest, username string) (models.DeployDryRunResponse, apierror.APIErrors) {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return models.DeployDryRunResponse{}, apierror.InternalError(err, "failed to get access to a kube client")
 	}
 
-	response.OKReturn(c, models.DeployResponse{
-		Routes: routes,
-	})
-	return nil
+	if apierr := pinRequestImage(ctx, cluster, &req); apierr != nil {
+		return models.DeployDryRunResponse{}, apierr
+	}
+
+	return deploy.DryRun(ctx, cluster, req.App, username, req.Stage.ID, req.ImageURL)
 }
@@ -0,0 +1,100 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/ociimage"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// registryTimeout limits the time spent talking to a registry when pinning an image.
+const registryTimeout = 30 * time.Second
+
+// pinRequestImage pins the image of the deploy request, unless it is the output of the
+// staging named by the request. The digest is recorded in the origin of container images.
+// The origin is given by the client, and therefore not considered in the decision.
+func pinRequestImage(ctx context.Context, cluster *kubernetes.Cluster, req *models.DeployRequest) apierror.APIErrors {
+	registryURL, err := getRegistryURL(ctx, cluster)
+	if err != nil {
+		return apierror.InternalError(err, "determining the registry of the staged images")
+	}
+	if !needsPinning(*req, registryURL) {
+		return nil
+	}
+
+	imageURL, digest, apierr := pinImage(ctx, cluster, req.App.Namespace, req.ImageURL)
+	if apierr != nil {
+		return apierr
+	}
+
+	req.ImageURL = imageURL
+	if req.Origin.Kind == models.OriginContainer {
+		req.Origin.Digest = digest
+	}
+	return nil
+}
+
+// needsPinning returns true if the image of the deploy request is not the image built by
+// the staging named in the request.
+func needsPinning(req models.DeployRequest, registryURL string) bool {
+	if req.Stage.ID == "" {
+		return true
+	}
+
+	staged := stageParam{AppRef: req.App, Stage: req.Stage}
+	return req.ImageURL != staged.ImageURL(registryURL)
+}
+
+// pinImage resolves the container image to the digest of its manifest, so that changes
+// to the tag do not change what runs. When the namespace requires signed images, the
+// signature of the image is verified as well. It returns the image by digest, and the
+// digest.
+func pinImage(ctx context.Context, cluster *kubernetes.Cluster, namespace, imageURL string) (string, string, apierror.APIErrors) {
+	credentials, err := ociimage.RegistryCredentials(ctx, cluster)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", "", apierror.InternalError(err, "reading the registry credentials")
+	}
+
+	keys, err := ociimage.SigningKeys(ctx, cluster, namespace)
+	if err != nil {
+		return "", "", apierror.InternalError(err, "reading the image signing keys")
+	}
+
+	client := ociimage.NewClient(&http.Client{Timeout: registryTimeout}, credentials)
+
+	image, err := client.Resolve(ctx, imageURL)
+	if err != nil {
+		return "", "", apierror.NewBadRequestError(err.Error()).
+			WithDetails("cannot resolve the container image to a digest")
+	}
+
+	if len(keys) > 0 {
+		err := client.VerifySignature(ctx, image, keys)
+		if errors.Is(err, ociimage.ErrNotSigned) || errors.Is(err, ociimage.ErrSignatureInvalid) {
+			return "", "", apierror.NewBadRequestErrorf("container image %s: %s", image, err.Error()).
+				WithDetailsf("namespace %s requires images signed with the configured keys", namespace)
+		}
+		if err != nil {
+			return "", "", apierror.InternalError(err, "verifying the container image signature")
+		}
+	}
+
+	return image.String(), image.Digest, nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("needsPinning", func() {
+	registry := "registry.example.com/apps"
+	app := models.NewAppRef("sample", "workspace")
+
+	It("does not pin the image built by the staging of the request", func() {
+		req := models.DeployRequest{
+			App:      app,
+			Stage:    models.NewStage("s1"),
+			ImageURL: "registry.example.com/apps/workspace-sample:s1",
+			Origin:   models.ApplicationOrigin{Kind: models.OriginPath},
+		}
+		Expect(needsPinning(req, registry)).To(BeFalse())
+	})
+
+	It("pins container images", func() {
+		req := models.DeployRequest{
+			App:      app,
+			ImageURL: "docker.io/library/nginx:latest",
+			Origin:   models.ApplicationOrigin{Kind: models.OriginContainer, Container: "nginx"},
+		}
+		Expect(needsPinning(req, registry)).To(BeTrue())
+	})
+
+	It("pins foreign images whatever the origin claimed by the client", func() {
+		for _, kind := range []int{models.OriginNone, models.OriginPath, models.OriginGit} {
+			req := models.DeployRequest{
+				App:      app,
+				Stage:    models.NewStage("s1"),
+				ImageURL: "docker.io/library/nginx:latest",
+				Origin:   models.ApplicationOrigin{Kind: kind},
+			}
+			Expect(needsPinning(req, registry)).To(BeTrue())
+		}
+	})
+
+	It("pins the image of another stage or app", func() {
+		req := models.DeployRequest{
+			App:      app,
+			Stage:    models.NewStage("s2"),
+			ImageURL: "registry.example.com/apps/workspace-sample:s1",
+		}
+		Expect(needsPinning(req, registry)).To(BeTrue())
+
+		req.Stage = models.NewStage("s1")
+		req.ImageURL = "registry.example.com/apps/workspace-other:s1"
+		Expect(needsPinning(req, registry)).To(BeTrue())
+	})
+})
@@ -71,28 +71,34 @@ package application
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 
 	"github.com/epinio/epinio/helpers"
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
-	"github.com/epinio/epinio/internal/helmchart"
-	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/epinio/epinio/internal/gitconfig"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 )
 
 // ImportGit handles the API endpoint /namespaces/:namespace/applications/:app/import-git.
 // It receives a Git repo url and revision, clones that (shallow clone), creates a tarball
-// of the repo and puts it on S3.
+// of the repo, or of the requested sub directory, and stores it in the blob storage.
+// Private repositories are accessed with the credentials of the best matching git
+// configuration of the namespace.
 func (hc Controller) ImportGit(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 	log := requestctx.Logger(ctx)
@@ -100,75 +106,118 @@ func (hc Controller) ImportGit(c *gin.Context) apierror.APIErrors {
 	namespace := c.Param("namespace")
 	name := c.Param("app")
 
-	url := c.PostForm("giturl")
-	revision := c.PostForm("gitrev")
+	gitRef := models.GitRef{
+		URL:      c.PostForm("giturl"),
+		Revision: c.PostForm("gitrev"),
+		Subdir:   c.PostForm("gitsubdir"),
+	}
+
+	if submodules := c.PostForm("gitsubmodules"); submodules != "" {
+		var err error
+		gitRef.Submodules, err = strconv.ParseBool(submodules)
+		if err != nil {
+			return apierror.NewBadRequestError(err.Error()).WithDetails("bad value for gitsubmodules")
+		}
+	}
+
+	username := requestctx.User(ctx).Username
+
+	blobUID, apierr := importGit(ctx, log, namespace, name, gitRef, username)
+	if apierr != nil {
+		return apierr
+	}
+
+	// Return the id of the new blob
+	response.OKReturn(c, models.ImportGitResponse{
+		BlobUID: blobUID,
+	})
+	return nil
+}
+
+// importGit clones the Git repository at the revision, with its submodules if requested,
+// creates a tarball of it, or of the requested sub directory, and puts that on S3. It
+// returns the id of the new blob.
+func importGit(ctx context.Context, log logr.Logger, namespace, name string, gitRef models.GitRef, username string) (string, apierror.APIErrors) {
+	url, revision := gitRef.URL, gitRef.Revision
 
 	gitRepo, err := os.MkdirTemp("", "epinio-app")
 	if err != nil {
-		return apierror.InternalError(err, "can't create temp directory")
+		return "", apierror.InternalError(err, "can't create temp directory")
 	}
 	defer os.RemoveAll(gitRepo)
 
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return "", apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	auth, err := gitconfig.AuthFor(ctx, cluster, namespace, url)
+	if err != nil {
+		return "", apierror.InternalError(err, "fetching the git credentials")
+	}
+
 	// clone/fetch/checkout
-	err = getRepository(ctx, log, gitRepo, url, revision)
+	err = getRepository(ctx, log, gitRepo, url, revision, auth)
 	if err != nil {
-		return apierror.InternalError(err,
+		return "", apierror.InternalError(err,
 			fmt.Sprintf("cloning the git repository: %s @ %s", url, revision))
 	}
 
+	if gitRef.Submodules {
+		err = updateSubmodules(ctx, log, gitRepo, gitRef.URL, auth, func(url string) (transport.AuthMethod, error) {
+			return gitconfig.AuthFor(ctx, cluster, namespace, url)
+		})
+		if err != nil {
+			return "", apierror.InternalError(err,
+				fmt.Sprintf("fetching the submodules of the git repository: %s @ %s", url, revision))
+		}
+	}
+
+	sources, err := sourceDir(gitRepo, gitRef.Subdir)
+	if err != nil {
+		return "", apierror.NewBadRequestError(err.Error()).WithDetails("bad sub directory")
+	}
+
 	// Create a tarball
-	tmpDir, tarball, err := helpers.Tar(gitRepo)
+	tmpDir, tarball, err := helpers.Tar(sources)
 	defer func() {
 		if tmpDir != "" {
 			_ = os.RemoveAll(tmpDir)
 		}
 	}()
 	if err != nil {
-		return apierror.InternalError(err, "create a tarball from the git repository")
+		return "", apierror.InternalError(err, "create a tarball from the git repository")
 	}
 
-	// Upload to S3
-	cluster, err := kubernetes.GetCluster(ctx)
-	if err != nil {
-		return apierror.InternalError(err, "failed to get access to a kube client")
-	}
-	connectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster, helmchart.Namespace(), "epinio-s3-connection-details")
-	if err != nil {
-		return apierror.InternalError(err, "fetching the S3 connection details from the Kubernetes secret")
-	}
-	manager, err := s3manager.New(connectionDetails)
+	// Upload to the blob storage
+	manager, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "creating an S3 manager")
+		return "", apierror.InternalError(err, "accessing the blob storage")
 	}
 
-	username := requestctx.User(ctx).Username
 	blobUID, err := manager.Upload(ctx, tarball, map[string]string{
 		"app": name, "namespace": namespace, "username": username,
 	})
 	if err != nil {
-		return apierror.InternalError(err, "uploading the application sources blob")
+		return "", apierror.InternalError(err, "uploading the application sources blob")
 	}
 	log.Info("uploaded app", "namespace", namespace, "app", name, "blobUID", blobUID)
 
-	// Return the id of the new blob
-	response.OKReturn(c, models.ImportGitResponse{
-		BlobUID: blobUID,
-	})
-	return nil
+	return blobUID, nil
 }
 
-func getRepository(ctx context.Context, log logr.Logger, gitRepo, url, revision string) error {
+func getRepository(ctx context.Context, log logr.Logger, gitRepo, url, revision string, auth transport.AuthMethod) error {
 	if revision == "" {
 		// Input A: repository, no revision.
 		log.Info("importgit, cloning simple", "url", url)
-		_, err := shallowClone(ctx, gitRepo, url)
+		_, err := shallowClone(ctx, gitRepo, url, auth)
 		return err
 	}
 
 	// Input B or C: Attempt to treat as B (revision is branch name)
 
 	log.Info("importgit, cloning branch", "url", url, "revision", revision)
-	_, err := branchClone(ctx, gitRepo, url, revision)
+	_, err := branchClone(ctx, gitRepo, url, revision, auth)
 	if err == nil {
 		// Was branch name, done.
 		return nil
@@ -182,7 +231,7 @@ func getRepository(ctx context.Context, log logr.Logger, gitRepo, url, revision
 	// 2 stage process - A simple clone followed by a checkout
 
 	log.Info("importgit, cloning simple, commit id", "url", url)
-	repository, err := generalClone(ctx, gitRepo, url)
+	repository, err := generalClone(ctx, gitRepo, url, auth)
 	if err != nil {
 		return err
 	}
@@ -208,25 +257,137 @@ func getRepository(ctx context.Context, log logr.Logger, gitRepo, url, revision
 	})
 }
 
-func branchClone(ctx context.Context, gitRepo, url, revision string) (*git.Repository, error) {
+// updateSubmodules initializes and fetches the submodules of the cloned repository,
+// recursively. Each submodule is accessed with the credentials of the git configuration
+// matching its url. Without such, the credentials of the repository holding the submodule
+// are used, but only for submodules on the same host. The submodule urls are chosen by
+// the author of the repository, the credentials must not be sent elsewhere.
+func updateSubmodules(ctx context.Context, log logr.Logger, gitRepo, url string, auth transport.AuthMethod,
+	authFor func(url string) (transport.AuthMethod, error)) error {
+
+	repository, err := git.PlainOpen(gitRepo)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return err
+	}
+
+	for _, submodule := range submodules {
+		config := submodule.Config()
+
+		submoduleAuth, err := authFor(config.URL)
+		if err != nil {
+			return err
+		}
+		if submoduleAuth == nil && submoduleInherits(url, config.URL) {
+			submoduleAuth = auth
+		}
+
+		log.Info("importgit, updating submodule", "name", config.Name, "url", config.URL)
+
+		err = submodule.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.NoRecurseSubmodules,
+			Auth:              submoduleAuth,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "submodule %s", config.Name)
+		}
+
+		// Recurse with the credentials and url of the submodule.
+		submoduleURL := config.URL
+		if isRelativeURL(submoduleURL) {
+			submoduleURL = url
+		}
+		err = updateSubmodules(ctx, log, filepath.Join(gitRepo, config.Path), submoduleURL, submoduleAuth, authFor)
+		if err != nil {
+			return errors.Wrapf(err, "submodule %s", config.Name)
+		}
+	}
+
+	return nil
+}
+
+// submoduleInherits returns true if the submodule at the url may be accessed with the
+// credentials of the repository at the parent url, i.e. if both are on the same host.
+func submoduleInherits(parentURL, url string) bool {
+	return isRelativeURL(url) || gitconfig.SameHost(parentURL, url)
+}
+
+// isRelativeURL returns true for the submodule urls relative to the repository holding
+// the submodule.
+func isRelativeURL(url string) bool {
+	return strings.HasPrefix(url, "./") || strings.HasPrefix(url, "../")
+}
+
+// sourceDir returns the directory holding the application sources in the cloned
+// repository. That is the sub directory, if specified. The sub directory has to exist,
+// and must not lead out of the repository.
+func sourceDir(gitRepo, subdir string) (string, error) {
+	if subdir == "" {
+		return gitRepo, nil
+	}
+
+	if filepath.IsAbs(subdir) {
+		return "", errors.Errorf("sub directory %s is not relative", subdir)
+	}
+
+	root, err := filepath.EvalSymlinks(gitRepo)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, subdir))
+	if err != nil {
+		return "", errors.Errorf("sub directory %s does not exist", subdir)
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("sub directory %s is outside of the repository", subdir)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", errors.Errorf("sub directory %s is not a directory", subdir)
+	}
+
+	return dir, nil
+}
+
+func branchClone(ctx context.Context, gitRepo, url, revision string, auth transport.AuthMethod) (*git.Repository, error) {
 	// Note, it is shallow too
 	return git.PlainCloneContext(ctx, gitRepo, false, &git.CloneOptions{
 		URL:           url,
+		Auth:          auth,
 		SingleBranch:  true,
 		ReferenceName: plumbing.NewBranchReferenceName(revision),
 		Depth:         1,
 	})
 }
 
-func shallowClone(ctx context.Context, gitRepo, url string) (*git.Repository, error) {
+func shallowClone(ctx context.Context, gitRepo, url string, auth transport.AuthMethod) (*git.Repository, error) {
 	return git.PlainCloneContext(ctx, gitRepo, false, &git.CloneOptions{
 		URL:   url,
+		Auth:  auth,
 		Depth: 1,
 	})
 }
 
-func generalClone(ctx context.Context, gitRepo, url string) (*git.Repository, error) {
+func generalClone(ctx context.Context, gitRepo, url string, auth transport.AuthMethod) (*git.Repository, error) {
 	return git.PlainCloneContext(ctx, gitRepo, false, &git.CloneOptions{
-		URL: url,
+		URL:  url,
+		Auth: auth,
 	})
 }
@@ -0,0 +1,84 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Import git sources", func() {
+	var gitRepo string
+
+	BeforeEach(func() {
+		var err error
+		gitRepo, err = os.MkdirTemp("", "epinio-sourcedir")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(gitRepo, "apps", "web"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(gitRepo, "README.md"), []byte("readme"), 0600)).To(Succeed())
+		Expect(os.Symlink(os.TempDir(), filepath.Join(gitRepo, "escape"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(gitRepo)).To(Succeed())
+	})
+
+	It("returns the repository without sub directory", func() {
+		dir, err := sourceDir(gitRepo, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dir).To(Equal(gitRepo))
+	})
+
+	It("returns the sub directory", func() {
+		dir, err := sourceDir(gitRepo, "apps/web")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dir).To(HaveSuffix(filepath.Join("apps", "web")))
+
+		dir, err = sourceDir(gitRepo, "./apps/../apps/web/")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dir).To(HaveSuffix(filepath.Join("apps", "web")))
+	})
+
+	It("rejects missing sub directories and files", func() {
+		_, err := sourceDir(gitRepo, "apps/api")
+		Expect(err).To(MatchError(ContainSubstring("does not exist")))
+
+		_, err = sourceDir(gitRepo, "README.md")
+		Expect(err).To(MatchError(ContainSubstring("not a directory")))
+	})
+
+	It("rejects sub directories outside of the repository", func() {
+		_, err := sourceDir(gitRepo, "/etc")
+		Expect(err).To(MatchError(ContainSubstring("not relative")))
+
+		_, err = sourceDir(gitRepo, "..")
+		Expect(err).To(MatchError(ContainSubstring("outside of the repository")))
+
+		_, err = sourceDir(gitRepo, "escape")
+		Expect(err).To(MatchError(ContainSubstring("outside of the repository")))
+	})
+
+	It("shares the repository credentials only with submodules on the same host", func() {
+		parent := "https://github.com/epinio/app.git"
+
+		Expect(submoduleInherits(parent, "https://github.com/epinio/lib.git")).To(BeTrue())
+		Expect(submoduleInherits(parent, "../lib.git")).To(BeTrue())
+
+		Expect(submoduleInherits(parent, "https://evil.io/epinio/lib.git")).To(BeFalse())
+		Expect(submoduleInherits(parent, "https://github.com.evil.io/epinio/lib.git")).To(BeFalse())
+		Expect(submoduleInherits(parent, "git@github.com:epinio/lib.git")).To(BeFalse())
+	})
+})
@@ -14,6 +14,7 @@ package application
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
@@ -24,7 +25,9 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/helmchart"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 
@@ -96,6 +99,14 @@ func (hc Controller) Logs(c *gin.Context) {
 	log.Info("streaming mode", "follow", follow)
 	log.Info("streaming begin")
 
+	if stageID != "" && follow {
+		err = hc.streamQueuePosition(ctx, conn, namespace, stageID, cluster)
+		if err != nil {
+			log.V(1).Error(err, "error occurred while reporting the staging queue position")
+			return
+		}
+	}
+
 	err = hc.streamPodLogs(ctx, conn, namespace, appName, stageID, cluster, follow)
 	if err != nil {
 		log.V(1).Error(err, "error occurred after upgrading the websockets connection")
@@ -105,6 +116,58 @@ func (hc Controller) Logs(c *gin.Context) {
 	log.Info("streaming completed")
 }
 
+// streamQueuePosition sends a message with the position of the staging in the build queue
+// to the websocket connection whenever that position changes. It returns when the
+// staging leaves the queue, or ctx is Done.
+func (hc Controller) streamQueuePosition(ctx context.Context, conn *websocket.Conn, namespaceName, stageID string, cluster *kubernetes.Cluster) error {
+	selector := fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,%s=%s",
+		namespaceName, models.EpinioStageIDLabel, stageID)
+
+	lastPosition := 0
+	for {
+		jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), selector)
+		if err != nil {
+			return err
+		}
+
+		queued := false
+		for _, job := range jobList.Items {
+			queued = queued || application.IsQueued(job)
+		}
+		if !queued {
+			return nil
+		}
+
+		position, err := application.StagingQueuePosition(ctx, cluster, stageID)
+		if err != nil {
+			return err
+		}
+
+		if position > 0 && position != lastPosition {
+			msg, err := json.Marshal(tailer.ContainerLogLine{
+				Message:   fmt.Sprintf("Queued for staging, position %d", position),
+				Namespace: namespaceName,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = conn.WriteMessage(websocket.TextMessage, msg)
+			if err != nil {
+				return err
+			}
+
+			lastPosition = position
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
 // streamPodLogs sends the logs of any containers matching namespaceName, appName
 // and stageID to hc.conn (websockets) until ctx is Done or the connection is
 // closed.
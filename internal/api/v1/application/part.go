@@ -314,7 +314,12 @@ func getFileImageAndJobCleanup(ctx context.Context, cluster *kubernetes.Cluster,
 }
 
 func fetchAppValues(c *gin.Context, logger logr.Logger, cluster *kubernetes.Cluster, app models.AppRef) apierror.APIErrors {
-	yaml, err := helm.Values(cluster, logger, app)
+	applicationCR, err := application.Get(c.Request.Context(), cluster, app)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	yaml, err := helm.Values(cluster, logger, app, application.LiveRelease(applicationCR))
 	if err != nil {
 		return apierror.InternalError(err)
 	}
@@ -0,0 +1,89 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/deploy"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Promote handles the API endpoint POST /namespaces/:namespace/applications/:app/promote
+// It promotes the candidate deployed by the blue/green or canary strategies, or shifts the
+// traffic split of a canary.
+func (hc Controller) Promote(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+	username := requestctx.User(ctx).Username
+
+	req := models.PromoteRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("failed to unmarshal app promote request")
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	staging, err := application.CurrentlyStaging(ctx, cluster, namespace, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if staging {
+		return apierror.NewBadRequestError("cannot promote while the application is staging")
+	}
+
+	log.Info("promoting app", "namespace", namespace, "app", appName, "weight", req.Weight)
+
+	resp, apierr := deploy.Promote(ctx, cluster, models.NewAppRef(appName, namespace), username, req.Weight)
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// Abort handles the API endpoint POST /namespaces/:namespace/applications/:app/abort
+// It removes the candidate deployed by the blue/green or canary strategies, leaving the
+// live build in place.
+func (hc Controller) Abort(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	log.Info("aborting app candidate", "namespace", namespace, "app", appName)
+
+	resp, apierr := deploy.Abort(ctx, cluster, models.NewAppRef(appName, namespace))
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/helm"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Releases handles the API endpoint GET /namespaces/:namespace/applications/:app/releases
+// It returns the revisions of the live helm release of the application, most recent first.
+func (hc Controller) Releases(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	releaseName, apierr := liveRelease(c, cluster, models.NewAppRef(appName, namespace))
+	if apierr != nil {
+		return apierr
+	}
+
+	history, err := helm.History(cluster, log, namespace, releaseName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, history)
+	return nil
+}
+
+// ReleaseDiff handles the API endpoint GET /namespaces/:namespace/applications/:app/releases/:rev/diff/:rev2
+// It returns the differences in values and manifests between the two revisions of the
+// live helm release of the application.
+func (hc Controller) ReleaseDiff(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	from, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || from < 1 {
+		return apierror.NewBadRequestErrorf("invalid revision '%s'", c.Param("rev"))
+	}
+	to, err := strconv.Atoi(c.Param("rev2"))
+	if err != nil || to < 1 {
+		return apierror.NewBadRequestErrorf("invalid revision '%s'", c.Param("rev2"))
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	releaseName, apierr := liveRelease(c, cluster, models.NewAppRef(appName, namespace))
+	if apierr != nil {
+		return apierr
+	}
+
+	diff, err := helm.ReleaseDiff(cluster, log, namespace, releaseName, from, to)
+	if err != nil {
+		if errors.Is(err, helm.ErrRevisionNotFound) {
+			return apierror.NewAPIError(err.Error(), http.StatusNotFound)
+		}
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, diff)
+	return nil
+}
+
+// liveRelease returns the name of the helm release serving the application. Its history
+// starts with the last promotion of a candidate, which removes the previously live release.
+func liveRelease(c *gin.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, apierror.APIErrors) {
+	applicationCR, err := application.Get(c.Request.Context(), cluster, appRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", apierror.AppIsNotKnown(appRef.Name)
+		}
+		return "", apierror.InternalError(err)
+	}
+
+	return application.LiveRelease(applicationCR), nil
+}
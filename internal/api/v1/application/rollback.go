@@ -0,0 +1,127 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/deploy"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rollback handles the API endpoint POST /namespaces/:namespace/applications/:app/rollback
+// It redeploys the application with the image of an earlier build, and the current
+// configuration.
+func (hc Controller) Rollback(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+	username := requestctx.User(ctx).Username
+
+	req := models.RollbackRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("failed to unmarshal app rollback request")
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	app, err := application.Lookup(ctx, cluster, namespace, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if app == nil {
+		return apierror.AppIsNotKnown(appName)
+	}
+
+	staging, err := application.CurrentlyStaging(ctx, cluster, namespace, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if staging {
+		return apierror.NewBadRequestError("cannot roll back while the application is staging")
+	}
+
+	currentStageID := app.StageID
+	if app.Workload != nil && app.Workload.StageID != "" {
+		currentStageID = app.Workload.StageID
+	}
+
+	builds, err := application.Builds(ctx, cluster, app.Meta)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	// The blob collector keeps the sources of the most recent builds only.
+	keep := 0
+	if viper.GetInt("source-blob-max-age-hours") > 0 {
+		keep = viper.GetInt("source-blob-keep-stages")
+	}
+
+	target, err := application.RollbackTarget(builds, currentStageID, req.StageID, keep)
+	if err != nil {
+		if errors.Is(err, application.ErrBuildNotFound) {
+			return apierror.NewNotFoundError("build", req.StageID)
+		}
+		if errors.Is(err, application.ErrNoRollbackTarget) {
+			return apierror.NewBadRequestError(err.Error())
+		}
+		return apierror.InternalError(err)
+	}
+
+	applicationCR, err := application.Get(ctx, cluster, app.Meta)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierror.AppIsNotKnown("cannot roll back app, application resource is missing")
+		}
+		return apierror.InternalError(err, "failed to get the application resource")
+	}
+
+	rollback := models.AppRollback{
+		StageID:     target.StageID,
+		FromStageID: currentStageID,
+		ImageURL:    target.ImageURL,
+		Username:    username,
+		Time:        metav1.Now(),
+	}
+
+	log.Info("rolling back app", "namespace", namespace, "app", appName,
+		"from", currentStageID, "to", target.StageID, "image", target.ImageURL)
+
+	var routes []string
+	apierr := application.DeployRollback(ctx, cluster, applicationCR, rollback, func() apierror.APIErrors {
+		var apierr apierror.APIErrors
+		routes, apierr = deploy.DeployApp(ctx, cluster, app.Meta, username, "", nil, nil)
+		return apierr
+	})
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, models.RollbackResponse{
+		Rollback: rollback,
+		Routes:   routes,
+	})
+	return nil
+}
@@ -13,7 +13,11 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"path"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -23,7 +27,6 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -32,6 +35,7 @@ import (
 	"github.com/epinio/epinio/helpers/randstr"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/internal/helmchart"
@@ -46,6 +50,7 @@ type stageParam struct {
 	models.AppRef
 	BlobUID             string
 	BuilderImage        string
+	BuilderArgs         []string
 	DownloadImage       string
 	UnpackImage         string
 	ServiceAccountName  string
@@ -53,13 +58,34 @@ type stageParam struct {
 	Owner               metav1.OwnerReference
 	RegistryURL         string
 	S3ConnectionDetails s3manager.ConnectionDetails
+	BlobStorage         string
+	BlobStoragePVC      string
+	BlobStorageGroup    int64
 	Stage               models.StageRef
 	Username            string
 	PreviousStageID     string
 	RegistryCASecret    string
 	RegistryCAHash      string
+	NoCache             bool
+	Strategy            string
+	Dockerfile          string
+	Queued              bool
+	AppResources        *models.StagingResources
+	Resources           corev1.ResourceRequirements
+	NodeSelector        map[string]string
+	Tolerations         []corev1.Toleration
 }
 
+const (
+	// stageSourceDir is the directory of the application sources in the staging job.
+	// The download script of the stage support places the blob there, as
+	// ${BLOBID}, and the unpack script reads it from there. The download step of the
+	// filesystem blob storage follows the same contract.
+	stageSourceDir = "/workspace/source"
+	// blobMountDir is where the PVC of the filesystem blob storage is mounted.
+	blobMountDir = "/blobs"
+)
+
 // ImageURL returns the URL of the container image to be, using the
 // ImageID. The ImageURL is later used in app.yml and to send in the
 // stage response.
@@ -67,46 +93,10 @@ func (app *stageParam) ImageURL(registryURL string) string {
 	return fmt.Sprintf("%s/%s-%s:%s", registryURL, app.Namespace, app.Name, app.Stage.ID)
 }
 
-// ensurePVC creates a PVC for the application if one doesn't already exist.
-// This PVC is used to store the application source blobs (as they are uploaded
-// on the "upload" endpoint). It is also mounted in the staging pod, as the
-// "source" workspace.
-// The same PVC stores the application's build cache (on a separate directory).
-func ensurePVC(ctx context.Context, cluster *kubernetes.Cluster, ar models.AppRef) error {
-	_, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
-		Get(ctx, ar.MakePVCName(), metav1.GetOptions{})
-	if err != nil && !apierrors.IsNotFound(err) { // Unknown error, irrelevant to non-existence
-		return err
-	}
-	if err == nil { // pvc already exists
-		return nil
-	}
-
-	// From here on, only if the PVC is missing
-	_, err = cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
-		Create(ctx, &corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      ar.MakePVCName(),
-				Namespace: helmchart.Namespace(),
-			},
-			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-				Resources: corev1.ResourceRequirements{
-					Requests: map[corev1.ResourceName]resource.Quantity{
-						corev1.ResourceStorage: resource.MustParse("1Gi"),
-					},
-				},
-			},
-		}, metav1.CreateOptions{})
-
-	return err
-}
-
 // Stage handles the API endpoint /namespaces/:namespace/applications/:app/stage
 // It creates a Job resource to stage the app
 func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
-	log := requestctx.Logger(ctx)
 
 	namespace := c.Param("namespace")
 	name := c.Param("app")
@@ -123,69 +113,147 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 		return apierror.NewBadRequestError("namespace parameter from URL does not match namespace param in body")
 	}
 
+	resp, apierr := stageApp(ctx, req, username)
+	if apierr != nil {
+		return apierr
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
+
+// stageApp creates a Job resource to stage the app described by the request.
+func stageApp(ctx context.Context, req models.StageRequest, username string) (models.StageResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+
 	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
-		return apierror.InternalError(err, "failed to get access to a kube client")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to get access to a kube client")
 	}
 
 	// check application resource
 	app, err := application.Get(ctx, cluster, req.App)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return apierror.AppIsNotKnown("cannot stage app, application resource is missing")
+			return models.StageResponse{}, apierror.AppIsNotKnown("cannot stage app, application resource is missing")
 		}
-		return apierror.InternalError(err, "failed to get the application resource")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to get the application resource")
 	}
 
 	config, err := cluster.GetConfigMap(ctx, helmchart.Namespace(), helmchart.EpinioStageScriptsName)
 	if err != nil {
-		return apierror.InternalError(err, "failed to retrieve staging image refs")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to retrieve staging image refs")
+	}
+
+	// get staging strategy and dockerfile from either request, application, or default as final fallback
+
+	strategy, dockerfile, strategyErr := getStagingStrategy(req, app)
+	if strategyErr != nil {
+		return models.StageResponse{}, strategyErr
+	}
+
+	// get staging job resources from either request, or application, merged over the
+	// server defaults
+
+	appResources, resourcesErr := getStagingResources(req, app)
+	if resourcesErr != nil {
+		return models.StageResponse{}, resourcesErr
+	}
+
+	defaultResources, resourceCaps, err := stagingDefaults()
+	if err != nil {
+		return models.StageResponse{}, apierror.InternalError(err, "bad staging resources configuration")
+	}
+
+	resources, err := resolveStagingResources(defaultResources, appResources, resourceCaps)
+	if err != nil {
+		return models.StageResponse{}, apierror.NewBadRequestError(err.Error())
+	}
+
+	requirements, err := resourceRequirements(resources)
+	if err != nil {
+		return models.StageResponse{}, apierror.NewBadRequestError(err.Error())
+	}
+
+	tolerations, err := stagingTolerations()
+	if err != nil {
+		return models.StageResponse{}, apierror.InternalError(err, "bad staging tolerations configuration")
 	}
 
 	// get builder image from either request, application, or default as final fallback
 
 	builderImage, builderErr := getBuilderImage(req, app)
 	if builderErr != nil {
-		return builderErr
+		return models.StageResponse{}, builderErr
 	}
 	if builderImage == "" {
 		builderImage = config.Data["builderImage"]
 	}
 
+	// The dockerfile strategy runs the image builder configured for it, instead of the
+	// buildpack builder. The buildpack builder is kept on the application, for when the
+	// application is switched back to buildpacks.
+	//
+	// The staging config map keys used by the dockerfile strategy are:
+	//   - dockerfileBuilderImage: the image builder, e.g. kaniko's executor image.
+	//   - dockerfileBuilderArgs: optional, the arguments passed to the entrypoint of the
+	//     image builder, one per line. Defaults to the arguments of kaniko's executor,
+	//     see `defaultDockerfileBuilderArgs`.
+	// The image builder runs with its own entrypoint and user.
+	imageBuilder := builderImage
+	var imageBuilderArgs []string
+	if strategy == models.StagingStrategyDockerfile {
+		imageBuilder = config.Data["dockerfileBuilderImage"]
+		if imageBuilder == "" {
+			return models.StageResponse{}, apierror.NewBadRequestError("staging with a Dockerfile is not configured on this Epinio installation")
+		}
+		imageBuilderArgs = builderArgs(config.Data["dockerfileBuilderArgs"])
+	}
+
 	downloadImage := config.Data["downloadImage"]
 	unpackImage := config.Data["unpackImage"]
 
-	log.Info("staging app", "namespace", namespace, "app", req)
+	log.Info("staging app", "namespace", req.App.Namespace, "app", req)
 
 	staging, err := application.CurrentlyStaging(ctx, cluster, req.App.Namespace, req.App.Name)
 	if err != nil {
-		return apierror.InternalError(err)
+		return models.StageResponse{}, apierror.InternalError(err)
 	}
 	if staging {
-		return apierror.NewBadRequestError("staging job for image ID still running")
+		return models.StageResponse{}, apierror.NewBadRequestError("staging job for image ID still running")
 	}
 
-	s3ConnectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
-		helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+	store, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "failed to fetch the S3 connection details")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to access the blob storage")
+	}
+
+	// The staging job downloads the sources from S3, or from the mounted blob storage.
+	blobStorage := blobstore.Backend()
+	s3ConnectionDetails := s3manager.ConnectionDetails{}
+	if blobStorage == blobstore.BackendS3 {
+		s3ConnectionDetails, err = s3manager.GetConnectionDetails(ctx, cluster,
+			helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+		if err != nil {
+			return models.StageResponse{}, apierror.InternalError(err, "failed to fetch the S3 connection details")
+		}
 	}
 
-	blobUID, blobErr := getBlobUID(ctx, s3ConnectionDetails, req, app)
+	blobUID, blobErr := getBlobUID(ctx, store, req, app)
 	if blobErr != nil {
-		return blobErr
+		return models.StageResponse{}, blobErr
 	}
 
 	// Create uid identifying the staging job to be
 
 	uid, err := randstr.Hex16()
 	if err != nil {
-		return apierror.InternalError(err, "failed to generate a uid")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to generate a uid")
 	}
 
 	environment, err := application.Environment(ctx, cluster, req.App)
 	if err != nil {
-		return apierror.InternalError(err, "failed to access application runtime environment")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to access application runtime environment")
 	}
 
 	owner := metav1.OwnerReference{
@@ -199,7 +267,7 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	// From the view of the new build we are about to create this is the previous id.
 	previousID, err := application.StageID(app)
 	if err != nil {
-		return apierror.InternalError(err, "failed to determine application stage id")
+		return models.StageResponse{}, apierror.InternalError(err, "failed to determine application stage id")
 	}
 	if previousID == "" {
 		previousID = uid
@@ -207,7 +275,7 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 
 	registryPublicURL, err := getRegistryURL(ctx, cluster)
 	if err != nil {
-		return apierror.InternalError(err, "getting the Epinio registry public URL")
+		return models.StageResponse{}, apierror.InternalError(err, "getting the Epinio registry public URL")
 	}
 
 	registryCertificateSecret := viper.GetString("registry-certificate-secret")
@@ -215,15 +283,17 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	if registryCertificateSecret != "" {
 		registryCertificateHash, err = getRegistryCertificateHash(ctx, cluster, helmchart.Namespace(), registryCertificateSecret)
 		if err != nil {
-			return apierror.InternalError(err, "cannot calculate Certificate hash")
+			return models.StageResponse{}, apierror.InternalError(err, "cannot calculate Certificate hash")
 		}
 	}
 
 	serviceAccountName := viper.GetString("staging-service-account-name")
+	limits := stagingLimits()
 
 	params := stageParam{
 		AppRef:              req.App,
-		BuilderImage:        builderImage,
+		BuilderImage:        imageBuilder,
+		BuilderArgs:         imageBuilderArgs,
 		DownloadImage:       downloadImage,
 		UnpackImage:         unpackImage,
 		ServiceAccountName:  serviceAccountName,
@@ -232,16 +302,31 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 		Owner:               owner,
 		RegistryURL:         registryPublicURL,
 		S3ConnectionDetails: s3ConnectionDetails,
+		BlobStorage:         blobStorage,
+		BlobStoragePVC:      blobstore.PVC(),
+		BlobStorageGroup:    int64(blobstore.Group()),
 		Stage:               models.NewStage(uid),
 		PreviousStageID:     previousID,
 		Username:            username,
 		RegistryCAHash:      registryCertificateHash,
 		RegistryCASecret:    registryCertificateSecret,
+		NoCache:             req.NoCache,
+		Strategy:            strategy,
+		Dockerfile:          dockerfile,
+		Queued:              limits.Enabled(),
+		AppResources:        appResources,
+		Resources:           requirements,
+		NodeSelector:        resources.NodeSelector,
+		Tolerations:         tolerations,
 	}
 
-	err = ensurePVC(ctx, cluster, req.App)
+	// The PVC holds the application's build cache.
+	err = application.EnsureCache(ctx, cluster, req.App)
 	if err != nil {
-		return apierror.InternalError(err, "failed to ensure a PersistenVolumeClaim for the application source and cache")
+		if errors.Is(err, application.ErrCacheBusy) {
+			return models.StageResponse{}, apierror.NewAPIError(err.Error(), http.StatusConflict)
+		}
+		return models.StageResponse{}, apierror.InternalError(err, "failed to ensure a PersistenVolumeClaim for the application cache")
 	}
 
 	job, jobenv := newJobRun(params)
@@ -249,31 +334,83 @@ func (hc Controller) Stage(c *gin.Context) apierror.APIErrors {
 	// Note: The secret is deleted with the job in function `Unstage()`.
 	err = cluster.CreateSecret(ctx, helmchart.Namespace(), *jobenv)
 	if err != nil {
-		return apierror.InternalError(err, fmt.Sprintf("failed to create job env: %#v", jobenv))
+		return models.StageResponse{}, apierror.InternalError(err, fmt.Sprintf("failed to create job env: %#v", jobenv))
 	}
 
 	err = cluster.CreateJob(ctx, helmchart.Namespace(), job)
 	if err != nil {
-		return apierror.InternalError(err, fmt.Sprintf("failed to create job run: %#v", job))
+		return models.StageResponse{}, apierror.InternalError(err, fmt.Sprintf("failed to create job run: %#v", job))
 	}
 
+	// Keep the buildpack builder on the application, see above.
+	params.BuilderImage = builderImage
 	if err := updateApp(ctx, cluster, app, params); err != nil {
-		return apierror.InternalError(err, "updating application CR with staging information")
+		return models.StageResponse{}, apierror.InternalError(err, "updating application CR with staging information")
 	}
 
 	imageURL := params.ImageURL(params.RegistryURL)
 
-	log.Info("staged app", "namespace", helmchart.Namespace(), "app", params.AppRef, "uid", uid, "image", imageURL)
+	origin, err := application.Origin(app)
+	if err != nil {
+		return models.StageResponse{}, apierror.InternalError(err, "failed to determine application origin")
+	}
 
-	response.OKReturn(c, models.StageResponse{
-		Stage:    models.NewStage(uid),
-		ImageURL: imageURL,
+	// A queued build is switched to running when its staging job is admitted.
+	buildStatus := models.BuildRunning
+	if params.Queued {
+		buildStatus = models.BuildQueued
+	}
+
+	err = application.BuildStarted(ctx, cluster, req.App, models.AppBuild{
+		StageID:      uid,
+		BlobUID:      blobUID,
+		BuilderImage: imageBuilder,
+		Strategy:     strategy,
+		Origin:       origin,
+		ImageURL:     imageURL,
+		Username:     username,
+		Status:       buildStatus,
+		StartedAt:    metav1.Now(),
 	})
-	return nil
+	if err != nil {
+		return models.StageResponse{}, apierror.InternalError(err, "recording the build in the application history")
+	}
+
+	queuePosition := 0
+	if limits.Enabled() {
+		err = application.AdmitStagings(ctx, cluster, limits)
+		if err != nil {
+			return models.StageResponse{}, apierror.InternalError(err, "admitting queued stagings")
+		}
+
+		queuePosition, err = application.StagingQueuePosition(ctx, cluster, uid)
+		if err != nil {
+			return models.StageResponse{}, apierror.InternalError(err, "determining the staging queue position")
+		}
+	}
+
+	log.Info("staged app", "namespace", helmchart.Namespace(), "app", params.AppRef, "uid", uid, "image", imageURL, "queue", queuePosition)
+
+	return models.StageResponse{
+		Stage:         models.NewStage(uid),
+		ImageURL:      imageURL,
+		QueuePosition: queuePosition,
+	}, nil
+}
+
+// stagingLimits returns the staging concurrency limits configured for the server.
+func stagingLimits() application.StagingLimits {
+	return application.StagingLimits{
+		Global:       viper.GetInt("staging-max-concurrent"),
+		PerNamespace: viper.GetInt("staging-max-concurrent-per-namespace"),
+	}
 }
 
 // Staged handles the API endpoint /namespaces/:namespace/staging/:stage_id/complete
-// It waits for the Job resource staging the app to complete
+// It waits for the Job resource staging the app to complete. With the query parameter
+// `queue=true` it instead returns immediately while the staging is still queued,
+// reporting the position in the queue. A staging still queued after the wait is reported
+// the same way.
 func (hc Controller) Staged(c *gin.Context) apierror.APIErrors {
 	ctx := c.Request.Context()
 
@@ -285,48 +422,129 @@ func (hc Controller) Staged(c *gin.Context) apierror.APIErrors {
 		return apierror.InternalError(err)
 	}
 
-	// Wait for the staging to be done, then check if it ended in failure.
-	// Select the job for this stage `id`.
+	jobs, apierr := stagingJobs(ctx, cluster, namespace, id)
+	if apierr != nil {
+		return apierr
+	}
+
+	queued := false
+	if c.Query("queue") == "true" {
+		for _, job := range jobs {
+			if application.IsQueued(job) {
+				queued = true
+			}
+		}
+	}
+
+	if !queued {
+		// A staging still queued after waiting is reported as such, like above.
+		queued, apierr = waitForStaging(ctx, cluster, jobs, id)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	if queued {
+		position, err := application.StagingQueuePosition(ctx, cluster, id)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+
+		response.OKReturn(c, models.StagingCompleteResponse{
+			Response:      models.Response{Status: models.StagingQueued},
+			QueuePosition: position,
+		})
+		return nil
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// stagingJobs returns the Job resources staging the app for stage `id`.
+func stagingJobs(ctx context.Context, cluster *kubernetes.Cluster, namespace, id string) ([]batchv1.Job, apierror.APIErrors) {
 	selector := fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,epinio.io/stage-id=%s",
 		namespace, id)
 
 	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), selector)
 	if err != nil {
-		return apierror.InternalError(err)
+		return nil, apierror.InternalError(err)
 	}
 	if len(jobList.Items) == 0 {
-		return apierror.InternalError(fmt.Errorf("no jobs in %s with selector %s", namespace, selector))
+		return nil, apierror.InternalError(fmt.Errorf("no jobs in %s with selector %s", namespace, selector))
 	}
 
-	for _, job := range jobList.Items {
-		// Wait for job to be done
+	return jobList.Items, nil
+}
+
+// waitForStaging waits for the staging jobs to be done, then checks if they ended in
+// failure. It returns true instead when a job is still queued after waiting for its
+// admission.
+func waitForStaging(ctx context.Context, cluster *kubernetes.Cluster, jobs []batchv1.Job, id string) (bool, apierror.APIErrors) {
+	for _, job := range jobs {
+		// Wait for the job to leave the queue, then for it to be done.
+		waitCtx, cancel := context.WithTimeout(ctx, duration.ToAppBuilt())
+		err := application.WaitForAdmission(waitCtx, cluster, job.Name)
+		timedOut := waitCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+		cancel()
+		if err != nil && timedOut {
+			return true, nil
+		}
+		if err != nil {
+			return false, apierror.InternalError(err)
+		}
+
 		err = cluster.WaitForJobDone(ctx, helmchart.Namespace(), job.Name, duration.ToAppBuilt())
 		if err != nil {
-			return apierror.InternalError(err)
+			return false, apierror.InternalError(err)
 		}
 		// Check job for failure
 		failed, err := cluster.IsJobFailed(ctx, job.Name, helmchart.Namespace())
 		if err != nil {
-			return apierror.InternalError(err)
+			return false, apierror.InternalError(err)
 		}
 		if failed {
-			return apierror.NewInternalError("Failed to stage",
+			return false, apierror.NewInternalError("Failed to stage",
 				fmt.Sprintf("stage-id = %s", id))
 		}
 	}
 
-	response.OK(c)
-	return nil
+	return false, nil
 }
 
-func validateBlob(ctx context.Context, blobUID string, app models.AppRef, s3ConnectionDetails s3manager.ConnectionDetails) apierror.APIErrors {
+// StagingCancel handles the API endpoint DELETE /namespaces/:namespace/staging/:stage_id
+// It stops the Job resource staging the app. The deployed app is not touched.
+func (hc Controller) StagingCancel(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
 
-	manager, err := s3manager.New(s3ConnectionDetails)
+	namespace := c.Param("namespace")
+	id := c.Param("stage_id")
+
+	cluster, err := kubernetes.GetCluster(ctx)
 	if err != nil {
-		return apierror.InternalError(err, "creating an S3 manager")
+		return apierror.InternalError(err)
 	}
 
-	blobMeta, err := manager.Meta(ctx, blobUID)
+	appRef, err := application.CancelStaging(ctx, cluster, namespace, id)
+	if err != nil {
+		if errors.Is(err, application.ErrStagingNotFound) {
+			return apierror.NewNotFoundError("staging", id)
+		}
+		if errors.Is(err, application.ErrStagingDone) {
+			return apierror.NewBadRequestErrorf("staging %s already finished", id)
+		}
+		return apierror.InternalError(err)
+	}
+
+	log.Info("cancelled staging", "namespace", namespace, "app", appRef.Name, "stage id", id)
+
+	response.OK(c)
+	return nil
+}
+
+func validateBlob(ctx context.Context, store blobstore.Store, blobUID string, app models.AppRef) apierror.APIErrors {
+	blobMeta, err := store.Meta(ctx, blobUID)
 	if err != nil {
 		return apierror.InternalError(err, "querying blob id meta-data")
 	}
@@ -369,34 +587,86 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 	// Note: `source` is required because the mounted files are not executable.
 
 	// runtime: AWSCLIImage
-	awsScript := fmt.Sprintf("source /stage-support/%s", helmchart.EpinioStageDownload)
+	downloadImage := app.DownloadImage
+	downloadScript := fmt.Sprintf("source /stage-support/%s", helmchart.EpinioStageDownload)
+	if app.BlobStorage == blobstore.BackendFilesystem {
+		// runtime: BashImage. Copy the blob from the mounted blob storage to where
+		// the download script places it.
+		downloadImage = app.UnpackImage
+		downloadScript = fmt.Sprintf(`cp "%s/${BLOBID}" "%s/${BLOBID}"`, blobMountDir, stageSourceDir)
+	}
 
 	// runtime: BashImage
 	unpackScript := fmt.Sprintf(`source /stage-support/%s`, helmchart.EpinioStageUnpack)
 
 	// runtime: app.BuilderImage
-	buildpackScript := fmt.Sprintf(`source /stage-support/%s`, helmchart.EpinioStageBuild)
+	buildScript := fmt.Sprintf(`source /stage-support/%s`, helmchart.EpinioStageBuild)
+	if app.NoCache {
+		// Wipe the build cache before building, so that the build starts from
+		// scratch and the cache is rebuilt from its results. The image builder of
+		// the dockerfile strategy has no shell, the unpack step wipes it instead.
+		wipeCache := "find /workspace/cache -mindepth 1 -delete && "
+		if app.Strategy == models.StagingStrategyDockerfile {
+			unpackScript = wipeCache + unpackScript
+		} else {
+			buildScript = wipeCache + buildScript
+		}
+	}
+
+	buildContainer := corev1.Container{
+		Name:    "buildpack",
+		Image:   app.BuilderImage,
+		Command: []string{"/bin/bash"},
+		Args: []string{
+			"-c",
+			buildScript,
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  pointer.Int64(1000),
+			RunAsGroup: pointer.Int64(1000),
+		},
+	}
+	if app.Strategy == models.StagingStrategyDockerfile {
+		// The image builder runs with its own entrypoint and security context,
+		// i.e. as the user of its image. kaniko for example has to run as root.
+		buildContainer = corev1.Container{
+			Name:  "dockerfile",
+			Image: app.BuilderImage,
+			Args:  app.BuilderArgs,
+		}
+	}
 
 	// build configuration
 	stageEnv := []corev1.EnvVar{}
 
-	protocol := "http"
-	if app.S3ConnectionDetails.UseSSL {
-		protocol = "https"
-	}
-	stageEnv = appendEnvVar(stageEnv, "PROTOCOL", protocol)
+	if app.BlobStorage != blobstore.BackendFilesystem {
+		protocol := "http"
+		if app.S3ConnectionDetails.UseSSL {
+			protocol = "https"
+		}
+		stageEnv = appendEnvVar(stageEnv, "PROTOCOL", protocol)
 
-	stageEnv = appendEnvVar(stageEnv, "ENDPOINT", app.S3ConnectionDetails.Endpoint)
-	stageEnv = appendEnvVar(stageEnv, "BUCKET", app.S3ConnectionDetails.Bucket)
+		stageEnv = appendEnvVar(stageEnv, "ENDPOINT", app.S3ConnectionDetails.Endpoint)
+		stageEnv = appendEnvVar(stageEnv, "BUCKET", app.S3ConnectionDetails.Bucket)
+	}
 	stageEnv = appendEnvVar(stageEnv, "BLOBID", app.BlobUID)
 	stageEnv = appendEnvVar(stageEnv, "PREIMAGE", previous.ImageURL(previous.RegistryURL))
 	stageEnv = appendEnvVar(stageEnv, "APPIMAGE", app.ImageURL(app.RegistryURL))
 
+	if app.Strategy == models.StagingStrategyDockerfile {
+		// The image builder finds the registry credentials through DOCKER_CONFIG.
+		// A builder configured with its own arguments may keep a local cache in
+		// CACHE_DIR, kaniko's default arguments do not use it.
+		stageEnv = appendEnvVar(stageEnv, "DOCKERFILE", app.Dockerfile)
+		stageEnv = appendEnvVar(stageEnv, "DOCKER_CONFIG", "/home/cnb/.docker/")
+		stageEnv = appendEnvVar(stageEnv, "CACHE_DIR", "/workspace/cache")
+	}
+
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "source",
 			SubPath:   "source",
-			MountPath: "/workspace/source",
+			MountPath: stageSourceDir,
 		},
 		{
 			Name:      "cache",
@@ -414,7 +684,7 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 		},
 		{
 			Name:      "app-environment",
-			MountPath: "/workspace/source/appenv",
+			MountPath: stageSourceDir + "/appenv",
 			ReadOnly:  true,
 		},
 	}
@@ -465,15 +735,6 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
-		{
-			Name: "s3-creds",
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName:  helmchart.S3ConnectionDetailsSecretName,
-					DefaultMode: pointer.Int32(420),
-				},
-			},
-		},
 		{
 			Name: "registry-creds",
 			VolumeSource: corev1.VolumeSource{
@@ -491,7 +752,27 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 		},
 	}
 
-	volumes, volumeMounts = mountS3Certs(volumes, volumeMounts)
+	var securityContext *corev1.PodSecurityContext
+	if app.BlobStorage == blobstore.BackendFilesystem {
+		volumes, volumeMounts = mountBlobStorage(app, volumes, volumeMounts)
+
+		// The blobs are readable by their group only. Not a fsGroup, which would
+		// change the ownership of all the blobs on every mount.
+		securityContext = &corev1.PodSecurityContext{
+			SupplementalGroups: []int64{app.BlobStorageGroup},
+		}
+	} else {
+		volumes = append(volumes, corev1.Volume{
+			Name: "s3-creds",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  helmchart.S3ConnectionDetailsSecretName,
+					DefaultMode: pointer.Int32(420),
+				},
+			},
+		})
+		volumes, volumeMounts = mountS3Certs(volumes, volumeMounts)
+	}
 	volumes, volumeMounts = mountRegistryCerts(app, volumes, volumeMounts)
 
 	// Create job environment as a copy of the app environment, plus standard variable.
@@ -521,6 +802,10 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 		},
 	}
 
+	buildContainer.Env = stageEnv
+	buildContainer.VolumeMounts = volumeMounts
+	buildContainer.Resources = app.Resources
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: jobName,
@@ -539,6 +824,8 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit: pointer.Int32(0),
+			// A queued job is created suspended. It is resumed when admitted.
+			Suspend: pointer.Bool(app.Queued),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{
@@ -558,15 +845,16 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: app.ServiceAccountName,
+					SecurityContext:    securityContext,
 					InitContainers: []corev1.Container{
 						{
 							Name:         "download-s3-blob",
-							Image:        app.DownloadImage,
+							Image:        downloadImage,
 							VolumeMounts: volumeMounts,
 							Command:      []string{"/bin/bash"},
 							Args: []string{
 								"-c",
-								awsScript,
+								downloadScript,
 							},
 							Env: stageEnv,
 						},
@@ -583,24 +871,12 @@ func newJobRun(app stageParam) (*batchv1.Job, *corev1.Secret) {
 						},
 					},
 					Containers: []corev1.Container{
-						{
-							Name:    "buildpack",
-							Image:   app.BuilderImage,
-							Command: []string{"/bin/bash"},
-							Args: []string{
-								"-c",
-								buildpackScript,
-							},
-							Env:          stageEnv,
-							VolumeMounts: volumeMounts,
-							SecurityContext: &corev1.SecurityContext{
-								RunAsUser:  pointer.Int64(1000),
-								RunAsGroup: pointer.Int64(1000),
-							},
-						},
+						buildContainer,
 					},
 					RestartPolicy: corev1.RestartPolicyNever,
 					Volumes:       volumes,
+					NodeSelector:  app.NodeSelector,
+					Tolerations:   app.Tolerations,
 				},
 			},
 		},
@@ -667,7 +943,39 @@ func getBuilderImage(req models.StageRequest, app *unstructured.Unstructured) (s
 	return builderImage, nil
 }
 
-func getBlobUID(ctx context.Context, s3ConnectionDetails s3manager.ConnectionDetails, req models.StageRequest, app *unstructured.Unstructured) (string, apierror.APIErrors) {
+// getStagingStrategy returns the staging strategy and Dockerfile location defined on the
+// request. If the strategy is not defined, it tries to find the strategy previously used
+// on the Application CR. If none is found, the buildpacks strategy is used.
+func getStagingStrategy(req models.StageRequest, app *unstructured.Unstructured) (string, string, apierror.APIErrors) {
+	strategy := req.Strategy
+	dockerfile := req.Dockerfile
+
+	if strategy == "" {
+		annotations := app.GetAnnotations()
+		strategy = annotations[models.EpinioStagingStrategyAnnotation]
+		if dockerfile == "" {
+			dockerfile = annotations[models.EpinioStagingDockerfileAnnotation]
+		}
+	}
+
+	switch strategy {
+	case "", models.StagingStrategyBuildpacks:
+		return models.StagingStrategyBuildpacks, "", nil
+	case models.StagingStrategyDockerfile:
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		dockerfile = path.Clean(dockerfile)
+		if path.IsAbs(dockerfile) || dockerfile == ".." || strings.HasPrefix(dockerfile, "../") {
+			return "", "", apierror.NewBadRequestErrorf("dockerfile '%s' is not a path inside the application sources", req.Dockerfile)
+		}
+		return strategy, dockerfile, nil
+	}
+
+	return "", "", apierror.NewBadRequestErrorf("unknown staging strategy '%s'", strategy)
+}
+
+func getBlobUID(ctx context.Context, store blobstore.Store, req models.StageRequest, app *unstructured.Unstructured) (string, apierror.APIErrors) {
 	var blobUID string
 	var err error
 	var returnErr apierror.APIErrors
@@ -688,7 +996,7 @@ func getBlobUID(ctx context.Context, s3ConnectionDetails s3manager.ConnectionDet
 	}
 
 	// Validate incoming blob id before attempting to stage
-	apierr := validateBlob(ctx, blobUID, req.App, s3ConnectionDetails)
+	apierr := validateBlob(ctx, store, blobUID, req.App)
 	if apierr != nil {
 		return "", apierr
 	}
@@ -716,6 +1024,25 @@ func updateApp(ctx context.Context, cluster *kubernetes.Cluster, app *unstructur
 		return err
 	}
 
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[models.EpinioStagingStrategyAnnotation] = params.Strategy
+	if params.AppResources != nil {
+		resources, err := json.Marshal(params.AppResources)
+		if err != nil {
+			return err
+		}
+		annotations[models.EpinioStagingResourcesAnnotation] = string(resources)
+	}
+	if params.Dockerfile != "" {
+		annotations[models.EpinioStagingDockerfileAnnotation] = params.Dockerfile
+	} else {
+		delete(annotations, models.EpinioStagingDockerfileAnnotation)
+	}
+	app.SetAnnotations(annotations)
+
 	client, err := cluster.ClientApp()
 	if err != nil {
 		return err
@@ -731,6 +1058,27 @@ func updateApp(ctx context.Context, cluster *kubernetes.Cluster, app *unstructur
 	return err
 }
 
+// mountBlobStorage mounts the PVC of the filesystem blob storage, read-only.
+func mountBlobStorage(app stageParam, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes = append(volumes, corev1.Volume{
+		Name: "blobs",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: app.BlobStoragePVC,
+				ReadOnly:  true,
+			},
+		},
+	})
+
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      "blobs",
+		MountPath: blobMountDir,
+		ReadOnly:  true,
+	})
+
+	return volumes, volumeMounts
+}
+
 func mountS3Certs(volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) ([]corev1.Volume, []corev1.VolumeMount) {
 	if s3CertificateSecret := viper.GetString("s3-certificate-secret"); s3CertificateSecret != "" {
 		volumes = append(volumes, corev1.Volume{
@@ -772,6 +1120,17 @@ func mountRegistryCerts(app stageParam, volumes []corev1.Volume, volumeMounts []
 			SubPath:   "tls.crt",
 			ReadOnly:  true,
 		})
+
+		// kaniko does not read /etc/ssl/certs, its executor image points SSL_CERT_DIR
+		// to its own directory of certificates.
+		if app.Strategy == models.StagingStrategyDockerfile {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      "registry-certs",
+				MountPath: fmt.Sprintf("%s/%s", kanikoCertsDir, app.RegistryCAHash),
+				SubPath:   "tls.crt",
+				ReadOnly:  true,
+			})
+		}
 	}
 
 	return volumes, volumeMounts
@@ -780,3 +1139,34 @@ func mountRegistryCerts(app stageParam, volumes []corev1.Volume, volumeMounts []
 func appendEnvVar(envs []corev1.EnvVar, name, value string) []corev1.EnvVar {
 	return append(envs, corev1.EnvVar{Name: name, Value: value})
 }
+
+// kanikoCertsDir is the directory of the certificates trusted by kaniko's executor.
+const kanikoCertsDir = "/kaniko/ssl/certs"
+
+// defaultDockerfileBuilderArgs are the arguments of kaniko's executor. Kubernetes expands
+// the `$(VAR)` references from the environment of the build container. The unpack step
+// places the application sources into /workspace/source/app.
+//
+// kaniko does not cache layers with these arguments, every build runs all the steps of the
+// Dockerfile. Its layer cache lives in a registry repository, enabling it requires
+// `--cache=true` and a `--cache-repo` in the configured arguments.
+var defaultDockerfileBuilderArgs = []string{
+	"--context=dir:///workspace/source/app",
+	"--dockerfile=/workspace/source/app/$(DOCKERFILE)",
+	"--destination=$(APPIMAGE)",
+}
+
+// builderArgs returns the arguments of the dockerfile image builder, given as one argument
+// per line in the staging config map. Without any, kaniko's arguments are used.
+func builderArgs(config string) []string {
+	args := []string{}
+	for _, line := range strings.Split(config, "\n") {
+		if arg := strings.TrimSpace(line); arg != "" {
+			args = append(args, arg)
+		}
+	}
+	if len(args) == 0 {
+		return defaultDockerfileBuilderArgs
+	}
+	return args
+}
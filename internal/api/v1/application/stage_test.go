@@ -0,0 +1,219 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staging strategy", func() {
+	var app *unstructured.Unstructured
+
+	BeforeEach(func() {
+		app = &unstructured.Unstructured{Object: map[string]interface{}{}}
+	})
+
+	Describe("getStagingStrategy", func() {
+		It("defaults to buildpacks", func() {
+			strategy, dockerfile, err := getStagingStrategy(models.StageRequest{}, app)
+			Expect(err).To(BeNil())
+			Expect(strategy).To(Equal(models.StagingStrategyBuildpacks))
+			Expect(dockerfile).To(BeEmpty())
+		})
+
+		It("defaults the Dockerfile location", func() {
+			req := models.StageRequest{Strategy: models.StagingStrategyDockerfile}
+			strategy, dockerfile, err := getStagingStrategy(req, app)
+			Expect(err).To(BeNil())
+			Expect(strategy).To(Equal(models.StagingStrategyDockerfile))
+			Expect(dockerfile).To(Equal("Dockerfile"))
+		})
+
+		It("falls back to the strategy recorded on the application", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioStagingStrategyAnnotation:   models.StagingStrategyDockerfile,
+				models.EpinioStagingDockerfileAnnotation: "build/Dockerfile",
+			})
+
+			strategy, dockerfile, err := getStagingStrategy(models.StageRequest{}, app)
+			Expect(err).To(BeNil())
+			Expect(strategy).To(Equal(models.StagingStrategyDockerfile))
+			Expect(dockerfile).To(Equal("build/Dockerfile"))
+		})
+
+		It("rejects unknown strategies", func() {
+			_, _, err := getStagingStrategy(models.StageRequest{Strategy: "magic"}, app)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("rejects Dockerfiles outside of the sources", func() {
+			req := models.StageRequest{Strategy: models.StagingStrategyDockerfile, Dockerfile: "../Dockerfile"}
+			_, _, err := getStagingStrategy(req, app)
+			Expect(err).ToNot(BeNil())
+
+			req.Dockerfile = "/etc/Dockerfile"
+			_, _, err = getStagingStrategy(req, app)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("builderArgs", func() {
+		It("defaults to kaniko's arguments", func() {
+			Expect(builderArgs("")).To(Equal(defaultDockerfileBuilderArgs))
+		})
+
+		It("takes one argument per line", func() {
+			Expect(builderArgs("build\n  --frontend=dockerfile.v0\n\n")).To(Equal([]string{"build", "--frontend=dockerfile.v0"}))
+		})
+	})
+
+	Describe("newJobRun", func() {
+		It("runs the image builder's entrypoint for the dockerfile strategy", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:       models.NewAppRef("app", "workspace"),
+				BuilderImage: "builder",
+				BuilderArgs:  builderArgs(""),
+				Stage:        models.NewStage("id"),
+				Strategy:     models.StagingStrategyDockerfile,
+				Dockerfile:   "Dockerfile",
+			})
+
+			containers := job.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].Name).To(Equal("dockerfile"))
+			Expect(containers[0].Image).To(Equal("builder"))
+			Expect(containers[0].Command).To(BeEmpty())
+			Expect(containers[0].SecurityContext).To(BeNil())
+			Expect(containers[0].Args).To(ContainElement("--destination=$(APPIMAGE)"))
+			Expect(containers[0].VolumeMounts).ToNot(BeEmpty())
+
+			env := map[string]string{}
+			for _, ev := range containers[0].Env {
+				env[ev.Name] = ev.Value
+			}
+			Expect(env).To(HaveKeyWithValue("DOCKERFILE", "Dockerfile"))
+		})
+
+		It("does not pass kaniko's base image cache as a layer cache", func() {
+			Expect(builderArgs("")).ToNot(ContainElement(HavePrefix("--cache-dir")))
+		})
+
+		It("trusts the registry certificate in kaniko's certificate directory", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:           models.NewAppRef("app", "workspace"),
+				BuilderImage:     "builder",
+				BuilderArgs:      builderArgs(""),
+				Stage:            models.NewStage("id"),
+				Strategy:         models.StagingStrategyDockerfile,
+				RegistryCASecret: "registry-ca",
+				RegistryCAHash:   "abcd1234.0",
+			})
+
+			mounts := []string{}
+			for _, mount := range job.Spec.Template.Spec.Containers[0].VolumeMounts {
+				if mount.Name == "registry-certs" {
+					mounts = append(mounts, mount.MountPath)
+				}
+			}
+			Expect(mounts).To(ConsistOf("/etc/ssl/certs/abcd1234.0", "/kaniko/ssl/certs/abcd1234.0"))
+		})
+
+		It("wipes the cache in the unpack step for the dockerfile strategy", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:       models.NewAppRef("app", "workspace"),
+				BuilderImage: "builder",
+				BuilderArgs:  builderArgs(""),
+				Stage:        models.NewStage("id"),
+				Strategy:     models.StagingStrategyDockerfile,
+				NoCache:      true,
+			})
+
+			unpack := job.Spec.Template.Spec.InitContainers[1]
+			Expect(unpack.Args[1]).To(HavePrefix("find /workspace/cache -mindepth 1 -delete && "))
+		})
+
+		It("runs the buildpack build script by default", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:       models.NewAppRef("app", "workspace"),
+				BuilderImage: "builder",
+				Stage:        models.NewStage("id"),
+				Strategy:     models.StagingStrategyBuildpacks,
+			})
+
+			containers := job.Spec.Template.Spec.Containers
+			Expect(containers[0].Name).To(Equal("buildpack"))
+			Expect(containers[0].Args[1]).To(Equal("source /stage-support/build"))
+		})
+
+		It("downloads the sources from S3 by default", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:        models.NewAppRef("app", "workspace"),
+				DownloadImage: "awscli",
+				Stage:         models.NewStage("id"),
+			})
+
+			download := job.Spec.Template.Spec.InitContainers[0]
+			Expect(download.Image).To(Equal("awscli"))
+			Expect(download.Args[1]).To(Equal("source /stage-support/download"))
+			Expect(job.Spec.Template.Spec.SecurityContext).To(BeNil())
+
+			volumes := []string{}
+			for _, volume := range job.Spec.Template.Spec.Volumes {
+				volumes = append(volumes, volume.Name)
+			}
+			Expect(volumes).To(ContainElement("s3-creds"))
+			Expect(volumes).ToNot(ContainElement("blobs"))
+		})
+
+		It("copies the sources from the mounted blob storage for the filesystem backend", func() {
+			job, _ := newJobRun(stageParam{
+				AppRef:           models.NewAppRef("app", "workspace"),
+				BlobUID:          "blob",
+				DownloadImage:    "awscli",
+				UnpackImage:      "bash",
+				Stage:            models.NewStage("id"),
+				BlobStorage:      blobstore.BackendFilesystem,
+				BlobStoragePVC:   "epinio-blobs",
+				BlobStorageGroup: 2000,
+			})
+
+			Expect(job.Spec.Template.Spec.SecurityContext).ToNot(BeNil())
+			Expect(job.Spec.Template.Spec.SecurityContext.SupplementalGroups).To(Equal([]int64{2000}))
+
+			download := job.Spec.Template.Spec.InitContainers[0]
+			Expect(download.Image).To(Equal("bash"))
+			Expect(download.Args[1]).To(Equal(`cp "/blobs/${BLOBID}" "/workspace/source/${BLOBID}"`))
+
+			env := map[string]string{}
+			for _, ev := range download.Env {
+				env[ev.Name] = ev.Value
+			}
+			Expect(env).To(HaveKeyWithValue("BLOBID", "blob"))
+			Expect(env).ToNot(HaveKey("ENDPOINT"))
+
+			var claim string
+			for _, volume := range job.Spec.Template.Spec.Volumes {
+				Expect(volume.Name).ToNot(Equal("s3-creds"))
+				if volume.Name == "blobs" {
+					claim = volume.PersistentVolumeClaim.ClaimName
+					Expect(volume.PersistentVolumeClaim.ReadOnly).To(BeTrue())
+				}
+			}
+			Expect(claim).To(Equal("epinio-blobs"))
+		})
+	})
+})
@@ -0,0 +1,218 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// stagingResourceCaps holds the maximum cpu and memory an application may request for
+// its staging jobs. Empty fields impose no cap.
+type stagingResourceCaps struct {
+	CPU    string
+	Memory string
+}
+
+// getStagingResources returns the staging job resources defined on the request. If these
+// are not defined, it tries to find the resources previously used on the Application CR.
+// The result may be nil, i.e. no resources specific to the application.
+func getStagingResources(req models.StageRequest, app *unstructured.Unstructured) (*models.StagingResources, apierror.APIErrors) {
+	if req.Resources != nil {
+		return req.Resources, nil
+	}
+
+	value, ok := app.GetAnnotations()[models.EpinioStagingResourcesAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	resources := &models.StagingResources{}
+	if err := json.Unmarshal([]byte(value), resources); err != nil {
+		return nil, apierror.InternalError(err, "staging resources annotation is not valid")
+	}
+
+	return resources, nil
+}
+
+// stagingDefaults returns the staging job resources configured for the server, and the
+// caps for the resources of applications.
+func stagingDefaults() (models.StagingResources, stagingResourceCaps, error) {
+	nodeSelector, err := parseNodeSelector(viper.GetString("staging-node-selector"))
+	if err != nil {
+		return models.StagingResources{}, stagingResourceCaps{}, err
+	}
+
+	defaults := models.StagingResources{
+		CPURequest:    viper.GetString("staging-cpu-request"),
+		CPULimit:      viper.GetString("staging-cpu-limit"),
+		MemoryRequest: viper.GetString("staging-memory-request"),
+		MemoryLimit:   viper.GetString("staging-memory-limit"),
+		NodeSelector:  nodeSelector,
+	}
+	caps := stagingResourceCaps{
+		CPU:    viper.GetString("staging-max-cpu"),
+		Memory: viper.GetString("staging-max-memory"),
+	}
+
+	return defaults, caps, nil
+}
+
+// stagingTolerations returns the tolerations configured for the staging jobs. They are
+// not overridable by applications.
+func stagingTolerations() ([]corev1.Toleration, error) {
+	value := viper.GetString("staging-tolerations")
+	if value == "" {
+		return nil, nil
+	}
+
+	tolerations := []corev1.Toleration{}
+	if err := json.Unmarshal([]byte(value), &tolerations); err != nil {
+		return nil, errors.Wrap(err, "bad staging tolerations")
+	}
+
+	return tolerations, nil
+}
+
+// resolveStagingResources merges the application's resources over the defaults. The
+// application's cpu and memory values must not exceed the caps. The application's node
+// selector adds to the default one, it may not change the keys set there.
+func resolveStagingResources(defaults models.StagingResources, app *models.StagingResources, caps stagingResourceCaps) (models.StagingResources, error) {
+	result := defaults
+	result.NodeSelector = map[string]string{}
+	for key, value := range defaults.NodeSelector {
+		result.NodeSelector[key] = value
+	}
+
+	if app == nil {
+		return result, nil
+	}
+
+	overrides := []struct {
+		name  string
+		value string
+		cap   string
+		field *string
+	}{
+		{"cpu request", app.CPURequest, caps.CPU, &result.CPURequest},
+		{"cpu limit", app.CPULimit, caps.CPU, &result.CPULimit},
+		{"memory request", app.MemoryRequest, caps.Memory, &result.MemoryRequest},
+		{"memory limit", app.MemoryLimit, caps.Memory, &result.MemoryLimit},
+	}
+
+	for _, override := range overrides {
+		if override.value == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(override.value)
+		if err != nil {
+			return result, errors.Wrapf(err, "bad staging %s '%s'", override.name, override.value)
+		}
+
+		if override.cap != "" {
+			limit, err := resource.ParseQuantity(override.cap)
+			if err != nil {
+				return result, errors.Wrapf(err, "bad maximum for the staging %s '%s'", override.name, override.cap)
+			}
+			if quantity.Cmp(limit) > 0 {
+				return result, fmt.Errorf("staging %s '%s' exceeds the maximum of '%s'",
+					override.name, override.value, override.cap)
+			}
+		}
+
+		*override.field = override.value
+	}
+
+	// The application only adds to the node selector of the administrator. It must
+	// not move the staging away from the nodes the administrator selected.
+	for key, value := range app.NodeSelector {
+		if current, ok := result.NodeSelector[key]; ok && current != value {
+			return result, fmt.Errorf("staging node selector '%s=%s' conflicts with '%s=%s' set by the administrator",
+				key, value, key, current)
+		}
+		result.NodeSelector[key] = value
+	}
+
+	return result, nil
+}
+
+// resourceRequirements converts the staging resources into the requirements of a
+// container. It fails for bad quantities, and for requests exceeding their limits.
+func resourceRequirements(resources models.StagingResources) (corev1.ResourceRequirements, error) {
+	requirements := corev1.ResourceRequirements{}
+
+	quantities := []struct {
+		name  string
+		value string
+		kind  corev1.ResourceName
+		list  *corev1.ResourceList
+	}{
+		{"cpu request", resources.CPURequest, corev1.ResourceCPU, &requirements.Requests},
+		{"cpu limit", resources.CPULimit, corev1.ResourceCPU, &requirements.Limits},
+		{"memory request", resources.MemoryRequest, corev1.ResourceMemory, &requirements.Requests},
+		{"memory limit", resources.MemoryLimit, corev1.ResourceMemory, &requirements.Limits},
+	}
+
+	for _, q := range quantities {
+		if q.value == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return requirements, errors.Wrapf(err, "bad staging %s '%s'", q.name, q.value)
+		}
+
+		if *q.list == nil {
+			*q.list = corev1.ResourceList{}
+		}
+		(*q.list)[q.kind] = quantity
+	}
+
+	for kind, request := range requirements.Requests {
+		if limit, ok := requirements.Limits[kind]; ok && request.Cmp(limit) > 0 {
+			return requirements, fmt.Errorf("staging %s request '%s' exceeds its limit '%s'",
+				kind, request.String(), limit.String())
+		}
+	}
+
+	return requirements, nil
+}
+
+// parseNodeSelector parses a node selector given as comma-separated `key=value` pairs.
+func parseNodeSelector(value string) (map[string]string, error) {
+	selector := map[string]string{}
+	if value == "" {
+		return selector, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("bad staging node selector '%s', expected key=value", pair)
+		}
+		selector[key] = val
+	}
+
+	return selector, nil
+}
@@ -0,0 +1,163 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staging resources", func() {
+	defaults := models.StagingResources{
+		CPURequest:    "500m",
+		MemoryRequest: "1Gi",
+		MemoryLimit:   "2Gi",
+		NodeSelector:  map[string]string{"pool": "build"},
+	}
+
+	Describe("getStagingResources", func() {
+		It("falls back to the resources recorded on the application", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			app.SetAnnotations(map[string]string{
+				models.EpinioStagingResourcesAnnotation: `{"cpu_limit":"2"}`,
+			})
+
+			resources, err := getStagingResources(models.StageRequest{}, app)
+			Expect(err).To(BeNil())
+			Expect(resources).To(Equal(&models.StagingResources{CPULimit: "2"}))
+		})
+
+		It("returns nothing without resources", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+			resources, err := getStagingResources(models.StageRequest{}, app)
+			Expect(err).To(BeNil())
+			Expect(resources).To(BeNil())
+		})
+	})
+
+	Describe("resolveStagingResources", func() {
+		It("returns the defaults without application resources", func() {
+			resources, err := resolveStagingResources(defaults, nil, stagingResourceCaps{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(Equal(defaults))
+		})
+
+		It("merges the application resources over the defaults", func() {
+			resources, err := resolveStagingResources(defaults, &models.StagingResources{
+				CPULimit:     "2",
+				MemoryLimit:  "4Gi",
+				NodeSelector: map[string]string{"zone": "a"},
+			}, stagingResourceCaps{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(Equal(models.StagingResources{
+				CPURequest:    "500m",
+				CPULimit:      "2",
+				MemoryRequest: "1Gi",
+				MemoryLimit:   "4Gi",
+				NodeSelector:  map[string]string{"pool": "build", "zone": "a"},
+			}))
+
+			// The defaults are not modified
+			Expect(defaults.NodeSelector).To(HaveLen(1))
+		})
+
+		It("rejects application resources above the caps", func() {
+			caps := stagingResourceCaps{CPU: "2", Memory: "4Gi"}
+
+			_, err := resolveStagingResources(defaults, &models.StagingResources{CPULimit: "2"}, caps)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = resolveStagingResources(defaults, &models.StagingResources{CPULimit: "2500m"}, caps)
+			Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+
+			_, err = resolveStagingResources(defaults, &models.StagingResources{MemoryRequest: "8Gi"}, caps)
+			Expect(err).To(MatchError(ContainSubstring("exceeds the maximum")))
+		})
+
+		It("rejects node selectors conflicting with the defaults", func() {
+			_, err := resolveStagingResources(defaults, &models.StagingResources{
+				NodeSelector: map[string]string{"pool": "build"},
+			}, stagingResourceCaps{})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = resolveStagingResources(defaults, &models.StagingResources{
+				NodeSelector: map[string]string{"pool": "gpu"},
+			}, stagingResourceCaps{})
+			Expect(err).To(MatchError(ContainSubstring("conflicts with 'pool=build'")))
+		})
+
+		It("rejects bad quantities", func() {
+			_, err := resolveStagingResources(defaults, &models.StagingResources{CPURequest: "lots"}, stagingResourceCaps{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("resourceRequirements", func() {
+		It("converts the resources", func() {
+			requirements, err := resourceRequirements(defaults)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requirements.Requests).To(Equal(corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}))
+			Expect(requirements.Limits).To(Equal(corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			}))
+		})
+
+		It("rejects requests above their limits", func() {
+			_, err := resourceRequirements(models.StagingResources{CPURequest: "2", CPULimit: "1"})
+			Expect(err).To(MatchError(ContainSubstring("exceeds its limit")))
+		})
+	})
+
+	Describe("parseNodeSelector", func() {
+		It("parses key=value pairs", func() {
+			selector, err := parseNodeSelector("pool=build, zone=a")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector).To(Equal(map[string]string{"pool": "build", "zone": "a"}))
+		})
+
+		It("rejects bad pairs", func() {
+			_, err := parseNodeSelector("pool")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("newJobRun", func() {
+		It("places the resources on the build container", func() {
+			requirements, err := resourceRequirements(defaults)
+			Expect(err).ToNot(HaveOccurred())
+
+			job, _ := newJobRun(stageParam{
+				AppRef:       models.NewAppRef("app", "workspace"),
+				Stage:        models.NewStage("id"),
+				Resources:    requirements,
+				NodeSelector: defaults.NodeSelector,
+				Tolerations: []corev1.Toleration{
+					{Key: "gpu", Operator: corev1.TolerationOpExists},
+				},
+			})
+
+			spec := job.Spec.Template.Spec
+			Expect(spec.Containers[0].Resources).To(Equal(requirements))
+			Expect(spec.NodeSelector).To(Equal(defaults.NodeSelector))
+			Expect(spec.Tolerations).To(HaveLen(1))
+		})
+	})
+})
@@ -63,6 +63,19 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		return apierror.NewBadRequestError("instances param should be integer equal or greater than zero")
 	}
 
+	if updateRequest.Deployment != nil {
+		apierr := validateDeploymentStrategy(ctx, cluster, *updateRequest.Deployment)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	if updateRequest.HealthChecks != nil {
+		if apierr := validateHealthChecks(*updateRequest.HealthChecks); apierr != nil {
+			return apierr
+		}
+	}
+
 	app, err := application.Lookup(ctx, cluster, namespace, appName)
 	if err != nil {
 		return apierror.InternalError(err)
@@ -76,11 +89,30 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		len(updateRequest.Settings) == 0 &&
 		updateRequest.Configurations == nil &&
 		updateRequest.Routes == nil &&
-		updateRequest.AppChart == "" {
+		updateRequest.AppChart == "" &&
+		updateRequest.Deployment == nil &&
+		updateRequest.HealthChecks == nil &&
+		updateRequest.Resources == nil {
 		response.OK(c)
 		return nil
 	}
 
+	// Merge the resources over the current ones, and validate the result before any
+	// change is made. This ensures that there will be no partial update of the
+	// application.
+	resources := models.AppResources{}
+	if updateRequest.Resources != nil && !updateRequest.Resources.IsEmpty() {
+		if app.Configuration.Resources != nil {
+			resources = *app.Configuration.Resources
+		}
+		resources = resources.Merge(*updateRequest.Resources)
+
+		apierr := validateResources(ctx, cluster, namespace, resources)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
 	if app.Workload != nil {
 		// For a running application we have to validate changed custom chart values against
 		// the configured app chart. It has to be done first, this ensures that there will
@@ -246,6 +278,30 @@ func (hc Controller) Update(c *gin.Context) apierror.APIErrors { // nolint:gocyc
 		}
 	}
 
+	// The deployment strategy takes effect with the next deployment of a new build.
+	if updateRequest.Deployment != nil {
+		err := application.SetDeploymentStrategy(ctx, cluster, app.Meta, *updateRequest.Deployment)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	// Health checks - Replace. Empty health checks remove them.
+	if updateRequest.HealthChecks != nil {
+		err := application.SetHealthChecks(ctx, cluster, app.Meta, *updateRequest.HealthChecks)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
+	// Resources - Merge. Empty resources remove them. See also the validation above.
+	if updateRequest.Resources != nil {
+		err := application.SetResources(ctx, cluster, app.Meta, resources)
+		if err != nil {
+			return apierror.InternalError(err)
+		}
+	}
+
 	// With everything saved, and a workload to update, re-deploy the changed state.
 	if app.Workload != nil {
 		_, apierr := deploy.DeployApp(ctx, cluster, app.Meta, username, "", nil, nil)
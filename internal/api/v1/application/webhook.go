@@ -0,0 +1,324 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/gitwebhook"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// webhookUsername is the user recorded for the builds triggered by a Git webhook.
+const webhookUsername = "git-webhook"
+
+// webhookMaxPayload limits the size of the webhook payloads read by the server.
+const webhookMaxPayload = 25 * 1024 * 1024
+
+// webhookBuildTimeout bounds the import, staging, and deployment of a webhook build.
+const webhookBuildTimeout = 30 * time.Minute
+
+// webhookBuilds serializes the webhook builds of each application.
+var webhookBuilds = newBuildSerializer()
+
+// buildSerializer runs the builds of an application one after the other. A build
+// triggered while another build of the application runs is not run in parallel, but
+// after it. Multiple such builds are coalesced into one, which imports the latest sources.
+type buildSerializer struct {
+	mutex   sync.Mutex
+	running map[string]bool
+	pending map[string]bool
+}
+
+func newBuildSerializer() *buildSerializer {
+	return &buildSerializer{
+		running: map[string]bool{},
+		pending: map[string]bool{},
+	}
+}
+
+// Run runs the build in the background, unless a build of the same key runs already. In
+// that case the build is run again when the running one is done. It returns false for the
+// latter.
+func (bs *buildSerializer) Run(key string, build func()) bool {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	if bs.running[key] {
+		bs.pending[key] = true
+		return false
+	}
+	bs.running[key] = true
+
+	go func() {
+		for {
+			build()
+
+			bs.mutex.Lock()
+			if !bs.pending[key] {
+				delete(bs.running, key)
+				bs.mutex.Unlock()
+				return
+			}
+			delete(bs.pending, key)
+			bs.mutex.Unlock()
+		}
+	}()
+
+	return true
+}
+
+// Webhook handles the API endpoint GET /namespaces/:namespace/applications/:app/webhook
+// It returns the state of the application's Git webhook, with its secret.
+func (hc Controller) Webhook(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, apierr := webhookApp(ctx, namespace, appName)
+	if apierr != nil {
+		return apierr
+	}
+
+	secret, err := application.WebhookSecret(ctx, cluster, models.NewAppRef(appName, namespace))
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, models.AppWebhook{
+		Enabled: secret != "",
+		Secret:  secret,
+	})
+	return nil
+}
+
+// WebhookEnable handles the API endpoint POST /namespaces/:namespace/applications/:app/webhook
+// It enables the application's Git webhook with a new secret, and returns that.
+func (hc Controller) WebhookEnable(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, apierr := webhookApp(ctx, namespace, appName)
+	if apierr != nil {
+		return apierr
+	}
+
+	secret, err := application.EnableWebhook(ctx, cluster, models.NewAppRef(appName, namespace))
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, models.AppWebhook{
+		Enabled: true,
+		Secret:  secret,
+	})
+	return nil
+}
+
+// WebhookDisable handles the API endpoint DELETE /namespaces/:namespace/applications/:app/webhook
+// It disables the application's Git webhook.
+func (hc Controller) WebhookDisable(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+
+	cluster, apierr := webhookApp(ctx, namespace, appName)
+	if apierr != nil {
+		return apierr
+	}
+
+	err := application.DisableWebhook(ctx, cluster, models.NewAppRef(appName, namespace))
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// GitWebhook handles the endpoint POST /webhooks/git/:namespace/:app
+// It is called by GitHub, GitLab, or Gitea on a push to the application's repository. The
+// request is not authenticated by Epinio, but verified against the webhook secret of the
+// application. When the push is to the application's repository and updates the branch the
+// application tracks, the sources are imported, staged, and deployed in the background.
+func (hc Controller) GitWebhook(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	log := requestctx.Logger(ctx)
+
+	namespace := c.Param("namespace")
+	appName := c.Param("app")
+	appRef := models.NewAppRef(appName, namespace)
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, webhookMaxPayload))
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error()).WithDetails("failed to read the webhook payload")
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	// Note: Unknown applications and applications without webhook are reported the same,
+	// so that callers cannot probe for applications.
+	secret, err := application.WebhookSecret(ctx, cluster, appRef)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if secret == "" {
+		return apierror.NewNotFoundError("webhook", namespace+"/"+appName)
+	}
+
+	push, err := gitwebhook.Parse(c.Request.Header, body, secret)
+	if err != nil {
+		if errors.Is(err, gitwebhook.ErrBadSignature) {
+			return apierror.NewAPIError(err.Error(), http.StatusUnauthorized)
+		}
+		if errors.Is(err, gitwebhook.ErrNotPush) {
+			response.OKReturn(c, models.GitWebhookResponse{Status: models.WebhookIgnored, Reason: err.Error()})
+			return nil
+		}
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	app, err := application.Lookup(ctx, cluster, namespace, appName)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if app == nil {
+		return apierror.AppIsNotKnown(appName)
+	}
+	if app.Origin.Kind != models.OriginGit || app.Origin.Git == nil {
+		return apierror.NewBadRequestError("application sources are not imported from git")
+	}
+
+	if !push.FromRepository(app.Origin.Git.URL) {
+		return apierror.NewBadRequestErrorf("push to repository '%s' does not match the application's repository",
+			push.Repository)
+	}
+
+	if !push.Matches(app.Origin.Git.Revision) {
+		response.OKReturn(c, models.GitWebhookResponse{
+			Status: models.WebhookIgnored,
+			Reason: "push does not update the tracked branch",
+		})
+		return nil
+	}
+
+	log.Info("git webhook", "provider", push.Provider, "branch", push.Branch, "commit", push.Commit, "pusher", push.Pusher)
+
+	// The webhook callers do not wait for the build. Run it detached from the request,
+	// after any other webhook build of the application.
+	origin := app.Origin
+	webhookBuilds.Run(namespace+"/"+appName, func() {
+		buildCtx, cancel := context.WithTimeout(requestctx.WithLogger(context.Background(), log), webhookBuildTimeout)
+		defer cancel()
+
+		if apierr := buildFromGit(buildCtx, appRef, origin); apierr != nil {
+			log.Info("git webhook build failed", "app", appRef, "errors", apierr)
+		}
+	})
+
+	response.OKReturn(c, models.GitWebhookResponse{Status: models.WebhookTriggered})
+	return nil
+}
+
+// buildFromGit imports the sources of the app from its Git origin, then stages and
+// deploys them.
+func buildFromGit(ctx context.Context, appRef models.AppRef, origin models.ApplicationOrigin) apierror.APIErrors {
+	log := requestctx.Logger(ctx)
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	// Wait for a staging started by other means, e.g. a push by a user.
+	err = wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		staging, err := application.CurrentlyStaging(ctx, cluster, appRef.Namespace, appRef.Name)
+		return !staging, err
+	}, ctx.Done())
+	if err != nil {
+		return apierror.InternalError(err, "waiting for the running staging")
+	}
+
+	blobUID, apierr := importGit(ctx, log, appRef.Namespace, appRef.Name, *origin.Git, webhookUsername)
+	if apierr != nil {
+		return apierr
+	}
+
+	stage, apierr := stageApp(ctx, models.StageRequest{App: appRef, BlobUID: blobUID}, webhookUsername)
+	if apierr != nil {
+		return apierr
+	}
+
+	jobs, apierr := stagingJobs(ctx, cluster, appRef.Namespace, stage.Stage.ID)
+	if apierr != nil {
+		return apierr
+	}
+
+	// Keep waiting while the staging is queued.
+	for queued := true; queued; {
+		queued, apierr = waitForStaging(ctx, cluster, jobs, stage.Stage.ID)
+		if apierr != nil {
+			return apierr
+		}
+	}
+
+	routes, apierr := deployImage(ctx, models.DeployRequest{
+		App:      appRef,
+		Stage:    stage.Stage,
+		ImageURL: stage.ImageURL,
+		Origin:   origin,
+	}, webhookUsername)
+	if apierr != nil {
+		return apierr
+	}
+
+	log.Info("git webhook deployed", "app", appRef, "stage", stage.Stage.ID, "routes", routes)
+
+	return nil
+}
+
+// webhookApp checks that the application exists, and returns the cluster.
+func webhookApp(ctx context.Context, namespace, appName string) (*kubernetes.Cluster, apierror.APIErrors) {
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return nil, apierror.InternalError(err, "failed to get access to a kube client")
+	}
+
+	exists, err := application.Exists(ctx, cluster, models.NewAppRef(appName, namespace))
+	if err != nil {
+		return nil, apierror.InternalError(err)
+	}
+	if !exists {
+		return nil, apierror.AppIsNotKnown(appName)
+	}
+
+	return cluster, nil
+}
@@ -0,0 +1,72 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("buildSerializer", func() {
+	It("runs the builds of an application one after the other, coalescing the waiting ones", func() {
+		bs := newBuildSerializer()
+
+		release := make(chan struct{})
+		var mutex sync.Mutex
+		running, maxRunning, runs := 0, 0, 0
+
+		build := func() {
+			mutex.Lock()
+			running++
+			runs++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			<-release
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}
+
+		Expect(bs.Run("ns/app", build)).To(BeTrue())
+		Expect(bs.Run("ns/app", build)).To(BeFalse())
+		Expect(bs.Run("ns/app", build)).To(BeFalse())
+
+		close(release)
+
+		Eventually(func() bool {
+			bs.mutex.Lock()
+			defer bs.mutex.Unlock()
+			return bs.running["ns/app"]
+		}).Should(BeFalse())
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		Expect(maxRunning).To(Equal(1))
+		Expect(runs).To(Equal(2))
+	})
+
+	It("runs the builds of different applications in parallel", func() {
+		bs := newBuildSerializer()
+
+		release := make(chan struct{})
+		defer close(release)
+
+		Expect(bs.Run("ns/one", func() { <-release })).To(BeTrue())
+		Expect(bs.Run("ns/two", func() { <-release })).To(BeTrue())
+	})
+})
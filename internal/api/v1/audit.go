@@ -0,0 +1,49 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strconv"
+
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/cli/server/audit"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/epinio/epinio/pkg/api/core/v1/errors"
+)
+
+// Audit handles the API endpoint /audit. It returns the recorded mutating requests,
+// most recent first, optionally filtered by the "user" and "namespace" query parameters,
+// and limited by the "limit" query parameter.
+func Audit(c *gin.Context) APIErrors {
+	filter := audit.Filter{
+		User:      c.Query("user"),
+		Namespace: c.Query("namespace"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			return NewBadRequestErrorf("invalid limit '%s', expected a non-negative number", limit)
+		}
+		filter.Limit = value
+	}
+
+	records, err := audit.GetSink().Query(filter)
+	if err != nil {
+		return InternalError(err)
+	}
+
+	response.OKReturn(c, records)
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/epinio/epinio/helpers/routes"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/auth"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
@@ -31,6 +32,7 @@ func AuthorizationMiddleware(c *gin.Context) {
 	method := c.Request.Method
 	path := c.Request.URL.Path
 	namespace := c.Param("namespace")
+	route := RouteName(method, c.FullPath())
 
 	logger.Info(fmt.Sprintf("authorization request from user [%s] with role [%s] for [%s - %s]", user.Username, user.Role, method, path))
 
@@ -39,7 +41,12 @@ func AuthorizationMiddleware(c *gin.Context) {
 	case "admin":
 		authorized = authorizeAdmin(logger)
 	case "user":
-		authorized = authorizeUser(logger, user, path, namespace)
+		authorized = authorizeUser(logger, user, method, path, route, namespace)
+	}
+
+	// read-only users have the permissions of a viewer, in every namespace
+	if authorized && user.IsReadOnly() {
+		authorized = authorizeNamespaceRole(auth.NamespaceRoleViewer, method, route)
 	}
 
 	logger.Info(fmt.Sprintf("user [%s] with role [%s] authorized [%t] for namespace [%s]", user.Username, user.Role, authorized, namespace))
@@ -56,7 +63,7 @@ func authorizeAdmin(logger logr.Logger) bool {
 	return true
 }
 
-func authorizeUser(logger logr.Logger, user auth.User, path, namespace string) bool {
+func authorizeUser(logger logr.Logger, user auth.User, method, path, route, namespace string) bool {
 	logger = logger.V(1).WithName("authorizeUser")
 
 	// check if the requested path is restricted
@@ -64,19 +71,99 @@ func authorizeUser(logger logr.Logger, user auth.User, path, namespace string) b
 		logger.Info(fmt.Sprintf("path [%s] is an admin route, user unauthorized", path))
 		return false
 	}
+	if _, found := AdminRouteNames[route]; found {
+		logger.Info(fmt.Sprintf("route [%s] is an admin route, user unauthorized", route))
+		return false
+	}
 
 	// check if the user has permission on the requested namespace
 	if namespace != "" {
-		for _, ns := range user.Namespaces {
-			if namespace == ns {
-				return true
-			}
+		role, found := user.NamespaceRole(namespace)
+		if !found {
+			logger.Info(fmt.Sprintf("namespace [%s] is not in user namespaces [%s]", namespace, strings.Join(user.Namespaces, ", ")))
+			return false
+		}
+
+		authorized := authorizeNamespaceRole(role, method, route)
+		if !authorized {
+			logger.Info(fmt.Sprintf("role [%s] in namespace [%s] is not allowed for route [%s - %s]", role, namespace, method, route))
 		}
+		return authorized
+	}
 
-		logger.Info(fmt.Sprintf("namespace [%s] is not in user namespaces [%s]", namespace, strings.Join(user.Namespaces, ", ")))
+	// a token limited to namespaces cannot change anything outside of them, e.g. create a namespace
+	if user.IsNamespaceScoped() && method != http.MethodGet {
+		logger.Info(fmt.Sprintf("route [%s - %s] is not namespaced, token limited to namespaces [%s]", method, route, strings.Join(user.Scope.Namespaces, ", ")))
 		return false
 	}
 
 	// all non-admin routes are public
 	return true
 }
+
+// ViewerForbiddenRoutes is the list of read-only routes not accessible by viewers of a namespace,
+// because they give access to the application workloads, or their secrets
+var ViewerForbiddenRoutes = map[string]struct{}{
+	"AppExec":        {},
+	"AppPortForward": {},
+	"AppWebhook":     {},
+}
+
+// DeveloperForbiddenRoutes is the list of namespaced routes not accessible by developers of a namespace,
+// only by its namespace admins
+var DeveloperForbiddenRoutes = map[string]struct{}{
+	"NamespaceDelete":          {},
+	"ConfigurationCreate":      {},
+	"ConfigurationBatchDelete": {},
+	"ConfigurationDelete":      {},
+	"ConfigurationUpdate":      {},
+	"ConfigurationReplace":     {},
+	"GitConfigCreate":          {},
+	"GitConfigDelete":          {},
+	"ServiceCreate":            {},
+	"ServiceDelete":            {},
+	"ServiceBatchDelete":       {},
+}
+
+// authorizeNamespaceRole checks if the role of a user in a namespace allows the call of the named route
+func authorizeNamespaceRole(role, method, route string) bool {
+	switch role {
+	case auth.NamespaceRoleAdmin:
+		return true
+	case auth.NamespaceRoleDeveloper:
+		_, forbidden := DeveloperForbiddenRoutes[route]
+		return !forbidden
+	case auth.NamespaceRoleViewer:
+		_, forbidden := ViewerForbiddenRoutes[route]
+		return method == http.MethodGet && !forbidden
+	}
+
+	// unknown roles are not allowed to do anything
+	return false
+}
+
+// RouteName returns the name of the API route registered with the method and the full path.
+// It returns an empty string if no route is found.
+func RouteName(method, fullPath string) string {
+	for _, r := range []struct {
+		root   string
+		routes routes.NamedRoutes
+	}{
+		{Root, Routes},
+		{WsRoot, WsRoutes},
+		{WebhookRoot, WebhookRoutes},
+	} {
+		if !strings.HasPrefix(fullPath, r.root) {
+			continue
+		}
+		path := strings.TrimPrefix(fullPath, r.root)
+
+		for name, route := range r.routes {
+			if route.Method == method && "/"+strings.TrimPrefix(route.Path, "/") == path {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
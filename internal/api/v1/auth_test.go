@@ -91,4 +91,109 @@ var _ = Describe("Authorization Middleware", func() {
 			})
 		})
 	})
+
+	Context("user has namespace roles", func() {
+		var router *gin.Engine
+		var user auth.User
+
+		BeforeEach(func() {
+			user = auth.User{
+				Role:       "user",
+				Namespaces: []string{"viewed", "developed", "owned"},
+				NamespaceRoles: map[string]string{
+					"viewed":    auth.NamespaceRoleViewer,
+					"developed": auth.NamespaceRoleDeveloper,
+				},
+			}
+
+			router = gin.New()
+			router.Use(func(c *gin.Context) {
+				reqCtx := requestctx.WithUser(ctx, user)
+				c.Request = c.Request.WithContext(reqCtx)
+			})
+			router.Use(v1.AuthorizationMiddleware)
+
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET(v1.Root+v1.Routes["Apps"].Path, ok)
+			router.POST(v1.Root+v1.Routes["AppCreate"].Path, ok)
+			router.POST(v1.Root+v1.Routes["ConfigurationCreate"].Path, ok)
+			router.GET(v1.WsRoot+v1.WsRoutes["AppExec"].Path, ok)
+			router.GET(v1.Root+v1.Routes["UserShow"].Path, ok)
+			router.GET(v1.Root+v1.Routes["Namespaces"].Path, ok)
+			router.POST(v1.Root+v1.Routes["NamespaceCreate"].Path, ok)
+		})
+
+		serve := func(method, path string) int {
+			req, err := http.NewRequest(method, path, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		When("the user is a viewer", func() {
+			It("can list the applications", func() {
+				Expect(serve(http.MethodGet, "/api/v1/namespaces/viewed/applications")).To(Equal(http.StatusOK))
+			})
+
+			It("cannot create an application", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces/viewed/applications")).To(Equal(http.StatusForbidden))
+			})
+
+			It("cannot exec into an application", func() {
+				Expect(serve(http.MethodGet, "/wapi/v1/namespaces/viewed/applications/app/exec")).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("the user is a developer", func() {
+			It("can create an application", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces/developed/applications")).To(Equal(http.StatusOK))
+			})
+
+			It("cannot create a configuration", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces/developed/configurations")).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("the route is restricted by name", func() {
+			It("returns status code 403", func() {
+				Expect(serve(http.MethodGet, "/api/v1/users/admin")).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("the user is a namespace admin", func() {
+			It("can create a configuration", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces/owned/configurations")).To(Equal(http.StatusOK))
+			})
+		})
+
+		When("the user authenticated with a token limited to namespaces", func() {
+			BeforeEach(func() {
+				user = user.WithScope(auth.TokenScope{Namespaces: []string{"owned"}})
+			})
+
+			It("can act in the namespaces of the token", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces/owned/configurations")).To(Equal(http.StatusOK))
+			})
+
+			It("can list the namespaces", func() {
+				Expect(serve(http.MethodGet, "/api/v1/namespaces")).To(Equal(http.StatusOK))
+			})
+
+			It("cannot create a namespace", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces")).To(Equal(http.StatusForbidden))
+			})
+		})
+
+		When("the user authenticated with a token for all namespaces", func() {
+			BeforeEach(func() {
+				user = user.WithScope(auth.TokenScope{})
+			})
+
+			It("can create a namespace", func() {
+				Expect(serve(http.MethodPost, "/api/v1/namespaces")).To(Equal(http.StatusOK))
+			})
+		})
+	})
 })
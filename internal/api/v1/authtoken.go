@@ -26,10 +26,19 @@ import (
 // token for further logins
 func AuthToken(c *gin.Context) APIErrors {
 	requestContext := c.Request.Context()
-	user := requestctx.User(requestContext).Username
+	user := requestctx.User(requestContext)
+
+	// the websocket connections keep the restrictions of a scoped user
+	var scope *authtoken.Scope
+	if user.Scope != nil {
+		scope = &authtoken.Scope{
+			Namespaces: user.Scope.Namespaces,
+			ReadOnly:   user.Scope.ReadOnly,
+		}
+	}
 
 	response.OKReturn(c, models.AuthTokenResponse{
-		Token: authtoken.Create(user, authtoken.DefaultExpiry),
+		Token: authtoken.CreateWithScope(user.Username, scope, authtoken.DefaultExpiry),
 	})
 	return nil
 }
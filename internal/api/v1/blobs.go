@@ -0,0 +1,76 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/spf13/viper"
+
+	"github.com/gin-gonic/gin"
+
+	. "github.com/epinio/epinio/pkg/api/core/v1/errors"
+)
+
+// defaultBlobMaxAge is the maximum age of unreferenced blobs used by the report when the
+// blob collector is disabled.
+const defaultBlobMaxAge = 24 * time.Hour
+
+// BlobCollection handles the API endpoint /blobs/collection. It returns a dry run of the
+// garbage collection of source blobs, i.e. the blobs which are kept, and the blobs which
+// would be removed. The retention of the blob collector is used, unless overridden by
+// the "keep_stages" and "max_age" query parameters.
+func BlobCollection(c *gin.Context) APIErrors {
+	ctx := c.Request.Context()
+
+	retention := application.BlobRetention{
+		KeepStages: viper.GetInt("source-blob-keep-stages"),
+		MaxAge:     time.Duration(viper.GetInt("source-blob-max-age-hours")) * time.Hour,
+	}
+	if retention.MaxAge <= 0 {
+		retention.MaxAge = defaultBlobMaxAge
+	}
+
+	if keep := c.Query("keep_stages"); keep != "" {
+		value, err := strconv.Atoi(keep)
+		if err != nil || value < 0 {
+			return NewBadRequestErrorf("invalid keep_stages '%s', expected a non-negative number", keep)
+		}
+		retention.KeepStages = value
+	}
+
+	if maxAge := c.Query("max_age"); maxAge != "" {
+		value, err := time.ParseDuration(maxAge)
+		if err != nil || value < 0 {
+			return NewBadRequestErrorf("invalid max_age '%s', expected a non-negative duration", maxAge)
+		}
+		retention.MaxAge = value
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return InternalError(err)
+	}
+
+	report, err := application.CollectBlobs(ctx, cluster, requestctx.Logger(ctx), retention, true)
+	if err != nil {
+		return InternalError(err)
+	}
+
+	response.OKReturn(c, report)
+	return nil
+}
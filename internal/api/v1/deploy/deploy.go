@@ -27,6 +27,7 @@ import (
 	"github.com/epinio/epinio/internal/domain"
 	"github.com/epinio/epinio/internal/helm"
 	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/namespaces"
 	"github.com/epinio/epinio/internal/registry"
 	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -54,6 +55,59 @@ func DeployApp(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 			WithDetailsf("expectedStageID: [%s] - stageID: [%s]", expectedStageID, stageID)
 	}
 
+	deployParams, apierr := chartParameters(ctx, cluster, appObj, username, start)
+	if apierr != nil {
+		return nil, apierr
+	}
+
+	log.Info("deploying app", "namespace", app.Namespace, "app", app.Name)
+
+	candidate, err := deployWithStrategy(ctx, cluster, appObj, deployParams)
+	if err != nil {
+		return nil, apierror.InternalError(err)
+	}
+
+	// A newer build, successfully deployed, supersedes any rollback. Not while a
+	// candidate awaits promotion, an abort returns to the build rolled back to.
+	rolledBack := appObj.Rollback != nil
+	if candidate == nil && application.RollbackSuperseded(appObj) {
+		if err := application.ClearRollback(ctx, cluster, app); err != nil {
+			return nil, apierror.InternalError(err, "clearing the rollback")
+		}
+		rolledBack = false
+	}
+
+	// Delete previous staging jobs except for the current one. Not while the app is
+	// rolled back, the sources of the newer builds stay available for restaging. Not
+	// while a candidate awaits promotion either, an abort returns to the live build.
+	if stageID != "" && !rolledBack && candidate == nil {
+		log.Info("app staging drop", "namespace", app.Namespace, "app", app.Name, "stage id", stageID)
+
+		if err := application.Unstage(ctx, cluster, app, stageID); err != nil {
+			return nil, apierror.InternalError(err)
+		}
+	}
+
+	if origin != nil {
+		err = application.SetOrigin(ctx, cluster,
+			models.NewAppRef(app.Name, app.Namespace), *origin)
+		if err != nil {
+			return nil, apierror.InternalError(err, "saving the app origin")
+		}
+
+		log.Info("saved app origin", "namespace", app.Namespace, "app", app.Name, "origin", *origin)
+	}
+
+	return appObj.Configuration.Routes, nil
+}
+
+// chartParameters assembles the helm parameters for deploying the application from the
+// state held by CRD and associated secrets. The parameters target the release named after
+// the application.
+func chartParameters(ctx context.Context, cluster *kubernetes.Cluster, appObj *models.App, username string, start *int64) (helm.ChartParameters, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	app := appObj.Meta
+
 	// Iterate over the bound configurations to determine their mount path ...
 
 	// (**) See below for explanation
@@ -65,7 +119,7 @@ func DeployApp(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 	for _, configName := range appObj.Configuration.Configurations {
 		config, err := configurations.Lookup(ctx, cluster, app.Namespace, configName)
 		if err != nil {
-			return nil, apierror.InternalError(err)
+			return helm.ChartParameters{}, apierror.InternalError(err)
 		}
 
 		// Default path is config name itself
@@ -111,6 +165,20 @@ func DeployApp(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 	chartName := appObj.Configuration.AppChart
 	domains := domain.MatchMapLoad(ctx, app.Namespace)
 
+	// Applications of a namespace with maximums are limited to them, whether they
+	// declare limits or not.
+	maxCPU, maxMemory, err := namespaces.AppResourceMaximums(ctx, cluster, app.Namespace)
+	if err != nil {
+		return helm.ChartParameters{}, apierror.InternalError(err, "reading the resource maximums of the namespace")
+	}
+	resources := application.LimitResources(appObj.Configuration.Resources, maxCPU, maxMemory)
+
+	// Health checks recorded while enabled are dropped when disabled later.
+	var healthChecks *models.AppHealthChecks
+	if application.HealthChecksEnabled() {
+		healthChecks = appObj.Configuration.HealthChecks
+	}
+
 	maplog := log.V(1)
 	maplog.Info("domain map begin")
 	for k, v := range domains {
@@ -128,45 +196,21 @@ func DeployApp(ctx context.Context, cluster *kubernetes.Cluster, app models.AppR
 		Instances:      *appObj.Configuration.Instances,
 		ImageURL:       imageURL,
 		Username:       username,
-		StageID:        stageID,
+		StageID:        appObj.StageID,
 		Routes:         routes,
 		Domains:        domains,
 		Start:          start,
 		Settings:       appObj.Configuration.Settings,
+		HealthChecks:   healthChecks,
+		Resources:      resources,
 	}
 
-	log.Info("deploying app", "namespace", app.Namespace, "app", app.Name)
-
 	deployParams.ImageURL, err = replaceInternalRegistry(ctx, cluster, imageURL)
 	if err != nil {
-		return nil, apierror.InternalError(err, "preparing ImageURL registry for use by Kubernetes", imageURL)
-	}
-
-	err = helm.Deploy(log, deployParams)
-	if err != nil {
-		return nil, apierror.InternalError(err)
-	}
-
-	// Delete previous staging jobs except for the current one
-	if stageID != "" {
-		log.Info("app staging drop", "namespace", app.Namespace, "app", app.Name, "stage id", stageID)
-
-		if err := application.Unstage(ctx, cluster, app, stageID); err != nil {
-			return nil, apierror.InternalError(err)
-		}
-	}
-
-	if origin != nil {
-		err = application.SetOrigin(ctx, cluster,
-			models.NewAppRef(app.Name, app.Namespace), *origin)
-		if err != nil {
-			return nil, apierror.InternalError(err, "saving the app origin")
-		}
-
-		log.Info("saved app origin", "namespace", app.Namespace, "app", app.Name, "origin", *origin)
+		return deployParams, apierror.InternalError(err, "preparing ImageURL registry for use by Kubernetes", imageURL)
 	}
 
-	return routes, nil
+	return deployParams, nil
 }
 
 // replaceInternalRegistry replaces the registry part of ImageURL with the localhost
@@ -0,0 +1,387 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/internal/helm"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// deployWithStrategy deploys the application according to its deployment strategy.
+//
+// A rolling deployment upgrades the live release in place. So do the other strategies
+// when nothing is running yet, or when the build did not change, i.e. for configuration
+// changes. Else the new build becomes a candidate, deployed to the second release of the
+// application. While a candidate exists all deployments update both releases, the live
+// one at its own build. The candidate is returned, if any.
+func deployWithStrategy(ctx context.Context, cluster *kubernetes.Cluster, appObj *models.App, params helm.ChartParameters) (*models.AppCandidate, error) {
+	log := requestctx.Logger(ctx)
+	app := appObj.Meta
+
+	candidate, live, err := planDeployment(ctx, cluster, appObj, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate == nil {
+		params.ReleaseName = live
+		return nil, helm.Deploy(log, params)
+	}
+
+	liveParams := params
+	liveParams.ReleaseName = candidate.FromRelease
+	liveParams.StageID = candidate.FromStageID
+	liveParams.ImageURL = candidate.FromImageURL
+
+	err = helm.Deploy(log, liveParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "deploying the live release")
+	}
+
+	err = helm.Deploy(log, candidateParameters(params, candidate))
+	if err != nil {
+		return nil, errors.Wrap(err, "deploying the candidate release")
+	}
+
+	err = application.SetCandidate(ctx, cluster, app, *candidate)
+	if err != nil {
+		return nil, errors.Wrap(err, "saving the candidate")
+	}
+
+	log.Info("deployed candidate", "namespace", app.Namespace, "app", app.Name,
+		"strategy", candidate.Strategy, "stage id", candidate.StageID, "release", candidate.Release)
+
+	return candidate, nil
+}
+
+// planDeployment decides where the build of the parameters goes, per the strategy of the
+// application. The result is either the new or updated candidate, or nil, for deploying
+// to the live release, whose name is returned as well.
+func planDeployment(ctx context.Context, cluster *kubernetes.Cluster, appObj *models.App, params helm.ChartParameters) (*models.AppCandidate, string, error) {
+	log := requestctx.Logger(ctx)
+	app := appObj.Meta
+
+	applicationCR, err := application.Get(ctx, cluster, app)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get the application resource")
+	}
+
+	live := application.LiveRelease(applicationCR)
+	candidate := appObj.Candidate
+
+	if candidate == nil {
+		strategy, err := application.DeploymentStrategy(applicationCR)
+		if err != nil {
+			return nil, live, err
+		}
+		if strategy.Strategy == models.DeploymentStrategyRolling {
+			return nil, live, nil
+		}
+
+		deployed, err := helm.Deployed(cluster, log, app.Namespace, live)
+		if err != nil {
+			return nil, live, err
+		}
+		if deployed == nil ||
+			(deployed.StageID == params.StageID && deployed.ImageURL == params.ImageURL) {
+			return nil, live, nil
+		}
+
+		// Without a traffic split the canary would receive an unweighted share of the traffic.
+		if strategy.Strategy == models.DeploymentStrategyCanary {
+			if err := application.CheckCanarySupport(ctx, cluster); err != nil {
+				return nil, live, err
+			}
+		}
+
+		candidate = &models.AppCandidate{
+			Strategy:     strategy.Strategy,
+			Weight:       strategy.Weight,
+			Release:      application.CandidateRelease(app.Name, live),
+			FromStageID:  deployed.StageID,
+			FromImageURL: deployed.ImageURL,
+			FromRelease:  live,
+			Username:     params.Username,
+			Time:         metav1.Now(),
+		}
+	}
+
+	candidate.StageID = params.StageID
+	candidate.ImageURL = appObj.ImageURL
+
+	return candidate, live, nil
+}
+
+// candidateParameters returns the parameters for deploying the candidate. A blue/green
+// candidate receives its routes on promotion. The ingresses of a canary are created as
+// canary, with its current weight.
+func candidateParameters(params helm.ChartParameters, candidate *models.AppCandidate) helm.ChartParameters {
+	params.ReleaseName = candidate.Release
+	switch candidate.Strategy {
+	case models.DeploymentStrategyBlueGreen:
+		params.Routes = nil
+	case models.DeploymentStrategyCanary:
+		params.CanaryWeight = candidate.Weight
+	}
+	return params
+}
+
+// DryRun renders the application chart as DeployApp would deploy it, without deploying
+// anything. A non-empty image url replaces the image and stage id of the application.
+func DryRun(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, username, stageID, imageURL string) (models.DeployDryRunResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	none := models.DeployDryRunResponse{}
+
+	appObj, err := application.Lookup(ctx, cluster, app.Namespace, app.Name)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+	if appObj == nil {
+		return none, apierror.AppIsNotKnown(app.Name)
+	}
+
+	if imageURL != "" {
+		appObj.ImageURL = imageURL
+		appObj.StageID = stageID
+	}
+
+	params, apierr := chartParameters(ctx, cluster, appObj, username, nil)
+	if apierr != nil {
+		return none, apierr
+	}
+
+	candidate, live, err := planDeployment(ctx, cluster, appObj, params)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+
+	if candidate == nil {
+		params.ReleaseName = live
+	} else {
+		params = candidateParameters(params, candidate)
+	}
+
+	result, err := helm.Render(log, params)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+
+	return result, nil
+}
+
+// Promote promotes the candidate of the referenced application. For a canary a weight
+// below 100 only shifts the traffic split. Else the candidate receives all routes and its
+// release becomes the live one, replacing the previously live release. See runPromotion
+// for the order of the steps.
+func Promote(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef, username string, weight *int32) (models.CandidateResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	none := models.CandidateResponse{}
+
+	appObj, err := application.Lookup(ctx, cluster, app.Namespace, app.Name)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+	if appObj == nil {
+		return none, apierror.AppIsNotKnown(app.Name)
+	}
+
+	candidate := appObj.Candidate
+	if candidate == nil {
+		return none, apierror.NewBadRequestError("application has no candidate to promote")
+	}
+
+	if weight != nil {
+		if *weight < 1 || *weight > 100 {
+			return none, apierror.NewBadRequestErrorf("weight %d is out of range, expected 1 to 100", *weight)
+		}
+		if *weight < 100 {
+			if candidate.Strategy != models.DeploymentStrategyCanary {
+				return none, apierror.NewBadRequestErrorf("weight is only supported for a canary, the candidate is %s",
+					candidate.Strategy)
+			}
+
+			err = application.SetCanary(ctx, cluster, app, candidate.Release, *weight)
+			if err != nil {
+				return none, apierror.InternalError(err)
+			}
+
+			candidate.Weight = *weight
+			err = application.SetCandidate(ctx, cluster, app, *candidate)
+			if err != nil {
+				return none, apierror.InternalError(err, "saving the candidate")
+			}
+
+			log.Info("shifted canary", "namespace", app.Namespace, "app", app.Name, "weight", *weight)
+
+			return models.CandidateResponse{Candidate: *candidate}, nil
+		}
+	}
+
+	// Switch the routes only to a candidate able to serve them.
+	err = application.WaitForRelease(ctx, cluster, app, candidate.Release, duration.ToDeployment())
+	if errors.Is(err, application.ErrReleaseNotReady) {
+		return none, apierror.NewAPIError(err.Error(), http.StatusConflict).
+			WithDetails("the candidate is not ready, the live release keeps the routes")
+	}
+	if err != nil {
+		return none, apierror.InternalError(err, "waiting for the candidate")
+	}
+
+	params, apierr := chartParameters(ctx, cluster, appObj, username, nil)
+	if apierr != nil {
+		return none, apierr
+	}
+	params.ReleaseName = candidate.Release
+
+	apierr = runPromotion(*candidate, promotion{
+		shiftTraffic: func(weight int32) error {
+			return application.SetCanary(ctx, cluster, app, candidate.Release, weight)
+		},
+		removeLive: func() error {
+			// The history of the previously live release goes with it. The release
+			// history of the application starts over, the build history is kept.
+			return helm.RemoveRelease(cluster, log, app.Namespace, candidate.FromRelease)
+		},
+		save: func() error {
+			return application.PromoteCandidate(ctx, cluster, app, *candidate)
+		},
+		deployCandidate: func() error {
+			return helm.Deploy(log, params)
+		},
+	})
+	if apierr != nil {
+		return none, apierr
+	}
+
+	log.Info("promoted candidate", "namespace", app.Namespace, "app", app.Name,
+		"stage id", candidate.StageID, "release", candidate.Release)
+
+	rolledBack := appObj.Rollback != nil
+	if application.RollbackSuperseded(appObj) {
+		if err := application.ClearRollback(ctx, cluster, app); err != nil {
+			return none, apierror.InternalError(err, "clearing the rollback")
+		}
+		rolledBack = false
+	}
+
+	if appObj.StageID != "" && !rolledBack {
+		if err := application.Unstage(ctx, cluster, app, appObj.StageID); err != nil {
+			return none, apierror.InternalError(err)
+		}
+	}
+
+	return models.CandidateResponse{
+		Candidate: *candidate,
+		Promoted:  true,
+		Routes:    appObj.Configuration.Routes,
+	}, nil
+}
+
+// promotion holds the steps of the promotion of a candidate, see runPromotion.
+type promotion struct {
+	shiftTraffic    func(weight int32) error // shifts the traffic split of a canary
+	removeLive      func() error             // removes the previously live release
+	save            func() error             // records the candidate's release as the live one
+	deployCandidate func() error             // deploys the candidate as a regular release, with all routes
+}
+
+// runPromotion runs the steps of the promotion of the candidate. The previously live
+// release is removed before the candidate is deployed with the routes, as a regular
+// release. The regular ingresses of the two releases never compete for the same routes. A
+// canary receives all the traffic before the live release is removed. The promotion is
+// recorded before the deployment of the candidate, a failed deployment is then retried by
+// any deployment of the application.
+func runPromotion(candidate models.AppCandidate, steps promotion) apierror.APIErrors {
+	if candidate.Strategy == models.DeploymentStrategyCanary {
+		if err := steps.shiftTraffic(100); err != nil {
+			return apierror.InternalError(err, "shifting all traffic to the canary")
+		}
+	}
+
+	if err := steps.removeLive(); err != nil {
+		return apierror.InternalError(err, "removing the previously live release")
+	}
+
+	if err := steps.save(); err != nil {
+		return apierror.InternalError(err, "saving the promotion")
+	}
+
+	if err := steps.deployCandidate(); err != nil {
+		return apierror.InternalError(err, "deploying the promoted release")
+	}
+
+	return nil
+}
+
+// Abort removes the candidate of the referenced application, and points the application
+// back to the build of the live release.
+func Abort(ctx context.Context, cluster *kubernetes.Cluster, app models.AppRef) (models.CandidateResponse, apierror.APIErrors) {
+	log := requestctx.Logger(ctx)
+	none := models.CandidateResponse{}
+
+	appObj, err := application.Lookup(ctx, cluster, app.Namespace, app.Name)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+	if appObj == nil {
+		return none, apierror.AppIsNotKnown(app.Name)
+	}
+
+	candidate := appObj.Candidate
+	if candidate == nil {
+		return none, apierror.NewBadRequestError("application has no candidate to abort")
+	}
+
+	err = helm.RemoveRelease(cluster, log, app.Namespace, candidate.Release)
+	if err != nil {
+		return none, apierror.InternalError(err, "removing the candidate release")
+	}
+
+	// The live release holds the image as seen by kubernetes. Prefer the image as
+	// recorded by the build.
+	imageURL := candidate.FromImageURL
+
+	builds, err := application.Builds(ctx, cluster, app)
+	if err != nil {
+		return none, apierror.InternalError(err)
+	}
+	for _, build := range builds {
+		if build.StageID == candidate.FromStageID && build.ImageURL != "" {
+			imageURL = build.ImageURL
+			break
+		}
+	}
+
+	err = application.AbortCandidate(ctx, cluster, app, candidate.FromStageID, imageURL)
+	if err != nil {
+		return none, apierror.InternalError(err, "saving the abort")
+	}
+
+	log.Info("aborted candidate", "namespace", app.Namespace, "app", app.Name,
+		"stage id", candidate.StageID, "release", candidate.Release)
+
+	return models.CandidateResponse{
+		Candidate: *candidate,
+		Routes:    appObj.Configuration.Routes,
+	}, nil
+}
@@ -0,0 +1,105 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/helm"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deployment strategies", func() {
+	Describe("candidateParameters", func() {
+		params := helm.ChartParameters{ReleaseName: "app", Routes: []string{"app.example.com"}}
+
+		It("deploys a blue/green candidate without routes", func() {
+			candidate := candidateParameters(params, &models.AppCandidate{
+				Strategy: models.DeploymentStrategyBlueGreen,
+				Release:  "app-b",
+			})
+			Expect(candidate.ReleaseName).To(Equal("app-b"))
+			Expect(candidate.Routes).To(BeEmpty())
+			Expect(candidate.CanaryWeight).To(BeZero())
+		})
+
+		It("deploys a canary with its routes, marked as canary", func() {
+			candidate := candidateParameters(params, &models.AppCandidate{
+				Strategy: models.DeploymentStrategyCanary,
+				Release:  "app-b",
+				Weight:   10,
+			})
+			Expect(candidate.ReleaseName).To(Equal("app-b"))
+			Expect(candidate.Routes).To(Equal(params.Routes))
+			Expect(candidate.CanaryWeight).To(Equal(int32(10)))
+		})
+	})
+
+	Describe("runPromotion", func() {
+		var steps []string
+		var failing string
+
+		recorder := func() promotion {
+			step := func(name string) error {
+				steps = append(steps, name)
+				if name == failing {
+					return errors.New("failed")
+				}
+				return nil
+			}
+
+			return promotion{
+				shiftTraffic:    func(weight int32) error { return step(fmt.Sprintf("shift %d", weight)) },
+				removeLive:      func() error { return step("remove live") },
+				save:            func() error { return step("save") },
+				deployCandidate: func() error { return step("deploy candidate") },
+			}
+		}
+
+		BeforeEach(func() {
+			steps = []string{}
+			failing = ""
+		})
+
+		It("shifts all traffic to a canary, and removes the live release, before unmarking the canary", func() {
+			apierr := runPromotion(models.AppCandidate{Strategy: models.DeploymentStrategyCanary}, recorder())
+			Expect(apierr).To(BeNil())
+			Expect(steps).To(Equal([]string{"shift 100", "remove live", "save", "deploy candidate"}))
+		})
+
+		It("removes the live release before routing to a blue/green candidate", func() {
+			apierr := runPromotion(models.AppCandidate{Strategy: models.DeploymentStrategyBlueGreen}, recorder())
+			Expect(apierr).To(BeNil())
+			Expect(steps).To(Equal([]string{"remove live", "save", "deploy candidate"}))
+		})
+
+		It("keeps the live release when the traffic cannot be shifted", func() {
+			failing = "shift 100"
+
+			apierr := runPromotion(models.AppCandidate{Strategy: models.DeploymentStrategyCanary}, recorder())
+			Expect(apierr).ToNot(BeNil())
+			Expect(steps).To(Equal([]string{"shift 100"}))
+		})
+
+		It("stops when the live release cannot be removed", func() {
+			failing = "remove live"
+
+			apierr := runPromotion(models.AppCandidate{Strategy: models.DeploymentStrategyBlueGreen}, recorder())
+			Expect(apierr).ToNot(BeNil())
+			Expect(steps).To(Equal([]string{"remove live"}))
+		})
+	})
+})
@@ -190,6 +190,8 @@ type StagingLogsResponse struct{}
 
 // swagger:route GET /namespaces/{Namespace}/staging/{StageID}/complete application StagingComplete
 // Waits for the completion of the staging process identified by `StageID` in the `Namespace`.
+// With `queue` set the call returns right away while the staging is waiting in the build
+// queue, with status `queued` and the position in the queue.
 // responses:
 //   200: StagingCompleteResponse
 
@@ -199,10 +201,31 @@ type StagingCompleteParam struct {
 	Namespace string
 	// in: path
 	StageID string
+	// in: query
+	Queue bool
 }
 
 // swagger:response StagingCompleteResponse
 type StagingCompleteResponse struct {
+	// in: body
+	Body models.StagingCompleteResponse
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/staging/{StageID} application StagingCancel
+// Cancels the running staging process identified by `StageID` in the `Namespace`.
+// responses:
+//   200: StagingCancelResponse
+
+// swagger:parameters StagingCancel
+type StagingCancelParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	StageID string
+}
+
+// swagger:response StagingCancelResponse
+type StagingCancelResponse struct {
 	// in: body
 	Body models.Response
 }
@@ -258,6 +281,116 @@ type AppUploadResponse struct {
 	Body models.UploadResponse
 }
 
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/uploads application AppUploadInit
+// Start a chunked upload of the sources of the named `App` in the `Namespace`.
+// responses:
+//   200: AppUploadInitResponse
+
+// swagger:parameters AppUploadInit
+type AppUploadInitParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Configuration models.UploadInitRequest
+}
+
+// swagger:response AppUploadInitResponse
+type AppUploadInitResponse struct {
+	// in: body
+	Body models.UploadInitResponse
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/uploads/{Blob} application AppUploadStatus
+// Return the parts of the chunked upload `Blob` stored so far.
+// responses:
+//   200: AppUploadStatusResponse
+
+// swagger:parameters AppUploadStatus
+type AppUploadStatusParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string `json:"upload"`
+}
+
+// swagger:response AppUploadStatusResponse
+type AppUploadStatusResponse struct {
+	// in: body
+	Body models.UploadStatusResponse
+}
+
+// swagger:route PUT /namespaces/{Namespace}/applications/{App}/uploads/{Blob}/parts/{Part} application AppUploadPart
+// Store the numbered `Part` of the chunked upload `Blob`. The sha256 checksum of the part
+// is carried in the X-Epinio-Checksum-Sha256 header.
+// responses:
+//   200: AppUploadPartResponse
+
+// swagger:parameters AppUploadPart
+type AppUploadPartParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: path
+	Part int
+	// in: query
+	Upload string `json:"upload"`
+}
+
+// swagger:response AppUploadPartResponse
+type AppUploadPartResponse struct {
+	// in: body
+	Body models.UploadPart
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/uploads/{Blob}/complete application AppUploadComplete
+// Assemble the stored parts of the chunked upload `Blob`, and verify its checksum.
+// responses:
+//   200: AppUploadResponse
+
+// swagger:parameters AppUploadComplete
+type AppUploadCompleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string `json:"upload"`
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/uploads/{Blob} application AppUploadAbort
+// Abort the chunked upload `Blob`, dropping the parts stored so far.
+// responses:
+//   200: AppUploadAbortResponse
+
+// swagger:parameters AppUploadAbort
+type AppUploadAbortParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Blob string
+	// in: query
+	Upload string `json:"upload"`
+}
+
+// swagger:response AppUploadAbortResponse
+type AppUploadAbortResponse struct {
+	// in: body
+	Body models.Response
+}
+
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/restart application AppRestart
 // Restart the named `App` in the `Namespace`.
 // responses:
@@ -277,6 +410,254 @@ type AppRestartResponse struct {
 	Body models.Response
 }
 
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/stages application AppBuilds
+// Return the build history of the named `App` in the `Namespace`, most recent first.
+// responses:
+//   200: AppBuildsResponse
+
+// swagger:parameters AppBuilds
+type AppBuildsParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppBuildsResponse
+type AppBuildsResponse struct {
+	// in: body
+	Body models.AppBuildList
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/releases application AppReleases
+// Return the revisions of the helm release serving the named `App` in the `Namespace`, most recent first.
+// A blue/green or canary promotion switches the application to its other release, and
+// uninstalls the previous one with its history. The history therefore starts over with every
+// promotion. The build history (`builds`) is kept across promotions.
+// responses:
+//   200: AppReleasesResponse
+
+// swagger:parameters AppReleases
+type AppReleasesParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppReleasesResponse
+type AppReleasesResponse struct {
+	// in: body
+	Body models.AppReleaseList
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/releases/{Rev}/diff/{Rev2} application AppReleaseDiff
+// Return the differences in values and manifests between the revisions `Rev` and `Rev2` of the
+// helm release serving the named `App` in the `Namespace`. Only revisions since the last
+// promotion of a candidate can be compared, see AppReleases.
+// responses:
+//   200: AppReleaseDiffResponse
+
+// swagger:parameters AppReleaseDiff
+type AppReleaseDiffParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: path
+	Rev int
+	// in: path
+	Rev2 int
+}
+
+// swagger:response AppReleaseDiffResponse
+type AppReleaseDiffResponse struct {
+	// in: body
+	Body models.AppReleaseDiff
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/cache application AppCache
+// Return information about the build cache of the named `App` in the `Namespace`.
+// responses:
+//   200: AppCacheResponse
+
+// swagger:parameters AppCache
+type AppCacheParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppCacheResponse
+type AppCacheResponse struct {
+	// in: body
+	Body models.AppCache
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/cache application AppCacheClear
+// Remove the build cache of the named `App` in the `Namespace`.
+// responses:
+//   200: AppCacheClearResponse
+
+// swagger:parameters AppCacheClear
+type AppCacheClearParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppCacheClearResponse
+type AppCacheClearResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/rollback application AppRollback
+// Roll the named `App` in the `Namespace` back to the image of an earlier build.
+// responses:
+//   200: AppRollbackResponse
+
+// swagger:parameters AppRollback
+type AppRollbackParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Body models.RollbackRequest
+}
+
+// swagger:response AppRollbackResponse
+type AppRollbackResponse struct {
+	// in: body
+	Body models.RollbackResponse
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/promote application AppPromote
+// Promote the candidate of the named `App` in the `Namespace`, or shift the traffic split of its canary.
+// The routes are switched only once the candidate is ready. A candidate not ready in time is
+// reported with status 409, and the live release keeps the routes. The live release is
+// removed before the candidate takes the routes over, a canary receives all the traffic
+// first. Canary deployments require the ingress-nginx controller.
+// responses:
+//   200: AppPromoteResponse
+
+// swagger:parameters AppPromote
+type AppPromoteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+	// in: body
+	Body models.PromoteRequest
+}
+
+// swagger:response AppPromoteResponse
+type AppPromoteResponse struct {
+	// in: body
+	Body models.CandidateResponse
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/abort application AppAbort
+// Remove the candidate of the named `App` in the `Namespace`, keeping the live build.
+// responses:
+//   200: AppAbortResponse
+
+// swagger:parameters AppAbort
+type AppAbortParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppAbortResponse
+type AppAbortResponse struct {
+	// in: body
+	Body models.CandidateResponse
+}
+
+// swagger:route GET /namespaces/{Namespace}/applications/{App}/webhook application AppWebhook
+// Return the state of the Git webhook of the named `App` in the `Namespace`, with its secret.
+// responses:
+//   200: AppWebhookResponse
+
+// swagger:parameters AppWebhook
+type AppWebhookParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookResponse
+type AppWebhookResponse struct {
+	// in: body
+	Body models.AppWebhook
+}
+
+// swagger:route POST /namespaces/{Namespace}/applications/{App}/webhook application AppWebhookEnable
+// Enable the Git webhook of the named `App` in the `Namespace`, with a new secret.
+// responses:
+//   200: AppWebhookEnableResponse
+
+// swagger:parameters AppWebhookEnable
+type AppWebhookEnableParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookEnableResponse
+type AppWebhookEnableResponse struct {
+	// in: body
+	Body models.AppWebhook
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/applications/{App}/webhook application AppWebhookDisable
+// Disable the Git webhook of the named `App` in the `Namespace`.
+// responses:
+//   200: AppWebhookDisableResponse
+
+// swagger:parameters AppWebhookDisable
+type AppWebhookDisableParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response AppWebhookDisableResponse
+type AppWebhookDisableResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route POST /webhooks/git/{Namespace}/{App} application GitWebhook
+// Receive a push event from GitHub, GitLab, or Gitea for the named `App` in the `Namespace`.
+// A push to the tracked branch of the application's repository imports, stages, and deploys
+// the application. The builds of an application run one after the other.
+// The request has to be signed with the secret of the application's webhook.
+// responses:
+//   200: GitWebhookResponse
+
+// swagger:parameters GitWebhook
+type GitWebhookParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	App string
+}
+
+// swagger:response GitWebhookResponse
+type GitWebhookResponse struct {
+	// in: body
+	Body models.GitWebhookResponse
+}
+
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/import-git application AppImportGit
 // Store the named `App` from a Git repo in the `Namespace`.
 // responses:
@@ -287,9 +668,11 @@ type AppImportGitParam struct {
 	// in: path
 	Namespace string
 	// in: path
-	App    string
-	GitUrl string
-	GitRev string
+	App           string
+	GitUrl        string
+	GitRev        string
+	GitSubdir     string
+	GitSubmodules bool
 }
 
 // swagger:response AppImportGitResponse
@@ -321,6 +704,8 @@ type AppStageResponse struct {
 
 // swagger:route POST /namespaces/{Namespace}/applications/{App}/deploy application AppDeploy
 // Create the deployment, configuration and ingress resources for the named `App` in the `Namespace`.
+// With `dryRun` the app chart is only rendered, and compared to the deployed release. The
+// response is an `AppDeployDryRunResponse` then.
 // responses:
 //   200: AppDeployResponse
 
@@ -330,6 +715,8 @@ type AppDeployParam struct {
 	Namespace string
 	// in: path
 	App string
+	// in: query
+	DryRun bool `json:"dryRun"`
 	// in: body
 	Body models.DeployRequest
 }
@@ -340,6 +727,12 @@ type AppDeployResponse struct {
 	Body models.DeployResponse
 }
 
+// swagger:response AppDeployDryRunResponse
+type AppDeployDryRunResponse struct {
+	// in: body
+	Body models.DeployDryRunResponse
+}
+
 // swagger:route PATCH /namespaces/{Namespace}/applications/{App} application AppUpdate
 // Patch the named `App` in the `Namespace`.
 // responses:
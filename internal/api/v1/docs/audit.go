@@ -0,0 +1,37 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /audit audit Audit
+// Return the recorded mutating requests, most recent first. Restricted to admins.
+// responses:
+//   200: AuditResponse
+
+// swagger:parameters Audit
+type AuditParam struct {
+	// in: query
+	User string `json:"user"`
+	// in: query
+	Namespace string `json:"namespace"`
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// swagger:response AuditResponse
+type AuditResponse struct {
+	// in: body
+	Body models.AuditRecordList
+}
@@ -0,0 +1,36 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /blobs/collection blobs BlobCollection
+// Return a dry run of the garbage collection of the source blobs, i.e. the blobs kept and
+// the blobs which would be removed. Restricted to admins.
+// responses:
+//   200: BlobCollectionResponse
+
+// swagger:parameters BlobCollection
+type BlobCollectionParam struct {
+	// in: query
+	KeepStages int `json:"keep_stages"`
+	// in: query
+	MaxAge string `json:"max_age"`
+}
+
+// swagger:response BlobCollectionResponse
+type BlobCollectionResponse struct {
+	// in: body
+	Body models.BlobCollectionReport
+}
@@ -0,0 +1,74 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+//go:generate swagger generate spec
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+// Git Configurations
+
+// swagger:route GET /namespaces/{Namespace}/gitconfigs gitconfig GitConfigs
+// Return list of git configurations in the `Namespace`, without their secrets.
+// responses:
+//   200: GitConfigsResponse
+
+// swagger:parameters GitConfigs
+type GitConfigsParam struct {
+	// in: path
+	Namespace string
+}
+
+// swagger:response GitConfigsResponse
+type GitConfigsResponse struct {
+	// in: body
+	Body models.GitConfigList
+}
+
+// swagger:route POST /namespaces/{Namespace}/gitconfigs gitconfig GitConfigCreate
+// Create the posted new git configuration in the `Namespace`.
+// Importing from a git repository uses the configuration with the longest url prefix of the repository url.
+// responses:
+//   200: GitConfigCreateResponse
+
+// swagger:parameters GitConfigCreate
+type GitConfigCreateParam struct {
+	// in: path
+	Namespace string
+	// in: body
+	GitConfig models.GitConfigCreateRequest
+}
+
+// swagger:response GitConfigCreateResponse
+type GitConfigCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /namespaces/{Namespace}/gitconfigs/{GitConfig} gitconfig GitConfigDelete
+// Delete the named `GitConfig` in the `Namespace`.
+// responses:
+//   200: GitConfigDeleteResponse
+
+// swagger:parameters GitConfigDelete
+type GitConfigDeleteParam struct {
+	// in: path
+	Namespace string
+	// in: path
+	GitConfig string
+}
+
+// swagger:response GitConfigDeleteResponse
+type GitConfigDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
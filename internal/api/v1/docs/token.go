@@ -0,0 +1,61 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /tokens token Tokens
+// Return the personal access tokens of the user.
+// responses:
+//   200: TokensResponse
+
+// swagger:response TokensResponse
+type TokensResponse struct {
+	// in: body
+	Body models.PersonalAccessTokenList
+}
+
+// swagger:route POST /tokens token TokenCreate
+// Create a personal access token for the user. Its value is only returned by this call.
+// responses:
+//   200: TokenCreateResponse
+
+// swagger:parameters TokenCreate
+type TokenCreateParam struct {
+	// in: body
+	Body models.TokenCreateRequest
+}
+
+// swagger:response TokenCreateResponse
+type TokenCreateResponse struct {
+	// in: body
+	Body models.TokenCreateResponse
+}
+
+// swagger:route DELETE /tokens/{Token} token TokenDelete
+// Revoke the personal access token of the user, specified by its name or ID.
+// responses:
+//   200: TokenDeleteResponse
+
+// swagger:parameters TokenDelete
+type TokenDeleteParam struct {
+	// in: path
+	Token string
+}
+
+// swagger:response TokenDeleteResponse
+type TokenDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
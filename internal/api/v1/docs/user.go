@@ -0,0 +1,135 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docs
+
+import "github.com/epinio/epinio/pkg/api/core/v1/models"
+
+//go:generate swagger generate spec
+
+// swagger:route GET /users user Users
+// Return all the users. Restricted to admins.
+// responses:
+//   200: UsersResponse
+
+// swagger:response UsersResponse
+type UsersResponse struct {
+	// in: body
+	Body models.UserList
+}
+
+// swagger:route POST /users user UserCreate
+// Create the posted new user. Restricted to admins.
+// responses:
+//   200: UserCreateResponse
+
+// swagger:parameters UserCreate
+type UserCreateParam struct {
+	// in: body
+	Body models.UserCreateRequest
+}
+
+// swagger:response UserCreateResponse
+type UserCreateResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route GET /users/{Username} user UserShow
+// Return details of the named `Username`. Restricted to admins.
+// responses:
+//   200: UserShowResponse
+
+// swagger:parameters UserShow
+type UserShowParam struct {
+	// in: path
+	Username string
+}
+
+// swagger:response UserShowResponse
+type UserShowResponse struct {
+	// in: body
+	Body models.User
+}
+
+// swagger:route DELETE /users/{Username} user UserDelete
+// Delete the named `Username`, and revoke its personal access tokens. Restricted to admins.
+// responses:
+//   200: UserDeleteResponse
+
+// swagger:parameters UserDelete
+type UserDeleteParam struct {
+	// in: path
+	Username string
+}
+
+// swagger:response UserDeleteResponse
+type UserDeleteResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route PUT /users/{Username}/password user UserPassword
+// Reset the password of the named `Username`. Restricted to admins.
+// responses:
+//   200: UserPasswordResponse
+
+// swagger:parameters UserPassword
+type UserPasswordParam struct {
+	// in: path
+	Username string
+	// in: body
+	Body models.UserPasswordRequest
+}
+
+// swagger:response UserPasswordResponse
+type UserPasswordResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route POST /users/{Username}/namespaces user UserNamespaceGrant
+// Grant the access to the posted namespace to the named `Username`. Restricted to admins.
+// responses:
+//   200: UserNamespaceGrantResponse
+
+// swagger:parameters UserNamespaceGrant
+type UserNamespaceGrantParam struct {
+	// in: path
+	Username string
+	// in: body
+	Body models.UserNamespaceGrantRequest
+}
+
+// swagger:response UserNamespaceGrantResponse
+type UserNamespaceGrantResponse struct {
+	// in: body
+	Body models.Response
+}
+
+// swagger:route DELETE /users/{Username}/namespaces/{Name} user UserNamespaceRevoke
+// Revoke the access to the namespace `Name` from the named `Username`. Restricted to admins.
+// responses:
+//   200: UserNamespaceRevokeResponse
+
+// swagger:parameters UserNamespaceRevoke
+type UserNamespaceRevokeParam struct {
+	// in: path
+	Username string
+	// in: path
+	Name string
+}
+
+// swagger:response UserNamespaceRevokeResponse
+type UserNamespaceRevokeResponse struct {
+	// in: body
+	Body models.Response
+}
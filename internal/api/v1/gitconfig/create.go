@@ -0,0 +1,67 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitconfig
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/gitconfig"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// Create handles the API end point /namespaces/:namespace/gitconfigs
+// It creates the named git configuration from the credentials
+func (gc Controller) Create(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	namespace := c.Param("namespace")
+	username := requestctx.User(ctx).Username
+
+	var createRequest models.GitConfigCreateRequest
+	err := c.BindJSON(&createRequest)
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	err = gitconfig.Validate(createRequest)
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(namespace)
+	}
+
+	err = gitconfig.Create(ctx, cluster, namespace, username, createRequest)
+	if errors.Is(err, gitconfig.ErrExists) {
+		return apierror.NewConflictError("git configuration", createRequest.Name)
+	}
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
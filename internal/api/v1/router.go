@@ -25,9 +25,12 @@ import (
 	"github.com/epinio/epinio/internal/api/v1/configuration"
 	"github.com/epinio/epinio/internal/api/v1/configurationbinding"
 	"github.com/epinio/epinio/internal/api/v1/env"
+	"github.com/epinio/epinio/internal/api/v1/gitconfig"
 	"github.com/epinio/epinio/internal/api/v1/namespace"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/api/v1/service"
+	"github.com/epinio/epinio/internal/api/v1/token"
+	"github.com/epinio/epinio/internal/api/v1/user"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/pkg/api/core/v1/errors"
 )
@@ -37,6 +40,8 @@ const (
 	Root = "/api/v1"
 	// WsRoot is the url path prefix for all websocket API endpoints.
 	WsRoot = "/wapi/v1"
+	// WebhookRoot is the url path prefix for all webhook endpoints.
+	WebhookRoot = "/webhooks"
 )
 
 // APIActionFunc is matched by all actions. Actions can return a list of errors.
@@ -83,29 +88,86 @@ func put(path string, h gin.HandlerFunc) routes.Route {
 // AdminRoutes is the list of restricted routes, only accessible by admins
 var AdminRoutes map[string]struct{} = map[string]struct{}{}
 
+// AdminRouteNames is the list of restricted named routes, only accessible by admins.
+// Unlike AdminRoutes it covers all the paths matching a parameterized route.
+var AdminRouteNames map[string]struct{} = map[string]struct{}{
+	"Audit":               {},
+	"BlobCollection":      {},
+	"Users":               {},
+	"UserCreate":          {},
+	"UserShow":            {},
+	"UserDelete":          {},
+	"UserPassword":        {},
+	"UserNamespaceGrant":  {},
+	"UserNamespaceRevoke": {},
+}
+
 var Routes = routes.NamedRoutes{
 	"Info":      get("/info", errorHandler(Info)),
 	"AuthToken": get("/authtoken", errorHandler(AuthToken)),
+	"Audit":     get("/audit", errorHandler(Audit)),
+
+	// Garbage collection of source blobs, restricted to admins
+	"BlobCollection": get("/blobs/collection", errorHandler(BlobCollection)),
+
+	// Personal access tokens of the user
+	"Tokens":      get("/tokens", errorHandler(token.Controller{}.Index)),
+	"TokenCreate": post("/tokens", errorHandler(token.Controller{}.Create)),
+	"TokenDelete": delete("/tokens/:token", errorHandler(token.Controller{}.Delete)),
+
+	// User management, restricted to admins
+	"Users":               get("/users", errorHandler(user.Controller{}.Index)),
+	"UserCreate":          post("/users", errorHandler(user.Controller{}.Create)),
+	"UserShow":            get("/users/:username", errorHandler(user.Controller{}.Show)),
+	"UserDelete":          delete("/users/:username", errorHandler(user.Controller{}.Delete)),
+	"UserPassword":        put("/users/:username/password", errorHandler(user.Controller{}.Password)),
+	"UserNamespaceGrant":  post("/users/:username/namespaces", errorHandler(user.Controller{}.GrantNamespace)),
+	"UserNamespaceRevoke": delete("/users/:username/namespaces/:name", errorHandler(user.Controller{}.RevokeNamespace)),
 
 	// app controller files see application/*.go
 
 	"AllApps":         get("/applications", errorHandler(application.Controller{}.FullIndex)),
 	"Apps":            get("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Index)),
+	"AppBuilds":       get("/namespaces/:namespace/applications/:app/stages", errorHandler(application.Controller{}.Builds)),
+	"AppCache":        get("/namespaces/:namespace/applications/:app/cache", errorHandler(application.Controller{}.Cache)),
+	"AppCacheClear":   delete("/namespaces/:namespace/applications/:app/cache", errorHandler(application.Controller{}.CacheClear)),
 	"AppCreate":       post("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Create)),
 	"AppShow":         get("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Show)),
-	"StagingComplete": get("/namespaces/:namespace/staging/:stage_id/complete", errorHandler(application.Controller{}.Staged)), // See stage.go
+	"StagingCancel":   delete("/namespaces/:namespace/staging/:stage_id", errorHandler(application.Controller{}.StagingCancel)), // See stage.go
+	"StagingComplete": get("/namespaces/:namespace/staging/:stage_id/complete", errorHandler(application.Controller{}.Staged)),  // See stage.go
 	"AppDelete":       delete("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Delete)),
 	"AppBatchDelete":  delete("/namespaces/:namespace/applications", errorHandler(application.Controller{}.Delete)),
 	"AppDeploy":       post("/namespaces/:namespace/applications/:app/deploy", errorHandler(application.Controller{}.Deploy)),
 	"AppImportGit":    post("/namespaces/:namespace/applications/:app/import-git", errorHandler(application.Controller{}.ImportGit)),
 	"AppPart":         get("/namespaces/:namespace/applications/:app/part/:part", errorHandler(application.Controller{}.GetPart)),
 	"AppRestart":      post("/namespaces/:namespace/applications/:app/restart", errorHandler(application.Controller{}.Restart)),
+	"AppRollback":     post("/namespaces/:namespace/applications/:app/rollback", errorHandler(application.Controller{}.Rollback)),
 	"AppRunning":      get("/namespaces/:namespace/applications/:app/running", errorHandler(application.Controller{}.Running)),
 	"AppStage":        post("/namespaces/:namespace/applications/:app/stage", errorHandler(application.Controller{}.Stage)), // See stage.go
 	"AppUpdate":       patch("/namespaces/:namespace/applications/:app", errorHandler(application.Controller{}.Update)),
 	"AppUpload":       post("/namespaces/:namespace/applications/:app/store", errorHandler(application.Controller{}.Upload)), // See upload.go
 	"AppValidateCV":   get("/namespaces/:namespace/applications/:app/validate-cv", errorHandler(application.Controller{}.ValidateChartValues)),
 
+	// Blue/green and canary deployments of an app, see promote.go
+	"AppPromote": post("/namespaces/:namespace/applications/:app/promote", errorHandler(application.Controller{}.Promote)),
+	"AppAbort":   post("/namespaces/:namespace/applications/:app/abort", errorHandler(application.Controller{}.Abort)),
+
+	// Helm release history of an app, see releases.go
+	"AppReleases":    get("/namespaces/:namespace/applications/:app/releases", errorHandler(application.Controller{}.Releases)),
+	"AppReleaseDiff": get("/namespaces/:namespace/applications/:app/releases/:rev/diff/:rev2", errorHandler(application.Controller{}.ReleaseDiff)),
+
+	// Git webhook of an app, see webhook.go
+	"AppWebhook":        get("/namespaces/:namespace/applications/:app/webhook", errorHandler(application.Controller{}.Webhook)),
+	"AppWebhookEnable":  post("/namespaces/:namespace/applications/:app/webhook", errorHandler(application.Controller{}.WebhookEnable)),
+	"AppWebhookDisable": delete("/namespaces/:namespace/applications/:app/webhook", errorHandler(application.Controller{}.WebhookDisable)),
+
+	// Chunked upload of app sources, see chunkedupload.go
+	"AppUploadInit":     post("/namespaces/:namespace/applications/:app/uploads", errorHandler(application.Controller{}.UploadInit)),
+	"AppUploadStatus":   get("/namespaces/:namespace/applications/:app/uploads/:blob", errorHandler(application.Controller{}.UploadStatus)),
+	"AppUploadPart":     put("/namespaces/:namespace/applications/:app/uploads/:blob/parts/:part", errorHandler(application.Controller{}.UploadPart)),
+	"AppUploadComplete": post("/namespaces/:namespace/applications/:app/uploads/:blob/complete", errorHandler(application.Controller{}.UploadComplete)),
+	"AppUploadAbort":    delete("/namespaces/:namespace/applications/:app/uploads/:blob", errorHandler(application.Controller{}.UploadAbort)),
+
 	"AppMatch":  get("/namespaces/:namespace/appsmatches/:pattern", errorHandler(application.Controller{}.Match)),
 	"AppMatch0": get("/namespaces/:namespace/appsmatches", errorHandler(application.Controller{}.Match)),
 
@@ -151,6 +213,11 @@ var Routes = routes.NamedRoutes{
 	"ConfigurationMatch":  get("/namespaces/:namespace/configurationsmatches/:pattern", errorHandler(configuration.Controller{}.Match)),
 	"ConfigurationMatch0": get("/namespaces/:namespace/configurationsmatches", errorHandler(configuration.Controller{}.Match)),
 
+	// List, create and delete git configurations
+	"GitConfigs":      get("/namespaces/:namespace/gitconfigs", errorHandler(gitconfig.Controller{}.Index)),
+	"GitConfigCreate": post("/namespaces/:namespace/gitconfigs", errorHandler(gitconfig.Controller{}.Create)),
+	"GitConfigDelete": delete("/namespaces/:namespace/gitconfigs/:gitconfig", errorHandler(gitconfig.Controller{}.Delete)),
+
 	// Service Catalog
 	"ServiceCatalog":     get("/catalogservices", errorHandler(service.Controller{}.Catalog)),
 	"ServiceCatalogShow": get("/catalogservices/:catalogservice", errorHandler(service.Controller{}.CatalogShow)),
@@ -196,6 +263,12 @@ var WsRoutes = routes.NamedRoutes{
 	"StagingLogs":    get("/namespaces/:namespace/staging/:stage_id/logs", application.Controller{}.Logs),
 }
 
+// WebhookRoutes are called by external services. They are not authenticated by Epinio,
+// but verified against the secrets of their targets.
+var WebhookRoutes = routes.NamedRoutes{
+	"GitWebhook": post("/git/:namespace/:app", errorHandler(application.Controller{}.GitWebhook)),
+}
+
 // Lemon extends the specified router with the methods and urls
 // handling the API endpoints
 func Lemon(router *gin.RouterGroup) {
@@ -211,3 +284,11 @@ func Spice(router *gin.RouterGroup) {
 		router.Handle(r.Method, r.Path, r.Handler)
 	}
 }
+
+// Ginger extends the specified router with the methods and urls
+// handling the webhook endpoints
+func Ginger(router *gin.RouterGroup) {
+	for _, r := range WebhookRoutes {
+		router.Handle(r.Method, r.Path, r.Handler)
+	}
+}
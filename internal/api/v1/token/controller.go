@@ -0,0 +1,49 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package token contains the API handlers to manage the personal access tokens of the users.
+package token
+
+import (
+	"net/http"
+
+	"github.com/epinio/epinio/internal/auth"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Controller represents all functionality of the API related to personal access tokens
+type Controller struct {
+}
+
+// checkUnscoped returns an error if the user of the request authenticated with a personal access token.
+// Tokens cannot be used to manage tokens, as this would allow to escape their scope.
+func checkUnscoped(c *gin.Context) (auth.User, apierror.APIErrors) {
+	user := requestctx.User(c.Request.Context())
+	if user.Scope != nil {
+		return user, apierror.NewAPIError("personal access tokens cannot be managed with a personal access token", http.StatusForbidden)
+	}
+	return user, nil
+}
+
+func toModel(token auth.Token) models.PersonalAccessToken {
+	return models.PersonalAccessToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Username:   token.Username,
+		Namespaces: token.Namespaces,
+		ReadOnly:   token.ReadOnly,
+		CreatedAt:  metav1.NewTime(token.CreatedAt),
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Create handles the API endpoint /tokens (POST).
+// It creates a personal access token for the user, and returns its value.
+func (tc Controller) Create(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	user, apiErr := checkUnscoped(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	var request models.TokenCreateRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	if request.Name == "" {
+		return apierror.NewBadRequestError("name of token to create not found")
+	}
+
+	// a user cannot grant access to namespaces it has no access to
+	if user.Role != "admin" {
+		for _, namespace := range request.Namespaces {
+			if _, found := user.NamespaceRole(namespace); !found {
+				return apierror.NewAPIError(fmt.Sprintf("namespace '%s' is not accessible by the user", namespace), http.StatusForbidden)
+			}
+		}
+	}
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	tokens, err := authService.GetTokens(ctx, user.Username)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	for _, token := range tokens {
+		if token.Name == request.Name {
+			return apierror.NewConflictError("token", request.Name)
+		}
+	}
+
+	token, value, err := authService.CreateToken(ctx, user.Username, request.Name, request.Namespaces, request.ReadOnly)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OKReturn(c, models.TokenCreateResponse{
+		Token: toModel(token),
+		Value: value,
+	})
+	return nil
+}
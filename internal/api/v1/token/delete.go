@@ -0,0 +1,47 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Delete handles the API endpoint /tokens/:token (DELETE).
+// It revokes the personal access token of the user, specified by its ID or name.
+func (tc Controller) Delete(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	tokenName := c.Param("token")
+
+	user, apiErr := checkUnscoped(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	err = authService.DeleteToken(ctx, user.Username, tokenName)
+	if err != nil {
+		if err == auth.ErrTokenNotFound {
+			return apierror.NewNotFoundError("token", tokenName)
+		}
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
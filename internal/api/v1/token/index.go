@@ -0,0 +1,49 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token
+
+import (
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Index handles the API endpoint /tokens (GET).
+// It returns the personal access tokens of the user.
+func (tc Controller) Index(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	user, apiErr := checkUnscoped(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	tokens, err := authService.GetTokens(ctx, user.Username)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	resp := models.PersonalAccessTokenList{}
+	for _, token := range tokens {
+		resp = append(resp, toModel(token))
+	}
+
+	response.OKReturn(c, resp)
+	return nil
+}
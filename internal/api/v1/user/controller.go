@@ -0,0 +1,71 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package user contains the API handlers to manage the Epinio users. They are restricted to admins.
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Controller represents all functionality of the API related to users
+type Controller struct {
+}
+
+func toModel(user auth.User) models.User {
+	namespaces := []models.UserNamespace{}
+	for _, ns := range user.Namespaces {
+		role, _ := user.NamespaceRole(ns)
+		namespaces = append(namespaces, models.UserNamespace{Name: ns, Role: role})
+	}
+
+	return models.User{
+		Username:   user.Username,
+		Role:       user.Role,
+		Namespaces: namespaces,
+		CreatedAt:  metav1.NewTime(user.CreatedAt),
+	}
+}
+
+func validateRole(role string) apierror.APIErrors {
+	switch role {
+	case "admin", "user":
+		return nil
+	}
+	return apierror.NewBadRequestError(fmt.Sprintf("invalid role '%s', expected 'admin' or 'user'", role))
+}
+
+func validateNamespaceRole(role string) apierror.APIErrors {
+	switch role {
+	case "", auth.NamespaceRoleViewer, auth.NamespaceRoleDeveloper, auth.NamespaceRoleAdmin:
+		return nil
+	}
+	return apierror.NewBadRequestError(fmt.Sprintf("invalid namespace role '%s', expected '%s', '%s' or '%s'",
+		role, auth.NamespaceRoleViewer, auth.NamespaceRoleDeveloper, auth.NamespaceRoleAdmin))
+}
+
+// findUser returns the user with the username, or a not found error
+func findUser(ctx context.Context, authService *auth.AuthService, username string) (auth.User, apierror.APIErrors) {
+	user, err := authService.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == auth.ErrUserNotFound {
+			return user, apierror.NewNotFoundError("user", username)
+		}
+		return user, apierror.InternalError(err)
+	}
+	return user, nil
+}
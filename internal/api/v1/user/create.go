@@ -0,0 +1,84 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Create handles the API endpoint /users (POST).
+// It creates a user, storing the bcrypt hash of its password.
+func (uc Controller) Create(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+
+	var request models.UserCreateRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	if request.Username == "" {
+		return apierror.NewBadRequestError("username of user to create not found")
+	}
+	if request.Password == "" {
+		return apierror.NewBadRequestError("password of user to create not found")
+	}
+	if request.Role == "" {
+		request.Role = "user"
+	}
+	if apiErr := validateRole(request.Role); apiErr != nil {
+		return apiErr
+	}
+
+	user := auth.User{
+		Username: request.Username,
+		Role:     request.Role,
+	}
+	for _, ns := range request.Namespaces {
+		if apiErr := validateNamespaceRole(ns.Role); apiErr != nil {
+			return apiErr
+		}
+		user.SetNamespaceRole(ns.Name, ns.Role)
+	}
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	_, err = authService.GetUserByUsername(ctx, request.Username)
+	if err == nil {
+		return apierror.NewConflictError("user", request.Username)
+	}
+	if err != auth.ErrUserNotFound {
+		return apierror.InternalError(err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return apierror.InternalError(err, "hashing the password")
+	}
+	user.Password = string(hash)
+
+	_, err = authService.SaveUser(ctx, user)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.Created(c)
+	return nil
+}
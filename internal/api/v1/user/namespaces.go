@@ -0,0 +1,106 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	"github.com/epinio/epinio/internal/namespaces"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GrantNamespace handles the API endpoint /users/:username/namespaces (POST).
+// It grants the access to a namespace to the specified user, with the requested role.
+// The role of a namespace already granted is replaced. The namespace has to exist.
+func (uc Controller) GrantNamespace(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	username := c.Param("username")
+
+	var request models.UserNamespaceGrantRequest
+	err := c.BindJSON(&request)
+	if err != nil {
+		return apierror.NewBadRequestError(err.Error())
+	}
+
+	if request.Namespace == "" {
+		return apierror.NewBadRequestError("namespace to grant not found")
+	}
+	if apiErr := validateNamespaceRole(request.Role); apiErr != nil {
+		return apiErr
+	}
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	exists, err := namespaces.Exists(ctx, cluster, request.Namespace)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+	if !exists {
+		return apierror.NamespaceIsNotKnown(request.Namespace)
+	}
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	user, apiErr := findUser(ctx, authService, username)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	user.SetNamespaceRole(request.Namespace, request.Role)
+
+	err = authService.UpdateUser(ctx, user)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
+
+// RevokeNamespace handles the API endpoint /users/:username/namespaces/:name (DELETE).
+// It revokes the access to the namespace from the specified user.
+func (uc Controller) RevokeNamespace(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	username := c.Param("username")
+	namespace := c.Param("name")
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	user, apiErr := findUser(ctx, authService, username)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if !user.RemoveNamespace(namespace) {
+		return apierror.NewNotFoundError("user namespace", namespace)
+	}
+
+	err = authService.UpdateUser(ctx, user)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	response.OK(c)
+	return nil
+}
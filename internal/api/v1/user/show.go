@@ -0,0 +1,39 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"github.com/epinio/epinio/internal/api/v1/response"
+	"github.com/epinio/epinio/internal/auth"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Show handles the API endpoint /users/:username (GET).
+// It returns the details of the specified user.
+func (uc Controller) Show(c *gin.Context) apierror.APIErrors {
+	ctx := c.Request.Context()
+	username := c.Param("username")
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	user, apiErr := findUser(ctx, authService, username)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	response.OKReturn(c, toModel(user))
+	return nil
+}
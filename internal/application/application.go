@@ -22,12 +22,12 @@ import (
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/helpers/kubernetes/tailer"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/internal/helm"
 	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/namespaces"
-	"github.com/epinio/epinio/internal/s3manager"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 
@@ -379,17 +379,12 @@ func ImageURL(app *unstructured.Unstructured) (string, error) {
 }
 
 // Unstage removes staging resources. It deletes either all Jobs of the named application,
-// or all but stageIDCurrent. It also deletes the staged objects from the S3 storage
+// or all but stageIDCurrent. It also deletes the staged objects from the blob storage
 // except for the current one.
 func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageIDCurrent string) error {
-	s3ConnectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
-		helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+	s3m, err := blobstore.New(ctx, cluster)
 	if err != nil {
-		return errors.Wrap(err, "fetching the S3 connection details from the Kubernetes secret")
-	}
-	s3m, err := s3manager.New(s3ConnectionDetails)
-	if err != nil {
-		return errors.Wrap(err, "creating an S3 manager")
+		return errors.Wrap(err, "accessing the blob storage")
 	}
 
 	jobs, err := cluster.ListJobs(ctx, helmchart.Namespace(),
@@ -409,6 +404,16 @@ func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.App
 			continue
 		}
 
+		// Preserve the outcome of the job in the build history before removing it.
+		// Not when the whole application is removed (no current stage id), the
+		// history goes away with it. Errors are only logged, the history is
+		// informational.
+		if stageIDCurrent != "" {
+			if err := recordJobOutcome(ctx, cluster, appRef, job); err != nil {
+				requestctx.Logger(ctx).Error(err, "failed to record build outcome", "job", job.Name)
+			}
+		}
+
 		err := cluster.DeleteJob(ctx, job.ObjectMeta.Namespace, job.ObjectMeta.Name)
 		if err != nil {
 			return err
@@ -436,6 +441,88 @@ func Unstage(ctx context.Context, cluster *kubernetes.Cluster, appRef models.App
 	return nil
 }
 
+// CancelStaging stops the running staging job with the given stage id, removes the job and
+// its environment, marks the build as cancelled in the build history, and points the
+// application back to the stage id it had before. It returns the application the job was
+// staging. The deployed workload of the application is not touched.
+func CancelStaging(ctx context.Context, cluster *kubernetes.Cluster, namespace, stageID string) (models.AppRef, error) {
+	var appRef models.AppRef
+
+	jobs, err := cluster.ListJobs(ctx, helmchart.Namespace(),
+		fmt.Sprintf("app.kubernetes.io/component=staging,app.kubernetes.io/part-of=%s,%s=%s",
+			namespace, models.EpinioStageIDLabel, stageID))
+	if err != nil {
+		return appRef, err
+	}
+	if len(jobs.Items) == 0 {
+		return appRef, ErrStagingNotFound
+	}
+
+	job := jobs.Items[0]
+	appRef = models.NewAppRef(job.Labels["app.kubernetes.io/name"], namespace)
+
+	if status, _ := jobOutcome(job); status != models.BuildRunning {
+		return appRef, ErrStagingDone
+	}
+
+	err = cluster.DeleteJob(ctx, job.ObjectMeta.Namespace, job.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return appRef, err
+	}
+
+	// And the associated secret holding the job environment
+	err = cluster.DeleteSecret(ctx, job.ObjectMeta.Namespace, job.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return appRef, err
+	}
+
+	now := metav1.Now()
+	err = BuildFinished(ctx, cluster, appRef, stageID, models.BuildCancelled, &now)
+	if err != nil {
+		return appRef, errors.Wrap(err, "recording the cancelled build")
+	}
+
+	err = restoreStageID(ctx, cluster, appRef, stageID, job.Labels[models.EpinioStageIDPrevious])
+	if err != nil {
+		return appRef, errors.Wrap(err, "restoring the previous stage id")
+	}
+
+	return appRef, nil
+}
+
+// restoreStageID points the application resource back to the stage id it had before the
+// cancelled staging. Nothing is done when the application moved on to another stage id.
+func restoreStageID(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, cancelledID, previousID string) error {
+	app, err := Get(ctx, cluster, appRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	current, err := StageID(app)
+	if err != nil {
+		return err
+	}
+	if current != cancelledID {
+		return nil
+	}
+
+	if err := unstructured.SetNestedField(app.Object, previousID, "spec", "stageid"); err != nil {
+		return err
+	}
+
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Update(ctx, app, metav1.UpdateOptions{})
+
+	return err
+}
+
 // Logs method writes log lines to the specified logChan. The caller can stop the logging
 // with the ctx cancelFunc. It's also the callers responsibility to close the logChan when
 // done.  When stageID is an empty string, no staging logs are returned. If it is set,
@@ -558,6 +645,35 @@ func fetch(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) er
 		return errors.Wrap(err, "finding settings")
 	}
 
+	rollback, err := Rollback(applicationCR)
+	if err != nil {
+		return errors.Wrap(err, "finding rollback")
+	}
+
+	candidate, err := Candidate(applicationCR)
+	if err != nil {
+		return errors.Wrap(err, "finding candidate")
+	}
+
+	var deployment *models.AppDeploymentStrategy
+	if _, ok := applicationCR.GetAnnotations()[models.EpinioDeploymentStrategyAnnotation]; ok {
+		strategy, err := DeploymentStrategy(applicationCR)
+		if err != nil {
+			return errors.Wrap(err, "finding deployment strategy")
+		}
+		deployment = &strategy
+	}
+
+	healthChecks, err := HealthChecks(applicationCR)
+	if err != nil {
+		return errors.Wrap(err, "finding health checks")
+	}
+
+	resources, err := Resources(applicationCR)
+	if err != nil {
+		return errors.Wrap(err, "finding resources")
+	}
+
 	app.Meta.CreatedAt = applicationCR.GetCreationTimestamp()
 
 	app.Configuration.Instances = &instances
@@ -566,9 +682,14 @@ func fetch(ctx context.Context, cluster *kubernetes.Cluster, app *models.App) er
 	app.Configuration.Routes = desiredRoutes
 	app.Configuration.AppChart = chartName
 	app.Configuration.Settings = settings
+	app.Configuration.Deployment = deployment
+	app.Configuration.HealthChecks = healthChecks
+	app.Configuration.Resources = resources
 	app.Origin = origin
 	app.StageID = stageID
 	app.ImageURL = imageURL
+	app.Rollback = rollback
+	app.Candidate = candidate
 
 	// Check if app is active, and if yes, fill the associated parts.  May have to
 	// straighten the workload structure a bit further.
@@ -0,0 +1,295 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/blobstore"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Every upload of application sources creates a new blob in the blob storage. The blob
+// collector removes the blobs no application refers to anymore. Objects in the storage
+// which were not uploaded by Epinio are never removed. A blob is referenced as
+// the current sources of an application, by one of the most recent builds of an
+// application, or by a staging job. Unreferenced blobs are kept for a while, as they may
+// be uploaded sources whose staging has not started yet.
+
+// BlobRetention determines which of the unreferenced blobs are removed.
+type BlobRetention struct {
+	// KeepStages is the number of most recent builds of each application whose
+	// blobs are kept.
+	KeepStages int
+	// MaxAge is the age after which unreferenced blobs are removed.
+	MaxAge time.Duration
+}
+
+// CollectBlobs removes the blobs which are not referenced, and older than the maximum age
+// of the retention. In a dry run nothing is removed. The report lists the blobs kept and
+// removed, and why.
+func CollectBlobs(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, retention BlobRetention, dryRun bool) (models.BlobCollectionReport, error) {
+	report := models.BlobCollectionReport{}
+
+	manager, err := blobstore.New(ctx, cluster)
+	if err != nil {
+		return report, errors.Wrap(err, "accessing the blob storage")
+	}
+
+	// Determine the references before listing the blobs. A blob uploaded and staged in
+	// between is younger than the maximum age, and kept.
+	referenced, err := ReferencedBlobs(ctx, cluster, retention.KeepStages)
+	if err != nil {
+		return report, err
+	}
+
+	blobs, err := manager.ListBlobs(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	report = planBlobCollection(blobs, referenced, time.Now(), retention)
+	report = keepForeignBlobs(report, func(blobUID string) (map[string]string, error) {
+		return manager.Meta(ctx, blobUID)
+	})
+	report.DryRun = dryRun
+	if dryRun {
+		return report, nil
+	}
+
+	if uploads, ok := manager.(blobstore.StaleUploadCollector); ok && retention.MaxAge > 0 {
+		removed, err := uploads.RemoveStaleUploads(ctx, time.Now().Add(-retention.MaxAge))
+		if err != nil {
+			return report, errors.Wrap(err, "removing stale uploads")
+		}
+		if removed > 0 {
+			logger.Info("removed stale uploads", "count", removed)
+		}
+	}
+
+	for _, blob := range report.Removed {
+		if err := manager.DeleteObject(ctx, blob.BlobUID); err != nil {
+			return report, errors.Wrapf(err, "removing blob %s", blob.BlobUID)
+		}
+
+		logger.Info("removed unreferenced blob", "blobUID", blob.BlobUID, "size", blob.Size)
+	}
+
+	return report, nil
+}
+
+// ReferencedBlobs returns the blobs referenced by the applications and the staging jobs,
+// with the reason for the reference. The blobs of the keepStages most recent builds of
+// each application are considered referenced.
+func ReferencedBlobs(ctx context.Context, cluster *kubernetes.Cluster, keepStages int) (map[string]string, error) {
+	referenced := map[string]string{}
+	reference := func(blobUID, reason string) {
+		if _, ok := referenced[blobUID]; !ok && blobUID != "" {
+			referenced[blobUID] = reason
+		}
+	}
+
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := client.Namespace("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range apps.Items {
+		appRef := models.NewAppRef(app.GetName(), app.GetNamespace())
+		appID := appRef.Namespace + "/" + appRef.Name
+
+		blobUID, _, err := unstructured.NestedString(app.UnstructuredContent(), "spec", "blobuid")
+		if err != nil {
+			return nil, errors.Wrapf(err, "blobuid of app %s", appID)
+		}
+		reference(blobUID, "current sources of app "+appID)
+
+		// Do not create the history where it is missing.
+		secret, err := cluster.GetSecret(ctx, appRef.Namespace, appRef.MakeBuildsSecretName())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "build history of app %s", appID)
+		}
+
+		builds, err := decodeBuilds(secret.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "build history of app %s", appID)
+		}
+
+		for i, build := range builds {
+			if i >= keepStages {
+				break
+			}
+			reference(build.BlobUID, fmt.Sprintf("stage %s of app %s", build.StageID, appID))
+		}
+	}
+
+	jobs, err := stagingJobs(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		reference(job.Labels[models.EpinioStageBlobUIDLabel], fmt.Sprintf("staging job %s", job.Name))
+	}
+
+	return referenced, nil
+}
+
+// planBlobCollection sorts the blobs into the kept and the removed ones, oldest first.
+func planBlobCollection(blobs []models.BlobInfo, referenced map[string]string, now time.Time, retention BlobRetention) models.BlobCollectionReport {
+	report := models.BlobCollectionReport{
+		KeepStages: retention.KeepStages,
+		MaxAge:     retention.MaxAge.String(),
+		Kept:       []models.BlobInfo{},
+		Removed:    []models.BlobInfo{},
+	}
+
+	sorted := make([]models.BlobInfo, len(blobs))
+	copy(sorted, blobs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LastModified.Before(sorted[j].LastModified)
+	})
+
+	for _, info := range sorted {
+		if reason, ok := referenced[info.BlobUID]; ok {
+			info.Reason = reason
+			report.Kept = append(report.Kept, info)
+			continue
+		}
+
+		if now.Sub(info.LastModified) <= retention.MaxAge {
+			info.Reason = "unreferenced, younger than " + retention.MaxAge.String()
+			report.Kept = append(report.Kept, info)
+			continue
+		}
+
+		info.Reason = "unreferenced"
+		report.Removed = append(report.Removed, info)
+		report.RemovedBytes += info.Size
+	}
+
+	return report
+}
+
+// keepForeignBlobs moves the blobs not written by Epinio from the removed to the kept
+// ones. The bucket of an external S3 storage may be shared with other users. Epinio
+// writes every blob with the application, namespace, and user it was uploaded for.
+func keepForeignBlobs(report models.BlobCollectionReport, meta func(blobUID string) (map[string]string, error)) models.BlobCollectionReport {
+	removed := []models.BlobInfo{}
+	for _, info := range report.Removed {
+		metadata, err := meta(info.BlobUID)
+		if err != nil || !isEpinioBlob(metadata) {
+			info.Reason = "not an epinio blob"
+			if err != nil {
+				info.Reason = "unknown meta data: " + err.Error()
+			}
+			report.Kept = append(report.Kept, info)
+			report.RemovedBytes -= info.Size
+			continue
+		}
+		removed = append(removed, info)
+	}
+	report.Removed = removed
+
+	return report
+}
+
+// isEpinioBlob returns true if the blob metadata is that of an upload by Epinio.
+func isEpinioBlob(metadata map[string]string) bool {
+	for _, key := range []string{"App", "Namespace", "Username"} {
+		if _, ok := metadata[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// BlobCollector periodically removes the unreferenced blobs.
+type BlobCollector struct {
+	logger    logr.Logger
+	retention BlobRetention
+	interval  time.Duration
+	stop      chan struct{}
+	done      sync.WaitGroup
+}
+
+// NewBlobCollector returns a collector removing the blobs per the retention, checking
+// every interval.
+func NewBlobCollector(logger logr.Logger, retention BlobRetention, interval time.Duration) *BlobCollector {
+	return &BlobCollector{
+		logger:    logger.WithName("BlobCollector"),
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start runs the collector in the background, until Stop is called.
+func (bc *BlobCollector) Start() {
+	bc.done.Add(1)
+
+	go func() {
+		defer bc.done.Done()
+
+		ticker := time.NewTicker(bc.interval)
+		defer ticker.Stop()
+
+		for {
+			bc.collect()
+
+			select {
+			case <-bc.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop terminates the collector and waits for it to finish.
+func (bc *BlobCollector) Stop() {
+	close(bc.stop)
+	bc.done.Wait()
+}
+
+func (bc *BlobCollector) collect() {
+	ctx := context.Background()
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		bc.logger.Error(err, "failed to get access to a kube client")
+		return
+	}
+
+	_, err = CollectBlobs(ctx, cluster, bc.logger, bc.retention, false)
+	if err != nil {
+		bc.logger.Error(err, "failed to collect unreferenced blobs")
+	}
+}
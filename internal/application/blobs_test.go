@@ -0,0 +1,107 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("planBlobCollection", func() {
+	now := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	retention := BlobRetention{KeepStages: 3, MaxAge: 24 * time.Hour}
+
+	blob := func(uid string, age time.Duration) models.BlobInfo {
+		return models.BlobInfo{BlobUID: uid, Size: 100, LastModified: now.Add(-age)}
+	}
+
+	It("removes the unreferenced blobs older than the maximum age", func() {
+		blobs := []models.BlobInfo{
+			blob("young", time.Hour),
+			blob("old", 48*time.Hour),
+			blob("older", 72*time.Hour),
+		}
+
+		report := planBlobCollection(blobs, map[string]string{}, now, retention)
+		Expect(report.Removed).To(HaveLen(2))
+		Expect(report.Removed[0].BlobUID).To(Equal("older"))
+		Expect(report.Removed[1].BlobUID).To(Equal("old"))
+		Expect(report.RemovedBytes).To(Equal(int64(200)))
+
+		Expect(report.Kept).To(HaveLen(1))
+		Expect(report.Kept[0].BlobUID).To(Equal("young"))
+		Expect(report.Kept[0].Reason).To(ContainSubstring("younger than"))
+	})
+
+	It("keeps the referenced blobs regardless of their age", func() {
+		blobs := []models.BlobInfo{
+			blob("current", 30*24*time.Hour),
+			blob("orphan", 30*24*time.Hour),
+		}
+		referenced := map[string]string{
+			"current": "current sources of app workspace/sample",
+			"missing": "stage 42 of app workspace/sample",
+		}
+
+		report := planBlobCollection(blobs, referenced, now, retention)
+		Expect(report.Kept).To(HaveLen(1))
+		Expect(report.Kept[0].BlobUID).To(Equal("current"))
+		Expect(report.Kept[0].Reason).To(Equal("current sources of app workspace/sample"))
+
+		Expect(report.Removed).To(HaveLen(1))
+		Expect(report.Removed[0].BlobUID).To(Equal("orphan"))
+	})
+
+	It("reports the retention", func() {
+		report := planBlobCollection(nil, nil, now, retention)
+		Expect(report.KeepStages).To(Equal(3))
+		Expect(report.MaxAge).To(Equal("24h0m0s"))
+		Expect(report.Kept).To(BeEmpty())
+		Expect(report.Removed).To(BeEmpty())
+	})
+})
+
+var _ = Describe("keepForeignBlobs", func() {
+	It("keeps the blobs not written by epinio", func() {
+		report := models.BlobCollectionReport{
+			Removed: []models.BlobInfo{
+				{BlobUID: "epinio", Size: 100},
+				{BlobUID: "foreign", Size: 200},
+				{BlobUID: "broken", Size: 300},
+			},
+			RemovedBytes: 600,
+		}
+		meta := func(blobUID string) (map[string]string, error) {
+			switch blobUID {
+			case "epinio":
+				return map[string]string{"App": "sample", "Namespace": "workspace", "Username": "admin"}, nil
+			case "foreign":
+				return map[string]string{"Owner": "someone"}, nil
+			}
+			return nil, errors.New("stat failed")
+		}
+
+		report = keepForeignBlobs(report, meta)
+		Expect(report.Removed).To(HaveLen(1))
+		Expect(report.Removed[0].BlobUID).To(Equal("epinio"))
+		Expect(report.RemovedBytes).To(Equal(int64(100)))
+
+		Expect(report.Kept).To(HaveLen(2))
+		Expect(report.Kept[0].Reason).To(Equal("not an epinio blob"))
+		Expect(report.Kept[1].Reason).To(ContainSubstring("stat failed"))
+	})
+})
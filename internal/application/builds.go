@@ -0,0 +1,268 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apibatchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+var (
+	// ErrStagingNotFound is returned when no staging job exists for a stage id.
+	ErrStagingNotFound = errors.New("staging not found")
+	// ErrStagingDone is returned when a staging job has already finished.
+	ErrStagingDone = errors.New("staging already finished")
+)
+
+// buildHistoryLimit is the maximum number of builds kept in the history of an
+// application. Older builds are dropped.
+const buildHistoryLimit = 50
+
+// Builds returns the build history of the application, most recent first. Builds still
+// marked as queued or running are reconciled with the state of their staging jobs first.
+func Builds(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.AppBuildList, error) {
+	secret, err := buildsLoad(ctx, cluster, appRef)
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := decodeBuilds(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := cluster.ListJobs(ctx, helmchart.Namespace(),
+		fmt.Sprintf("app.kubernetes.io/name=%s,app.kubernetes.io/part-of=%s",
+			appRef.Name, appRef.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	jobOf := map[string]apibatchv1.Job{}
+	for _, job := range jobs.Items {
+		jobOf[job.Labels[models.EpinioStageIDLabel]] = job
+	}
+
+	for i, build := range builds {
+		if build.Status != models.BuildQueued && build.Status != models.BuildRunning {
+			continue
+		}
+
+		status, finishedAt := models.BuildUnknown, (*metav1.Time)(nil)
+		if job, ok := jobOf[build.StageID]; ok {
+			status, finishedAt = jobStatus(job)
+		}
+		if status == build.Status {
+			continue
+		}
+
+		if status == models.BuildRunning {
+			err = BuildAdmitted(ctx, cluster, appRef, build.StageID)
+		} else {
+			err = BuildFinished(ctx, cluster, appRef, build.StageID, status, finishedAt)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		builds[i].Status = status
+		builds[i].FinishedAt = finishedAt
+	}
+
+	return builds, nil
+}
+
+// BuildStarted records a new staging run in the build history of the application.
+func BuildStarted(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, build models.AppBuild) error {
+	return buildsUpdate(ctx, cluster, appRef, func(buildsSecret *v1.Secret) error {
+		data, err := json.Marshal(build)
+		if err != nil {
+			return err
+		}
+		buildsSecret.Data[build.StageID] = data
+
+		return pruneBuilds(buildsSecret.Data, buildHistoryLimit)
+	})
+}
+
+// BuildAdmitted records in the build history of the application that the queued staging
+// run was admitted, i.e. that it is running now. Unknown stage ids, and builds which are
+// not queued anymore, are ignored.
+func BuildAdmitted(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageID string) error {
+	return buildUpdate(ctx, cluster, appRef, stageID, func(build *models.AppBuild) {
+		if build.Status == models.BuildQueued {
+			build.Status = models.BuildRunning
+		}
+	})
+}
+
+// BuildFinished records the outcome of a staging run in the build history of the
+// application. Unknown stage ids are ignored.
+func BuildFinished(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageID, status string, finishedAt *metav1.Time) error {
+	return buildUpdate(ctx, cluster, appRef, stageID, func(build *models.AppBuild) {
+		build.Status = status
+		build.FinishedAt = finishedAt
+	})
+}
+
+// buildUpdate modifies a single build in the build history of the application. Unknown
+// stage ids are ignored.
+func buildUpdate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageID string, modifyBuild func(*models.AppBuild)) error {
+	return buildsUpdate(ctx, cluster, appRef, func(buildsSecret *v1.Secret) error {
+		data, ok := buildsSecret.Data[stageID]
+		if !ok {
+			return nil
+		}
+
+		var build models.AppBuild
+		if err := json.Unmarshal(data, &build); err != nil {
+			return errors.Wrapf(err, "decoding build %s", stageID)
+		}
+
+		modifyBuild(&build)
+
+		data, err := json.Marshal(build)
+		if err != nil {
+			return err
+		}
+		buildsSecret.Data[stageID] = data
+
+		return nil
+	})
+}
+
+// recordJobOutcome records the outcome of the staging job in the build history of its
+// application. It is used before the job is removed.
+func recordJobOutcome(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, job apibatchv1.Job) error {
+	status, finishedAt := jobOutcome(job)
+	if status == models.BuildRunning {
+		// The job is removed while still running. Its outcome will never be known.
+		status = models.BuildUnknown
+	}
+
+	return BuildFinished(ctx, cluster, appRef, job.Labels[models.EpinioStageIDLabel], status, finishedAt)
+}
+
+// jobStatus determines the build status of a staging job like jobOutcome, reporting a
+// staging job waiting in the queue as queued.
+func jobStatus(job apibatchv1.Job) (string, *metav1.Time) {
+	status, finishedAt := jobOutcome(job)
+	if status == models.BuildRunning && IsQueued(job) {
+		return models.BuildQueued, nil
+	}
+
+	return status, finishedAt
+}
+
+// jobOutcome determines the build status of a staging job from its conditions, and the
+// time the job finished, if it did.
+func jobOutcome(job apibatchv1.Job) (string, *metav1.Time) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case apibatchv1.JobComplete:
+			if job.Status.CompletionTime != nil {
+				return models.BuildSucceeded, job.Status.CompletionTime
+			}
+			finishedAt := condition.LastTransitionTime
+			return models.BuildSucceeded, &finishedAt
+		case apibatchv1.JobFailed:
+			finishedAt := condition.LastTransitionTime
+			return models.BuildFailed, &finishedAt
+		}
+	}
+
+	return models.BuildRunning, nil
+}
+
+// decodeBuilds returns the builds stored in the secret data, most recent first.
+func decodeBuilds(data map[string][]byte) (models.AppBuildList, error) {
+	builds := models.AppBuildList{}
+
+	for stageID, value := range data {
+		var build models.AppBuild
+		if err := json.Unmarshal(value, &build); err != nil {
+			return nil, errors.Wrapf(err, "decoding build %s", stageID)
+		}
+		builds = append(builds, build)
+	}
+
+	sort.SliceStable(builds, func(i, j int) bool {
+		if builds[i].StartedAt.Equal(&builds[j].StartedAt) {
+			return builds[i].StageID < builds[j].StageID
+		}
+		return builds[j].StartedAt.Before(&builds[i].StartedAt)
+	})
+
+	return builds, nil
+}
+
+// pruneBuilds removes the oldest builds from the secret data, keeping at most limit.
+func pruneBuilds(data map[string][]byte, limit int) error {
+	builds, err := decodeBuilds(data)
+	if err != nil {
+		return err
+	}
+
+	for i := limit; i < len(builds); i++ {
+		delete(data, builds[i].StageID)
+	}
+
+	return nil
+}
+
+// buildsUpdate is a helper for the public functions. It encapsulates the read/modify/write
+// cycle necessary to update the application's kube resource holding the build history.
+func buildsUpdate(ctx context.Context, cluster *kubernetes.Cluster,
+	appRef models.AppRef, modifyBuilds func(*v1.Secret) error) error {
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		buildsSecret, err := buildsLoad(ctx, cluster, appRef)
+		if err != nil {
+			return err
+		}
+
+		if buildsSecret.Data == nil {
+			buildsSecret.Data = map[string][]byte{}
+		}
+
+		if err := modifyBuilds(buildsSecret); err != nil {
+			return err
+		}
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(
+			ctx, buildsSecret, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// buildsLoad locates and returns the kube secret storing the referenced application's build
+// history. If necessary it creates that secret.
+func buildsLoad(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (*v1.Secret, error) {
+	secretName := appRef.MakeBuildsSecretName()
+	return loadOrCreateSecret(ctx, cluster, appRef, secretName, "builds")
+}
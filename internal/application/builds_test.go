@@ -0,0 +1,143 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	apibatchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build history", func() {
+	start := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	buildData := func(stageID string, startedAt time.Time) []byte {
+		data, err := json.Marshal(models.AppBuild{
+			StageID:   stageID,
+			Status:    models.BuildSucceeded,
+			StartedAt: metav1.NewTime(startedAt),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		return data
+	}
+
+	Describe("decodeBuilds", func() {
+		It("returns the builds most recent first", func() {
+			builds, err := decodeBuilds(map[string][]byte{
+				"a": buildData("a", start),
+				"b": buildData("b", start.Add(2*time.Hour)),
+				"c": buildData("c", start.Add(time.Hour)),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(builds).To(HaveLen(3))
+			Expect(builds[0].StageID).To(Equal("b"))
+			Expect(builds[1].StageID).To(Equal("c"))
+			Expect(builds[2].StageID).To(Equal("a"))
+		})
+
+		It("fails for bad data", func() {
+			_, err := decodeBuilds(map[string][]byte{"a": []byte("{")})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("pruneBuilds", func() {
+		It("drops the oldest builds beyond the limit", func() {
+			data := map[string][]byte{
+				"a": buildData("a", start),
+				"b": buildData("b", start.Add(2*time.Hour)),
+				"c": buildData("c", start.Add(time.Hour)),
+			}
+
+			Expect(pruneBuilds(data, 2)).To(Succeed())
+			Expect(data).To(HaveLen(2))
+			Expect(data).To(HaveKey("b"))
+			Expect(data).To(HaveKey("c"))
+		})
+	})
+
+	Describe("jobOutcome", func() {
+		It("reports running jobs", func() {
+			status, finishedAt := jobOutcome(apibatchv1.Job{})
+			Expect(status).To(Equal(models.BuildRunning))
+			Expect(finishedAt).To(BeNil())
+		})
+
+		It("reports completed jobs", func() {
+			completion := metav1.NewTime(start)
+			job := apibatchv1.Job{
+				Status: apibatchv1.JobStatus{
+					CompletionTime: &completion,
+					Conditions: []apibatchv1.JobCondition{
+						{Type: apibatchv1.JobComplete, Status: v1.ConditionTrue},
+					},
+				},
+			}
+
+			status, finishedAt := jobOutcome(job)
+			Expect(status).To(Equal(models.BuildSucceeded))
+			Expect(finishedAt).To(Equal(&completion))
+		})
+
+		It("reports failed jobs", func() {
+			job := apibatchv1.Job{
+				Status: apibatchv1.JobStatus{
+					Conditions: []apibatchv1.JobCondition{
+						{Type: apibatchv1.JobFailed, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(start)},
+					},
+				},
+			}
+
+			status, finishedAt := jobOutcome(job)
+			Expect(status).To(Equal(models.BuildFailed))
+			Expect(finishedAt.Time).To(Equal(start))
+		})
+	})
+	Describe("jobStatus", func() {
+		It("reports suspended jobs as queued", func() {
+			job := apibatchv1.Job{Spec: apibatchv1.JobSpec{Suspend: pointer.Bool(true)}}
+
+			status, finishedAt := jobStatus(job)
+			Expect(status).To(Equal(models.BuildQueued))
+			Expect(finishedAt).To(BeNil())
+		})
+
+		It("reports admitted jobs as running", func() {
+			job := apibatchv1.Job{Spec: apibatchv1.JobSpec{Suspend: pointer.Bool(false)}}
+
+			status, _ := jobStatus(job)
+			Expect(status).To(Equal(models.BuildRunning))
+		})
+
+		It("reports the outcome of finished jobs", func() {
+			job := apibatchv1.Job{
+				Spec: apibatchv1.JobSpec{Suspend: pointer.Bool(true)},
+				Status: apibatchv1.JobStatus{
+					Conditions: []apibatchv1.JobCondition{
+						{Type: apibatchv1.JobFailed, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(start)},
+					},
+				},
+			}
+
+			status, _ := jobStatus(job)
+			Expect(status).To(Equal(models.BuildFailed))
+		})
+	})
+})
@@ -0,0 +1,288 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CacheComponent is the value of the `app.kubernetes.io/component` label placed on
+	// the build cache PVCs.
+	CacheComponent = "staging-cache"
+	// CacheLastStagedAnnotation records the time of the last staging run which used
+	// the build cache. It is the basis for the garbage collection of stale caches.
+	CacheLastStagedAnnotation = "epinio.io/last-staged"
+)
+
+// ErrCacheBusy is returned when the build cache cannot be used or removed because it is
+// still in use or in the process of being removed.
+var ErrCacheBusy = errors.New("build cache is in use")
+
+// EnsureCache creates the PVC holding the build cache of the application if one doesn't
+// already exist, and records the current time as the time of its last use by staging.
+func EnsureCache(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) error {
+	pvcs := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace())
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	pvc, err := pvcs.Get(ctx, appRef.MakePVCName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) { // Unknown error, irrelevant to non-existence
+		return err
+	}
+	if err == nil { // pvc already exists
+		if pvc.DeletionTimestamp != nil {
+			return errors.Wrap(ErrCacheBusy, "build cache is being cleared, try again later")
+		}
+
+		// Older PVCs carry no labels yet. Add them, so that the garbage collector
+		// can find them.
+		if pvc.Labels == nil {
+			pvc.Labels = map[string]string{}
+		}
+		for key, value := range cacheLabels(appRef) {
+			pvc.Labels[key] = value
+		}
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[CacheLastStagedAnnotation] = now
+
+		_, err = pvcs.Update(ctx, pvc, metav1.UpdateOptions{})
+		return err
+	}
+
+	// From here on, only if the PVC is missing
+	_, err = pvcs.Create(ctx, &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appRef.MakePVCName(),
+			Namespace: helmchart.Namespace(),
+			Labels:    cacheLabels(appRef),
+			Annotations: map[string]string{
+				CacheLastStagedAnnotation: now,
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: map[v1.ResourceName]resource.Quantity{
+					v1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+
+	return err
+}
+
+// Cache returns information about the build cache of the application.
+func Cache(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (models.AppCache, error) {
+	cache := models.AppCache{
+		Name: appRef.MakePVCName(),
+	}
+
+	pvc, err := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace()).
+		Get(ctx, appRef.MakePVCName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return cache, nil
+		}
+		return cache, err
+	}
+
+	cache.Exists = true
+	cache.Phase = string(pvc.Status.Phase)
+	if pvc.DeletionTimestamp != nil {
+		cache.Phase = "Terminating"
+	}
+	if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+		cache.Capacity = capacity.String()
+	}
+	cache.CreatedAt = pvc.CreationTimestamp
+	if lastStaged, ok := cacheLastStaged(*pvc); ok {
+		cache.LastStaged = metav1.NewTime(lastStaged)
+	}
+
+	return cache, nil
+}
+
+// ClearCache removes the build cache of the application. The next staging run starts
+// with an empty cache. It is an error to clear the cache while the application is
+// staging.
+func ClearCache(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) error {
+	staging, err := CurrentlyStaging(ctx, cluster, appRef.Namespace, appRef.Name)
+	if err != nil {
+		return err
+	}
+	if staging {
+		return errors.Wrap(ErrCacheBusy, "application is staging")
+	}
+
+	err = deleteStagePVC(ctx, cluster, appRef)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// StaleCaches returns the build cache PVCs from the list whose last staging run is older
+// than the given maximum age. PVCs without a last-staged annotation are aged by their
+// creation time.
+func StaleCaches(pvcs []v1.PersistentVolumeClaim, now time.Time, maxAge time.Duration) []v1.PersistentVolumeClaim {
+	stale := []v1.PersistentVolumeClaim{}
+
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+
+		lastStaged, ok := cacheLastStaged(pvc)
+		if !ok {
+			lastStaged = pvc.CreationTimestamp.Time
+		}
+
+		if now.Sub(lastStaged) > maxAge {
+			stale = append(stale, pvc)
+		}
+	}
+
+	return stale
+}
+
+// CollectCaches removes the build caches of all applications which have not staged
+// within the given maximum age. Applications which are currently staging are skipped.
+func CollectCaches(ctx context.Context, cluster *kubernetes.Cluster, logger logr.Logger, maxAge time.Duration) error {
+	pvcs := cluster.Kubectl.CoreV1().PersistentVolumeClaims(helmchart.Namespace())
+
+	list, err := pvcs.List(ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/component=" + CacheComponent,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pvc := range StaleCaches(list.Items, time.Now(), maxAge) {
+		appRef := models.NewAppRef(pvc.Labels["app.kubernetes.io/name"], pvc.Labels["app.kubernetes.io/part-of"])
+
+		err := ClearCache(ctx, cluster, appRef)
+		if errors.Is(err, ErrCacheBusy) {
+			logger.V(1).Info("skipping busy cache", "pvc", pvc.Name)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "clearing cache %s", pvc.Name)
+		}
+
+		logger.Info("removed stale cache", "pvc", pvc.Name, "namespace", appRef.Namespace, "app", appRef.Name)
+	}
+
+	return nil
+}
+
+// CacheCollector periodically removes the stale build caches.
+type CacheCollector struct {
+	logger   logr.Logger
+	maxAge   time.Duration
+	interval time.Duration
+	stop     chan struct{}
+	done     sync.WaitGroup
+}
+
+// NewCacheCollector returns a collector removing the build caches of applications which
+// have not staged within maxAge, checking every interval.
+func NewCacheCollector(logger logr.Logger, maxAge, interval time.Duration) *CacheCollector {
+	return &CacheCollector{
+		logger:   logger.WithName("CacheCollector"),
+		maxAge:   maxAge,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start runs the collector in the background, until Stop is called.
+func (cc *CacheCollector) Start() {
+	cc.done.Add(1)
+
+	go func() {
+		defer cc.done.Done()
+
+		ticker := time.NewTicker(cc.interval)
+		defer ticker.Stop()
+
+		for {
+			cc.collect()
+
+			select {
+			case <-cc.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop terminates the collector and waits for it to finish.
+func (cc *CacheCollector) Stop() {
+	close(cc.stop)
+	cc.done.Wait()
+}
+
+func (cc *CacheCollector) collect() {
+	ctx := context.Background()
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		cc.logger.Error(err, "failed to get access to a kube client")
+		return
+	}
+
+	err = CollectCaches(ctx, cluster, cc.logger, cc.maxAge)
+	if err != nil {
+		cc.logger.Error(err, "failed to collect stale caches")
+	}
+}
+
+func cacheLabels(appRef models.AppRef) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       appRef.Name,
+		"app.kubernetes.io/part-of":    appRef.Namespace,
+		"app.kubernetes.io/managed-by": "epinio",
+		"app.kubernetes.io/component":  CacheComponent,
+	}
+}
+
+func cacheLastStaged(pvc v1.PersistentVolumeClaim) (time.Time, bool) {
+	value, ok := pvc.Annotations[CacheLastStagedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	lastStaged, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return lastStaged, true
+}
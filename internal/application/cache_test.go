@@ -0,0 +1,74 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StaleCaches", func() {
+	now := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	maxAge := 7 * 24 * time.Hour
+
+	pvc := func(name string, created time.Time, lastStaged string) v1.PersistentVolumeClaim {
+		p := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+		if lastStaged != "" {
+			p.Annotations = map[string]string{CacheLastStagedAnnotation: lastStaged}
+		}
+		return p
+	}
+
+	It("returns the caches which have not staged within the maximum age", func() {
+		old := now.Add(-30 * 24 * time.Hour)
+
+		pvcs := []v1.PersistentVolumeClaim{
+			pvc("recent", old, now.Add(-24*time.Hour).Format(time.RFC3339)),
+			pvc("stale", old, now.Add(-8*24*time.Hour).Format(time.RFC3339)),
+		}
+
+		stale := StaleCaches(pvcs, now, maxAge)
+		Expect(stale).To(HaveLen(1))
+		Expect(stale[0].Name).To(Equal("stale"))
+	})
+
+	It("falls back to the creation time when the last staging is not recorded", func() {
+		pvcs := []v1.PersistentVolumeClaim{
+			pvc("new", now.Add(-time.Hour), ""),
+			pvc("old", now.Add(-10*24*time.Hour), ""),
+			pvc("broken", now.Add(-10*24*time.Hour), "not a time"),
+		}
+
+		stale := StaleCaches(pvcs, now, maxAge)
+		Expect(stale).To(HaveLen(2))
+		Expect(stale[0].Name).To(Equal("old"))
+		Expect(stale[1].Name).To(Equal("broken"))
+	})
+
+	It("skips caches which are already being removed", func() {
+		p := pvc("terminating", now.Add(-10*24*time.Hour), "")
+		deleted := metav1.NewTime(now)
+		p.DeletionTimestamp = &deleted
+
+		Expect(StaleCaches([]v1.PersistentVolumeClaim{p}, now, maxAge)).To(BeEmpty())
+	})
+})
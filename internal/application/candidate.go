@@ -0,0 +1,331 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helm"
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
+)
+
+// helmReleaseAnnotation records the owning release of each resource created by helm.
+const helmReleaseAnnotation = "meta.helm.sh/release-name"
+
+const (
+	// nginxIngressController is the controller of the ingress classes of ingress-nginx.
+	// It is the only controller understanding the canary annotations.
+	nginxIngressController = "k8s.io/ingress-nginx"
+	// defaultIngressClassAnnotation marks the ingress class used by ingresses without
+	// an ingressClassName.
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+)
+
+// ErrCanaryUnsupported is returned when the ingress controller of the applications cannot
+// split the traffic of a route between two releases.
+var ErrCanaryUnsupported = errors.New("canary deployments require the ingress-nginx controller")
+
+// ErrReleaseNotReady is returned when the deployment of a release does not become ready in
+// time.
+var ErrReleaseNotReady = errors.New("release is not ready")
+
+// DeploymentStrategy returns the deployment strategy recorded on the application resource.
+// Without one the application is deployed rolling.
+func DeploymentStrategy(app *unstructured.Unstructured) (models.AppDeploymentStrategy, error) {
+	strategy := models.AppDeploymentStrategy{
+		Strategy: models.DeploymentStrategyRolling,
+	}
+
+	value, ok := app.GetAnnotations()[models.EpinioDeploymentStrategyAnnotation]
+	if !ok || value == "" {
+		return strategy, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &strategy); err != nil {
+		return strategy, errors.Wrap(err, "deployment strategy annotation is not valid")
+	}
+
+	return strategy, nil
+}
+
+// SetDeploymentStrategy records the deployment strategy on the application resource. It
+// takes effect with the next deployment of a new build.
+func SetDeploymentStrategy(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, strategy models.AppDeploymentStrategy) error {
+	data, err := json.Marshal(strategy)
+	if err != nil {
+		return err
+	}
+
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioDeploymentStrategyAnnotation: string(data),
+	})
+}
+
+// Candidate returns the candidate recorded on the application resource, if any.
+func Candidate(app *unstructured.Unstructured) (*models.AppCandidate, error) {
+	value, ok := app.GetAnnotations()[models.EpinioCandidateAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	candidate := &models.AppCandidate{}
+	if err := json.Unmarshal([]byte(value), candidate); err != nil {
+		return nil, errors.Wrap(err, "candidate annotation is not valid")
+	}
+
+	return candidate, nil
+}
+
+// SetCandidate records the candidate on the application resource.
+func SetCandidate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, candidate models.AppCandidate) error {
+	data, err := json.Marshal(candidate)
+	if err != nil {
+		return err
+	}
+
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioCandidateAnnotation: string(data),
+	})
+}
+
+// LiveRelease returns the helm release serving the routes of the application. This is the
+// release named after the application, until the promotion of a candidate deployed to the
+// alternate release.
+func LiveRelease(app *unstructured.Unstructured) string {
+	if release := app.GetAnnotations()[models.EpinioLiveReleaseAnnotation]; release != "" {
+		return release
+	}
+	return names.ReleaseName(app.GetName())
+}
+
+// CandidateRelease returns the helm release to deploy a candidate to, i.e. the release of
+// the application which is not live.
+func CandidateRelease(appName, liveRelease string) string {
+	if liveRelease == names.AlternateReleaseName(appName) {
+		return names.ReleaseName(appName)
+	}
+	return names.AlternateReleaseName(appName)
+}
+
+// PromoteCandidate makes the release of the candidate the live release of the application,
+// and removes the candidate from the application resource.
+func PromoteCandidate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, candidate models.AppCandidate) error {
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioLiveReleaseAnnotation: candidate.Release,
+		models.EpinioCandidateAnnotation:   nil,
+	})
+}
+
+// AbortCandidate points the application resource back to the image and stage id of the
+// live release, and removes the candidate.
+func AbortCandidate(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, stageID, imageURL string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				models.EpinioCandidateAnnotation: nil,
+			},
+		},
+		"spec": map[string]interface{}{
+			"stageid":  stageID,
+			"imageurl": imageURL,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Patch(ctx, appRef.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
+
+// SetCanary shifts the traffic of the deployed canary, i.e. sets the percentage of the
+// traffic of their routes the ingresses of the named application release receive. The app
+// chart creates these ingresses as canary, see helm.ChartParameters. A zero weight removes
+// the marking, turning the ingresses into regular ones.
+func SetCanary(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, release string, weight int32) error {
+	ingressList, err := ingressListForApp(ctx, cluster, appRef)
+	if err != nil {
+		return err
+	}
+
+	annotations := map[string]interface{}{
+		helm.CanaryAnnotation:       nil,
+		helm.CanaryWeightAnnotation: nil,
+	}
+	if weight > 0 {
+		annotations[helm.CanaryAnnotation] = "true"
+		annotations[helm.CanaryWeightAnnotation] = fmt.Sprintf("%d", weight)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, ingress := range ingressList.Items {
+		if ingress.Annotations[helmReleaseAnnotation] != release {
+			continue
+		}
+
+		_, err := cluster.Kubectl.NetworkingV1().Ingresses(appRef.Namespace).Patch(ctx,
+			ingress.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "marking ingress %s as canary", ingress.Name)
+		}
+	}
+
+	return nil
+}
+
+// CheckCanarySupport returns ErrCanaryUnsupported unless the ingresses of the applications
+// are served by ingress-nginx. Other controllers ignore the canary annotations, and serve
+// both releases side by side without any weighting.
+func CheckCanarySupport(ctx context.Context, cluster *kubernetes.Cluster) error {
+	return checkCanarySupport(ctx, cluster.Kubectl.NetworkingV1().IngressClasses(),
+		viper.GetString("ingress-class-name"))
+}
+
+// checkCanarySupport checks the controller of the named ingress class, or of the default
+// ingress class if no name is given.
+func checkCanarySupport(ctx context.Context, ingressClasses networkingv1client.IngressClassInterface, name string) error {
+	var class *networkingv1.IngressClass
+
+	if name != "" {
+		named, err := ingressClasses.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return errors.Wrapf(ErrCanaryUnsupported, "ingress class %s does not exist", name)
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading the ingress class")
+		}
+		class = named
+	} else {
+		list, err := ingressClasses.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "listing the ingress classes")
+		}
+		for i := range list.Items {
+			if list.Items[i].Annotations[defaultIngressClassAnnotation] == "true" {
+				class = &list.Items[i]
+				break
+			}
+		}
+		if class == nil {
+			return errors.Wrap(ErrCanaryUnsupported, "there is no default ingress class")
+		}
+	}
+
+	if class.Spec.Controller != nginxIngressController {
+		return errors.Wrapf(ErrCanaryUnsupported, "ingress class %s is served by %s",
+			class.Name, class.Spec.Controller)
+	}
+
+	return nil
+}
+
+// WaitForRelease waits until the deployments of the named application release are ready,
+// i.e. all their replicas run the current revision and are available. It returns
+// ErrReleaseNotReady if they are not ready within the timeout.
+func WaitForRelease(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, release string, timeout time.Duration) error {
+	selector := labels.Set(map[string]string{
+		"app.kubernetes.io/name":    appRef.Name,
+		"app.kubernetes.io/part-of": appRef.Namespace,
+	}).AsSelector().String()
+
+	err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		deployments, err := cluster.Kubectl.AppsV1().Deployments(appRef.Namespace).List(ctx,
+			metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, err
+		}
+
+		return releaseReady(deployments.Items, release), nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return errors.Wrapf(ErrReleaseNotReady, "release %s", release)
+	}
+
+	return err
+}
+
+// releaseReady returns true if the release has deployments, and all are ready.
+func releaseReady(deployments []appsv1.Deployment, release string) bool {
+	found := false
+	for _, deployment := range deployments {
+		if deployment.Annotations[helmReleaseAnnotation] != release {
+			continue
+		}
+		found = true
+
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		status := deployment.Status
+		if status.ObservedGeneration < deployment.Generation ||
+			status.UpdatedReplicas < desired ||
+			status.AvailableReplicas < desired ||
+			status.Replicas > status.UpdatedReplicas {
+			return false
+		}
+	}
+
+	return found
+}
+
+// patchAnnotations merges the given annotations into the application resource. A nil
+// value removes the annotation.
+func patchAnnotations(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Namespace(appRef.Namespace).Patch(ctx, appRef.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err
+}
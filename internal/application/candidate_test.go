@@ -0,0 +1,194 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/internal/names"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Candidate", func() {
+	var app *unstructured.Unstructured
+
+	BeforeEach(func() {
+		app = &unstructured.Unstructured{Object: map[string]interface{}{}}
+		app.SetName("foo")
+	})
+
+	Describe("DeploymentStrategy", func() {
+		It("defaults to rolling", func() {
+			strategy, err := DeploymentStrategy(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy.Strategy).To(Equal(models.DeploymentStrategyRolling))
+		})
+
+		It("decodes the recorded strategy", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioDeploymentStrategyAnnotation: `{"strategy":"canary","weight":20}`,
+			})
+
+			strategy, err := DeploymentStrategy(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy).To(Equal(models.AppDeploymentStrategy{
+				Strategy: models.DeploymentStrategyCanary,
+				Weight:   20,
+			}))
+		})
+	})
+
+	Describe("Candidate", func() {
+		It("returns nothing for an application without candidate", func() {
+			candidate, err := Candidate(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidate).To(BeNil())
+		})
+
+		It("decodes the recorded candidate", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioCandidateAnnotation: `{"strategy":"bluegreen","stage_id":"s2","from_stage_id":"s1"}`,
+			})
+
+			candidate, err := Candidate(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(candidate.Strategy).To(Equal(models.DeploymentStrategyBlueGreen))
+			Expect(candidate.StageID).To(Equal("s2"))
+			Expect(candidate.FromStageID).To(Equal("s1"))
+		})
+	})
+
+	Describe("LiveRelease", func() {
+		It("defaults to the release named after the application", func() {
+			Expect(LiveRelease(app)).To(Equal(names.ReleaseName("foo")))
+		})
+
+		It("returns the recorded release", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioLiveReleaseAnnotation: names.AlternateReleaseName("foo"),
+			})
+			Expect(LiveRelease(app)).To(Equal(names.AlternateReleaseName("foo")))
+		})
+	})
+
+	Describe("CandidateRelease", func() {
+		It("alternates between the two releases of the application", func() {
+			Expect(CandidateRelease("foo", names.ReleaseName("foo"))).To(Equal(names.AlternateReleaseName("foo")))
+			Expect(CandidateRelease("foo", names.AlternateReleaseName("foo"))).To(Equal(names.ReleaseName("foo")))
+		})
+	})
+
+	Describe("checkCanarySupport", func() {
+		ctx := context.Background()
+
+		ingressClass := func(name, controller string, isDefault bool) *networkingv1.IngressClass {
+			class := &networkingv1.IngressClass{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       networkingv1.IngressClassSpec{Controller: controller},
+			}
+			if isDefault {
+				class.Annotations = map[string]string{defaultIngressClassAnnotation: "true"}
+			}
+			return class
+		}
+
+		It("accepts a named ingress-nginx class", func() {
+			classes := fake.NewSimpleClientset(
+				ingressClass("nginx", nginxIngressController, false),
+				ingressClass("traefik", "traefik.io/ingress-controller", true),
+			).NetworkingV1().IngressClasses()
+
+			Expect(checkCanarySupport(ctx, classes, "nginx")).To(Succeed())
+		})
+
+		It("rejects other ingress controllers", func() {
+			classes := fake.NewSimpleClientset(
+				ingressClass("nginx", nginxIngressController, false),
+				ingressClass("traefik", "traefik.io/ingress-controller", true),
+			).NetworkingV1().IngressClasses()
+
+			err := checkCanarySupport(ctx, classes, "traefik")
+			Expect(errors.Is(err, ErrCanaryUnsupported)).To(BeTrue())
+
+			err = checkCanarySupport(ctx, classes, "")
+			Expect(errors.Is(err, ErrCanaryUnsupported)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("traefik.io/ingress-controller"))
+		})
+
+		It("uses the default ingress class", func() {
+			classes := fake.NewSimpleClientset(
+				ingressClass("nginx", nginxIngressController, true),
+			).NetworkingV1().IngressClasses()
+
+			Expect(checkCanarySupport(ctx, classes, "")).To(Succeed())
+		})
+
+		It("rejects missing ingress classes", func() {
+			classes := fake.NewSimpleClientset().NetworkingV1().IngressClasses()
+
+			err := checkCanarySupport(ctx, classes, "nginx")
+			Expect(errors.Is(err, ErrCanaryUnsupported)).To(BeTrue())
+
+			err = checkCanarySupport(ctx, classes, "")
+			Expect(errors.Is(err, ErrCanaryUnsupported)).To(BeTrue())
+		})
+	})
+
+	Describe("releaseReady", func() {
+		deployment := func(release string, updated, available int32) appsv1.Deployment {
+			return appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation:  2,
+					Annotations: map[string]string{helmReleaseAnnotation: release},
+				},
+				Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 2,
+					Replicas:           2,
+					UpdatedReplicas:    updated,
+					AvailableReplicas:  available,
+				},
+			}
+		}
+
+		It("is ready when all replicas of the release are updated and available", func() {
+			deployments := []appsv1.Deployment{
+				deployment("live", 0, 0),
+				deployment("candidate", 2, 2),
+			}
+			Expect(releaseReady(deployments, "candidate")).To(BeTrue())
+			Expect(releaseReady(deployments, "live")).To(BeFalse())
+		})
+
+		It("is not ready while replicas are unavailable or not observed", func() {
+			Expect(releaseReady([]appsv1.Deployment{deployment("candidate", 2, 1)}, "candidate")).To(BeFalse())
+
+			stale := deployment("candidate", 2, 2)
+			stale.Status.ObservedGeneration = 1
+			Expect(releaseReady([]appsv1.Deployment{stale}, "candidate")).To(BeFalse())
+		})
+
+		It("is not ready without deployments", func() {
+			Expect(releaseReady([]appsv1.Deployment{deployment("live", 2, 2)}, "candidate")).To(BeFalse())
+		})
+	})
+})
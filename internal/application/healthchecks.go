@@ -0,0 +1,63 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// HealthChecksEnabled returns true if the server hands the health checks of the applications
+// to the app chart, as `epinio.probes` values. The app chart has to support them.
+func HealthChecksEnabled() bool {
+	return viper.GetBool("app-health-checks")
+}
+
+// HealthChecks returns the health checks recorded on the application resource, if any.
+func HealthChecks(app *unstructured.Unstructured) (*models.AppHealthChecks, error) {
+	value, ok := app.GetAnnotations()[models.EpinioHealthChecksAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	checks := &models.AppHealthChecks{}
+	if err := json.Unmarshal([]byte(value), checks); err != nil {
+		return nil, errors.Wrap(err, "health checks annotation is not valid")
+	}
+
+	return checks, nil
+}
+
+// SetHealthChecks records the health checks on the application resource. Empty health
+// checks remove the record, handing the probes back to the app chart.
+func SetHealthChecks(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, checks models.AppHealthChecks) error {
+	if checks.IsEmpty() {
+		return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+			models.EpinioHealthChecksAnnotation: nil,
+		})
+	}
+
+	data, err := json.Marshal(checks)
+	if err != nil {
+		return err
+	}
+
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioHealthChecksAnnotation: string(data),
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Health checks", func() {
+
+	Describe("HealthChecks", func() {
+		var app *unstructured.Unstructured
+
+		BeforeEach(func() {
+			app = &unstructured.Unstructured{Object: map[string]interface{}{}}
+			app.SetName("foo")
+		})
+
+		It("is nil without a record", func() {
+			checks, err := HealthChecks(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checks).To(BeNil())
+		})
+
+		It("decodes the recorded checks", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioHealthChecksAnnotation: `{"readiness":{"httpPath":"/healthz","failureThreshold":5}}`,
+			})
+			checks, err := HealthChecks(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checks).To(Equal(&models.AppHealthChecks{
+				Readiness: &models.AppProbe{HTTPPath: "/healthz", FailureThreshold: 5},
+			}))
+		})
+
+		It("fails for a bad record", func() {
+			app.SetAnnotations(map[string]string{
+				models.EpinioHealthChecksAnnotation: `{`,
+			})
+			_, err := HealthChecks(app)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("probeFailures", func() {
+		pod := func(name string, ready bool) corev1.Pod {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				},
+			}
+		}
+		event := func(podName, message string, minute int) corev1.Event {
+			return corev1.Event{
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: podName},
+				Reason:         "Unhealthy",
+				Message:        message,
+				LastTimestamp:  metav1.NewTime(time.Date(2023, 3, 1, 10, minute, 0, 0, time.UTC)),
+			}
+		}
+
+		It("reports the latest failure of each probe of pods which are not ready", func() {
+			failures := probeFailures(
+				[]corev1.Pod{pod("a", false), pod("b", true)},
+				[]corev1.Event{
+					event("a", "Readiness probe failed: HTTP probe failed with statuscode: 500", 1),
+					event("a", "Readiness probe failed: HTTP probe failed with statuscode: 503", 2),
+					event("a", "Liveness probe failed: dial tcp 10.0.0.1:8080: connect: connection refused", 1),
+					event("b", "Startup probe failed: command failed", 3),
+				})
+			Expect(failures).To(Equal([]string{
+				"Liveness probe failed: dial tcp 10.0.0.1:8080: connect: connection refused",
+				"Readiness probe failed: HTTP probe failed with statuscode: 503",
+			}))
+		})
+
+		It("is empty for events of other reasons", func() {
+			e := event("a", "Back-off restarting failed container", 1)
+			e.Reason = "BackOff"
+			Expect(probeFailures([]corev1.Pod{pod("a", false)}, []corev1.Event{e})).To(BeEmpty())
+		})
+	})
+})
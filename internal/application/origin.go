@@ -71,6 +71,15 @@ func Origin(app *unstructured.Unstructured) (models.ApplicationOrigin, error) {
 			return result, errors.New("bad container origin, empty string")
 		}
 
+		// And the optional digest the image was pinned to.
+		digest, found, err := unstructured.NestedString(origin, "digest")
+		if found {
+			if err != nil {
+				return result, err
+			}
+			result.Digest = digest
+		}
+
 		result.Kind = models.OriginContainer
 		result.Container = container
 		return result, nil
@@ -97,6 +106,22 @@ func Origin(app *unstructured.Unstructured) (models.ApplicationOrigin, error) {
 			result.Git.Revision = revision
 		}
 
+		// And the optional sub directory, and submodule flag.
+		subdir, found, err := unstructured.NestedString(origin, "git", "subdir")
+		if found {
+			if err != nil {
+				return result, err
+			}
+			result.Git.Subdir = subdir
+		}
+		submodules, found, err := unstructured.NestedBool(origin, "git", "submodules")
+		if found {
+			if err != nil {
+				return result, err
+			}
+			result.Git.Submodules = submodules
+		}
+
 		result.Kind = models.OriginGit
 		result.Git.URL = repository
 		return result, nil
@@ -67,6 +67,14 @@ var _ = Describe("Build body for SetOrigin PATCH", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(string(body)).To(MatchJSON(`[{"op":"replace","path":"/spec/origin","value":{"Kind":3,"container":"my-container"}}]`))
 			})
+
+			It("includes the digest the container was pinned to", func() {
+				origin.Digest = "sha256:1234"
+				body, err := buildBodyPatch(origin)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(body)).To(MatchJSON(`[{"op":"replace","path":"/spec/origin","value":{"Kind":3,"container":"my-container","digest":"sha256:1234"}}]`))
+			})
 		})
 
 		When("origin is Git", func() {
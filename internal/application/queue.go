@@ -0,0 +1,347 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	apibatchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+// The staging queue is kept in the cluster itself. Staging jobs are created suspended,
+// and admitted, i.e. resumed, in order of creation as long as the number of running
+// staging jobs stays within the configured limits. The admission is serialized across the
+// replicas of the server through a Lease in the epinio namespace.
+
+// StagingLimits caps the number of concurrently running staging jobs, across the whole
+// cluster, and per namespace. A zero means no limit.
+type StagingLimits struct {
+	Global       int
+	PerNamespace int
+}
+
+// Enabled returns true if any limit is set, i.e. if staging jobs have to be queued.
+func (l StagingLimits) Enabled() bool {
+	return l.Global > 0 || l.PerNamespace > 0
+}
+
+const (
+	// admissionLeaseName names the Lease serializing the admission of queued staging
+	// jobs across all replicas of the server.
+	admissionLeaseName = "epinio-staging-admission"
+	// admissionLeaseDuration is the time after which the lease of a replica which
+	// failed to release it is taken over.
+	admissionLeaseDuration = 30 * time.Second
+	// admissionLeaseTimeout is the time to wait for the lease. When it is not
+	// acquired in time the admission is left to the replica holding it, and to the
+	// periodic StagingAdmitter.
+	admissionLeaseTimeout = 10 * time.Second
+)
+
+var (
+	// admissionMutex serializes the admission of queued staging jobs within the server.
+	admissionMutex sync.Mutex
+	// admissionHolder identifies this server replica as holder of the admission lease.
+	admissionHolder = uuid.NewString()
+)
+
+// IsQueued returns true if the staging job is waiting in the queue.
+func IsQueued(job apibatchv1.Job) bool {
+	return job.Spec.Suspend != nil && *job.Spec.Suspend
+}
+
+// AdmitStagings resumes as many of the queued staging jobs as the limits allow, oldest
+// first.
+func AdmitStagings(ctx context.Context, cluster *kubernetes.Cluster, limits StagingLimits) error {
+	admissionMutex.Lock()
+	defer admissionMutex.Unlock()
+
+	leases := cluster.Kubectl.CoordinationV1().Leases(helmchart.Namespace())
+
+	err := wait.PollImmediate(200*time.Millisecond, admissionLeaseTimeout, func() (bool, error) {
+		return acquireAdmissionLease(ctx, leases, admissionHolder, time.Now())
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = releaseAdmissionLease(context.Background(), leases, admissionHolder)
+	}()
+
+	jobs, err := stagingJobs(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range planAdmission(jobs, limits) {
+		_, err := cluster.Kubectl.BatchV1().Jobs(job.Namespace).Patch(ctx, job.Name,
+			types.MergePatchType, []byte(`{"spec":{"suspend":false}}`), metav1.PatchOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// The history is reconciled with the job when it is listed, a failure to record
+		// the admission is not fatal.
+		appRef := models.NewAppRef(job.Labels["app.kubernetes.io/name"], job.Labels["app.kubernetes.io/part-of"])
+		err = BuildAdmitted(ctx, cluster, appRef, job.Labels[models.EpinioStageIDLabel])
+		if err != nil {
+			requestctx.Logger(ctx).Error(err, "failed to record the admitted build", "job", job.Name)
+		}
+	}
+
+	return nil
+}
+
+// acquireAdmissionLease tries to take the admission lease for the holder. It returns false
+// while another holder has the lease. Concurrent attempts are decided by the optimistic
+// concurrency of the cluster, only one of them succeeds.
+func acquireAdmissionLease(ctx context.Context, leases coordinationv1client.LeaseInterface, holder string, now time.Time) (bool, error) {
+	renew := metav1.NewMicroTime(now)
+	duration := int32(admissionLeaseDuration.Seconds())
+
+	lease, err := leases.Get(ctx, admissionLeaseName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: admissionLeaseName,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &renew,
+				RenewTime:            &renew,
+			},
+		}, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if held := lease.Spec.HolderIdentity; held != nil && *held != "" && *held != holder &&
+		lease.Spec.RenewTime != nil &&
+		lease.Spec.RenewTime.Add(admissionLeaseDuration).After(now) {
+		return false, nil
+	}
+
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.AcquireTime = &renew
+	lease.Spec.RenewTime = &renew
+
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// releaseAdmissionLease gives the admission lease up, if the holder has it.
+func releaseAdmissionLease(ctx context.Context, leases coordinationv1client.LeaseInterface, holder string) error {
+	lease, err := leases.Get(ctx, admissionLeaseName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return nil
+	}
+
+	lease.Spec.HolderIdentity = nil
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+
+	return err
+}
+
+// StagingQueuePosition returns the position of the staging job in the queue, starting at
+// 1. It returns 0 if the job is not queued.
+func StagingQueuePosition(ctx context.Context, cluster *kubernetes.Cluster, stageID string) (int, error) {
+	jobs, err := stagingJobs(ctx, cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	return queuePosition(jobs, stageID), nil
+}
+
+// WaitForAdmission waits until the named staging job leaves the queue.
+func WaitForAdmission(ctx context.Context, cluster *kubernetes.Cluster, jobName string) error {
+	return wait.PollImmediateUntil(time.Second, func() (bool, error) {
+		job, err := cluster.Kubectl.BatchV1().Jobs(helmchart.Namespace()).Get(ctx, jobName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return !IsQueued(*job), nil
+	}, ctx.Done())
+}
+
+// planAdmission returns the queued jobs which can be resumed without exceeding the limits,
+// oldest first.
+func planAdmission(jobs []apibatchv1.Job, limits StagingLimits) []apibatchv1.Job {
+	active := 0
+	activeIn := map[string]int{}
+
+	for _, job := range jobs {
+		if !IsQueued(job) && isStaging(job) {
+			active++
+			activeIn[job.Labels["app.kubernetes.io/part-of"]]++
+		}
+	}
+
+	admit := []apibatchv1.Job{}
+	for _, job := range queuedJobs(jobs) {
+		if limits.Global > 0 && active >= limits.Global {
+			break
+		}
+
+		namespace := job.Labels["app.kubernetes.io/part-of"]
+		if limits.PerNamespace > 0 && activeIn[namespace] >= limits.PerNamespace {
+			continue
+		}
+
+		admit = append(admit, job)
+		active++
+		activeIn[namespace]++
+	}
+
+	return admit
+}
+
+// queuePosition returns the position of the staging job in the queue, starting at 1. It
+// returns 0 if the job is not queued.
+func queuePosition(jobs []apibatchv1.Job, stageID string) int {
+	for i, job := range queuedJobs(jobs) {
+		if job.Labels[models.EpinioStageIDLabel] == stageID {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// queuedJobs returns the queued jobs, oldest first.
+func queuedJobs(jobs []apibatchv1.Job) []apibatchv1.Job {
+	queued := []apibatchv1.Job{}
+	for _, job := range jobs {
+		if IsQueued(job) && isStaging(job) {
+			queued = append(queued, job)
+		}
+	}
+
+	sort.SliceStable(queued, func(i, j int) bool {
+		return queued[i].CreationTimestamp.Before(&queued[j].CreationTimestamp)
+	})
+
+	return queued
+}
+
+// isStaging returns true if the staging job has not finished yet.
+func isStaging(job apibatchv1.Job) bool {
+	status, _ := jobOutcome(job)
+	return status == models.BuildRunning
+}
+
+func stagingJobs(ctx context.Context, cluster *kubernetes.Cluster) ([]apibatchv1.Job, error) {
+	jobList, err := cluster.ListJobs(ctx, helmchart.Namespace(), "app.kubernetes.io/component=staging")
+	if err != nil {
+		return nil, err
+	}
+
+	return jobList.Items, nil
+}
+
+// StagingAdmitter periodically admits queued staging jobs. It picks up the capacity freed
+// by finished jobs.
+type StagingAdmitter struct {
+	logger   logr.Logger
+	limits   StagingLimits
+	interval time.Duration
+	stop     chan struct{}
+	done     sync.WaitGroup
+}
+
+// NewStagingAdmitter returns an admitter for the given limits, checking every interval.
+func NewStagingAdmitter(logger logr.Logger, limits StagingLimits, interval time.Duration) *StagingAdmitter {
+	return &StagingAdmitter{
+		logger:   logger.WithName("StagingAdmitter"),
+		limits:   limits,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start runs the admitter in the background, until Stop is called.
+func (sa *StagingAdmitter) Start() {
+	sa.done.Add(1)
+
+	go func() {
+		defer sa.done.Done()
+
+		ticker := time.NewTicker(sa.interval)
+		defer ticker.Stop()
+
+		for {
+			sa.admit()
+
+			select {
+			case <-sa.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop terminates the admitter and waits for it to finish.
+func (sa *StagingAdmitter) Stop() {
+	close(sa.stop)
+	sa.done.Wait()
+}
+
+func (sa *StagingAdmitter) admit() {
+	ctx := context.Background()
+
+	cluster, err := kubernetes.GetCluster(ctx)
+	if err != nil {
+		sa.logger.Error(err, "failed to get access to a kube client")
+		return
+	}
+
+	err = AdmitStagings(ctx, cluster, sa.limits)
+	if err != nil {
+		sa.logger.Error(err, "failed to admit queued stagings")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	apibatchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staging queue", func() {
+	start := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	job := func(stageID, namespace string, created time.Duration, queued bool) apibatchv1.Job {
+		return apibatchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "stage-" + stageID,
+				CreationTimestamp: metav1.NewTime(start.Add(created)),
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of": namespace,
+					models.EpinioStageIDLabel:   stageID,
+				},
+			},
+			Spec: apibatchv1.JobSpec{
+				Suspend: pointer.Bool(queued),
+			},
+		}
+	}
+
+	finished := func(job apibatchv1.Job) apibatchv1.Job {
+		job.Status.Conditions = []apibatchv1.JobCondition{
+			{Type: apibatchv1.JobComplete, Status: v1.ConditionTrue},
+		}
+		return job
+	}
+
+	stageIDs := func(jobs []apibatchv1.Job) []string {
+		ids := []string{}
+		for _, job := range jobs {
+			ids = append(ids, job.Labels[models.EpinioStageIDLabel])
+		}
+		return ids
+	}
+
+	Describe("planAdmission", func() {
+		It("admits the oldest queued jobs up to the global limit", func() {
+			jobs := []apibatchv1.Job{
+				job("a", "ns1", 0, false),
+				job("c", "ns1", 2*time.Minute, true),
+				job("b", "ns2", time.Minute, true),
+				job("d", "ns2", 3*time.Minute, true),
+			}
+
+			admit := planAdmission(jobs, StagingLimits{Global: 3})
+			Expect(stageIDs(admit)).To(Equal([]string{"b", "c"}))
+		})
+
+		It("does not count finished jobs", func() {
+			jobs := []apibatchv1.Job{
+				finished(job("a", "ns1", 0, false)),
+				job("b", "ns1", time.Minute, true),
+			}
+
+			admit := planAdmission(jobs, StagingLimits{Global: 1})
+			Expect(stageIDs(admit)).To(Equal([]string{"b"}))
+		})
+
+		It("skips namespaces at their limit", func() {
+			jobs := []apibatchv1.Job{
+				job("a", "ns1", 0, false),
+				job("b", "ns1", time.Minute, true),
+				job("c", "ns2", 2*time.Minute, true),
+				job("d", "ns2", 3*time.Minute, true),
+			}
+
+			admit := planAdmission(jobs, StagingLimits{PerNamespace: 1})
+			Expect(stageIDs(admit)).To(Equal([]string{"c"}))
+		})
+
+		It("applies both limits", func() {
+			jobs := []apibatchv1.Job{
+				job("a", "ns1", 0, true),
+				job("b", "ns1", time.Minute, true),
+				job("c", "ns2", 2*time.Minute, true),
+				job("d", "ns3", 3*time.Minute, true),
+			}
+
+			admit := planAdmission(jobs, StagingLimits{Global: 2, PerNamespace: 1})
+			Expect(stageIDs(admit)).To(Equal([]string{"a", "c"}))
+		})
+	})
+
+	Describe("queuePosition", func() {
+		jobs := []apibatchv1.Job{
+			job("a", "ns1", 0, false),
+			job("c", "ns2", 2*time.Minute, true),
+			job("b", "ns1", time.Minute, true),
+		}
+
+		It("returns the position in creation order", func() {
+			Expect(queuePosition(jobs, "b")).To(Equal(1))
+			Expect(queuePosition(jobs, "c")).To(Equal(2))
+		})
+
+		It("returns 0 for jobs not queued", func() {
+			Expect(queuePosition(jobs, "a")).To(Equal(0))
+			Expect(queuePosition(jobs, "x")).To(Equal(0))
+		})
+	})
+
+	Describe("admission lease", func() {
+		var leases coordinationv1client.LeaseInterface
+
+		BeforeEach(func() {
+			leases = fake.NewSimpleClientset().CoordinationV1().Leases("epinio")
+		})
+
+		It("creates the lease for the first holder", func() {
+			acquired, err := acquireAdmissionLease(context.Background(), leases, "one", start)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+
+			lease, err := leases.Get(context.Background(), admissionLeaseName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*lease.Spec.HolderIdentity).To(Equal("one"))
+		})
+
+		It("refuses the lease while another holder has it", func() {
+			_, err := acquireAdmissionLease(context.Background(), leases, "one", start)
+			Expect(err).ToNot(HaveOccurred())
+
+			acquired, err := acquireAdmissionLease(context.Background(), leases, "two", start.Add(time.Second))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeFalse())
+		})
+
+		It("takes an expired lease over", func() {
+			_, err := acquireAdmissionLease(context.Background(), leases, "one", start)
+			Expect(err).ToNot(HaveOccurred())
+
+			acquired, err := acquireAdmissionLease(context.Background(), leases, "two", start.Add(admissionLeaseDuration+time.Second))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+
+		It("hands a released lease to the next holder", func() {
+			_, err := acquireAdmissionLease(context.Background(), leases, "one", start)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(releaseAdmissionLease(context.Background(), leases, "two")).To(Succeed())
+			acquired, err := acquireAdmissionLease(context.Background(), leases, "two", start.Add(time.Second))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeFalse())
+
+			Expect(releaseAdmissionLease(context.Background(), leases, "one")).To(Succeed())
+			acquired, err = acquireAdmissionLease(context.Background(), leases, "two", start.Add(time.Second))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,116 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Resources returns the compute resources recorded on the application resource, if any.
+func Resources(app *unstructured.Unstructured) (*models.AppResources, error) {
+	value, ok := app.GetAnnotations()[models.EpinioResourcesAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	resources := &models.AppResources{}
+	if err := json.Unmarshal([]byte(value), resources); err != nil {
+		return nil, errors.Wrap(err, "resources annotation is not valid")
+	}
+
+	return resources, nil
+}
+
+// SetResources records the compute resources on the application resource. Empty resources
+// remove the record, handing the choice back to the app chart.
+func SetResources(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef, resources models.AppResources) error {
+	if resources.IsEmpty() {
+		return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+			models.EpinioResourcesAnnotation: nil,
+		})
+	}
+
+	data, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioResourcesAnnotation: string(data),
+	})
+}
+
+// CheckResourceMaximums checks that neither the requests nor the limits of the resources
+// exceed the maximum cpu and memory. Empty maximums impose no cap.
+func CheckResourceMaximums(resources models.AppResources, maxCPU, maxMemory string) error {
+	checks := []struct {
+		name  string
+		value string
+		max   string
+	}{
+		{"cpu request", resources.CPURequest, maxCPU},
+		{"cpu limit", resources.CPULimit, maxCPU},
+		{"memory request", resources.MemoryRequest, maxMemory},
+		{"memory limit", resources.MemoryLimit, maxMemory},
+	}
+
+	for _, check := range checks {
+		if check.value == "" || check.max == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(check.value)
+		if err != nil {
+			return errors.Wrapf(err, "bad %s '%s'", check.name, check.value)
+		}
+		limit, err := resource.ParseQuantity(check.max)
+		if err != nil {
+			return errors.Wrapf(err, "bad maximum for the %s '%s'", check.name, check.max)
+		}
+		if quantity.Cmp(limit) > 0 {
+			return fmt.Errorf("%s '%s' exceeds the namespace maximum of '%s'",
+				check.name, check.value, check.max)
+		}
+	}
+
+	return nil
+}
+
+// LimitResources returns the resources with the limits missing from them set to the
+// maximum cpu and memory. Empty maximums impose no limit. The result is nil if there are
+// neither resources nor maximums.
+func LimitResources(resources *models.AppResources, maxCPU, maxMemory string) *models.AppResources {
+	if maxCPU == "" && maxMemory == "" {
+		return resources
+	}
+
+	limited := models.AppResources{}
+	if resources != nil {
+		limited = *resources
+	}
+	if limited.CPULimit == "" {
+		limited.CPULimit = maxCPU
+	}
+	if limited.MemoryLimit == "" {
+		limited.MemoryLimit = maxMemory
+	}
+
+	return &limited
+}
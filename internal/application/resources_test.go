@@ -0,0 +1,101 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resources", func() {
+
+	Describe("Resources", func() {
+		It("decodes the recorded resources", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			app.SetAnnotations(map[string]string{
+				models.EpinioResourcesAnnotation: `{"cpu_request":"250m","memory_limit":"512Mi"}`,
+			})
+			resources, err := Resources(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(Equal(&models.AppResources{CPURequest: "250m", MemoryLimit: "512Mi"}))
+		})
+
+		It("is nil without a record", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			resources, err := Resources(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources).To(BeNil())
+		})
+	})
+
+	Describe("CheckResourceMaximums", func() {
+		resources := models.AppResources{
+			CPURequest:    "500m",
+			CPULimit:      "1",
+			MemoryRequest: "256Mi",
+			MemoryLimit:   "1Gi",
+		}
+
+		It("accepts resources within the maximums", func() {
+			Expect(CheckResourceMaximums(resources, "1", "1Gi")).To(Succeed())
+		})
+
+		It("accepts anything without maximums", func() {
+			Expect(CheckResourceMaximums(resources, "", "")).To(Succeed())
+		})
+
+		It("rejects a limit above the maximum", func() {
+			err := CheckResourceMaximums(resources, "2", "512Mi")
+			Expect(err).To(MatchError("memory limit '1Gi' exceeds the namespace maximum of '512Mi'"))
+		})
+
+		It("rejects a bad maximum", func() {
+			err := CheckResourceMaximums(resources, "lots", "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("bad maximum for the cpu request 'lots'"))
+		})
+	})
+
+	Describe("LimitResources", func() {
+		It("keeps the resources without maximums", func() {
+			Expect(LimitResources(nil, "", "")).To(BeNil())
+
+			resources := &models.AppResources{CPURequest: "250m"}
+			Expect(LimitResources(resources, "", "")).To(Equal(resources))
+		})
+
+		It("limits applications without resources to the maximums", func() {
+			Expect(LimitResources(nil, "1", "1Gi")).To(Equal(&models.AppResources{
+				CPULimit:    "1",
+				MemoryLimit: "1Gi",
+			}))
+		})
+
+		It("sets the missing limits to the maximums", func() {
+			resources := &models.AppResources{CPURequest: "250m", MemoryRequest: "256Mi", MemoryLimit: "512Mi"}
+			Expect(LimitResources(resources, "1", "1Gi")).To(Equal(&models.AppResources{
+				CPURequest:    "250m",
+				CPULimit:      "1",
+				MemoryRequest: "256Mi",
+				MemoryLimit:   "512Mi",
+			}))
+			Expect(resources.CPULimit).To(BeEmpty())
+		})
+
+		It("leaves the limits without a maximum unset", func() {
+			Expect(LimitResources(nil, "", "1Gi")).To(Equal(&models.AppResources{MemoryLimit: "1Gi"}))
+		})
+	})
+})
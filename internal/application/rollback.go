@@ -0,0 +1,228 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/cli/server/requestctx"
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	// ErrBuildNotFound is returned when a requested build is not in the build history.
+	ErrBuildNotFound = errors.New("build not found")
+	// ErrNoRollbackTarget is returned when no build suitable for a rollback exists.
+	ErrNoRollbackTarget = errors.New("no build to roll back to")
+)
+
+// RollbackTarget returns the build to roll back to, from the build history (most recent
+// first). With a requested stage id that build is returned, if it succeeded. Else the
+// most recent successful build older than the current one is returned. With keep greater
+// than zero only the keep most recent builds are considered. The sources of the older
+// builds are removed by the blob collector.
+func RollbackTarget(builds models.AppBuildList, currentStageID, requestedStageID string, keep int) (models.AppBuild, error) {
+	usable := func(build models.AppBuild) bool {
+		return build.Status == models.BuildSucceeded && build.ImageURL != ""
+	}
+
+	if requestedStageID != "" {
+		for i, build := range builds {
+			if build.StageID != requestedStageID {
+				continue
+			}
+			if build.StageID == currentStageID {
+				return build, errors.Wrapf(ErrNoRollbackTarget, "build %s is already deployed", build.StageID)
+			}
+			if !usable(build) {
+				return build, errors.Wrapf(ErrNoRollbackTarget, "build %s did not succeed", build.StageID)
+			}
+			if keep > 0 && i >= keep {
+				return build, errors.Wrapf(ErrNoRollbackTarget,
+					"build %s is older than the %d most recent builds, whose sources are kept", build.StageID, keep)
+			}
+			return build, nil
+		}
+
+		return models.AppBuild{}, errors.Wrapf(ErrBuildNotFound, "build %s", requestedStageID)
+	}
+
+	// Skip the current build and everything newer than it.
+	start := 0
+	for i, build := range builds {
+		if build.StageID == currentStageID {
+			start = i + 1
+			break
+		}
+	}
+
+	kept := builds
+	if keep > 0 && keep < len(builds) {
+		kept = builds[:keep]
+	}
+	if start > len(kept) {
+		start = len(kept)
+	}
+
+	for _, build := range kept[start:] {
+		if build.StageID != currentStageID && usable(build) {
+			return build, nil
+		}
+	}
+
+	return models.AppBuild{}, ErrNoRollbackTarget
+}
+
+// Rollback returns the rollback recorded on the application resource, if any.
+func Rollback(app *unstructured.Unstructured) (*models.AppRollback, error) {
+	value, ok := app.GetAnnotations()[models.EpinioRollbackAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	rollback := &models.AppRollback{}
+	if err := json.Unmarshal([]byte(value), rollback); err != nil {
+		return nil, errors.Wrap(err, "rollback annotation is not valid")
+	}
+
+	return rollback, nil
+}
+
+// RollbackSuperseded returns true when the application was rolled back, and a newer build
+// than the one rolled back to is now current.
+func RollbackSuperseded(app *models.App) bool {
+	return app.Rollback != nil && app.StageID != app.Rollback.StageID
+}
+
+// ClearRollback removes the rollback record from the application resource.
+func ClearRollback(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) error {
+	return patchAnnotations(ctx, cluster, appRef, map[string]interface{}{
+		models.EpinioRollbackAnnotation: nil,
+	})
+}
+
+// SetRollback points the application resource to the image and stage id of the build
+// rolled back to, and records the rollback. The rollback is cleared by the next
+// successful deployment of a newer build.
+func SetRollback(ctx context.Context, cluster *kubernetes.Cluster, app *unstructured.Unstructured, rollback models.AppRollback) error {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return err
+	}
+
+	return setRollback(ctx, client.Namespace(app.GetNamespace()), app, rollback)
+}
+
+// DeployRollback records the rollback on the application resource, see SetRollback, and
+// runs the deployment. The deployment reads the image to deploy from the resource. When
+// it fails the stage id, image and rollback record the resource had before are restored,
+// so that the resource matches the workload which is still running.
+func DeployRollback(ctx context.Context, cluster *kubernetes.Cluster, app *unstructured.Unstructured, rollback models.AppRollback, deploy func() apierror.APIErrors) apierror.APIErrors {
+	client, err := cluster.ClientApp()
+	if err != nil {
+		return apierror.InternalError(err)
+	}
+
+	return deployRollback(ctx, client.Namespace(app.GetNamespace()), app, rollback, deploy)
+}
+
+func deployRollback(ctx context.Context, client dynamic.ResourceInterface, app *unstructured.Unstructured, rollback models.AppRollback, deploy func() apierror.APIErrors) apierror.APIErrors {
+	previous := app.DeepCopy()
+
+	err := setRollback(ctx, client, app, rollback)
+	if err != nil {
+		return apierror.InternalError(err, "failed to record the rollback")
+	}
+
+	apierr := deploy()
+	if apierr == nil {
+		return nil
+	}
+
+	err = restoreRollback(ctx, client, previous)
+	if err != nil {
+		requestctx.Logger(ctx).Error(err, "restoring the application resource after a failed rollback",
+			"namespace", app.GetNamespace(), "app", app.GetName())
+	}
+
+	return apierr
+}
+
+func setRollback(ctx context.Context, client dynamic.ResourceInterface, app *unstructured.Unstructured, rollback models.AppRollback) error {
+	data, err := json.Marshal(rollback)
+	if err != nil {
+		return err
+	}
+
+	if err := unstructured.SetNestedField(app.Object, rollback.StageID, "spec", "stageid"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(app.Object, rollback.ImageURL, "spec", "imageurl"); err != nil {
+		return err
+	}
+
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[models.EpinioRollbackAnnotation] = string(data)
+	app.SetAnnotations(annotations)
+
+	_, err = client.Update(ctx, app, metav1.UpdateOptions{})
+
+	return err
+}
+
+// restoreRollback points the application resource back to the stage id and image of the
+// previous resource, and restores its rollback record.
+func restoreRollback(ctx context.Context, client dynamic.ResourceInterface, previous *unstructured.Unstructured) error {
+	app, err := client.Get(ctx, previous.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []string{"stageid", "imageurl"} {
+		value, found, err := unstructured.NestedString(previous.Object, "spec", field)
+		if err != nil {
+			return err
+		}
+		if !found {
+			unstructured.RemoveNestedField(app.Object, "spec", field)
+			continue
+		}
+		if err := unstructured.SetNestedField(app.Object, value, "spec", field); err != nil {
+			return err
+		}
+	}
+
+	annotations := app.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if value, ok := previous.GetAnnotations()[models.EpinioRollbackAnnotation]; ok {
+		annotations[models.EpinioRollbackAnnotation] = value
+	} else {
+		delete(annotations, models.EpinioRollbackAnnotation)
+	}
+	app.SetAnnotations(annotations)
+
+	_, err = client.Update(ctx, app, metav1.UpdateOptions{})
+
+	return err
+}
@@ -0,0 +1,201 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	apierror "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rollback", func() {
+	// Most recent first, as returned by Builds.
+	builds := models.AppBuildList{
+		{StageID: "s5", Status: models.BuildRunning},
+		{StageID: "s4", Status: models.BuildSucceeded, ImageURL: "img:s4"},
+		{StageID: "s3", Status: models.BuildFailed},
+		{StageID: "s2", Status: models.BuildSucceeded, ImageURL: "img:s2"},
+		{StageID: "s1", Status: models.BuildSucceeded, ImageURL: "img:s1"},
+	}
+
+	Describe("RollbackTarget", func() {
+		It("picks the successful build before the current one", func() {
+			target, err := RollbackTarget(builds, "s4", "", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.StageID).To(Equal("s2"))
+		})
+
+		It("picks the most recent successful build when the current one is unknown", func() {
+			target, err := RollbackTarget(builds, "s0", "", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.StageID).To(Equal("s4"))
+		})
+
+		It("fails when there is no earlier successful build", func() {
+			_, err := RollbackTarget(builds, "s1", "", 0)
+			Expect(errors.Is(err, ErrNoRollbackTarget)).To(BeTrue())
+		})
+
+		It("picks the requested build", func() {
+			target, err := RollbackTarget(builds, "s2", "s4", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.ImageURL).To(Equal("img:s4"))
+		})
+
+		It("rejects unknown, failed, and deployed builds", func() {
+			_, err := RollbackTarget(builds, "s4", "s9", 0)
+			Expect(errors.Is(err, ErrBuildNotFound)).To(BeTrue())
+
+			_, err = RollbackTarget(builds, "s4", "s3", 0)
+			Expect(errors.Is(err, ErrNoRollbackTarget)).To(BeTrue())
+
+			_, err = RollbackTarget(builds, "s4", "s4", 0)
+			Expect(errors.Is(err, ErrNoRollbackTarget)).To(BeTrue())
+		})
+	})
+
+	Describe("RollbackTarget with kept builds", func() {
+		It("only considers the kept builds", func() {
+			target, err := RollbackTarget(builds, "s4", "", 4)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.StageID).To(Equal("s2"))
+
+			_, err = RollbackTarget(builds, "s4", "", 3)
+			Expect(errors.Is(err, ErrNoRollbackTarget)).To(BeTrue())
+
+			_, err = RollbackTarget(builds, "s4", "s1", 4)
+			Expect(err).To(MatchError(ContainSubstring("older than the 4 most recent builds")))
+		})
+	})
+
+	Describe("Rollback", func() {
+		It("returns nothing for an application which was not rolled back", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			rollback, err := Rollback(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rollback).To(BeNil())
+		})
+
+		It("decodes the recorded rollback", func() {
+			app := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			app.SetAnnotations(map[string]string{
+				models.EpinioRollbackAnnotation: `{"stage_id":"s2","from_stage_id":"s4"}`,
+			})
+
+			rollback, err := Rollback(app)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rollback.StageID).To(Equal("s2"))
+			Expect(rollback.FromStageID).To(Equal("s4"))
+		})
+	})
+
+	Describe("RollbackSuperseded", func() {
+		It("is false for an application which was not rolled back", func() {
+			Expect(RollbackSuperseded(&models.App{StageID: "s4"})).To(BeFalse())
+		})
+
+		It("is false while the build rolled back to is current", func() {
+			app := &models.App{StageID: "s2", Rollback: &models.AppRollback{StageID: "s2"}}
+			Expect(RollbackSuperseded(app)).To(BeFalse())
+		})
+
+		It("is true once a newer build is current", func() {
+			app := &models.App{StageID: "s5", Rollback: &models.AppRollback{StageID: "s2"}}
+			Expect(RollbackSuperseded(app)).To(BeTrue())
+		})
+	})
+
+	Describe("deployRollback", func() {
+		appsGVR := schema.GroupVersionResource{Group: "application.epinio.io", Version: "v1", Resource: "apps"}
+
+		var app *unstructured.Unstructured
+		var rollback models.AppRollback
+
+		newClient := func() *dynamicfake.FakeDynamicClient {
+			return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{appsGVR: "AppList"}, app.DeepCopy())
+		}
+
+		BeforeEach(func() {
+			app = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "application.epinio.io/v1",
+				"kind":       "App",
+				"metadata": map[string]interface{}{
+					"name":      "app",
+					"namespace": "workspace",
+				},
+				"spec": map[string]interface{}{
+					"stageid":  "s4",
+					"imageurl": "img:s4",
+				},
+			}}
+			rollback = models.AppRollback{StageID: "s2", FromStageID: "s4", ImageURL: "img:s2"}
+		})
+
+		It("keeps the rollback when the deployment succeeds", func() {
+			client := newClient().Resource(appsGVR).Namespace("workspace")
+
+			apierr := deployRollback(context.Background(), client, app, rollback, func() apierror.APIErrors {
+				deployed, err := client.Get(context.Background(), "app", metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ImageURL(deployed)).To(Equal("img:s2"))
+				return nil
+			})
+			Expect(apierr).To(BeNil())
+
+			stored, err := client.Get(context.Background(), "app", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(StageID(stored)).To(Equal("s2"))
+			Expect(Rollback(stored)).ToNot(BeNil())
+		})
+
+		It("restores the application resource when the deployment fails", func() {
+			client := newClient().Resource(appsGVR).Namespace("workspace")
+
+			apierr := deployRollback(context.Background(), client, app, rollback, func() apierror.APIErrors {
+				return apierror.NewInternalError("deployment failed")
+			})
+			Expect(apierr).ToNot(BeNil())
+			Expect(apierr.FirstStatus()).To(Equal(500))
+
+			stored, err := client.Get(context.Background(), "app", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(StageID(stored)).To(Equal("s4"))
+			Expect(ImageURL(stored)).To(Equal("img:s4"))
+			Expect(stored.GetAnnotations()).ToNot(HaveKey(models.EpinioRollbackAnnotation))
+		})
+
+		It("restores an earlier rollback record when the deployment fails", func() {
+			app.SetAnnotations(map[string]string{models.EpinioRollbackAnnotation: `{"stage_id":"s4"}`})
+			client := newClient().Resource(appsGVR).Namespace("workspace")
+
+			apierr := deployRollback(context.Background(), client, app, rollback, func() apierror.APIErrors {
+				return apierror.NewInternalError("deployment failed")
+			})
+			Expect(apierr).ToNot(BeNil())
+
+			stored, err := client.Get(context.Background(), "app", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.GetAnnotations()).To(HaveKeyWithValue(models.EpinioRollbackAnnotation, `{"stage_id":"s4"}`))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// webhookSecretKey is the key of the webhook secret in the application's kube secret.
+const webhookSecretKey = "secret"
+
+// WebhookSecret returns the secret of the application's Git webhook. It returns the empty
+// string if the webhook is not enabled.
+func WebhookSecret(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, error) {
+	secret, err := cluster.GetSecret(ctx, appRef.Namespace, appRef.MakeWebhookSecretName())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return string(secret.Data[webhookSecretKey]), nil
+}
+
+// EnableWebhook generates a new secret for the application's Git webhook, enabling the
+// webhook, and returns it. A previous secret becomes invalid.
+func EnableWebhook(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) (string, error) {
+	randBytes := make([]byte, 32)
+	if _, err := rand.Read(randBytes); err != nil {
+		return "", errors.Wrap(err, "generating the webhook secret")
+	}
+	value := hex.EncodeToString(randBytes)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := loadOrCreateSecret(ctx, cluster, appRef, appRef.MakeWebhookSecretName(), "webhook")
+		if err != nil {
+			return err
+		}
+
+		secret.Data = map[string][]byte{
+			webhookSecretKey: []byte(value),
+		}
+
+		_, err = cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// DisableWebhook removes the secret of the application's Git webhook, disabling it.
+func DisableWebhook(ctx context.Context, cluster *kubernetes.Cluster, appRef models.AppRef) error {
+	err := cluster.Kubectl.CoreV1().Secrets(appRef.Namespace).Delete(ctx,
+		appRef.MakeWebhookSecretName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
@@ -220,6 +222,20 @@ func (a *Workload) Get(ctx context.Context) (*models.AppDeployment, error) {
 		routes = []string{err.Error()}
 	}
 
+	// Surface the failing health checks of replicas which are not ready. Failures to
+	// retrieve the events are not fatal, they only hide this detail.
+	if int(readyReplicas) < len(podList) {
+		events, err := a.cluster.Kubectl.CoreV1().Events(a.app.Namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=Pod,reason=" + probeFailureReason,
+		})
+		if err == nil {
+			failures := probeFailures(podList, events.Items)
+			if len(failures) > 0 {
+				status = fmt.Sprintf("%s, %s", status, strings.Join(failures, ", "))
+			}
+		}
+	}
+
 	replicas, err := a.Replicas(ctx)
 	if err != nil {
 		status = pkgerrors.Wrap(err, "failed to get replica details").Error()
@@ -239,6 +255,54 @@ func (a *Workload) Get(ctx context.Context) (*models.AppDeployment, error) {
 	}, nil
 }
 
+// probeFailureReason is the reason of the events the kubelet records for failed probes.
+const probeFailureReason = "Unhealthy"
+
+// probeFailures returns the most recent failure of each kind of probe (liveness,
+// readiness, startup), as reported by the events of the pods which are not ready. Failures
+// of ready pods are history, and ignored.
+func probeFailures(pods []corev1.Pod, events []corev1.Event) []string {
+	notReady := map[string]bool{}
+	for i := range pods {
+		if !podutils.IsPodReady(&pods[i]) {
+			notReady[pods[i].Name] = true
+		}
+	}
+
+	latest := map[string]corev1.Event{}
+	for _, event := range events {
+		if event.Reason != probeFailureReason || !notReady[event.InvolvedObject.Name] {
+			continue
+		}
+
+		// The message is of the form "<Kind> probe failed: <details>"
+		kind, _, found := strings.Cut(event.Message, ":")
+		if !found {
+			continue
+		}
+
+		if current, ok := latest[kind]; !ok || eventTime(current).Before(eventTime(event)) {
+			latest[kind] = event
+		}
+	}
+
+	failures := []string{}
+	for _, event := range latest {
+		failures = append(failures, strings.TrimSpace(event.Message))
+	}
+	sort.Strings(failures)
+
+	return failures
+}
+
+// eventTime returns the time the event was last seen.
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
 func (a *Workload) getPodMetrics(ctx context.Context) ([]metricsv1beta1.PodMetrics, error) {
 	result := []metricsv1beta1.PodMetrics{}
 
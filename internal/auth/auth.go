@@ -25,6 +25,7 @@ import (
 	"github.com/epinio/epinio/internal/names"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -105,6 +106,17 @@ func (s *AuthService) SaveUser(ctx context.Context, user User) (User, error) {
 		},
 	}
 
+	if user.Password != "" {
+		userSecret.StringData["password"] = user.Password
+	}
+
+	if len(user.Namespaces) > 0 {
+		userSecret.StringData["namespaces"] = user.namespacesData()
+	}
+	if len(user.GroupNamespaces) > 0 {
+		userSecret.StringData["groupNamespaces"] = strings.Join(user.GroupNamespaces, "\n")
+	}
+
 	createdUserSecret, err := s.Create(ctx, userSecret, metav1.CreateOptions{})
 	if err != nil {
 		return User{}, err
@@ -113,6 +125,65 @@ func (s *AuthService) SaveUser(ctx context.Context, user User) (User, error) {
 	return NewUserFromSecret(*createdUserSecret), nil
 }
 
+// UpdateUser will update the role and the namespaces of the User
+func (s *AuthService) UpdateUser(ctx context.Context, user User) error {
+	if user.secretName == "" {
+		current, err := s.GetUserByUsername(ctx, user.Username)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error getting user [%s] by username", user.Username))
+		}
+		user.secretName = current.secretName
+	}
+
+	err := s.updateUserSecret(ctx, user)
+	return errors.Wrap(err, fmt.Sprintf("error updating user secret [%s]", user.Username))
+}
+
+// UpdateUserPassword will replace the password of the User with the provided bcrypt hash
+func (s *AuthService) UpdateUserPassword(ctx context.Context, username, passwordHash string) error {
+	user, err := s.GetUserByUsername(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error getting user [%s] by username", username))
+	}
+
+	// note: Wrap (nil, ...) returns nil.
+	return errors.Wrap(retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		userSecret, err := s.SecretInterface.Get(ctx, user.secretName, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("error getting the user secret [%s]", username))
+		}
+
+		userSecret.StringData = map[string]string{
+			"password": passwordHash,
+		}
+
+		_, err = s.SecretInterface.Update(ctx, userSecret, metav1.UpdateOptions{})
+		return err
+	}), fmt.Sprintf("error updating the password of the user [%s]", username))
+}
+
+// DeleteUser will delete the User, and revoke its personal access tokens
+func (s *AuthService) DeleteUser(ctx context.Context, username string) error {
+	user, err := s.GetUserByUsername(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error getting user [%s] by username", username))
+	}
+
+	tokens, err := s.GetTokens(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, "error getting tokens")
+	}
+	for _, token := range tokens {
+		err = s.Delete(ctx, token.secretName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, fmt.Sprintf("error deleting the token secret [%s]", token.ID))
+		}
+	}
+
+	err = s.Delete(ctx, user.secretName, metav1.DeleteOptions{})
+	return errors.Wrap(err, fmt.Sprintf("error deleting the user secret [%s]", username))
+}
+
 // AddNamespaceToUser will add to the User the specified namespace
 func (s *AuthService) AddNamespaceToUser(ctx context.Context, username, namespace string) error {
 	user, err := s.GetUserByUsername(ctx, username)
@@ -176,15 +247,24 @@ func (s *AuthService) updateUserSecret(ctx context.Context, user User) error {
 			return errors.Wrap(err, fmt.Sprintf("error getting the user secret [%s]", user.Username))
 		}
 
-		if len(user.Namespaces) > 0 {
-			userSecret.StringData = map[string]string{
-				"namespaces": strings.Join(user.Namespaces, "\n"),
+		// the namespaces are always written, to persist the removal of the last one
+		userSecret.StringData = map[string]string{
+			"namespaces": user.namespacesData(),
+		}
+		if _, found := userSecret.Data["groupNamespaces"]; found || len(user.GroupNamespaces) > 0 {
+			userSecret.StringData["groupNamespaces"] = strings.Join(user.GroupNamespaces, "\n")
+		}
+
+		if user.Role != "" {
+			if userSecret.Labels == nil {
+				userSecret.Labels = map[string]string{}
 			}
+			userSecret.Labels[kubernetes.EpinioAPISecretRoleLabelKey] = user.Role
 		}
 
 		_, err = s.SecretInterface.Update(ctx, userSecret, metav1.UpdateOptions{})
 		return err
-	}), fmt.Sprintf("error updating the user secret [%s]", user))
+	}), fmt.Sprintf("error updating the user secret [%s]", user.Username))
 }
 
 type NamespacedResource interface {
@@ -79,6 +79,30 @@ var _ = Describe("Auth users", func() {
 				Expect(users[1].Namespaces).To(HaveLen(2))
 			})
 		})
+
+		When("the user secret declares namespace roles", func() {
+			It("returns the users with their namespace roles", func() {
+				userSecrets := []corev1.Secret{
+					newUserSecret("epinio", "mypass", "user", "workspace:viewer\nworkspace2"),
+				}
+				fake.ListReturns(&corev1.SecretList{Items: userSecrets}, nil)
+
+				users, err := authService.GetUsers(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(users[0].Namespaces).To(Equal([]string{"workspace", "workspace2"}))
+
+				role, found := users[0].NamespaceRole("workspace")
+				Expect(found).To(BeTrue())
+				Expect(role).To(Equal(auth.NamespaceRoleViewer))
+
+				role, found = users[0].NamespaceRole("workspace2")
+				Expect(found).To(BeTrue())
+				Expect(role).To(Equal(auth.NamespaceRoleAdmin))
+
+				_, found = users[0].NamespaceRole("other")
+				Expect(found).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("AddNamespaceToUser", func() {
@@ -146,6 +170,101 @@ var _ = Describe("Auth users", func() {
 			})
 		})
 	})
+
+	Describe("DeleteUser", func() {
+
+		When("the user exists", func() {
+			It("deletes the user secret", func() {
+				userSecrets := []corev1.Secret{
+					newUserSecret("user1", "password", "user", ""),
+				}
+				fake.ListReturnsOnCall(0, &corev1.SecretList{Items: userSecrets}, nil)
+				fake.ListReturnsOnCall(1, &corev1.SecretList{Items: []corev1.Secret{}}, nil)
+
+				err := authService.DeleteUser(context.Background(), "user1")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fake.DeleteCallCount()).To(Equal(1))
+				_, secretName, _ := fake.DeleteArgsForCall(0)
+				Expect(secretName).To(Equal("user1"))
+			})
+		})
+
+		When("the user doesn't exist", func() {
+			It("returns an error", func() {
+				fake.ListReturns(&corev1.SecretList{Items: []corev1.Secret{}}, nil)
+
+				err := authService.DeleteUser(context.Background(), "user1")
+				Expect(errors.Is(err, auth.ErrUserNotFound)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("UpdateUser", func() {
+
+		When("the role and the namespaces changed", func() {
+			It("updates the role label and the namespaces", func() {
+				userSecret := newUserSecret("user1", "password", "user", "workspace")
+
+				// setup mock
+				fake.GetReturns(&userSecret, nil)
+				fake.UpdateReturns(&userSecret, nil)
+
+				user := auth.NewUserFromSecret(userSecret)
+				user.Role = "admin"
+				user.SetGroupNamespaces([]string{"team"})
+
+				// do test
+				err := authService.UpdateUser(context.Background(), user)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, secret, _ := fake.UpdateArgsForCall(0)
+				Expect(secret.Labels[kubernetes.EpinioAPISecretRoleLabelKey]).To(Equal("admin"))
+				Expect(secret.StringData["namespaces"]).To(Equal("workspace\nteam"))
+				Expect(secret.StringData["groupNamespaces"]).To(Equal("team"))
+			})
+		})
+	})
+})
+
+var _ = Describe("User", func() {
+
+	Describe("SetGroupNamespaces", func() {
+		var user auth.User
+
+		BeforeEach(func() {
+			user = auth.User{
+				Namespaces:      []string{"owned", "team-a", "team-b"},
+				GroupNamespaces: []string{"team-a", "team-b"},
+			}
+		})
+
+		When("the groups grant the same namespaces", func() {
+			It("doesn't change the user", func() {
+				changed := user.SetGroupNamespaces([]string{"team-b", "team-a"})
+				Expect(changed).To(BeFalse())
+				Expect(user.Namespaces).To(Equal([]string{"owned", "team-a", "team-b"}))
+			})
+		})
+
+		When("a group is not granted anymore", func() {
+			It("revokes only the group namespace", func() {
+				changed := user.SetGroupNamespaces([]string{"team-a", "team-c"})
+				Expect(changed).To(BeTrue())
+				Expect(user.Namespaces).To(Equal([]string{"owned", "team-a", "team-c"}))
+				Expect(user.GroupNamespaces).To(Equal([]string{"team-a", "team-c"}))
+			})
+		})
+
+		When("no groups are granted", func() {
+			It("keeps the namespaces owned by the user", func() {
+				changed := user.SetGroupNamespaces([]string{})
+				Expect(changed).To(BeTrue())
+				Expect(user.Namespaces).To(Equal([]string{"owned"}))
+				Expect(user.GroupNamespaces).To(BeEmpty())
+			})
+		})
+	})
 })
 
 func newUserSecret(username, password, role, namespaces string) corev1.Secret {
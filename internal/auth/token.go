@@ -0,0 +1,264 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dchest/uniuri"
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// PersonalAccessTokenPrefix identifies the personal access tokens among the bearer tokens
+	PersonalAccessTokenPrefix = "epinio_pat_"
+
+	tokenIDChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+var (
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenInvalid  = errors.New("invalid token")
+)
+
+// Token is a personal access token of an Epinio User. Only the hash of its value is stored.
+type Token struct {
+	ID         string
+	Name       string
+	Username   string
+	Namespaces []string
+	ReadOnly   bool
+	CreatedAt  time.Time
+
+	hash       string
+	secretName string
+}
+
+// IsPersonalAccessToken returns true if the value looks like a personal access token
+func IsPersonalAccessToken(value string) bool {
+	return strings.HasPrefix(value, PersonalAccessTokenPrefix)
+}
+
+// NewTokenFromSecret create a Token from a Secret
+func NewTokenFromSecret(secret corev1.Secret) Token {
+	return Token{
+		ID:         string(secret.Data["id"]),
+		Name:       string(secret.Data["name"]),
+		Username:   string(secret.Data["username"]),
+		Namespaces: parseNamespaces(secret.Data["namespaces"]),
+		ReadOnly:   string(secret.Data["readOnly"]) == "true",
+		CreatedAt:  secret.ObjectMeta.CreationTimestamp.Time,
+
+		hash:       string(secret.Data["hash"]),
+		secretName: secret.GetName(),
+	}
+}
+
+// Scope returns the restrictions the token applies to the access of its user
+func (t Token) Scope() TokenScope {
+	return TokenScope{
+		Namespaces: t.Namespaces,
+		ReadOnly:   t.ReadOnly,
+	}
+}
+
+// TokenScope restricts the access of a user authenticated with a personal access token
+type TokenScope struct {
+	// Namespaces limits the access to these namespaces, when not empty
+	Namespaces []string
+	ReadOnly   bool
+}
+
+// WithScope returns a copy of the user, with its access restricted by the scope
+func (u User) WithScope(scope TokenScope) User {
+	if len(scope.Namespaces) > 0 {
+		if u.Role == "admin" {
+			// an admin has access to all the namespaces
+			u.Role = "user"
+			u.Namespaces = append([]string{}, scope.Namespaces...)
+			u.NamespaceRoles = map[string]string{}
+		} else {
+			allowed := make(map[string]struct{})
+			for _, ns := range scope.Namespaces {
+				allowed[ns] = struct{}{}
+			}
+
+			namespaces := []string{}
+			roles := map[string]string{}
+			for _, ns := range u.Namespaces {
+				if _, found := allowed[ns]; !found {
+					continue
+				}
+				namespaces = append(namespaces, ns)
+				if role, found := u.NamespaceRoles[ns]; found {
+					roles[ns] = role
+				}
+			}
+			u.Namespaces = namespaces
+			u.NamespaceRoles = roles
+		}
+	}
+
+	u.Scope = &scope
+
+	return u
+}
+
+// IsNamespaceScoped returns true if the access of the user is limited to the namespaces of a
+// personal access token
+func (u User) IsNamespaceScoped() bool {
+	return u.Scope != nil && len(u.Scope.Namespaces) > 0
+}
+
+// IsReadOnly returns true if the user is not allowed to change anything
+func (u User) IsReadOnly() bool {
+	return u.Scope != nil && u.Scope.ReadOnly
+}
+
+// CreateToken creates a new personal access token for the user. It returns the token and its
+// value, which is not stored and cannot be retrieved later.
+func (s *AuthService) CreateToken(ctx context.Context, username, name string, namespaces []string, readOnly bool) (Token, string, error) {
+	id := uniuri.NewLenChars(12, []byte(tokenIDChars))
+	value := PersonalAccessTokenPrefix + id + "_" + uniuri.NewLen(40)
+
+	tokenSecret := &corev1.Secret{
+		Type: "Opaque",
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tokenSecretName(id),
+			Namespace: helmchart.Namespace(),
+			Labels: map[string]string{
+				kubernetes.EpinioAPITokenLabelKey: kubernetes.EpinioAPITokenLabelValue,
+			},
+		},
+		StringData: map[string]string{
+			"id":         id,
+			"name":       name,
+			"username":   username,
+			"namespaces": strings.Join(namespaces, "\n"),
+			"readOnly":   fmt.Sprintf("%t", readOnly),
+			"hash":       hashToken(value),
+		},
+	}
+
+	createdSecret, err := s.Create(ctx, tokenSecret, metav1.CreateOptions{})
+	if err != nil {
+		return Token{}, "", errors.Wrap(err, "error creating the token secret")
+	}
+
+	token := Token{
+		ID:         id,
+		Name:       name,
+		Username:   username,
+		Namespaces: namespaces,
+		ReadOnly:   readOnly,
+		CreatedAt:  createdSecret.ObjectMeta.CreationTimestamp.Time,
+
+		secretName: createdSecret.GetName(),
+	}
+
+	return token, value, nil
+}
+
+// GetTokens returns the personal access tokens of the user. All the tokens are returned
+// if the username is empty.
+func (s *AuthService) GetTokens(ctx context.Context, username string) ([]Token, error) {
+	secretSelector := labels.Set(map[string]string{
+		kubernetes.EpinioAPITokenLabelKey: kubernetes.EpinioAPITokenLabelValue,
+	}).AsSelector().String()
+
+	secretList, err := s.SecretInterface.List(ctx, metav1.ListOptions{
+		LabelSelector: secretSelector,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting the list of the token secrets")
+	}
+
+	tokens := []Token{}
+	for _, secret := range secretList.Items {
+		token := NewTokenFromSecret(secret)
+		if username == "" || token.Username == username {
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens, nil
+}
+
+// VerifyToken returns the token matching the value.
+// It returns an ErrTokenInvalid error if the value doesn't match any token.
+func (s *AuthService) VerifyToken(ctx context.Context, value string) (Token, error) {
+	if !IsPersonalAccessToken(value) {
+		return Token{}, ErrTokenInvalid
+	}
+
+	id, _, found := strings.Cut(strings.TrimPrefix(value, PersonalAccessTokenPrefix), "_")
+	if !found || id == "" {
+		return Token{}, ErrTokenInvalid
+	}
+
+	secret, err := s.SecretInterface.Get(ctx, tokenSecretName(id), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return Token{}, ErrTokenInvalid
+		}
+		return Token{}, errors.Wrap(err, "error getting the token secret")
+	}
+
+	token := NewTokenFromSecret(*secret)
+	if subtle.ConstantTimeCompare([]byte(token.hash), []byte(hashToken(value))) != 1 {
+		return Token{}, ErrTokenInvalid
+	}
+
+	return token, nil
+}
+
+// DeleteToken revokes the personal access token of the user with the specified ID or name
+func (s *AuthService) DeleteToken(ctx context.Context, username, idOrName string) error {
+	tokens, err := s.GetTokens(ctx, username)
+	if err != nil {
+		return errors.Wrap(err, "error getting tokens")
+	}
+
+	for _, token := range tokens {
+		if token.ID == idOrName || token.Name == idOrName {
+			err := s.Delete(ctx, token.secretName, metav1.DeleteOptions{})
+			return errors.Wrap(err, fmt.Sprintf("error deleting the token secret [%s]", token.ID))
+		}
+	}
+
+	return ErrTokenNotFound
+}
+
+func tokenSecretName(id string) string {
+	return "rtoken-" + id
+}
+
+func hashToken(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
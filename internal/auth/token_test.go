@@ -0,0 +1,101 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"context"
+
+	"github.com/epinio/epinio/internal/auth"
+	"github.com/epinio/epinio/internal/auth/authfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Personal access tokens", func() {
+	var authService *auth.AuthService
+	var fake *authfakes.FakeSecretInterface
+
+	BeforeEach(func() {
+		fake = &authfakes.FakeSecretInterface{}
+		authService = &auth.AuthService{
+			SecretInterface: fake,
+		}
+
+		// store the created secret, converting its StringData as the cluster would do
+		fake.CreateStub = func(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+			stored := secret.DeepCopy()
+			stored.Data = map[string][]byte{}
+			for key, value := range secret.StringData {
+				stored.Data[key] = []byte(value)
+			}
+			stored.StringData = nil
+
+			fake.GetReturns(stored, nil)
+			return stored, nil
+		}
+	})
+
+	Describe("CreateToken and VerifyToken", func() {
+
+		It("verifies the value of the created token", func() {
+			token, value, err := authService.CreateToken(context.Background(), "user1", "ci", []string{"workspace"}, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(auth.IsPersonalAccessToken(value)).To(BeTrue())
+
+			_, secret, _ := fake.CreateArgsForCall(0)
+			Expect(secret.StringData["hash"]).ToNot(BeEmpty())
+			Expect(secret.StringData).ToNot(ContainElement(value))
+
+			verified, err := authService.VerifyToken(context.Background(), value)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified.ID).To(Equal(token.ID))
+			Expect(verified.Username).To(Equal("user1"))
+			Expect(verified.Namespaces).To(Equal([]string{"workspace"}))
+			Expect(verified.ReadOnly).To(BeTrue())
+		})
+
+		It("rejects a wrong value", func() {
+			token, _, err := authService.CreateToken(context.Background(), "user1", "ci", nil, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = authService.VerifyToken(context.Background(), auth.PersonalAccessTokenPrefix+token.ID+"_wrong")
+			Expect(err).To(Equal(auth.ErrTokenInvalid))
+		})
+	})
+
+	Describe("WithScope", func() {
+
+		It("restricts the namespaces of a user", func() {
+			user := auth.User{
+				Role:           "user",
+				Namespaces:     []string{"workspace", "other"},
+				NamespaceRoles: map[string]string{"other": auth.NamespaceRoleViewer},
+			}
+
+			scoped := user.WithScope(auth.TokenScope{Namespaces: []string{"other", "unknown"}})
+			Expect(scoped.Namespaces).To(Equal([]string{"other"}))
+			Expect(scoped.NamespaceRoles).To(HaveKeyWithValue("other", auth.NamespaceRoleViewer))
+			Expect(scoped.IsReadOnly()).To(BeFalse())
+		})
+
+		It("restricts an admin to the namespaces of the scope", func() {
+			user := auth.User{Role: "admin"}
+
+			scoped := user.WithScope(auth.TokenScope{Namespaces: []string{"workspace"}, ReadOnly: true})
+			Expect(scoped.Role).To(Equal("user"))
+			Expect(scoped.Namespaces).To(Equal([]string{"workspace"}))
+			Expect(scoped.IsReadOnly()).To(BeTrue())
+		})
+	})
+})
@@ -24,6 +24,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// NamespaceRoleViewer allows read-only access to the resources of a namespace
+	NamespaceRoleViewer = "viewer"
+	// NamespaceRoleDeveloper allows to manage the applications of a namespace
+	NamespaceRoleDeveloper = "developer"
+	// NamespaceRoleAdmin allows to manage all the resources of a namespace, including
+	// configurations and services. It is the role of namespaces declared without one.
+	NamespaceRoleAdmin = "namespace-admin"
+)
+
 // User is a struct containing all the information of an Epinio User
 type User struct {
 	Username   string
@@ -32,6 +42,18 @@ type User struct {
 	Role       string
 	Namespaces []string
 
+	// NamespaceRoles holds the role of the user in a namespace, when it is not the default
+	// NamespaceRoleAdmin. In the user secret they are declared as "namespace:role" lines.
+	NamespaceRoles map[string]string
+
+	// GroupNamespaces are the namespaces granted to the user by the groups of its
+	// identity provider. They are a subset of Namespaces, tracked to be able to revoke them.
+	GroupNamespaces []string
+
+	// Scope is set when the user authenticated with a personal access token,
+	// restricting its access to the scope of the token (see WithScope)
+	Scope *TokenScope
+
 	secretName string
 }
 
@@ -44,22 +66,46 @@ func NewUserFromSecret(secret corev1.Secret) User {
 		Role:       secret.Labels[kubernetes.EpinioAPISecretRoleLabelKey],
 		Namespaces: []string{},
 
+		NamespaceRoles:  map[string]string{},
+		GroupNamespaces: []string{},
+
 		secretName: secret.GetName(),
 	}
 
 	if ns, found := secret.Data["namespaces"]; found {
-		namespaces := strings.TrimSpace(string(ns))
-		for _, namespace := range strings.Split(namespaces, "\n") {
-			namespace = strings.TrimSpace(namespace)
-			if namespace != "" {
-				user.Namespaces = append(user.Namespaces, namespace)
+		for _, namespace := range parseNamespaces(ns) {
+			name, role, hasRole := strings.Cut(namespace, ":")
+			name = strings.TrimSpace(name)
+			role = strings.TrimSpace(role)
+
+			user.AddNamespace(name)
+			if hasRole && role != "" && role != NamespaceRoleAdmin {
+				user.NamespaceRoles[name] = role
 			}
 		}
 	}
+	if ns, found := secret.Data["groupNamespaces"]; found {
+		user.GroupNamespaces = parseNamespaces(ns)
+	}
 
 	return user
 }
 
+// parseNamespaces returns the non-empty namespaces of a newline separated list
+func parseNamespaces(data []byte) []string {
+	result := []string{}
+
+	namespaces := strings.TrimSpace(string(data))
+	for _, namespace := range strings.Split(namespaces, "\n") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			result = append(result, namespace)
+		}
+	}
+
+	return result
+}
+
 // NewUserFromIDToken create an Epinio User from an IDToken
 func NewUserFromIDToken(idToken *oidc.IDToken) (User, error) {
 	user := User{}
@@ -81,6 +127,54 @@ func NewUserFromIDToken(idToken *oidc.IDToken) (User, error) {
 	return user, nil
 }
 
+// NamespaceRole returns the role of the user in the namespace. It returns false if the user
+// has no access to the namespace.
+func (u *User) NamespaceRole(namespace string) (string, bool) {
+	for _, ns := range u.Namespaces {
+		if ns != namespace {
+			continue
+		}
+
+		if role, found := u.NamespaceRoles[namespace]; found {
+			return role, true
+		}
+		return NamespaceRoleAdmin, true
+	}
+
+	return "", false
+}
+
+// SetNamespaceRole sets the role of the user in the namespace, adding it if needed
+func (u *User) SetNamespaceRole(namespace, role string) {
+	if namespace == "" {
+		return
+	}
+	u.AddNamespace(namespace)
+
+	if role == "" || role == NamespaceRoleAdmin {
+		delete(u.NamespaceRoles, namespace)
+		return
+	}
+
+	if u.NamespaceRoles == nil {
+		u.NamespaceRoles = map[string]string{}
+	}
+	u.NamespaceRoles[namespace] = role
+}
+
+// namespacesData returns the namespaces of the user, with their roles, in the format of the user secret
+func (u *User) namespacesData() string {
+	lines := []string{}
+	for _, ns := range u.Namespaces {
+		if role, found := u.NamespaceRoles[ns]; found {
+			ns = ns + ":" + role
+		}
+		lines = append(lines, ns)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // AddNamespace adds the namespace to the User's namespaces, if not already exists
 func (u *User) AddNamespace(namespace string) {
 	if namespace == "" {
@@ -109,7 +203,41 @@ func (u *User) RemoveNamespace(namespace string) bool {
 			removed = true
 		}
 	}
+	delete(u.NamespaceRoles, namespace)
 
 	u.Namespaces = updatedNamespaces
 	return removed
 }
+
+// SetGroupNamespaces replaces the namespaces granted by the identity provider groups with the
+// specified ones. Namespaces previously granted by a group, and not granted anymore, are removed,
+// while the namespaces not coming from a group (i.e. the ones created by the user) are kept.
+// It returns true if the namespaces of the user changed.
+func (u *User) SetGroupNamespaces(namespaces []string) bool {
+	granted := make(map[string]struct{})
+	for _, ns := range namespaces {
+		granted[ns] = struct{}{}
+	}
+
+	changed := false
+	for _, ns := range u.GroupNamespaces {
+		if _, found := granted[ns]; !found {
+			changed = u.RemoveNamespace(ns) || changed
+		}
+	}
+
+	for _, ns := range namespaces {
+		before := len(u.Namespaces)
+		u.AddNamespace(ns)
+		changed = changed || len(u.Namespaces) != before
+	}
+
+	u.GroupNamespaces = []string{}
+	for _, ns := range namespaces {
+		if ns != "" {
+			u.GroupNamespaces = append(u.GroupNamespaces, ns)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,146 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blobstore stores the blobs of application sources. The blobs are kept either
+// in an S3 compatible object storage, or in a directory of the filesystem, usually a
+// mounted PVC.
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/s3manager"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	// BackendS3 keeps the blobs in an S3 compatible object storage.
+	BackendS3 = "s3"
+	// BackendFilesystem keeps the blobs in a directory.
+	BackendFilesystem = "filesystem"
+)
+
+// Store is the storage of the blobs of application sources.
+type Store interface {
+	// Upload stores the file as a new blob, and returns its blobUID.
+	Upload(ctx context.Context, filepath string, metadata map[string]string) (string, error)
+	// UploadStream stores the content of the reader as a new blob, and returns its blobUID.
+	UploadStream(ctx context.Context, file io.Reader, size int64, metadata map[string]string) (string, error)
+	// Meta returns the metadata of the blob. The keys are in canonical form, e.g. "App".
+	Meta(ctx context.Context, blobUID string) (map[string]string, error)
+	// DeleteObject removes the blob. Removing a missing blob is not an error.
+	DeleteObject(ctx context.Context, blobUID string) error
+	// ListBlobs returns all the stored blobs.
+	ListBlobs(ctx context.Context) ([]models.BlobInfo, error)
+}
+
+// MultipartStore is a Store able to assemble a blob from parts uploaded separately. It
+// is the base of chunked uploads.
+type MultipartStore interface {
+	Store
+
+	StartUpload(ctx context.Context, sha256Hex string, metadata map[string]string) (string, string, error)
+	// UploadMeta returns the metadata given to StartUpload, with keys in canonical form.
+	UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error)
+	UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64, sha256Hex string) (models.UploadPart, error)
+	UploadedParts(ctx context.Context, blobUID, uploadID string) ([]models.UploadPart, error)
+	// CompleteUpload assembles the blob from the given parts.
+	CompleteUpload(ctx context.Context, blobUID, uploadID string, parts []models.UploadPart) error
+	AbortUpload(ctx context.Context, blobUID, uploadID string) error
+}
+
+// StaleUploadCollector is a Store keeping partial blobs while they are uploaded. Those
+// left over by an interrupted upload are removed by the blob collector.
+type StaleUploadCollector interface {
+	// RemoveStaleUploads removes the partial blobs last written before the given time.
+	RemoveStaleUploads(ctx context.Context, before time.Time) (int, error)
+}
+
+// Backend returns the configured storage backend. It defaults to S3.
+func Backend() string {
+	backend := viper.GetString("blob-storage")
+	if backend == "" {
+		return BackendS3
+	}
+	return backend
+}
+
+// Path returns the directory holding the blobs of the filesystem backend.
+func Path() string {
+	return viper.GetString("blob-storage-path")
+}
+
+// Group returns the group owning the blobs of the filesystem backend. The staging jobs
+// are made members of it, to read the blobs. A negative group is the group of the server.
+func Group() int {
+	gid := viper.GetInt("blob-storage-group")
+	if gid < 0 {
+		return os.Getegid()
+	}
+	return gid
+}
+
+// PVC returns the name of the PVC holding the blobs of the filesystem backend. It is
+// mounted into the staging jobs.
+func PVC() string {
+	return viper.GetString("blob-storage-pvc")
+}
+
+// ValidateConfig checks the configuration of the blob storage.
+func ValidateConfig() error {
+	switch backend := Backend(); backend {
+	case BackendS3:
+		return nil
+	case BackendFilesystem:
+		if Path() == "" {
+			return errors.New("the filesystem blob storage needs a path")
+		}
+		if PVC() == "" {
+			return errors.New("the filesystem blob storage needs a PVC")
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown blob storage backend '%s'", backend)
+	}
+}
+
+// New returns the configured blob store.
+func New(ctx context.Context, cluster *kubernetes.Cluster) (Store, error) {
+	switch backend := Backend(); backend {
+	case BackendS3:
+		connectionDetails, err := s3manager.GetConnectionDetails(ctx, cluster,
+			helmchart.Namespace(), helmchart.S3ConnectionDetailsSecretName)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetching the S3 connection details from the Kubernetes secret")
+		}
+
+		manager, err := s3manager.New(connectionDetails)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating an S3 manager")
+		}
+		return manager, nil
+	case BackendFilesystem:
+		filesystem, err := NewFilesystem(Path(), Group())
+		if err != nil {
+			return nil, errors.Wrap(err, "creating the filesystem blob store")
+		}
+		return filesystem, nil
+	default:
+		return nil, errors.Errorf("unknown blob storage backend '%s'", backend)
+	}
+}
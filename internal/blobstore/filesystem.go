@@ -0,0 +1,279 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// metaSuffix is the suffix of the files holding the metadata of the blobs.
+	metaSuffix = ".meta"
+	// uploadPattern is the pattern of the temporary names of the blobs being written.
+	uploadPattern = ".upload-*"
+
+	// dirMode and fileMode give the group read access to the blobs. The staging jobs
+	// run as another user than the server, and read the blobs through the group.
+	dirMode  = 0750
+	fileMode = 0640
+)
+
+// Filesystem stores the blobs as files of a directory. The metadata of a blob is kept
+// next to it, as JSON. Blobs are written under a temporary name first, and renamed when
+// complete, so that partial blobs are never seen.
+type Filesystem struct {
+	dir string
+	gid int
+}
+
+// NewFilesystem returns a store keeping the blobs in the directory. The directory is
+// created if missing. The directory and the blobs belong to the group gid, readable by
+// it. A negative gid keeps the group of the server.
+func NewFilesystem(dir string, gid int) (*Filesystem, error) {
+	if dir == "" {
+		return nil, errors.New("no directory for the blobs")
+	}
+
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return nil, errors.Wrap(err, "creating the blob directory")
+	}
+	if err := os.Chmod(dir, dirMode); err != nil {
+		return nil, errors.Wrap(err, "setting the permissions of the blob directory")
+	}
+	if err := os.Chown(dir, -1, gid); err != nil {
+		return nil, errors.Wrap(err, "setting the group of the blob directory")
+	}
+
+	return &Filesystem{dir: dir, gid: gid}, nil
+}
+
+// Upload stores the file as a new blob, and returns its blobUID.
+func (f *Filesystem) Upload(ctx context.Context, filepath string, metadata map[string]string) (string, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", errors.Wrap(err, "opening the file")
+	}
+	defer file.Close()
+
+	return f.UploadStream(ctx, file, -1, metadata)
+}
+
+// UploadStream stores the content of the reader as a new blob, and returns its blobUID. A
+// negative size means the size is not known.
+func (f *Filesystem) UploadStream(ctx context.Context, file io.Reader, size int64, metadata map[string]string) (string, error) {
+	blobUID := uuid.New().String()
+
+	tmp, err := os.CreateTemp(f.dir, uploadPattern)
+	if err != nil {
+		return "", errors.Wrap(err, "creating the new blob")
+	}
+	defer func() {
+		// Leftover of a failed upload, if any
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := f.share(tmp); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+
+	written, err := io.Copy(tmp, file)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "writing the new blob")
+	}
+	if size >= 0 && written != size {
+		return "", errors.Errorf("blob size mismatch, expected %d bytes, got %d", size, written)
+	}
+
+	canonical := map[string]string{}
+	for key, value := range metadata {
+		canonical[http.CanonicalHeaderKey(key)] = value
+	}
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+
+	if err := f.writeMeta(blobUID, data); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), f.path(blobUID)); err != nil {
+		_ = os.Remove(f.path(blobUID) + metaSuffix)
+		return "", errors.Wrap(err, "writing the new blob")
+	}
+
+	return blobUID, nil
+}
+
+// Meta retrieves the meta data for the blob specified by its blobUID.
+func (f *Filesystem) Meta(ctx context.Context, blobUID string) (map[string]string, error) {
+	if err := validateBlobUID(blobUID); err != nil {
+		return map[string]string{}, err
+	}
+
+	if _, err := os.Stat(f.path(blobUID)); err != nil {
+		return map[string]string{}, errors.Wrap(err, "reading the blob")
+	}
+
+	data, err := os.ReadFile(f.path(blobUID) + metaSuffix)
+	if err != nil {
+		return map[string]string{}, errors.Wrap(err, "reading the blob meta data")
+	}
+
+	metadata := map[string]string{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return map[string]string{}, errors.Wrap(err, "decoding the blob meta data")
+	}
+
+	return metadata, nil
+}
+
+// DeleteObject removes the blob and its meta data.
+func (f *Filesystem) DeleteObject(ctx context.Context, blobUID string) error {
+	if err := validateBlobUID(blobUID); err != nil {
+		return err
+	}
+
+	for _, path := range []string{f.path(blobUID), f.path(blobUID) + metaSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "removing the blob")
+		}
+	}
+
+	return nil
+}
+
+// ListBlobs returns all the stored blobs.
+func (f *Filesystem) ListBlobs(ctx context.Context) ([]models.BlobInfo, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing the blobs")
+	}
+
+	blobs := []models.BlobInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, metaSuffix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			// Removed while listing
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "listing the blobs")
+		}
+
+		blobs = append(blobs, models.BlobInfo{
+			BlobUID:      name,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+
+	return blobs, nil
+}
+
+// RemoveStaleUploads removes the partial blobs of the uploads interrupted before the
+// given time, e.g. by a crash of the server. It returns the number of removed files.
+func (f *Filesystem) RemoveStaleUploads(ctx context.Context, before time.Time) (int, error) {
+	uploads, err := filepath.Glob(filepath.Join(f.dir, uploadPattern))
+	if err != nil {
+		return 0, errors.Wrap(err, "listing the uploads")
+	}
+
+	removed := 0
+	for _, upload := range uploads {
+		info, err := os.Stat(upload)
+		if os.IsNotExist(err) {
+			// Completed or failed while listing
+			continue
+		}
+		if err != nil {
+			return removed, errors.Wrap(err, "listing the uploads")
+		}
+		// An upload in progress keeps modifying its file.
+		if !info.ModTime().Before(before) {
+			continue
+		}
+
+		if err := os.Remove(upload); err != nil && !os.IsNotExist(err) {
+			return removed, errors.Wrap(err, "removing the upload")
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// writeMeta writes the meta data of the blob, readable like the blob.
+func (f *Filesystem) writeMeta(blobUID string, data []byte) error {
+	file, err := os.OpenFile(f.path(blobUID)+metaSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode)
+	if err != nil {
+		return errors.Wrap(err, "writing the blob meta data")
+	}
+
+	err = f.share(file)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return errors.Wrap(err, "writing the blob meta data")
+	}
+
+	return nil
+}
+
+// share makes the file readable by the group of the blobs. The mode is set explicitly,
+// as the umask of the server may remove the group permissions.
+func (f *Filesystem) share(file *os.File) error {
+	if err := file.Chmod(fileMode); err != nil {
+		return errors.Wrap(err, "setting the permissions of the blob")
+	}
+	if err := file.Chown(-1, f.gid); err != nil {
+		return errors.Wrap(err, "setting the group of the blob")
+	}
+	return nil
+}
+
+func (f *Filesystem) path(blobUID string) string {
+	return filepath.Join(f.dir, blobUID)
+}
+
+// validateBlobUID ensures that the blobUID names a file of the blob directory.
+func validateBlobUID(blobUID string) error {
+	if _, err := uuid.Parse(blobUID); err != nil {
+		return errors.Errorf("invalid blob id '%s'", blobUID)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/epinio/epinio/internal/blobstore"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filesystem", func() {
+	var dir string
+	var store *blobstore.Filesystem
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "epinio-blobs")
+		Expect(err).ToNot(HaveOccurred())
+
+		store, err = blobstore.NewFilesystem(filepath.Join(dir, "blobs"), os.Getegid())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("stores blobs with their meta data", func() {
+		blobUID, err := store.UploadStream(ctx, strings.NewReader("sources"), 7, map[string]string{
+			"app": "sample", "namespace": "workspace",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		meta, err := store.Meta(ctx, blobUID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(meta).To(Equal(map[string]string{"App": "sample", "Namespace": "workspace"}))
+
+		data, err := os.ReadFile(filepath.Join(dir, "blobs", blobUID))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("sources"))
+	})
+
+	It("uploads files", func() {
+		file := filepath.Join(dir, "sources.tar")
+		Expect(os.WriteFile(file, []byte("tarball"), 0600)).To(Succeed())
+
+		blobUID, err := store.Upload(ctx, file, map[string]string{"app": "sample"})
+		Expect(err).ToNot(HaveOccurred())
+
+		blobs, err := store.ListBlobs(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blobs).To(HaveLen(1))
+		Expect(blobs[0].BlobUID).To(Equal(blobUID))
+		Expect(blobs[0].Size).To(Equal(int64(7)))
+	})
+
+	It("rejects incomplete uploads", func() {
+		_, err := store.UploadStream(ctx, strings.NewReader("short"), 100, map[string]string{})
+		Expect(err).To(MatchError(ContainSubstring("size mismatch")))
+
+		blobs, err := store.ListBlobs(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(blobs).To(BeEmpty())
+
+		entries, err := os.ReadDir(filepath.Join(dir, "blobs"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("deletes blobs and their meta data", func() {
+		blobUID, err := store.UploadStream(ctx, strings.NewReader("sources"), -1, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(store.DeleteObject(ctx, blobUID)).To(Succeed())
+		Expect(store.DeleteObject(ctx, blobUID)).To(Succeed())
+
+		_, err = store.Meta(ctx, blobUID)
+		Expect(err).To(HaveOccurred())
+
+		entries, err := os.ReadDir(filepath.Join(dir, "blobs"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("makes the blobs readable by their group", func() {
+		blobUID, err := store.UploadStream(ctx, strings.NewReader("sources"), -1, map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		info, err := os.Stat(filepath.Join(dir, "blobs"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+		for _, name := range []string{blobUID, blobUID + ".meta"} {
+			info, err := os.Stat(filepath.Join(dir, "blobs", name))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+			Expect(info.Sys().(*syscall.Stat_t).Gid).To(Equal(uint32(os.Getegid())))
+		}
+	})
+
+	It("removes the stale uploads", func() {
+		stale := filepath.Join(dir, "blobs", ".upload-stale")
+		fresh := filepath.Join(dir, "blobs", ".upload-fresh")
+		Expect(os.WriteFile(stale, []byte("partial"), 0600)).To(Succeed())
+		Expect(os.WriteFile(fresh, []byte("partial"), 0600)).To(Succeed())
+		old := time.Now().Add(-2 * time.Hour)
+		Expect(os.Chtimes(stale, old, old)).To(Succeed())
+
+		removed, err := store.RemoveStaleUploads(ctx, time.Now().Add(-time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removed).To(Equal(1))
+
+		Expect(stale).ToNot(BeAnExistingFile())
+		Expect(fresh).To(BeAnExistingFile())
+	})
+
+	It("rejects blob ids which are not uuids", func() {
+		_, err := store.Meta(ctx, "../secret")
+		Expect(err).To(MatchError(ContainSubstring("invalid blob id")))
+
+		err = store.DeleteObject(ctx, "../secret")
+		Expect(err).To(MatchError(ContainSubstring("invalid blob id")))
+	})
+})
@@ -0,0 +1,71 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdAppCache implements the command: epinio app cache
+var CmdAppCache = &cobra.Command{
+	Use:   "cache",
+	Short: "Epinio application build cache management",
+	Long:  `Inspect and clear the build cache of epinio applications`,
+}
+
+func init() {
+	CmdAppCache.AddCommand(CmdAppCacheShow)
+	CmdAppCache.AddCommand(CmdAppCacheClear)
+}
+
+// CmdAppCacheShow implements the command: epinio app cache show
+var CmdAppCacheShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Show the build cache of the application",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppCacheShow(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app cache")
+	},
+}
+
+// CmdAppCacheClear implements the command: epinio app cache clear
+var CmdAppCacheClear = &cobra.Command{
+	Use:               "clear NAME",
+	Short:             "Clear the build cache of the application",
+	Long:              "Clear the build cache of the application. The next staging of the application starts from scratch.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppCacheClear(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error clearing app cache")
+	},
+}
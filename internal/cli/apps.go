@@ -35,6 +35,12 @@ func init() {
 	CmdAppLogs.Flags().Bool("staging", false, "show the staging logs of the application")
 	CmdAppExec.Flags().StringP("instance", "i", "", "The name of the instance to shell to")
 	CmdAppPortForward.Flags().StringSliceVar(&portForwardAddress, "address", []string{"localhost"}, "Addresses to listen on (comma separated). Only accepts IP addresses or localhost as a value. When localhost is supplied, kubectl will try to bind on both 127.0.0.1 and ::1 and will fail if neither of these addresses are available to bind.")
+	CmdAppRestage.Flags().Bool("no-cache", false, "wipe the build cache before staging")
+	CmdAppRollback.Flags().String("to", "", "stage id of the build to roll back to. Defaults to the build before the deployed one")
+	CmdAppPromote.Flags().Int32("weight", 0, "percentage of the traffic to route to the canary. Omit to promote fully")
+	CmdAppHistory.Flags().IntSlice("diff", nil, "two revisions to compare, as REV1,REV2")
+	CmdAppDeploy.Flags().Bool("dry-run", false, "render the app chart and show the differences to the deployed release, without deploying")
+	CmdAppDeploy.Flags().String("container-image-url", "", "Container image url to deploy instead of the current image")
 	CmdAppPortForward.Flags().StringVarP(&portForwardInstance, "instance", "i", "", "The name of the instance to shell to")
 
 	routeOption(CmdAppCreate)
@@ -50,7 +56,14 @@ func init() {
 
 	CmdAppCreate.Flags().String("app-chart", "", "App chart to use for deployment")
 	CmdAppUpdate.Flags().String("app-chart", "", "App chart to use for deployment")
-
+	deploymentOption(CmdAppCreate)
+	deploymentOption(CmdAppUpdate)
+	resourcesOption(CmdAppCreate)
+	resourcesOption(CmdAppUpdate)
+
+	CmdApp.AddCommand(CmdAppBuilds)
+	CmdApp.AddCommand(CmdAppHistory)
+	CmdApp.AddCommand(CmdAppCache) // See appcache.go for implementation
 	CmdApp.AddCommand(CmdAppCreate)
 	CmdApp.AddCommand(CmdAppChart) // See chart.go for implementation
 	CmdApp.AddCommand(CmdAppEnv)   // See env.go for implementation
@@ -67,6 +80,12 @@ func init() {
 	CmdApp.AddCommand(CmdAppPush) // See push.go for implementation
 	CmdApp.AddCommand(CmdAppRestart)
 	CmdApp.AddCommand(CmdAppRestage)
+	CmdApp.AddCommand(CmdAppRollback)
+	CmdApp.AddCommand(CmdAppPromote)
+	CmdApp.AddCommand(CmdAppAbort)
+	CmdApp.AddCommand(CmdAppDeploy)
+	CmdApp.AddCommand(CmdAppStage)   // See appstage.go for implementation
+	CmdApp.AddCommand(CmdAppWebhook) // See appwebhook.go for implementation
 }
 
 // CmdAppList implements the command: epinio app list
@@ -122,6 +141,16 @@ var CmdAppCreate = &cobra.Command{
 			return err
 		}
 
+		m, err = manifest.UpdateDeployment(m, cmd)
+		if err != nil {
+			return errors.Wrap(err, "unable to get deployment strategy")
+		}
+
+		m, err = manifest.UpdateResources(m, cmd)
+		if err != nil {
+			return errors.Wrap(err, "unable to get resources")
+		}
+
 		err = client.AppCreate(args[0], m.Configuration)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error creating app")
@@ -291,6 +320,16 @@ var CmdAppUpdate = &cobra.Command{
 			return errors.Wrap(err, "unable to update domains")
 		}
 
+		m, err = manifest.UpdateDeployment(m, cmd)
+		if err != nil {
+			return errors.Wrap(err, "unable to get deployment strategy")
+		}
+
+		m, err = manifest.UpdateResources(m, cmd)
+		if err != nil {
+			return errors.Wrap(err, "unable to get resources")
+		}
+
 		err = client.AppUpdate(args[0], m.Configuration)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error updating the app")
@@ -337,6 +376,63 @@ var CmdAppRestart = &cobra.Command{
 	},
 }
 
+// CmdAppBuilds implements the command: epinio app builds
+var CmdAppBuilds = &cobra.Command{
+	Use:               "builds NAME",
+	Short:             "List the builds of the application",
+	Long:              "List the build history of the application, most recent first",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppBuilds(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error listing app builds")
+	},
+}
+
+// CmdAppHistory implements the command: epinio app history
+var CmdAppHistory = &cobra.Command{
+	Use:               "history NAME [--diff REV1,REV2]",
+	Short:             "List the release history of the application",
+	Long:              "List the revisions of the helm release of the application, most recent first. With `--diff` the values and manifests of the two revisions are compared instead. The history starts over with each promotion of a blue/green or canary candidate, see `epinio app builds` for the builds before.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		revisions, err := cmd.Flags().GetIntSlice("diff")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --diff")
+		}
+
+		if cmd.Flags().Changed("diff") {
+			if len(revisions) != 2 {
+				return errors.New("option --diff expects two revisions, as REV1,REV2")
+			}
+
+			err = client.AppHistoryDiff(args[0], revisions[0], revisions[1])
+			// Note: errors.Wrap (nil, "...") == nil
+			return errors.Wrap(err, "error comparing app releases")
+		}
+
+		err = client.AppHistory(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error listing app release history")
+	},
+}
+
 // CmdAppRestage implements the command: epinio app restage
 var CmdAppRestage = &cobra.Command{
 	Use:               "restage NAME",
@@ -351,8 +447,120 @@ var CmdAppRestage = &cobra.Command{
 			return errors.Wrap(err, "error initializing cli")
 		}
 
-		err = client.AppRestage(args[0])
+		noCache, err := cmd.Flags().GetBool("no-cache")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --no-cache")
+		}
+
+		err = client.AppRestage(args[0], noCache)
 		// Note: errors.Wrap (nil, "...") == nil
 		return errors.Wrap(err, "error restaging app")
 	},
 }
+
+// CmdAppRollback implements the command: epinio app rollback
+var CmdAppRollback = &cobra.Command{
+	Use:               "rollback NAME [--to STAGE_ID]",
+	Short:             "Roll the application back to an earlier build",
+	Long:              "Redeploy the application with the image of an earlier build and its current configuration. See `epinio app builds` for the available builds.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		stageID, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --to")
+		}
+
+		err = client.AppRollback(args[0], stageID)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error rolling back app")
+	},
+}
+
+// CmdAppPromote implements the command: epinio app promote
+var CmdAppPromote = &cobra.Command{
+	Use:               "promote NAME [--weight PERCENT]",
+	Short:             "Promote the candidate of the application",
+	Long:              "Switch the routes of a blue/green or canary deployment over to the candidate build. For a canary `--weight` below 100 only shifts the traffic split.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		var weight *int32
+		if cmd.Flags().Changed("weight") {
+			w, err := cmd.Flags().GetInt32("weight")
+			if err != nil {
+				return errors.Wrap(err, "error reading option --weight")
+			}
+			weight = &w
+		}
+
+		err = client.AppPromote(args[0], weight)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error promoting app")
+	},
+}
+
+// CmdAppAbort implements the command: epinio app abort
+var CmdAppAbort = &cobra.Command{
+	Use:               "abort NAME",
+	Short:             "Remove the candidate of the application",
+	Long:              "Remove the candidate build of a blue/green or canary deployment, keeping the live build.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppAbort(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error aborting app candidate")
+	},
+}
+
+// CmdAppDeploy implements the command: epinio app deploy
+var CmdAppDeploy = &cobra.Command{
+	Use:               "deploy NAME [--dry-run] [--container-image-url URL]",
+	Short:             "Deploy the application",
+	Long:              "Deploy the current image of the application, or the image given by `--container-image-url`. With `--dry-run` the app chart is only rendered, and the values and manifests are shown together with their differences to the deployed release.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --dry-run")
+		}
+		imageURL, err := cmd.Flags().GetString("container-image-url")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --container-image-url")
+		}
+
+		err = client.AppDeploy(args[0], imageURL, dryRun)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error deploying app")
+	},
+}
@@ -0,0 +1,50 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdAppStage implements the command: epinio app stage
+var CmdAppStage = &cobra.Command{
+	Use:   "stage",
+	Short: "Epinio application staging management",
+	Long:  `Manage the staging of epinio applications`,
+}
+
+func init() {
+	CmdAppStage.AddCommand(CmdAppStageCancel)
+}
+
+// CmdAppStageCancel implements the command: epinio app stage cancel
+var CmdAppStageCancel = &cobra.Command{
+	Use:               "cancel NAME",
+	Short:             "Cancel the running staging of the application",
+	Long:              "Cancel the running staging of the application. The deployed application is not touched.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppStageCancel(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error cancelling app staging")
+	},
+}
@@ -0,0 +1,95 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdAppWebhook implements the command: epinio app webhook
+var CmdAppWebhook = &cobra.Command{
+	Use:   "webhook",
+	Short: "Epinio application git webhook management",
+	Long:  `Manage the git webhooks which build and deploy epinio applications on push`,
+}
+
+func init() {
+	CmdAppWebhook.AddCommand(CmdAppWebhookShow)
+	CmdAppWebhook.AddCommand(CmdAppWebhookEnable)
+	CmdAppWebhook.AddCommand(CmdAppWebhookDisable)
+}
+
+// CmdAppWebhookShow implements the command: epinio app webhook show
+var CmdAppWebhookShow = &cobra.Command{
+	Use:               "show NAME",
+	Short:             "Show the git webhook of the application",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookShow(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error showing app webhook")
+	},
+}
+
+// CmdAppWebhookEnable implements the command: epinio app webhook enable
+var CmdAppWebhookEnable = &cobra.Command{
+	Use:   "enable NAME",
+	Short: "Enable the git webhook of the application",
+	Long: `Enable the git webhook of the application, with a new secret.
+Register the shown url and secret with the GitHub, GitLab, or Gitea repository of the application.
+A push to the tracked branch then imports, stages, and deploys the application.
+Enabling an already enabled webhook replaces its secret.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookEnable(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error enabling app webhook")
+	},
+}
+
+// CmdAppWebhookDisable implements the command: epinio app webhook disable
+var CmdAppWebhookDisable = &cobra.Command{
+	Use:               "disable NAME",
+	Short:             "Disable the git webhook of the application",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: matchingAppsFinder,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.AppWebhookDisable(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error disabling app webhook")
+	},
+}
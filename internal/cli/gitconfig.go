@@ -0,0 +1,156 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	CmdGitConfigCreate.Flags().String("username", "", "User to authenticate as (default \"git\")")
+	CmdGitConfigCreate.Flags().String("password", "", "Password or access token for https access")
+	CmdGitConfigCreate.Flags().String("ssh-key-file", "", "File holding the private key for ssh access")
+	CmdGitConfigCreate.Flags().String("known-hosts-file", "", "File holding the known hosts for ssh access. Required with a private key")
+
+	CmdGitConfig.AddCommand(CmdGitConfigCreate)
+	CmdGitConfig.AddCommand(CmdGitConfigList)
+	CmdGitConfig.AddCommand(CmdGitConfigDelete)
+}
+
+// CmdGitConfig implements the command: epinio gitconfig
+var CmdGitConfig = &cobra.Command{
+	Use:           "gitconfig",
+	Aliases:       []string{"gitconfigs"},
+	Short:         "Epinio git configuration features",
+	Long:          `Manage the credentials used to import applications from private git repositories`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+// CmdGitConfigCreate implements the command: epinio gitconfig create
+var CmdGitConfigCreate = &cobra.Command{
+	Use:   "create NAME URL",
+	Short: "Create a git configuration",
+	Long: `Create a git configuration holding either a password or access token for https access,
+or a private key for ssh access. Importing from a git repository uses the configuration of
+the namespace with the longest url which is a prefix of the repository url.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		request := models.GitConfigCreateRequest{
+			Name: args[0],
+			URL:  args[1],
+		}
+
+		request.Username, err = cmd.Flags().GetString("username")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --username")
+		}
+		request.Password, err = cmd.Flags().GetString("password")
+		if err != nil {
+			return errors.Wrap(err, "error reading option --password")
+		}
+		request.PrivateKey, err = readFileOption(cmd, "ssh-key-file")
+		if err != nil {
+			return err
+		}
+		request.KnownHosts, err = readFileOption(cmd, "known-hosts-file")
+		if err != nil {
+			return err
+		}
+
+		if request.Password == "" && request.PrivateKey == "" {
+			return errors.New("either --password or --ssh-key-file is required")
+		}
+
+		err = client.GitConfigCreate(request)
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error creating git configuration")
+	},
+}
+
+// CmdGitConfigList implements the command: epinio gitconfig list
+var CmdGitConfigList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists git configurations",
+	Long:  "Lists the git configurations in the targeted namespace, without their secrets",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.GitConfigs()
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error listing git configurations")
+	},
+}
+
+// CmdGitConfigDelete implements the command: epinio gitconfig delete
+var CmdGitConfigDelete = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a git configuration",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.GitConfigDelete(args[0])
+		// Note: errors.Wrap (nil, "...") == nil
+		return errors.Wrap(err, "error deleting git configuration")
+	},
+}
+
+// readFileOption returns the contents of the file named by the option, or an empty string
+// if the option is not set.
+func readFileOption(cmd *cobra.Command, option string) (string, error) {
+	path, err := cmd.Flags().GetString(option)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading option --%s", option)
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading file of option --%s", option)
+	}
+
+	return string(content), nil
+}
@@ -21,6 +21,7 @@ func init() {
 	CmdLogin.Flags().StringP("password", "p", "", "password that will be used to login")
 	CmdLogin.Flags().Bool("trust-ca", false, "automatically trust the unknown CA")
 	CmdLogin.Flags().Bool("oidc", false, "perform OIDC authentication (user and password will be ignored)")
+	CmdLogin.Flags().String("token", "", "personal access token that will be used to login (user and password will be ignored)")
 	CmdLogin.Flags().Bool("prompt", false, "enable the prompt of the authorization code and disable the local server during OIDC authentication")
 }
 
@@ -64,6 +65,14 @@ var CmdLogin = &cobra.Command{
 			return err
 		}
 
+		token, err := cmd.Flags().GetString("token")
+		if err != nil {
+			return err
+		}
+
+		if token != "" {
+			return client.LoginToken(cmd.Context(), token, address, trustCA)
+		}
 		if oidc {
 			return client.LoginOIDC(cmd.Context(), address, trustCA, prompt)
 		}
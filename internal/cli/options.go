@@ -16,6 +16,7 @@ import (
 
 	"github.com/epinio/epinio/internal/api/v1/application"
 	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +31,28 @@ func routeOption(cmd *cobra.Command) {
 	cmd.Flags().StringSliceP("route", "r", []string{}, "Custom route to use for the application (a subdomain of the default domain will be used if this is not set). Can be set multiple times to use multiple routes with the same application.")
 }
 
+// deploymentOption initializes the --deployment-strategy and --canary-weight options for
+// the provided command
+func deploymentOption(cmd *cobra.Command) {
+	cmd.Flags().String("deployment-strategy", "", "How new builds replace the running application: rolling, bluegreen, or canary")
+	cmd.Flags().Int32("canary-weight", 0, "Percentage of the traffic routed to a canary before its promotion")
+	// nolint:errcheck // Unable to handle error in init block this will be called from
+	cmd.RegisterFlagCompletionFunc("deployment-strategy",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{
+				models.DeploymentStrategyRolling,
+				models.DeploymentStrategyBlueGreen,
+				models.DeploymentStrategyCanary,
+			}, cobra.ShellCompDirectiveNoFileComp
+		})
+}
+
+// resourcesOption initializes the --memory and --cpu options for the provided command
+func resourcesOption(cmd *cobra.Command) {
+	cmd.Flags().String("memory", "", "Memory of the application, e.g. 512Mi. Used as both request and limit")
+	cmd.Flags().String("cpu", "", "CPU requested by the application, e.g. 250m")
+}
+
 // bindOption initializes the --bind/-b option for the provided command
 func bindOption(cmd *cobra.Command) {
 	cmd.Flags().StringSliceP("bind", "b", []string{}, "configurations to bind immediately")
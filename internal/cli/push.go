@@ -27,6 +27,8 @@ var ()
 func init() {
 	// The following options override manifest data
 	CmdAppPush.Flags().StringP("git", "g", "", "Git repository and revision of sources separated by comma (e.g. GIT_URL,REVISION)")
+	CmdAppPush.Flags().String("git-subdir", "", "Directory of the git repository holding the sources")
+	CmdAppPush.Flags().Bool("git-submodules", false, "Import the submodules of the git repository")
 	CmdAppPush.Flags().String("container-image-url", "", "Container image url for the app workload image")
 	CmdAppPush.Flags().StringP("name", "n", "", "Application name. (mandatory if no manifest is provided)")
 	CmdAppPush.Flags().StringP("path", "p", "", "Path to application sources.")
@@ -38,13 +40,20 @@ func init() {
 	envOption(CmdAppPush)
 	chartValueOption(CmdAppPush)
 	instancesOption(CmdAppPush)
+	deploymentOption(CmdAppPush)
+	resourcesOption(CmdAppPush)
 }
 
 // CmdAppPush implements the command: epinio app push
 var CmdAppPush = &cobra.Command{
 	Use:   "push [flags] [PATH_TO_APPLICATION_MANIFEST]",
 	Short: "Push an application declared in the specified manifest",
-	Args:  cobra.RangeArgs(0, 1),
+	Long: `Push an application declared in the specified manifest.
+
+Local sources leave out the files and directories matching the patterns of the
+.epinioignore file, or of the .gitignore file without one. Like with git, the ignore
+file of a directory applies to everything below that directory.`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
@@ -90,6 +99,16 @@ var CmdAppPush = &cobra.Command{
 			return err
 		}
 
+		m, err = manifest.UpdateDeployment(m, cmd)
+		if err != nil {
+			return err
+		}
+
+		m, err = manifest.UpdateResources(m, cmd)
+		if err != nil {
+			return err
+		}
+
 		// Final manifest verify: Name is specified
 
 		if m.Name == "" {
@@ -111,10 +111,13 @@ func init() {
 	rootCmd.AddCommand(CmdApp)
 	rootCmd.AddCommand(CmdTarget)
 	rootCmd.AddCommand(CmdConfiguration)
+	rootCmd.AddCommand(CmdGitConfig)
 	rootCmd.AddCommand(CmdServer)
 	rootCmd.AddCommand(cmdVersion)
 	rootCmd.AddCommand(CmdServices)
 	rootCmd.AddCommand(CmdLogin)
+	rootCmd.AddCommand(CmdToken)
+	rootCmd.AddCommand(CmdUser)
 
 	// Hidden command providing developer tools
 	rootCmd.AddCommand(CmdDebug)
@@ -26,6 +26,8 @@ import (
 
 	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/helpers/tracelog"
+	"github.com/epinio/epinio/internal/application"
+	"github.com/epinio/epinio/internal/blobstore"
 	"github.com/epinio/epinio/internal/cli/server"
 	"github.com/epinio/epinio/internal/upgraderesponder"
 	"github.com/epinio/epinio/internal/version"
@@ -75,6 +77,30 @@ func init() {
 	err = viper.BindEnv("s3-certificate-secret", "S3_CERTIFICATE_SECRET")
 	checkErr(err)
 
+	flags.String("blob-storage", "s3", "(BLOB_STORAGE) Storage of the application sources [s3,filesystem]")
+	err = viper.BindPFlag("blob-storage", flags.Lookup("blob-storage"))
+	checkErr(err)
+	err = viper.BindEnv("blob-storage", "BLOB_STORAGE")
+	checkErr(err)
+
+	flags.String("blob-storage-path", "", "(BLOB_STORAGE_PATH) Directory holding the application sources, for the filesystem blob storage. The PVC named by --blob-storage-pvc has to be mounted there.")
+	err = viper.BindPFlag("blob-storage-path", flags.Lookup("blob-storage-path"))
+	checkErr(err)
+	err = viper.BindEnv("blob-storage-path", "BLOB_STORAGE_PATH")
+	checkErr(err)
+
+	flags.String("blob-storage-pvc", "", "(BLOB_STORAGE_PVC) PVC holding the application sources, for the filesystem blob storage. It is mounted into the staging jobs, and needs ReadWriteMany access when they run on other nodes than the server.")
+	err = viper.BindPFlag("blob-storage-pvc", flags.Lookup("blob-storage-pvc"))
+	checkErr(err)
+	err = viper.BindEnv("blob-storage-pvc", "BLOB_STORAGE_PVC")
+	checkErr(err)
+
+	flags.Int("blob-storage-group", -1, "(BLOB_STORAGE_GROUP) Group owning the application sources, for the filesystem blob storage. The sources are readable by the group, and the staging jobs are made members of it. Leave at -1 for the group of the server.")
+	err = viper.BindPFlag("blob-storage-group", flags.Lookup("blob-storage-group"))
+	checkErr(err)
+	err = viper.BindEnv("blob-storage-group", "BLOB_STORAGE_GROUP")
+	checkErr(err)
+
 	flags.String("trace-output", "text", "(TRACE_OUTPUT) logs output format [text,json]")
 	err = viper.BindPFlag("trace-output", flags.Lookup("trace-output"))
 	checkErr(err)
@@ -93,6 +119,12 @@ func init() {
 	err = viper.BindEnv("app-image-exporter", "APP_IMAGE_EXPORTER")
 	checkErr(err)
 
+	flags.Bool("app-health-checks", false, "(APP_HEALTH_CHECKS) Hand the health checks of the applications to the app chart, as epinio.probes values. The app chart has to support them.")
+	err = viper.BindPFlag("app-health-checks", flags.Lookup("app-health-checks"))
+	checkErr(err)
+	err = viper.BindEnv("app-health-checks", "APP_HEALTH_CHECKS")
+	checkErr(err)
+
 	flags.Bool("disable-tracking", false, "(DISABLE_TRACKING) Disable tracking of the running Epinio and Kubernetes versions")
 	err = viper.BindPFlag("disable-tracking", flags.Lookup("disable-tracking"))
 	checkErr(err)
@@ -105,18 +137,118 @@ func init() {
 	err = viper.BindEnv("staging-service-account-name", "STAGING_SERVICE_ACCOUNT_NAME")
 	checkErr(err)
 
+	flags.String("staging-cpu-request", "", "(STAGING_CPU_REQUEST) CPU request of the staging jobs, e.g. 500m. Applications may override it. Leave empty for no request.")
+	err = viper.BindPFlag("staging-cpu-request", flags.Lookup("staging-cpu-request"))
+	checkErr(err)
+	err = viper.BindEnv("staging-cpu-request", "STAGING_CPU_REQUEST")
+	checkErr(err)
+
+	flags.String("staging-cpu-limit", "", "(STAGING_CPU_LIMIT) CPU limit of the staging jobs, e.g. 2. Applications may override it. Leave empty for no limit.")
+	err = viper.BindPFlag("staging-cpu-limit", flags.Lookup("staging-cpu-limit"))
+	checkErr(err)
+	err = viper.BindEnv("staging-cpu-limit", "STAGING_CPU_LIMIT")
+	checkErr(err)
+
+	flags.String("staging-memory-request", "", "(STAGING_MEMORY_REQUEST) Memory request of the staging jobs, e.g. 1Gi. Applications may override it. Leave empty for no request.")
+	err = viper.BindPFlag("staging-memory-request", flags.Lookup("staging-memory-request"))
+	checkErr(err)
+	err = viper.BindEnv("staging-memory-request", "STAGING_MEMORY_REQUEST")
+	checkErr(err)
+
+	flags.String("staging-memory-limit", "", "(STAGING_MEMORY_LIMIT) Memory limit of the staging jobs, e.g. 4Gi. Applications may override it. Leave empty for no limit.")
+	err = viper.BindPFlag("staging-memory-limit", flags.Lookup("staging-memory-limit"))
+	checkErr(err)
+	err = viper.BindEnv("staging-memory-limit", "STAGING_MEMORY_LIMIT")
+	checkErr(err)
+
+	flags.String("staging-max-cpu", "", "(STAGING_MAX_CPU) Maximum CPU request and limit applications may set for their staging jobs. Leave empty for no maximum.")
+	err = viper.BindPFlag("staging-max-cpu", flags.Lookup("staging-max-cpu"))
+	checkErr(err)
+	err = viper.BindEnv("staging-max-cpu", "STAGING_MAX_CPU")
+	checkErr(err)
+
+	flags.String("staging-max-memory", "", "(STAGING_MAX_MEMORY) Maximum memory request and limit applications may set for their staging jobs. Leave empty for no maximum.")
+	err = viper.BindPFlag("staging-max-memory", flags.Lookup("staging-max-memory"))
+	checkErr(err)
+	err = viper.BindEnv("staging-max-memory", "STAGING_MAX_MEMORY")
+	checkErr(err)
+
+	flags.String("staging-node-selector", "", "(STAGING_NODE_SELECTOR) Node selector of the staging jobs, as comma-separated key=value pairs. Applications may add to it.")
+	err = viper.BindPFlag("staging-node-selector", flags.Lookup("staging-node-selector"))
+	checkErr(err)
+	err = viper.BindEnv("staging-node-selector", "STAGING_NODE_SELECTOR")
+	checkErr(err)
+
+	flags.String("staging-tolerations", "", "(STAGING_TOLERATIONS) Tolerations of the staging jobs, as a JSON list of kubernetes tolerations.")
+	err = viper.BindPFlag("staging-tolerations", flags.Lookup("staging-tolerations"))
+	checkErr(err)
+	err = viper.BindEnv("staging-tolerations", "STAGING_TOLERATIONS")
+	checkErr(err)
+
 	flags.String("upgrade-responder-address", upgraderesponder.UpgradeResponderAddress, "(UPGRADE_RESPONDER_ADDRESS) Disable tracking of the running Epinio and Kubernetes versions")
 	err = viper.BindPFlag("upgrade-responder-address", flags.Lookup("upgrade-responder-address"))
 	checkErr(err)
 	err = viper.BindEnv("upgrade-responder-address", "UPGRADE_RESPONDER_ADDRESS")
 	checkErr(err)
 
+	flags.String("audit-log-file", "", "(AUDIT_LOG_FILE) File recording the mutating API requests as JSON lines. Leave empty to disable the audit log.")
+	err = viper.BindPFlag("audit-log-file", flags.Lookup("audit-log-file"))
+	checkErr(err)
+	err = viper.BindEnv("audit-log-file", "AUDIT_LOG_FILE")
+	checkErr(err)
+
+	flags.Int("audit-log-max-size", 10, "(AUDIT_LOG_MAX_SIZE) Size in MiB at which the audit log file is rotated. One rotated file is kept.")
+	err = viper.BindPFlag("audit-log-max-size", flags.Lookup("audit-log-max-size"))
+	checkErr(err)
+	err = viper.BindEnv("audit-log-max-size", "AUDIT_LOG_MAX_SIZE")
+	checkErr(err)
+
+	flags.Int("staging-cache-max-age-days", 0, "(STAGING_CACHE_MAX_AGE_DAYS) Remove the build cache of applications which have not staged for this many days. Leave at 0 to keep the caches.")
+	err = viper.BindPFlag("staging-cache-max-age-days", flags.Lookup("staging-cache-max-age-days"))
+	checkErr(err)
+	err = viper.BindEnv("staging-cache-max-age-days", "STAGING_CACHE_MAX_AGE_DAYS")
+	checkErr(err)
+
+	flags.Int("staging-max-concurrent", 0, "(STAGING_MAX_CONCURRENT) Maximum number of staging jobs running at the same time. Further stagings are queued. Leave at 0 for no limit.")
+	err = viper.BindPFlag("staging-max-concurrent", flags.Lookup("staging-max-concurrent"))
+	checkErr(err)
+	err = viper.BindEnv("staging-max-concurrent", "STAGING_MAX_CONCURRENT")
+	checkErr(err)
+
+	flags.Int("staging-max-concurrent-per-namespace", 0, "(STAGING_MAX_CONCURRENT_PER_NAMESPACE) Maximum number of staging jobs running at the same time in a namespace. Further stagings are queued. Leave at 0 for no limit.")
+	err = viper.BindPFlag("staging-max-concurrent-per-namespace", flags.Lookup("staging-max-concurrent-per-namespace"))
+	checkErr(err)
+	err = viper.BindEnv("staging-max-concurrent-per-namespace", "STAGING_MAX_CONCURRENT_PER_NAMESPACE")
+	checkErr(err)
+
+	flags.Int("source-blob-max-age-hours", 0, "(SOURCE_BLOB_MAX_AGE_HOURS) Remove the blobs of application sources no longer referenced after this many hours. Leave at 0 to keep the blobs.")
+	err = viper.BindPFlag("source-blob-max-age-hours", flags.Lookup("source-blob-max-age-hours"))
+	checkErr(err)
+	err = viper.BindEnv("source-blob-max-age-hours", "SOURCE_BLOB_MAX_AGE_HOURS")
+	checkErr(err)
+
+	flags.Int("source-blob-keep-stages", 3, "(SOURCE_BLOB_KEEP_STAGES) Number of the most recent builds of each application whose source blobs are kept.")
+	err = viper.BindPFlag("source-blob-keep-stages", flags.Lookup("source-blob-keep-stages"))
+	checkErr(err)
+	err = viper.BindEnv("source-blob-keep-stages", "SOURCE_BLOB_KEEP_STAGES")
+	checkErr(err)
+
 	version.ChartVersion = os.Getenv("CHART_VERSION")
 	if !strings.HasPrefix(version.ChartVersion, "v") {
 		version.ChartVersion = "v" + version.ChartVersion
 	}
 }
 
+// cacheCollectionInterval is the time between two runs of the build cache collector.
+const cacheCollectionInterval = time.Hour
+
+// blobCollectionInterval is the time between two runs of the source blob collector.
+const blobCollectionInterval = time.Hour
+
+// stagingAdmissionInterval is the time between two checks of the staging queue for jobs
+// which can be started.
+const stagingAdmissionInterval = 5 * time.Second
+
 // CmdServer implements the command: epinio server
 var CmdServer = &cobra.Command{
 	Use:   "server",
@@ -126,6 +258,10 @@ var CmdServer = &cobra.Command{
 		cmd.SilenceUsage = true
 		logger := tracelog.NewLogger().WithName("EpinioServer")
 
+		if err := blobstore.ValidateConfig(); err != nil {
+			return errors.Wrap(err, "error in the blob storage configuration")
+		}
+
 		handler, err := server.NewHandler(logger)
 		if err != nil {
 			return errors.Wrap(err, "error creating handler")
@@ -156,6 +292,41 @@ var CmdServer = &cobra.Command{
 			defer checker.Stop()
 		}
 
+		cacheMaxAgeDays := viper.GetInt("staging-cache-max-age-days")
+		if cacheMaxAgeDays > 0 {
+			logger.Info("Starting build cache collector", "maxAgeDays", cacheMaxAgeDays)
+
+			collector := application.NewCacheCollector(logger,
+				time.Duration(cacheMaxAgeDays)*24*time.Hour, cacheCollectionInterval)
+			collector.Start()
+			defer collector.Stop()
+		}
+
+		blobMaxAgeHours := viper.GetInt("source-blob-max-age-hours")
+		if blobMaxAgeHours > 0 {
+			retention := application.BlobRetention{
+				KeepStages: viper.GetInt("source-blob-keep-stages"),
+				MaxAge:     time.Duration(blobMaxAgeHours) * time.Hour,
+			}
+			logger.Info("Starting source blob collector", "maxAgeHours", blobMaxAgeHours, "keepStages", retention.KeepStages)
+
+			collector := application.NewBlobCollector(logger, retention, blobCollectionInterval)
+			collector.Start()
+			defer collector.Stop()
+		}
+
+		stagingLimits := application.StagingLimits{
+			Global:       viper.GetInt("staging-max-concurrent"),
+			PerNamespace: viper.GetInt("staging-max-concurrent-per-namespace"),
+		}
+		if stagingLimits.Enabled() {
+			logger.Info("Starting staging queue", "global", stagingLimits.Global, "perNamespace", stagingLimits.PerNamespace)
+
+			admitter := application.NewStagingAdmitter(logger, stagingLimits, stagingAdmissionInterval)
+			admitter.Start()
+			defer admitter.Stop()
+		}
+
 		return startServerGracefully(listener, handler)
 	},
 }
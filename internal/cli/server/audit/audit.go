@@ -0,0 +1,203 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records the mutating requests handled by the API server into a durable sink,
+// and gives access to the recorded requests.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+// Sink is the destination of the audit records
+type Sink interface {
+	Write(record models.AuditRecord) error
+	Query(filter Filter) (models.AuditRecordList, error)
+}
+
+// Filter selects the audit records returned by a query. Empty fields match everything.
+type Filter struct {
+	User      string
+	Namespace string
+	// Limit is the maximum number of records returned, 0 means no limit
+	Limit int
+}
+
+// Match returns true if the record is selected by the filter
+func (f Filter) Match(record models.AuditRecord) bool {
+	if f.User != "" && f.User != record.User {
+		return false
+	}
+	if f.Namespace != "" && f.Namespace != record.Namespace {
+		return false
+	}
+	return true
+}
+
+var (
+	sinkMutex sync.RWMutex
+	sink      Sink = NoopSink{}
+)
+
+// SetSink configures the sink used to record the requests
+func SetSink(s Sink) {
+	sinkMutex.Lock()
+	defer sinkMutex.Unlock()
+	sink = s
+}
+
+// GetSink returns the configured sink
+func GetSink() Sink {
+	sinkMutex.RLock()
+	defer sinkMutex.RUnlock()
+	return sink
+}
+
+// IsMutating returns true if requests with the method can change the state of the system
+func IsMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// NoopSink discards all the records. It is used when no audit log is configured.
+type NoopSink struct{}
+
+func (NoopSink) Write(models.AuditRecord) error { return nil }
+
+func (NoopSink) Query(Filter) (models.AuditRecordList, error) {
+	return models.AuditRecordList{}, nil
+}
+
+// FileSink appends the records to a file, one JSON object per line. When the file would
+// grow beyond the maximum size it is rotated, i.e. renamed to `<path>.1`, replacing the
+// previously rotated file. Queries are bounded by reading only these two files.
+type FileSink struct {
+	path    string
+	maxSize int64
+	mutex   sync.Mutex
+}
+
+// DefaultMaxSize is the size at which the file of a FileSink is rotated, if not specified.
+const DefaultMaxSize = 10 * 1024 * 1024
+
+// NewFileSink returns a FileSink writing into the file at the specified path, rotating it
+// at the specified size. A size of 0 or less uses the DefaultMaxSize.
+func NewFileSink(path string, maxSize int64) *FileSink {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &FileSink{path: path, maxSize: maxSize}
+}
+
+// Write appends the record to the file, rotating it first if needed
+func (s *FileSink) Write(record models.AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "marshalling audit record")
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if info, err := os.Stat(s.path); err == nil && info.Size() > 0 &&
+		info.Size()+int64(len(line)) > s.maxSize {
+		if err := os.Rename(s.path, s.rotatedPath()); err != nil {
+			return errors.Wrap(err, "rotating audit log")
+		}
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening audit log")
+	}
+
+	_, err = file.Write(line)
+	if err != nil {
+		file.Close()
+		return errors.Wrap(err, "writing audit log")
+	}
+
+	return errors.Wrap(file.Close(), "closing audit log")
+}
+
+// Query returns the records of the rotated and the current file matching the filter, most
+// recent first
+func (s *FileSink) Query(filter Filter) (models.AuditRecordList, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records := models.AuditRecordList{}
+
+	for _, path := range []string{s.rotatedPath(), s.path} {
+		var err error
+		records, err = readRecords(path, filter, records)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// reverse, to return the most recent first
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+
+	return records, nil
+}
+
+func (s *FileSink) rotatedPath() string {
+	return s.path + ".1"
+}
+
+// readRecords appends the records of the file matching the filter to the list. A missing
+// file has no records.
+func readRecords(path string, filter Filter, records models.AuditRecordList) (models.AuditRecordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, errors.Wrap(err, "opening audit log")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record := models.AuditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errors.Wrap(err, "parsing audit log")
+		}
+
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading audit log")
+	}
+
+	return records, nil
+}
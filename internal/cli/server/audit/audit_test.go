@@ -0,0 +1,111 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/epinio/epinio/internal/cli/server/audit"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileSink", func() {
+	var sink *audit.FileSink
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "epinio-audit")
+		Expect(err).ToNot(HaveOccurred())
+
+		sink = audit.NewFileSink(filepath.Join(dir, "audit.log"), 0)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	When("nothing was recorded", func() {
+		It("returns no records", func() {
+			records, err := sink.Query(audit.Filter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+		})
+	})
+
+	When("some requests were recorded", func() {
+		BeforeEach(func() {
+			for _, record := range []models.AuditRecord{
+				{RequestID: "1", User: "admin", Route: "NamespaceCreate", Namespace: "workspace"},
+				{RequestID: "2", User: "dev", Route: "AppCreate", Namespace: "workspace"},
+				{RequestID: "3", User: "dev", Route: "AppDelete", Namespace: "other"},
+			} {
+				Expect(sink.Write(record)).To(Succeed())
+			}
+		})
+
+		It("returns them, most recent first", func() {
+			records, err := sink.Query(audit.Filter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+			Expect(records[0].RequestID).To(Equal("3"))
+			Expect(records[2].RequestID).To(Equal("1"))
+		})
+
+		It("filters them by user and namespace", func() {
+			records, err := sink.Query(audit.Filter{User: "dev", Namespace: "workspace"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].RequestID).To(Equal("2"))
+		})
+
+		It("limits them", func() {
+			records, err := sink.Query(audit.Filter{Limit: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(records[1].RequestID).To(Equal("2"))
+		})
+	})
+
+	When("the file grows beyond its maximum size", func() {
+		BeforeEach(func() {
+			sink = audit.NewFileSink(filepath.Join(dir, "audit.log"), 300)
+			for i := 1; i <= 6; i++ {
+				Expect(sink.Write(models.AuditRecord{RequestID: fmt.Sprintf("%d", i), User: "dev"})).To(Succeed())
+			}
+		})
+
+		It("rotates it, keeping a single rotated file", func() {
+			_, err := os.Stat(filepath.Join(dir, "audit.log.1"))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = os.Stat(filepath.Join(dir, "audit.log.2"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			info, err := os.Stat(filepath.Join(dir, "audit.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Size()).To(BeNumerically("<=", 300))
+		})
+
+		It("queries the rotated and the current file, most recent first", func() {
+			records, err := sink.Query(audit.Filter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(records)).To(BeNumerically(">", 1))
+			Expect(len(records)).To(BeNumerically("<", 6))
+			Expect(records[0].RequestID).To(Equal("6"))
+			Expect(records[1].RequestID).To(Equal("5"))
+		})
+	})
+})
@@ -13,9 +13,11 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -26,12 +28,14 @@ import (
 	apiv1 "github.com/epinio/epinio/internal/api/v1"
 	"github.com/epinio/epinio/internal/api/v1/response"
 	"github.com/epinio/epinio/internal/auth"
+	"github.com/epinio/epinio/internal/cli/server/audit"
 	"github.com/epinio/epinio/internal/cli/server/requestctx"
 	"github.com/epinio/epinio/internal/dex"
 	"github.com/epinio/epinio/internal/domain"
 	"github.com/epinio/epinio/internal/helmchart"
 	"github.com/epinio/epinio/internal/version"
 	apierrors "github.com/epinio/epinio/pkg/api/core/v1/errors"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 
@@ -55,6 +59,7 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 	// | Path              | Notes      | Logging
 	// | ---               | ---        | ----
 	// | <Root>/...        | API        | Via "<Root>" Group
+	// | /webhooks/...     | Webhooks   | Via "<WebhookRoot>" Group
 	// | /ready            | L/R Probes |
 	// | /namespaces/target/:namespace | ditto      | ditto
 
@@ -122,9 +127,15 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 		return nil, errors.Wrap(err, "extending local trust with dex")
 	}
 
+	if auditLogFile := viper.GetString("audit-log-file"); auditLogFile != "" {
+		maxSize := int64(viper.GetInt("audit-log-max-size")) * 1024 * 1024
+		audit.SetSink(audit.NewFileSink(auditLogFile, maxSize))
+	}
+
 	// Register api routes
 	{
 		apiRoutesGroup := router.Group(apiv1.Root,
+			auditMiddleware,
 			authMiddleware,
 			versionMiddleware,
 			apiv1.NamespaceMiddleware,
@@ -136,6 +147,7 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 	// Register web socket routes
 	{
 		wapiRoutesGroup := router.Group(apiv1.WsRoot,
+			auditMiddleware,
 			tokenAuthMiddleware,
 			versionMiddleware,
 			apiv1.NamespaceMiddleware,
@@ -144,6 +156,13 @@ func NewHandler(logger logr.Logger) (*gin.Engine, error) {
 		apiv1.Spice(wapiRoutesGroup)
 	}
 
+	// Register webhook routes
+	// No authentication, the handlers verify the requests against the secrets of their targets.
+	{
+		webhookRoutesGroup := router.Group(apiv1.WebhookRoot, auditMiddleware)
+		apiv1.Ginger(webhookRoutesGroup)
+	}
+
 	// print all registered routes
 	if logger.V(3).Enabled() {
 		for _, h := range router.Routes() {
@@ -195,6 +214,97 @@ func initContextMiddleware(logger logr.Logger) gin.HandlerFunc {
 	}
 }
 
+// auditMiddleware records the mutating requests, with their outcome, into the audit sink.
+// It runs first in every group, to record the requests failing authentication, or the
+// namespace and authorization checks, too.
+func auditMiddleware(ctx *gin.Context) {
+	if !audit.IsMutating(ctx.Request.Method) {
+		return
+	}
+
+	bodyName := requestBodyName(ctx)
+
+	ctx.Next()
+
+	reqCtx := ctx.Request.Context()
+	user := requestctx.User(reqCtx)
+
+	// Without authenticated user record the name the request claimed, if any.
+	username := user.Username
+	if username == "" {
+		username, _, _ = ctx.Request.BasicAuth()
+	}
+
+	resource := []string{}
+	for _, param := range ctx.Params {
+		if param.Key != "namespace" {
+			resource = append(resource, param.Key+"="+param.Value)
+		}
+	}
+	if bodyName != "" {
+		resource = append(resource, "name="+bodyName)
+	}
+	for _, key := range auditBatchKeys {
+		if names, ok := ctx.GetQueryArray(key); ok {
+			resource = append(resource, strings.TrimSuffix(key, "[]")+"="+strings.Join(names, "+"))
+		}
+	}
+
+	record := models.AuditRecord{
+		Time:      time.Now(),
+		RequestID: requestctx.ID(reqCtx),
+		User:      username,
+		Role:      user.Role,
+		Method:    ctx.Request.Method,
+		Route:     apiv1.RouteName(ctx.Request.Method, ctx.FullPath()),
+		Namespace: ctx.Param("namespace"),
+		Resource:  strings.Join(resource, ","),
+		Status:    ctx.Writer.Status(),
+	}
+
+	if err := audit.GetSink().Write(record); err != nil {
+		requestctx.Logger(reqCtx).WithName("auditMiddleware").Error(err, "failed to write audit record", "record", record)
+	}
+}
+
+// auditBatchKeys are the query parameters naming the resources of the batch deletions.
+var auditBatchKeys = []string{"applications[]", "configurations[]", "services[]"}
+
+// maxAuditBody is the size of the largest request body inspected for the name of the
+// created resource. Uploads and other large bodies are not inspected.
+const maxAuditBody = 64 * 1024
+
+// requestBodyName returns the `name` of the resource created by a request with a JSON
+// body, if any. The body is restored for the handler.
+func requestBodyName(ctx *gin.Context) string {
+	if ctx.Request.Method != http.MethodPost || ctx.Request.Body == nil {
+		return ""
+	}
+	if ctx.Request.ContentLength < 0 || ctx.Request.ContentLength > maxAuditBody {
+		return ""
+	}
+	switch ctx.ContentType() {
+	case "", "application/json":
+	default:
+		return ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxAuditBody))
+	ctx.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), ctx.Request.Body))
+	if err != nil {
+		return ""
+	}
+
+	named := struct {
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return ""
+	}
+
+	return named.Name
+}
+
 var oidcProvider *dex.OIDCProvider
 
 // getOIDCProvider returns a lazy constructed OIDC provider
@@ -245,6 +355,8 @@ func authMiddleware(ctx *gin.Context) {
 
 	if strings.HasPrefix(authorizationHeader, "Basic ") {
 		user, authError = basicAuthentication(ctx)
+	} else if strings.HasPrefix(authorizationHeader, "Bearer "+auth.PersonalAccessTokenPrefix) {
+		user, authError = personalAccessTokenAuthentication(ctx)
 	} else if strings.HasPrefix(authorizationHeader, "Bearer ") {
 		user, authError = oidcAuthentication(ctx)
 	} else {
@@ -292,6 +404,40 @@ func basicAuthentication(ctx *gin.Context) (auth.User, apierrors.APIErrors) {
 	return userMap[username], nil
 }
 
+// personalAccessTokenAuthentication performs the authentication with a personal access token.
+// The returned user is restricted to the scope of the token.
+func personalAccessTokenAuthentication(ctx *gin.Context) (auth.User, apierrors.APIErrors) {
+	reqCtx := ctx.Request.Context()
+	logger := requestctx.Logger(reqCtx).WithName("personalAccessTokenAuthentication")
+	logger.V(1).Info("starting Personal Access Token Authentication")
+
+	authService, err := auth.NewAuthServiceFromContext(ctx)
+	if err != nil {
+		return auth.User{}, apierrors.InternalError(err)
+	}
+
+	value := strings.TrimPrefix(ctx.Request.Header.Get("Authorization"), "Bearer ")
+	token, err := authService.VerifyToken(ctx, value)
+	if err != nil {
+		if err == auth.ErrTokenInvalid {
+			return auth.User{}, apierrors.NewAPIError("invalid token", http.StatusUnauthorized)
+		}
+		return auth.User{}, apierrors.InternalError(err)
+	}
+
+	user, err := authService.GetUserByUsername(ctx, token.Username)
+	if err != nil {
+		if err == auth.ErrUserNotFound {
+			return auth.User{}, apierrors.NewAPIError("invalid token", http.StatusUnauthorized)
+		}
+		return auth.User{}, apierrors.InternalError(err)
+	}
+
+	logger.V(1).Info("token verified", "user", user.Username, "token", token.ID)
+
+	return user.WithScope(token.Scope()), nil
+}
+
 // oidcAuthentication perform the OIDC authentication with dex
 func oidcAuthentication(ctx *gin.Context) (auth.User, apierrors.APIErrors) {
 	reqCtx := ctx.Request.Context()
@@ -322,11 +468,11 @@ func oidcAuthentication(ctx *gin.Context) (auth.User, apierrors.APIErrors) {
 		return auth.User{}, apierrors.NewAPIError(errors.Wrap(err, "error parsing claims").Error(), http.StatusUnauthorized)
 	}
 
-	role := getRoleFromProviderGroups(logger, oidcProvider, claims.FederatedClaims.ConnectorID, claims.Groups)
+	role, namespaces := getRoleAndNamespacesFromProviderGroups(logger, oidcProvider, claims.FederatedClaims.ConnectorID, claims.Groups)
 
-	user, err := getOrCreateUserByEmail(ctx, claims.Email, role)
+	user, err := syncUserByEmail(ctx, claims.Email, role, namespaces)
 	if err != nil {
-		return auth.User{}, apierrors.InternalError(err, "getting/creating user with email")
+		return auth.User{}, apierrors.InternalError(err, "syncing user with email")
 	}
 
 	logger.V(1).Info("token verified", "user", fmt.Sprintf("%#v", user))
@@ -334,10 +480,11 @@ func oidcAuthentication(ctx *gin.Context) (auth.User, apierrors.APIErrors) {
 	return user, nil
 }
 
-// getRoleFromProviderGroups returns the user role, looking for it in the groups defined for the provider.
-// If there are no groups that matches then the default role 'user' is returned.
-// If a user has more than one group matching, the first from the Dex Configuration will be returned.
-func getRoleFromProviderGroups(logger logr.Logger, oidcProvider *dex.OIDCProvider, providerID string, groups []string) string {
+// getRoleAndNamespacesFromProviderGroups returns the user role and namespaces, looking for them in the groups defined for the provider.
+// If there are no groups that matches then the default role 'user' and no namespaces are returned.
+// If a user has more than one group matching, the role of the first from the Dex Configuration will be returned,
+// while the namespaces of all the matching groups are granted.
+func getRoleAndNamespacesFromProviderGroups(logger logr.Logger, oidcProvider *dex.OIDCProvider, providerID string, groups []string) (string, []string) {
 	defaultRole := "user"
 
 	pg, err := oidcProvider.GetProviderGroups(providerID)
@@ -347,9 +494,11 @@ func getRoleFromProviderGroups(logger logr.Logger, oidcProvider *dex.OIDCProvide
 			"provider", providerID,
 		)
 
-		return defaultRole
+		return defaultRole, []string{}
 	}
 
+	namespaces := pg.GetNamespacesFromGroups(groups...)
+
 	roles := pg.GetRolesFromGroups(groups...)
 	if len(roles) == 0 {
 		logger.Info(
@@ -359,10 +508,10 @@ func getRoleFromProviderGroups(logger logr.Logger, oidcProvider *dex.OIDCProvide
 			"groups", strings.Join(groups, ","),
 		)
 
-		return defaultRole
+		return defaultRole, namespaces
 	}
 
-	return roles[0]
+	return roles[0], namespaces
 }
 
 func loadUsersMap(ctx context.Context) (map[string]auth.User, error) {
@@ -384,7 +533,10 @@ func loadUsersMap(ctx context.Context) (map[string]auth.User, error) {
 	return userMap, nil
 }
 
-func getOrCreateUserByEmail(ctx context.Context, email, role string) (auth.User, error) {
+// syncUserByEmail returns the user with the specified email, creating it if it doesn't exist.
+// The role and the group namespaces of an existing user are synchronized with the provided ones,
+// to reflect the changes done in the identity provider.
+func syncUserByEmail(ctx context.Context, email, role string, namespaces []string) (auth.User, error) {
 	user := auth.User{}
 	var err error
 
@@ -401,10 +553,25 @@ func getOrCreateUserByEmail(ctx context.Context, email, role string) (auth.User,
 
 		user.Username = email
 		user.Role = role
+		user.SetGroupNamespaces(namespaces)
+
 		user, err = authService.SaveUser(ctx, user)
-		if err != auth.ErrUserNotFound {
+		if err != nil {
 			return user, errors.Wrap(err, "couldn't create user")
 		}
+
+		return user, nil
+	}
+
+	roleChanged := user.Role != role
+	user.Role = role
+	namespacesChanged := user.SetGroupNamespaces(namespaces)
+
+	if roleChanged || namespacesChanged {
+		err = authService.UpdateUser(ctx, user)
+		if err != nil {
+			return user, errors.Wrap(err, "couldn't update user")
+		}
 	}
 
 	return user, nil
@@ -456,6 +623,13 @@ func tokenAuthMiddleware(ctx *gin.Context) {
 
 	for _, user := range users {
 		if user.Username == claims.Username {
+			if claims.Scope != nil {
+				user = user.WithScope(auth.TokenScope{
+					Namespaces: claims.Scope.Namespaces,
+					ReadOnly:   claims.Scope.ReadOnly,
+				})
+			}
+
 			newCtx := ctx.Request.Context()
 			newCtx = requestctx.WithUser(newCtx, user)
 			ctx.Request = ctx.Request.Clone(newCtx)
@@ -0,0 +1,124 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdToken implements the command: epinio token
+var CmdToken = &cobra.Command{
+	Use:           "token",
+	Aliases:       []string{"tokens"},
+	Short:         "Personal access tokens",
+	Long:          `Manage the personal access tokens of the user, used to login from automated pipelines`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	flags := CmdTokenCreate.Flags()
+	flags.StringSliceP("namespace", "n", []string{}, "namespace the token is restricted to (can be repeated). Without, the token has access to all the namespaces of the user")
+	flags.Bool("read-only", false, "restrict the token to read-only operations")
+
+	CmdToken.AddCommand(CmdTokenCreate)
+	CmdToken.AddCommand(CmdTokenList)
+	CmdToken.AddCommand(CmdTokenRevoke)
+}
+
+// CmdTokenCreate implements the command: epinio token create
+var CmdTokenCreate = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Creates a personal access token",
+	Long:  "Creates a personal access token. Its value is shown only once, and can be used with 'epinio login --token'.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		namespaces, err := cmd.Flags().GetStringSlice("namespace")
+		if err != nil {
+			return errors.Wrap(err, "could not read the namespaces")
+		}
+
+		readOnly, err := cmd.Flags().GetBool("read-only")
+		if err != nil {
+			return errors.Wrap(err, "could not read the read-only flag")
+		}
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.CreateToken(args[0], namespaces, readOnly)
+		if err != nil {
+			return errors.Wrap(err, "error creating personal access token")
+		}
+
+		return nil
+	},
+}
+
+// CmdTokenList implements the command: epinio token list
+var CmdTokenList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the personal access tokens",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Tokens()
+		if err != nil {
+			return errors.Wrap(err, "error listing personal access tokens")
+		}
+
+		return nil
+	},
+}
+
+// CmdTokenRevoke implements the command: epinio token revoke
+var CmdTokenRevoke = &cobra.Command{
+	Use:   "revoke NAME",
+	Short: "Revokes a personal access token, by name or ID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RevokeToken(args[0])
+		if err != nil {
+			return errors.Wrap(err, "error revoking personal access token")
+		}
+
+		return nil
+	},
+}
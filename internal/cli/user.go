@@ -0,0 +1,216 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/internal/cli/usercmd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// CmdUser implements the command: epinio user
+var CmdUser = &cobra.Command{
+	Use:           "user",
+	Aliases:       []string{"users"},
+	Short:         "Epinio users",
+	Long:          `Manage the Epinio users. Restricted to admins.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Args:          cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Usage(); err != nil {
+			return err
+		}
+		return fmt.Errorf(`Unknown method "%s"`, args[0])
+	},
+}
+
+func init() {
+	flags := CmdUserCreate.Flags()
+	flags.StringP("password", "p", "", "password of the user. Asked if not specified")
+	flags.String("role", "user", "role of the user [admin,user]")
+	flags.StringSliceP("namespace", "n", []string{}, "namespace accessible by the user, as NAME[:ROLE] with ROLE one of [viewer,developer,namespace-admin] (can be repeated)")
+
+	CmdUserPassword.Flags().StringP("password", "p", "", "new password of the user. Asked if not specified")
+	CmdUserGrant.Flags().String("role", "", "role of the user in the namespace [viewer,developer,namespace-admin]. Defaults to namespace-admin")
+
+	CmdUser.AddCommand(CmdUserList)
+	CmdUser.AddCommand(CmdUserShow)
+	CmdUser.AddCommand(CmdUserCreate)
+	CmdUser.AddCommand(CmdUserDelete)
+	CmdUser.AddCommand(CmdUserPassword)
+	CmdUser.AddCommand(CmdUserGrant)
+	CmdUser.AddCommand(CmdUserRevoke)
+}
+
+// CmdUserList implements the command: epinio user list
+var CmdUserList = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all the users",
+	Args:  cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.Users()
+		return errors.Wrap(err, "error listing users")
+	},
+}
+
+// CmdUserShow implements the command: epinio user show
+var CmdUserShow = &cobra.Command{
+	Use:   "show USERNAME",
+	Short: "Shows the details of a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ShowUser(args[0])
+		return errors.Wrap(err, "error showing user")
+	},
+}
+
+// CmdUserCreate implements the command: epinio user create
+var CmdUserCreate = &cobra.Command{
+	Use:   "create USERNAME",
+	Short: "Creates a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		password, err := cmd.Flags().GetString("password")
+		if err != nil {
+			return errors.Wrap(err, "could not read the password")
+		}
+
+		role, err := cmd.Flags().GetString("role")
+		if err != nil {
+			return errors.Wrap(err, "could not read the role")
+		}
+
+		namespaces, err := cmd.Flags().GetStringSlice("namespace")
+		if err != nil {
+			return errors.Wrap(err, "could not read the namespaces")
+		}
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.CreateUser(args[0], password, role, namespaces)
+		return errors.Wrap(err, "error creating user")
+	},
+}
+
+// CmdUserDelete implements the command: epinio user delete
+var CmdUserDelete = &cobra.Command{
+	Use:   "delete USERNAME",
+	Short: "Deletes a user, and revokes its personal access tokens",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.DeleteUser(args[0])
+		return errors.Wrap(err, "error deleting user")
+	},
+}
+
+// CmdUserPassword implements the command: epinio user passwd
+var CmdUserPassword = &cobra.Command{
+	Use:   "passwd USERNAME",
+	Short: "Resets the password of a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		password, err := cmd.Flags().GetString("password")
+		if err != nil {
+			return errors.Wrap(err, "could not read the password")
+		}
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.ResetUserPassword(args[0], password)
+		return errors.Wrap(err, "error resetting password")
+	},
+}
+
+// CmdUserGrant implements the command: epinio user grant
+var CmdUserGrant = &cobra.Command{
+	Use:               "grant USERNAME NAMESPACE",
+	Short:             "Grants the access to a namespace to a user",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: findUserNamespace,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		role, err := cmd.Flags().GetString("role")
+		if err != nil {
+			return errors.Wrap(err, "could not read the role")
+		}
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.GrantUserNamespace(args[0], args[1], role)
+		return errors.Wrap(err, "error granting namespace")
+	},
+}
+
+// CmdUserRevoke implements the command: epinio user revoke
+var CmdUserRevoke = &cobra.Command{
+	Use:               "revoke USERNAME NAMESPACE",
+	Short:             "Revokes the access to a namespace from a user",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: findUserNamespace,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		client, err := usercmd.New(cmd.Context())
+		if err != nil {
+			return errors.Wrap(err, "error initializing cli")
+		}
+
+		err = client.RevokeUserNamespace(args[0], args[1])
+		return errors.Wrap(err, "error revoking namespace")
+	},
+}
+
+// findUserNamespace completes the namespace argument of the grant and revoke commands
+func findUserNamespace(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matchingNamespaceFinder(cmd, []string{}, toComplete)
+}
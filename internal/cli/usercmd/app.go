@@ -556,6 +556,48 @@ func (c *EpinioClient) printAppDetails(app models.App) error {
 		}
 	}
 
+	if app.Rollback != nil {
+		msg = msg.WithTableRow("Rolled Back", fmt.Sprintf("to %s from %s by %s, %s",
+			app.Rollback.StageID, app.Rollback.FromStageID, app.Rollback.Username, app.Rollback.Time.String()))
+	}
+
+	if app.Candidate != nil {
+		candidate := fmt.Sprintf("%s %s, replacing %s, by %s, %s",
+			app.Candidate.Strategy, app.Candidate.StageID, app.Candidate.FromStageID,
+			app.Candidate.Username, app.Candidate.Time.String())
+		if app.Candidate.Strategy == models.DeploymentStrategyCanary {
+			candidate = fmt.Sprintf("%s, %d%% of traffic", candidate, app.Candidate.Weight)
+		}
+		msg = msg.WithTableRow("Candidate", candidate)
+	}
+
+	if app.Configuration.Deployment != nil {
+		strategy := app.Configuration.Deployment.Strategy
+		if strategy == models.DeploymentStrategyCanary {
+			strategy = fmt.Sprintf("%s, %d%% of traffic", strategy, app.Configuration.Deployment.Weight)
+		}
+		msg = msg.WithTableRow("Deployment Strategy", strategy)
+	}
+
+	if app.Configuration.Resources != nil {
+		msg = msg.WithTableRow("Resources", resourcesString(*app.Configuration.Resources))
+	}
+
+	if checks := app.Configuration.HealthChecks; checks != nil {
+		for _, probe := range []struct {
+			name  string
+			probe *models.AppProbe
+		}{
+			{"Liveness Probe", checks.Liveness},
+			{"Readiness Probe", checks.Readiness},
+			{"Startup Probe", checks.Startup},
+		} {
+			if probe.probe != nil {
+				msg = msg.WithTableRow(probe.name, probeString(*probe.probe))
+			}
+		}
+	}
+
 	msg = msg.
 		WithTableRow("App Chart", app.Configuration.AppChart).
 		WithTableRow("Desired Instances", fmt.Sprintf("%d", *app.Configuration.Instances)).
@@ -612,8 +654,9 @@ func (c *EpinioClient) printReplicaDetails(app models.App) error {
 	return nil
 }
 
-// AppRestage restage an application
-func (c *EpinioClient) AppRestage(appName string) error {
+// AppRestage restage an application. With noCache set the build cache is wiped before
+// building.
+func (c *EpinioClient) AppRestage(appName string, noCache bool) error {
 	log := c.Log.WithName("AppRestage").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
 	log.Info("start")
 	defer log.Info("return")
@@ -621,6 +664,7 @@ func (c *EpinioClient) AppRestage(appName string) error {
 	c.ui.Note().
 		WithStringValue("Namespace", c.Settings.Namespace).
 		WithStringValue("Application", appName).
+		WithBoolValue("No Cache", noCache).
 		Msg("Restaging application")
 
 	if err := c.TargetOk(); err != nil {
@@ -639,7 +683,7 @@ func (c *EpinioClient) AppRestage(appName string) error {
 		return nil
 	}
 
-	req := models.StageRequest{App: app.Meta}
+	req := models.StageRequest{App: app.Meta, NoCache: noCache}
 	stageResponse, err := c.API.AppStage(req)
 	if err != nil {
 		return err
@@ -651,12 +695,51 @@ func (c *EpinioClient) AppRestage(appName string) error {
 	log.V(1).Info("start tailing logs", "StageID", stageID)
 	c.stageLogs(app.Meta, stageID)
 
-	log.V(1).Info("wait for job", "StageID", stageID)
+	log.V(1).Info("wait for job", "StageID", stageID, "QueuePosition", stageResponse.QueuePosition)
 	// blocking function that wait until the staging is done
-	_, err = c.API.StagingComplete(app.Meta.Namespace, stageID)
+	err = c.waitForStaging(app.Meta, stageID)
 	return errors.Wrap(err, "waiting for staging failed")
 }
 
+// AppStageCancel cancels the running staging of an application
+func (c *EpinioClient) AppStageCancel(appName string) error {
+	log := c.Log.WithName("AppStageCancel").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Cancelling application staging")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	app, err := c.API.AppShow(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if app.StageID == "" {
+		c.ui.Exclamation().Msg("The application was never staged")
+		return nil
+	}
+
+	log.V(1).Info("cancelling staging", "StageID", app.StageID)
+
+	_, err = c.API.StagingCancel(app.Meta.Namespace, app.StageID)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Stage", app.StageID).
+		Msg("Staging cancelled.")
+
+	return nil
+}
+
 func formatRoutes(routes []string) string {
 	if len(routes) > 0 {
 		sort.Strings(routes)
@@ -665,3 +748,54 @@ func formatRoutes(routes []string) string {
 		return "<<none>>"
 	}
 }
+
+// probeString returns a short description of the health check
+func probeString(probe models.AppProbe) string {
+	check := ""
+	switch {
+	case probe.HTTPPath != "":
+		check = fmt.Sprintf("http %s", probe.HTTPPath)
+		if probe.Port != 0 {
+			check = fmt.Sprintf("http :%d%s", probe.Port, probe.HTTPPath)
+		}
+	case probe.TCPPort != 0:
+		check = fmt.Sprintf("tcp :%d", probe.TCPPort)
+	default:
+		check = fmt.Sprintf("exec %s", strings.Join(probe.Command, " "))
+	}
+
+	details := []string{check}
+	if probe.InitialDelaySeconds != 0 {
+		details = append(details, fmt.Sprintf("delay %ds", probe.InitialDelaySeconds))
+	}
+	if probe.PeriodSeconds != 0 {
+		details = append(details, fmt.Sprintf("period %ds", probe.PeriodSeconds))
+	}
+	if probe.TimeoutSeconds != 0 {
+		details = append(details, fmt.Sprintf("timeout %ds", probe.TimeoutSeconds))
+	}
+	if probe.SuccessThreshold != 0 {
+		details = append(details, fmt.Sprintf("success threshold %d", probe.SuccessThreshold))
+	}
+	if probe.FailureThreshold != 0 {
+		details = append(details, fmt.Sprintf("failure threshold %d", probe.FailureThreshold))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// resourcesString returns a short description of the compute resources
+func resourcesString(resources models.AppResources) string {
+	details := []string{}
+	for _, r := range []struct{ name, value string }{
+		{"cpu request", resources.CPURequest},
+		{"cpu limit", resources.CPULimit},
+		{"memory request", resources.MemoryRequest},
+		{"memory limit", resources.MemoryLimit},
+	} {
+		if r.value != "" {
+			details = append(details, fmt.Sprintf("%s %s", r.name, r.value))
+		}
+	}
+	return strings.Join(details, ", ")
+}
@@ -43,8 +43,8 @@ var _ = Describe("Client Apps unit tests", Label("wip"), func() {
 					return nil
 				}
 
-				fake.StagingCompleteStub = func(namespace, id string) (models.Response, error) {
-					return models.Response{Status: "ok"}, nil
+				fake.StagingStatusStub = func(namespace, id string) (models.StagingCompleteResponse, error) {
+					return models.StagingCompleteResponse{Response: models.ResponseOK}, nil
 				}
 			})
 
@@ -52,11 +52,72 @@ var _ = Describe("Client Apps unit tests", Label("wip"), func() {
 				epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
 				Expect(err).ToNot(HaveOccurred())
 
-				err = epinioClient.AppRestage("appname")
+				err = epinioClient.AppRestage("appname", false)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
 
+		When("restaging an app without cache", func() {
+
+			BeforeEach(func() {
+				fake = &usercmdfakes.FakeAPIClient{}
+
+				fake.AppShowStub = func(namespace, appName string) (models.App, error) {
+					return *models.NewApp(appName, namespace), nil
+				}
+
+				fake.AppStageStub = func(req models.StageRequest) (*models.StageResponse, error) {
+					return &models.StageResponse{Stage: models.NewStage("ID")}, nil
+				}
+
+				fake.StagingStatusStub = func(namespace, id string) (models.StagingCompleteResponse, error) {
+					return models.StagingCompleteResponse{Response: models.ResponseOK}, nil
+				}
+			})
+
+			It("requests a stage without cache", func() {
+				epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = epinioClient.AppRestage("appname", true)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fake.AppStageCallCount()).To(Equal(1))
+				Expect(fake.AppStageArgsForCall(0).NoCache).To(BeTrue())
+			})
+		})
+
+		When("restaging an app waiting in the build queue", func() {
+
+			BeforeEach(func() {
+				fake = &usercmdfakes.FakeAPIClient{}
+
+				fake.AppShowStub = func(namespace, appName string) (models.App, error) {
+					return *models.NewApp(appName, namespace), nil
+				}
+
+				fake.AppStageStub = func(req models.StageRequest) (*models.StageResponse, error) {
+					return &models.StageResponse{Stage: models.NewStage("ID"), QueuePosition: 1}, nil
+				}
+
+				fake.StagingStatusReturnsOnCall(0, models.StagingCompleteResponse{
+					Response:      models.Response{Status: models.StagingQueued},
+					QueuePosition: 1,
+				}, nil)
+				fake.StagingStatusReturnsOnCall(1, models.StagingCompleteResponse{Response: models.ResponseOK}, nil)
+			})
+
+			It("waits until the staging is done", func() {
+				epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = epinioClient.AppRestage("appname", false)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fake.StagingStatusCallCount()).To(Equal(2))
+			})
+		})
+
 		When("restaging a container-based app", func() {
 
 			BeforeEach(func() {
@@ -77,9 +138,48 @@ var _ = Describe("Client Apps unit tests", Label("wip"), func() {
 				epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
 				Expect(err).ToNot(HaveOccurred())
 
-				err = epinioClient.AppRestage("appname")
+				err = epinioClient.AppRestage("appname", false)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
 	})
+
+	Describe("AppStageCancel", func() {
+
+		BeforeEach(func() {
+			fake = &usercmdfakes.FakeAPIClient{}
+		})
+
+		It("cancels the last staging of the app", func() {
+			fake.AppShowStub = func(namespace, appName string) (models.App, error) {
+				app := models.NewApp(appName, namespace)
+				app.StageID = "ID"
+				return *app, nil
+			}
+
+			epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = epinioClient.AppStageCancel("appname")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fake.StagingCancelCallCount()).To(Equal(1))
+			namespace, id := fake.StagingCancelArgsForCall(0)
+			Expect(namespace).To(Equal("workspace"))
+			Expect(id).To(Equal("ID"))
+		})
+
+		It("does nothing for an app which was never staged", func() {
+			fake.AppShowStub = func(namespace, appName string) (models.App, error) {
+				return *models.NewApp(appName, namespace), nil
+			}
+
+			epinioClient, err := usercmd.NewEpinioClient(&settings.Settings{Namespace: "workspace"}, fake)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = epinioClient.AppStageCancel("appname")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fake.StagingCancelCallCount()).To(Equal(0))
+		})
+	})
 })
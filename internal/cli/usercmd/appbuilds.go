@@ -0,0 +1,66 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"time"
+)
+
+// AppBuilds lists the build history of an application
+func (c *EpinioClient) AppBuilds(appName string) error {
+	log := c.Log.WithName("AppBuilds").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Listing application builds")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	builds, err := c.API.AppBuilds(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if len(builds) == 0 {
+		c.ui.Exclamation().Msg("The application has no builds")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Stage ID", "Status", "Started", "Duration", "Origin", "Builder", "Image", "User")
+
+	for _, build := range builds {
+		duration := "n/a"
+		if build.FinishedAt != nil {
+			duration = build.FinishedAt.Sub(build.StartedAt.Time).Round(time.Second).String()
+		}
+
+		msg = msg.WithTableRow(
+			build.StageID,
+			build.Status,
+			build.StartedAt.String(),
+			duration,
+			build.Origin.String(),
+			build.BuilderImage,
+			build.ImageURL,
+			build.Username,
+		)
+	}
+
+	msg.Msg("Builds, most recent first:")
+
+	return nil
+}
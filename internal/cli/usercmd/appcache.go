@@ -0,0 +1,78 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+// AppCacheShow shows the build cache of an application
+func (c *EpinioClient) AppCacheShow(appName string) error {
+	log := c.Log.WithName("AppCacheShow").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Show application build cache")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	cache, err := c.API.AppCache(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if !cache.Exists {
+		c.ui.Exclamation().Msg("The application has no build cache")
+		return nil
+	}
+
+	lastStaged := "<<unknown>>"
+	if !cache.LastStaged.IsZero() {
+		lastStaged = cache.LastStaged.String()
+	}
+
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Volume Claim", cache.Name).
+		WithTableRow("Status", cache.Phase).
+		WithTableRow("Capacity", cache.Capacity).
+		WithTableRow("Created", cache.CreatedAt.String()).
+		WithTableRow("Last Staged", lastStaged).
+		Msg("Details:")
+
+	return nil
+}
+
+// AppCacheClear removes the build cache of an application
+func (c *EpinioClient) AppCacheClear(appName string) error {
+	log := c.Log.WithName("AppCacheClear").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Clearing application build cache")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.AppCacheClear(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Build cache cleared. The next staging starts from scratch.")
+
+	return nil
+}
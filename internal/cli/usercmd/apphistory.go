@@ -0,0 +1,107 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AppHistory lists the revisions of the helm release of an application
+func (c *EpinioClient) AppHistory(appName string) error {
+	log := c.Log.WithName("AppHistory").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Listing application release history")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	releases, err := c.API.AppReleases(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if len(releases) == 0 {
+		c.ui.Exclamation().Msg("The application has no release history")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Revision", "Updated", "Status", "Chart", "Stage ID", "Description")
+
+	for _, release := range releases {
+		chart := release.Chart
+		if release.ChartVersion != "" {
+			chart = fmt.Sprintf("%s-%s", chart, release.ChartVersion)
+		}
+
+		msg = msg.WithTableRow(
+			strconv.Itoa(release.Revision),
+			release.Updated.String(),
+			release.Status,
+			chart,
+			release.StageID,
+			release.Description,
+		)
+	}
+
+	msg.Msg(fmt.Sprintf("Revisions of release %s, most recent first:", releases[0].Release))
+
+	return nil
+}
+
+// AppHistoryDiff shows the differences in values and manifests between two revisions of the
+// helm release of an application
+func (c *EpinioClient) AppHistoryDiff(appName string, from, to int) error {
+	log := c.Log.WithName("AppHistoryDiff").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		WithStringValue("From", strconv.Itoa(from)).
+		WithStringValue("To", strconv.Itoa(to)).
+		Msg("Comparing application release revisions")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	diff, err := c.API.AppReleaseDiff(c.Settings.Namespace, appName, from, to)
+	if err != nil {
+		return err
+	}
+
+	if diff.ValuesDiff == "" && diff.ManifestDiff == "" {
+		c.ui.Success().Msg(fmt.Sprintf("Revisions %d and %d of release %s do not differ.", from, to, diff.Release))
+		return nil
+	}
+
+	// The diffs are printed as is, see printDryRun.
+	if diff.ValuesDiff != "" {
+		c.ui.Normal().Msg("# Values Diff")
+		fmt.Println(strings.TrimRight(diff.ValuesDiff, "\n"))
+	}
+	if diff.ManifestDiff != "" {
+		c.ui.Normal().Msg("# Manifests Diff")
+		fmt.Println(strings.TrimRight(diff.ManifestDiff, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+// AppWebhookShow shows the git webhook of an application
+func (c *EpinioClient) AppWebhookShow(appName string) error {
+	log := c.Log.WithName("AppWebhookShow").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Show application git webhook")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	webhook, err := c.API.AppWebhook(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	if !webhook.Enabled {
+		c.ui.Exclamation().Msg("The application has no git webhook")
+		return nil
+	}
+
+	c.webhookDetails(appName, webhook.Secret)
+
+	return nil
+}
+
+// AppWebhookEnable enables the git webhook of an application
+func (c *EpinioClient) AppWebhookEnable(appName string) error {
+	log := c.Log.WithName("AppWebhookEnable").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Enabling application git webhook")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	webhook, err := c.API.AppWebhookEnable(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.webhookDetails(appName, webhook.Secret)
+
+	return nil
+}
+
+// AppWebhookDisable disables the git webhook of an application
+func (c *EpinioClient) AppWebhookDisable(appName string) error {
+	log := c.Log.WithName("AppWebhookDisable").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Disabling application git webhook")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.AppWebhookDisable(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Git webhook disabled.")
+
+	return nil
+}
+
+func (c *EpinioClient) webhookDetails(appName, secret string) {
+	c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Payload URL", c.API.AppWebhookURL(c.Settings.Namespace, appName)).
+		WithTableRow("Content Type", "application/json").
+		WithTableRow("Secret", secret).
+		Msg("Register the webhook with the git repository of the application:")
+}
@@ -50,11 +50,15 @@ type APIClient interface {
 	AppUpdate(req models.ApplicationUpdateRequest, namespace string, appName string) (models.Response, error)
 	AppDelete(namespace string, names []string) (models.ApplicationDeleteResponse, error)
 	AppUpload(namespace string, name string, tarball string) (models.UploadResponse, error)
+	AppUploadChunked(namespace string, name string, tarball string) (models.UploadResponse, error)
 	AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.ImportGitResponse, error)
 	AppStage(req models.StageRequest) (*models.StageResponse, error)
 	AppDeploy(req models.DeployRequest) (*models.DeployResponse, error)
+	AppDeployDryRun(req models.DeployRequest) (models.DeployDryRunResponse, error)
 	AppLogs(namespace, appName, stageID string, follow bool, callback func(tailer.ContainerLogLine)) error
 	StagingComplete(namespace string, id string) (models.Response, error)
+	StagingStatus(namespace string, id string) (models.StagingCompleteResponse, error)
+	StagingCancel(namespace string, id string) (models.Response, error)
 	AppRunning(app models.AppRef) (models.Response, error)
 	AppExec(ctx context.Context, namespace string, appName, instance string, tty kubectlterm.TTY) error
 	AppPortForward(namespace string, appName, instance string, opts *epinioapi.PortForwardOpts) error
@@ -62,6 +66,18 @@ type APIClient interface {
 	AppGetPart(namespace, appName, part, destinationPath string) error
 	AppMatch(namespace, prefix string) (models.AppMatchResponse, error)
 	AppValidateCV(namespace string, name string) (models.Response, error)
+	AppBuilds(namespace string, appName string) (models.AppBuildList, error)
+	AppReleases(namespace string, appName string) (models.AppReleaseList, error)
+	AppReleaseDiff(namespace string, appName string, from, to int) (models.AppReleaseDiff, error)
+	AppRollback(namespace string, appName string, req models.RollbackRequest) (models.RollbackResponse, error)
+	AppPromote(namespace string, appName string, req models.PromoteRequest) (models.CandidateResponse, error)
+	AppAbort(namespace string, appName string) (models.CandidateResponse, error)
+	AppCache(namespace string, appName string) (models.AppCache, error)
+	AppCacheClear(namespace string, appName string) (models.Response, error)
+	AppWebhook(namespace string, appName string) (models.AppWebhook, error)
+	AppWebhookEnable(namespace string, appName string) (models.AppWebhook, error)
+	AppWebhookDisable(namespace string, appName string) (models.Response, error)
+	AppWebhookURL(namespace string, appName string) string
 
 	// env
 	EnvList(namespace string, appName string) (models.EnvVariableMap, error)
@@ -92,6 +108,11 @@ type APIClient interface {
 	ConfigurationApps(namespace string) (models.ConfigurationAppsResponse, error)
 	ConfigurationMatch(namespace, prefix string) (models.ConfigurationMatchResponse, error)
 
+	// git configurations
+	GitConfigs(namespace string) (models.GitConfigList, error)
+	GitConfigCreate(req models.GitConfigCreateRequest, namespace string) (models.Response, error)
+	GitConfigDelete(namespace string, name string) (models.Response, error)
+
 	// services
 	ServiceCatalog() (models.CatalogServices, error)
 	ServiceCatalogShow(serviceName string) (*models.CatalogService, error)
@@ -106,6 +127,20 @@ type APIClient interface {
 	ServiceList(namespace string) (models.ServiceList, error)
 	ServiceMatch(namespace, prefix string) (models.ServiceMatchResponse, error)
 
+	// personal access tokens
+	Tokens() (models.PersonalAccessTokenList, error)
+	TokenCreate(req models.TokenCreateRequest) (models.TokenCreateResponse, error)
+	TokenDelete(name string) (models.Response, error)
+
+	// users
+	Users() (models.UserList, error)
+	UserShow(username string) (models.User, error)
+	UserCreate(req models.UserCreateRequest) (models.Response, error)
+	UserDelete(username string) (models.Response, error)
+	UserPassword(username string, req models.UserPasswordRequest) (models.Response, error)
+	UserNamespaceGrant(username string, req models.UserNamespaceGrantRequest) (models.Response, error)
+	UserNamespaceRevoke(username, namespace string) (models.Response, error)
+
 	// application charts
 	ChartList() ([]models.AppChart, error)
 	ChartShow(name string) (models.AppChart, error)
@@ -0,0 +1,130 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppDeploy deploys the application with its current image, or the image given by url.
+// In a dry run the app chart is only rendered, and the values, manifests, and their
+// differences to the deployed release are shown.
+func (c *EpinioClient) AppDeploy(appName, imageURL string, dryRun bool) error {
+	log := c.Log.WithName("AppDeploy").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName)
+	if imageURL != "" {
+		msg = msg.WithStringValue("Image", imageURL)
+	}
+	if dryRun {
+		msg.Msg("Dry-run of application deployment")
+	} else {
+		msg.Msg("Deploying application")
+	}
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	app, err := c.API.AppShow(c.Settings.Namespace, appName)
+	if err != nil {
+		return err
+	}
+
+	req := models.DeployRequest{
+		App:      app.Meta,
+		Stage:    models.StageRef{ID: app.StageID},
+		ImageURL: app.ImageURL,
+		Origin:   app.Origin,
+	}
+	if imageURL != "" {
+		req.Stage = models.StageRef{}
+		req.ImageURL = imageURL
+		req.Origin = models.ApplicationOrigin{
+			Kind:      models.OriginContainer,
+			Container: imageURL,
+		}
+	}
+	if req.ImageURL == "" {
+		return fmt.Errorf("application %s has no image to deploy, push it first", appName)
+	}
+
+	if dryRun {
+		resp, err := c.API.AppDeployDryRun(req)
+		if err != nil {
+			return err
+		}
+
+		log.V(3).Info("dry-run response", "response", resp)
+
+		c.printDryRun(resp)
+		return nil
+	}
+
+	s := c.ui.Progress("Waiting for deployment")
+	resp, err := c.API.AppDeploy(req)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	routes := []string{}
+	for _, d := range resp.Routes {
+		routes = append(routes, fmt.Sprintf("https://%s", d))
+	}
+
+	c.ui.Success().
+		WithStringValue("Routes", formatRoutes(routes)).
+		Msg("Application deployed.")
+
+	return nil
+}
+
+// printDryRun shows the result of a deployment dry-run. The documents are printed as is,
+// for easy redirection into files and tools.
+func (c *EpinioClient) printDryRun(resp models.DeployDryRunResponse) {
+	section := func(title, text string) {
+		c.ui.Normal().Msg(fmt.Sprintf("# %s", title))
+		fmt.Println(strings.TrimRight(text, "\n"))
+	}
+
+	section("Values", resp.Values)
+	section("Manifests", resp.Manifests)
+
+	if !resp.Deployed {
+		c.ui.Exclamation().Msg(fmt.Sprintf("Release %s is not deployed, the diffs are against nothing.", resp.Release))
+	}
+
+	if resp.ValuesDiff == "" && resp.ManifestDiff == "" {
+		c.ui.Success().Msg(fmt.Sprintf("No changes to release %s.", resp.Release))
+		return
+	}
+
+	if resp.ValuesDiff != "" {
+		section("Values Diff", resp.ValuesDiff)
+	}
+	if resp.ManifestDiff != "" {
+		section("Manifests Diff", resp.ManifestDiff)
+	}
+
+	c.ui.Success().
+		WithStringValue("Release", resp.Release).
+		WithIntValue("Revision", resp.Revision).
+		Msg("Dry-run complete, nothing deployed.")
+}
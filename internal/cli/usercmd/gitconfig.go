@@ -0,0 +1,117 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// GitConfigs lists the git configurations of the targeted namespace
+func (c *EpinioClient) GitConfigs() error {
+	log := c.Log.WithName("GitConfigs").WithValues("Namespace", c.Settings.Namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Listing git configurations")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	configs, err := c.API.GitConfigs(c.Settings.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(configs) == 0 {
+		c.ui.Exclamation().Msg("No git configurations found")
+		return nil
+	}
+
+	msg := c.ui.Success().WithTable("Name", "URL", "Kind", "Username", "Created")
+	for _, config := range configs {
+		msg = msg.WithTableRow(
+			config.Meta.Name,
+			config.URL,
+			config.Kind,
+			config.Username,
+			config.Meta.CreatedAt.String())
+	}
+	msg.Msg("Epinio Git Configurations:")
+
+	return nil
+}
+
+// GitConfigCreate creates a git configuration in the targeted namespace
+func (c *EpinioClient) GitConfigCreate(request models.GitConfigCreateRequest) error {
+	log := c.Log.WithName("GitConfigCreate").WithValues("Namespace", c.Settings.Namespace, "Name", request.Name)
+	log.Info("start")
+	defer log.Info("return")
+
+	kind := models.GitConfigHTTPS
+	if request.PrivateKey != "" {
+		kind = models.GitConfigSSH
+	}
+
+	c.ui.Note().
+		WithStringValue("Name", request.Name).
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("URL", request.URL).
+		WithStringValue("Kind", kind).
+		Msg("Create Git Configuration")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.GitConfigCreate(request, c.Settings.Namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Name", request.Name).
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Git Configuration Saved.")
+
+	return nil
+}
+
+// GitConfigDelete deletes a git configuration of the targeted namespace
+func (c *EpinioClient) GitConfigDelete(name string) error {
+	log := c.Log.WithName("GitConfigDelete").WithValues("Namespace", c.Settings.Namespace, "Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Delete Git Configuration")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	_, err := c.API.GitConfigDelete(c.Settings.Namespace, name)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Name", name).
+		WithStringValue("Namespace", c.Settings.Namespace).
+		Msg("Git Configuration Removed.")
+
+	return nil
+}
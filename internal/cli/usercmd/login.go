@@ -23,6 +23,7 @@ import (
 	"syscall"
 
 	"github.com/epinio/epinio/helpers/termui"
+	"github.com/epinio/epinio/internal/auth"
 	"github.com/epinio/epinio/internal/cli/settings"
 	epinioapi "github.com/epinio/epinio/pkg/api/core/v1/client"
 	"github.com/pkg/errors"
@@ -77,6 +78,46 @@ func (c *EpinioClient) Login(ctx context.Context, username, password, address st
 	return errors.Wrap(err, "error saving new settings")
 }
 
+// LoginToken will update the settings file to authenticate with the personal access token
+func (c *EpinioClient) LoginToken(ctx context.Context, token, address string, trustCA bool) error {
+	log := c.Log.WithName("LoginToken")
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().Msgf("Login to your Epinio cluster [%s]", address)
+
+	if !auth.IsPersonalAccessToken(token) {
+		return errors.New("the token is not a personal access token")
+	}
+
+	// check if the server has a trusted authority, or if we want to trust it anyway
+	serverCertificate, err := checkAndAskCA(c.ui, []string{address}, trustCA)
+	if err != nil {
+		return errors.Wrap(err, "error while checking CA")
+	}
+
+	// load settings and update them (in memory)
+	updatedSettings, err := updateSettings(address, "", "", serverCertificate)
+	if err != nil {
+		return errors.Wrap(err, "error updating settings")
+	}
+	updatedSettings.Token = settings.TokenSetting{
+		AccessToken: token,
+		TokenType:   "Bearer",
+	}
+
+	// verify that settings are valid
+	err = verifyCredentials(ctx, updatedSettings)
+	if err != nil {
+		return errors.Wrap(err, "error verifying credentials")
+	}
+
+	c.ui.Success().Msg("Login successful")
+
+	err = updatedSettings.Save()
+	return errors.Wrap(err, "error saving new settings")
+}
+
 func askUsername(ui *termui.UI) (string, error) {
 	var username string
 	var err error
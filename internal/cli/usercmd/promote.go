@@ -0,0 +1,101 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppPromote promotes the candidate of an application deployed by the blue/green or canary
+// strategies. A weight below 100 only shifts the traffic split of a canary.
+func (c *EpinioClient) AppPromote(appName string, weight *int32) error {
+	log := c.Log.WithName("AppPromote").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName)
+	if weight != nil {
+		msg = msg.WithStringValue("Weight", fmt.Sprintf("%d%%", *weight))
+	}
+	msg.Msg("Promoting application candidate")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	s := c.ui.Progress("Waiting for deployment")
+	resp, err := c.API.AppPromote(c.Settings.Namespace, appName, models.PromoteRequest{Weight: weight})
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	log.V(3).Info("promote response", "response", resp)
+
+	if !resp.Promoted {
+		c.ui.Success().
+			WithStringValue("Stage", resp.Candidate.StageID).
+			WithStringValue("Weight", fmt.Sprintf("%d%%", resp.Candidate.Weight)).
+			Msg("Canary traffic shifted.")
+		return nil
+	}
+
+	routes := []string{}
+	for _, d := range resp.Routes {
+		routes = append(routes, fmt.Sprintf("https://%s", d))
+	}
+
+	c.ui.Success().
+		WithStringValue("Stage", resp.Candidate.StageID).
+		WithStringValue("Previous Stage", resp.Candidate.FromStageID).
+		WithStringValue("Routes", formatRoutes(routes)).
+		Msg("Application candidate promoted.")
+
+	return nil
+}
+
+// AppAbort removes the candidate of an application deployed by the blue/green or canary
+// strategies. The live build stays in place.
+func (c *EpinioClient) AppAbort(appName string) error {
+	log := c.Log.WithName("AppAbort").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName).
+		Msg("Aborting application candidate")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	s := c.ui.Progress("Removing candidate")
+	resp, err := c.API.AppAbort(c.Settings.Namespace, appName)
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	log.V(3).Info("abort response", "response", resp)
+
+	c.ui.Success().
+		WithStringValue("Removed Stage", resp.Candidate.StageID).
+		WithStringValue("Stage", resp.Candidate.FromStageID).
+		Msg("Application candidate aborted.")
+
+	return nil
+}
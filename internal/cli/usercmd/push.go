@@ -19,11 +19,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/epinio/epinio/helpers"
+	"github.com/epinio/epinio/helpers/bytes"
 	"github.com/epinio/epinio/helpers/termui"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/pkg/api/core/v1/models"
@@ -84,6 +86,16 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		params.Staging.Builder != "" {
 		msg = msg.WithStringValue("Builder", params.Staging.Builder)
 	}
+	if params.Origin.Kind != models.OriginContainer &&
+		params.Staging.Strategy != "" {
+		msg = msg.WithStringValue("Staging Strategy", params.Staging.Strategy)
+	}
+	if params.Configuration.Deployment != nil {
+		msg = msg.WithStringValue("Deployment Strategy", params.Configuration.Deployment.Strategy)
+	}
+	if params.Configuration.Resources != nil {
+		msg = msg.WithStringValue("Resources", resourcesString(*params.Configuration.Resources))
+	}
 
 	if params.Configuration.Instances != nil {
 		msg = msg.WithStringValue("Instances",
@@ -150,7 +162,7 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 	case models.OriginPath:
 		c.ui.Normal().Msg("Collecting the application sources ...")
 
-		tmpDir, tarball, err := helpers.Tar(source)
+		tmpDir, tarball, stats, err := helpers.TarSources(source)
 		defer func() {
 			if tmpDir != "" {
 				_ = os.RemoveAll(tmpDir)
@@ -160,10 +172,23 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 			return err
 		}
 
+		msg := c.ui.Normal().
+			WithIntValue("Files", stats.Files).
+			WithStringValue("Size", bytes.ByteCountIEC(stats.Size))
+		if stats.IgnoreFile != "" {
+			msg = msg.WithStringValue("Ignored", fmt.Sprintf("%d (per %s)", stats.Ignored, stats.IgnoreFile))
+		}
+		msg.Msg("Collected the application sources")
+
 		c.ui.Normal().Msg("Uploading application code ...")
 
 		details.Info("upload code")
-		upload, err := c.API.AppUpload(appRef.Namespace, appRef.Name, tarball)
+		upload, err := c.API.AppUploadChunked(appRef.Namespace, appRef.Name, tarball)
+		if rerr, ok := err.(interface{ StatusCode() int }); ok && rerr.StatusCode() == http.StatusNotFound {
+			// The server predates chunked uploads. Fall back to a single request.
+			details.Info("upload code, single request")
+			upload, err = c.API.AppUpload(appRef.Namespace, appRef.Name, tarball)
+		}
 		if err != nil {
 			return err
 		}
@@ -201,6 +226,9 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 			App:          appRef,
 			BlobUID:      blobUID,
 			BuilderImage: params.Staging.Builder,
+			Strategy:     params.Staging.Strategy,
+			Dockerfile:   params.Staging.Dockerfile,
+			Resources:    params.Staging.Resources,
 		}
 		details.Info("staging code", "Blob", blobUID)
 		stageResponse, err = c.API.AppStage(req)
@@ -213,9 +241,9 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 		details.Info("start tailing logs", "StageID", stageResponse.Stage.ID)
 		c.stageLogs(appRef, stageResponse.Stage.ID)
 
-		details.Info("wait for job", "StageID", stageID)
+		details.Info("wait for job", "StageID", stageID, "QueuePosition", stageResponse.QueuePosition)
 		// blocking function that wait until the staging is done
-		_, err := c.API.StagingComplete(appRef.Namespace, stageID)
+		err := c.waitForStaging(appRef, stageID)
 		if err != nil {
 			c.ui.Note().Msgf(
 				"You can access the staging logs at any time, either in the UI or with the CLI using this command:\n\nepinio app logs --staging %s",
@@ -259,6 +287,29 @@ func (c *EpinioClient) Push(ctx context.Context, params PushParams) error { // n
 	return nil
 }
 
+// stagingQueuePollInterval is the time between two checks of a queued staging.
+const stagingQueuePollInterval = 2 * time.Second
+
+// waitForStaging waits until the staging is done. While the staging is queued it polls the
+// server instead of blocking, so that a long wait in the build queue does not time out.
+// The queue position itself is reported through the staging logs.
+func (c *EpinioClient) waitForStaging(appRef models.AppRef, stageID string) error {
+	details := c.Log.WithName("waitForStaging").V(1)
+
+	for {
+		status, err := c.API.StagingStatus(appRef.Namespace, stageID)
+		if err != nil {
+			return err
+		}
+		if status.Status != models.StagingQueued {
+			return nil
+		}
+
+		details.Info("staging queued", "StageID", stageID, "QueuePosition", status.QueuePosition)
+		time.Sleep(stagingQueuePollInterval)
+	}
+}
+
 func (c *EpinioClient) stageLogs(appRef models.AppRef, stageID string) {
 	go func() {
 		err := c.AppLogs(appRef.Name, stageID, true)
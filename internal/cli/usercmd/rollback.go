@@ -0,0 +1,61 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"fmt"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// AppRollback rolls an application back to the image of an earlier build. Without a stage
+// id the build before the deployed one is used.
+func (c *EpinioClient) AppRollback(appName, stageID string) error {
+	log := c.Log.WithName("AppRollback").WithValues("Namespace", c.Settings.Namespace, "Application", appName)
+	log.Info("start")
+	defer log.Info("return")
+
+	msg := c.ui.Note().
+		WithStringValue("Namespace", c.Settings.Namespace).
+		WithStringValue("Application", appName)
+	if stageID != "" {
+		msg = msg.WithStringValue("To Stage", stageID)
+	}
+	msg.Msg("Rolling back application")
+
+	if err := c.TargetOk(); err != nil {
+		return err
+	}
+
+	s := c.ui.Progress("Waiting for deployment")
+	resp, err := c.API.AppRollback(c.Settings.Namespace, appName, models.RollbackRequest{StageID: stageID})
+	s.Stop()
+	if err != nil {
+		return err
+	}
+
+	log.V(3).Info("rollback response", "response", resp)
+
+	routes := []string{}
+	for _, d := range resp.Routes {
+		routes = append(routes, fmt.Sprintf("https://%s", d))
+	}
+
+	c.ui.Success().
+		WithStringValue("Stage", resp.Rollback.StageID).
+		WithStringValue("Previous Stage", resp.Rollback.FromStageID).
+		WithStringValue("Image", resp.Rollback.ImageURL).
+		WithStringValue("Routes", formatRoutes(routes)).
+		Msg("Application rolled back.")
+
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// CreateToken creates a personal access token, and shows its value
+func (c *EpinioClient) CreateToken(name string, namespaces []string, readOnly bool) error {
+	log := c.Log.WithName("CreateToken").WithValues("Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		WithStringValue("Namespaces", namespacesOrAll(namespaces)).
+		WithBoolValue("Read-only", readOnly).
+		Msg("Creating personal access token...")
+
+	resp, err := c.API.TokenCreate(models.TokenCreateRequest{
+		Name:       name,
+		Namespaces: namespaces,
+		ReadOnly:   readOnly,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().
+		WithStringValue("Token", resp.Value).
+		Msg("Personal access token created. Store its value now, it cannot be shown again.")
+
+	return nil
+}
+
+// Tokens lists the personal access tokens of the user
+func (c *EpinioClient) Tokens() error {
+	log := c.Log.WithName("Tokens")
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().Msg("Listing personal access tokens")
+
+	tokens, err := c.API.Tokens()
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("Name", "ID", "Created", "Namespaces", "Read-only")
+	for _, token := range tokens {
+		msg = msg.WithTableRow(
+			token.Name,
+			token.ID,
+			token.CreatedAt.String(),
+			namespacesOrAll(token.Namespaces),
+			fmt.Sprintf("%t", token.ReadOnly))
+	}
+
+	msg.Msg("Personal access tokens:")
+
+	return nil
+}
+
+// RevokeToken revokes the personal access token with the specified name or ID
+func (c *EpinioClient) RevokeToken(name string) error {
+	log := c.Log.WithName("RevokeToken").WithValues("Name", name)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Name", name).
+		Msg("Revoking personal access token...")
+
+	_, err := c.API.TokenDelete(name)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Personal access token revoked.")
+
+	return nil
+}
+
+func namespacesOrAll(namespaces []string) string {
+	if len(namespaces) == 0 {
+		return "(all)"
+	}
+	return strings.Join(namespaces, ", ")
+}
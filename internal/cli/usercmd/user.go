@@ -0,0 +1,228 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package usercmd
+
+import (
+	"strings"
+
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+// Users lists all the users
+func (c *EpinioClient) Users() error {
+	log := c.Log.WithName("Users")
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().Msg("Listing users")
+
+	users, err := c.API.Users()
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("Username", "Role", "Created", "Namespaces")
+	for _, user := range users {
+		msg = msg.WithTableRow(
+			user.Username,
+			user.Role,
+			user.CreatedAt.String(),
+			userNamespaces(user.Namespaces))
+	}
+
+	msg.Msg("Epinio Users:")
+
+	return nil
+}
+
+// ShowUser shows the details of a user
+func (c *EpinioClient) ShowUser(username string) error {
+	log := c.Log.WithName("ShowUser").WithValues("Username", username)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		Msg("Showing user...")
+
+	user, err := c.API.UserShow(username)
+	if err != nil {
+		return err
+	}
+
+	msg := c.ui.Success().WithTable("Key", "Value").
+		WithTableRow("Username", user.Username).
+		WithTableRow("Role", user.Role).
+		WithTableRow("Created", user.CreatedAt.String())
+
+	for _, ns := range user.Namespaces {
+		msg = msg.WithTableRow("Namespace", ns.Name+" ("+ns.Role+")")
+	}
+
+	msg.Msg("Details:")
+
+	return nil
+}
+
+// CreateUser creates a user. The password is asked if not provided.
+func (c *EpinioClient) CreateUser(username, password, role string, namespaces []string) error {
+	log := c.Log.WithName("CreateUser").WithValues("Username", username)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		WithStringValue("Role", role).
+		Msg("Creating user...")
+
+	var err error
+	if password == "" {
+		password, err = askPassword(c.ui)
+		if err != nil {
+			return errors.Wrap(err, "error while asking for password")
+		}
+	}
+
+	request := models.UserCreateRequest{
+		Username: username,
+		Password: password,
+		Role:     role,
+	}
+	for _, ns := range namespaces {
+		userNamespace, err := parseUserNamespace(ns)
+		if err != nil {
+			return err
+		}
+		request.Namespaces = append(request.Namespaces, userNamespace)
+	}
+
+	_, err = c.API.UserCreate(request)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("User created.")
+
+	return nil
+}
+
+// DeleteUser deletes a user
+func (c *EpinioClient) DeleteUser(username string) error {
+	log := c.Log.WithName("DeleteUser").WithValues("Username", username)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		Msg("Deleting user...")
+
+	_, err := c.API.UserDelete(username)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("User deleted.")
+
+	return nil
+}
+
+// ResetUserPassword resets the password of a user. The password is asked if not provided.
+func (c *EpinioClient) ResetUserPassword(username, password string) error {
+	log := c.Log.WithName("ResetUserPassword").WithValues("Username", username)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		Msg("Resetting password of user...")
+
+	var err error
+	if password == "" {
+		password, err = askPassword(c.ui)
+		if err != nil {
+			return errors.Wrap(err, "error while asking for password")
+		}
+	}
+
+	_, err = c.API.UserPassword(username, models.UserPasswordRequest{Password: password})
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Password reset.")
+
+	return nil
+}
+
+// GrantUserNamespace grants the access to a namespace, with an optional role, to a user
+func (c *EpinioClient) GrantUserNamespace(username, namespace, role string) error {
+	log := c.Log.WithName("GrantUserNamespace").WithValues("Username", username, "Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		WithStringValue("Namespace", namespace).
+		WithStringValue("Role", role).
+		Msg("Granting namespace to user...")
+
+	_, err := c.API.UserNamespaceGrant(username, models.UserNamespaceGrantRequest{
+		Namespace: namespace,
+		Role:      role,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace granted.")
+
+	return nil
+}
+
+// RevokeUserNamespace revokes the access to a namespace from a user
+func (c *EpinioClient) RevokeUserNamespace(username, namespace string) error {
+	log := c.Log.WithName("RevokeUserNamespace").WithValues("Username", username, "Namespace", namespace)
+	log.Info("start")
+	defer log.Info("return")
+
+	c.ui.Note().
+		WithStringValue("Username", username).
+		WithStringValue("Namespace", namespace).
+		Msg("Revoking namespace from user...")
+
+	_, err := c.API.UserNamespaceRevoke(username, namespace)
+	if err != nil {
+		return err
+	}
+
+	c.ui.Success().Msg("Namespace revoked.")
+
+	return nil
+}
+
+// parseUserNamespace parses a namespace in the "NAME[:ROLE]" format
+func parseUserNamespace(value string) (models.UserNamespace, error) {
+	name, role, _ := strings.Cut(value, ":")
+	if name == "" {
+		return models.UserNamespace{}, errors.Errorf("invalid namespace '%s', expected NAME[:ROLE]", value)
+	}
+	return models.UserNamespace{Name: name, Role: role}, nil
+}
+
+func userNamespaces(namespaces []models.UserNamespace) string {
+	result := []string{}
+	for _, ns := range namespaces {
+		result = append(result, ns.Name+" ("+ns.Role+")")
+	}
+	return strings.Join(result, ", ")
+}
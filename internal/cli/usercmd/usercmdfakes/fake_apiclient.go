@@ -61,6 +61,62 @@ type FakeAPIClient struct {
 		result1 models.ServiceList
 		result2 error
 	}
+	AppAbortStub        func(string, string) (models.CandidateResponse, error)
+	appAbortMutex       sync.RWMutex
+	appAbortArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appAbortReturns struct {
+		result1 models.CandidateResponse
+		result2 error
+	}
+	appAbortReturnsOnCall map[int]struct {
+		result1 models.CandidateResponse
+		result2 error
+	}
+	AppBuildsStub        func(string, string) (models.AppBuildList, error)
+	appBuildsMutex       sync.RWMutex
+	appBuildsArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appBuildsReturns struct {
+		result1 models.AppBuildList
+		result2 error
+	}
+	appBuildsReturnsOnCall map[int]struct {
+		result1 models.AppBuildList
+		result2 error
+	}
+	AppCacheStub        func(string, string) (models.AppCache, error)
+	appCacheMutex       sync.RWMutex
+	appCacheArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appCacheReturns struct {
+		result1 models.AppCache
+		result2 error
+	}
+	appCacheReturnsOnCall map[int]struct {
+		result1 models.AppCache
+		result2 error
+	}
+	AppCacheClearStub        func(string, string) (models.Response, error)
+	appCacheClearMutex       sync.RWMutex
+	appCacheClearArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appCacheClearReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	appCacheClearReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	AppCreateStub        func(models.ApplicationCreateRequest, string) (models.Response, error)
 	appCreateMutex       sync.RWMutex
 	appCreateArgsForCall []struct {
@@ -102,6 +158,19 @@ type FakeAPIClient struct {
 		result1 *models.DeployResponse
 		result2 error
 	}
+	AppDeployDryRunStub        func(models.DeployRequest) (models.DeployDryRunResponse, error)
+	appDeployDryRunMutex       sync.RWMutex
+	appDeployDryRunArgsForCall []struct {
+		arg1 models.DeployRequest
+	}
+	appDeployDryRunReturns struct {
+		result1 models.DeployDryRunResponse
+		result2 error
+	}
+	appDeployDryRunReturnsOnCall map[int]struct {
+		result1 models.DeployDryRunResponse
+		result2 error
+	}
 	AppExecStub        func(context.Context, string, string, string, term.TTY) error
 	appExecMutex       sync.RWMutex
 	appExecArgsForCall []struct {
@@ -188,6 +257,51 @@ type FakeAPIClient struct {
 	appPortForwardReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AppPromoteStub        func(string, string, models.PromoteRequest) (models.CandidateResponse, error)
+	appPromoteMutex       sync.RWMutex
+	appPromoteArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 models.PromoteRequest
+	}
+	appPromoteReturns struct {
+		result1 models.CandidateResponse
+		result2 error
+	}
+	appPromoteReturnsOnCall map[int]struct {
+		result1 models.CandidateResponse
+		result2 error
+	}
+	AppReleaseDiffStub        func(string, string, int, int) (models.AppReleaseDiff, error)
+	appReleaseDiffMutex       sync.RWMutex
+	appReleaseDiffArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	appReleaseDiffReturns struct {
+		result1 models.AppReleaseDiff
+		result2 error
+	}
+	appReleaseDiffReturnsOnCall map[int]struct {
+		result1 models.AppReleaseDiff
+		result2 error
+	}
+	AppReleasesStub        func(string, string) (models.AppReleaseList, error)
+	appReleasesMutex       sync.RWMutex
+	appReleasesArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appReleasesReturns struct {
+		result1 models.AppReleaseList
+		result2 error
+	}
+	appReleasesReturnsOnCall map[int]struct {
+		result1 models.AppReleaseList
+		result2 error
+	}
 	AppRestartStub        func(string, string) error
 	appRestartMutex       sync.RWMutex
 	appRestartArgsForCall []struct {
@@ -200,6 +314,21 @@ type FakeAPIClient struct {
 	appRestartReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AppRollbackStub        func(string, string, models.RollbackRequest) (models.RollbackResponse, error)
+	appRollbackMutex       sync.RWMutex
+	appRollbackArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 models.RollbackRequest
+	}
+	appRollbackReturns struct {
+		result1 models.RollbackResponse
+		result2 error
+	}
+	appRollbackReturnsOnCall map[int]struct {
+		result1 models.RollbackResponse
+		result2 error
+	}
 	AppRunningStub        func(models.AppRef) (models.Response, error)
 	appRunningMutex       sync.RWMutex
 	appRunningArgsForCall []struct {
@@ -270,6 +399,21 @@ type FakeAPIClient struct {
 		result1 models.UploadResponse
 		result2 error
 	}
+	AppUploadChunkedStub        func(string, string, string) (models.UploadResponse, error)
+	appUploadChunkedMutex       sync.RWMutex
+	appUploadChunkedArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	appUploadChunkedReturns struct {
+		result1 models.UploadResponse
+		result2 error
+	}
+	appUploadChunkedReturnsOnCall map[int]struct {
+		result1 models.UploadResponse
+		result2 error
+	}
 	AppValidateCVStub        func(string, string) (models.Response, error)
 	appValidateCVMutex       sync.RWMutex
 	appValidateCVArgsForCall []struct {
@@ -284,6 +428,60 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	AppWebhookStub        func(string, string) (models.AppWebhook, error)
+	appWebhookMutex       sync.RWMutex
+	appWebhookArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookReturns struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	appWebhookReturnsOnCall map[int]struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	AppWebhookDisableStub        func(string, string) (models.Response, error)
+	appWebhookDisableMutex       sync.RWMutex
+	appWebhookDisableArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookDisableReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	appWebhookDisableReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	AppWebhookEnableStub        func(string, string) (models.AppWebhook, error)
+	appWebhookEnableMutex       sync.RWMutex
+	appWebhookEnableArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookEnableReturns struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	appWebhookEnableReturnsOnCall map[int]struct {
+		result1 models.AppWebhook
+		result2 error
+	}
+	AppWebhookURLStub        func(string, string) string
+	appWebhookURLMutex       sync.RWMutex
+	appWebhookURLArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	appWebhookURLReturns struct {
+		result1 string
+	}
+	appWebhookURLReturnsOnCall map[int]struct {
+		result1 string
+	}
 	AppsStub        func(string) (models.AppList, error)
 	appsMutex       sync.RWMutex
 	appsArgsForCall []struct {
@@ -554,6 +752,47 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	GitConfigCreateStub        func(models.GitConfigCreateRequest, string) (models.Response, error)
+	gitConfigCreateMutex       sync.RWMutex
+	gitConfigCreateArgsForCall []struct {
+		arg1 models.GitConfigCreateRequest
+		arg2 string
+	}
+	gitConfigCreateReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	gitConfigCreateReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	GitConfigDeleteStub        func(string, string) (models.Response, error)
+	gitConfigDeleteMutex       sync.RWMutex
+	gitConfigDeleteArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	gitConfigDeleteReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	gitConfigDeleteReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	GitConfigsStub        func(string) (models.GitConfigList, error)
+	gitConfigsMutex       sync.RWMutex
+	gitConfigsArgsForCall []struct {
+		arg1 string
+	}
+	gitConfigsReturns struct {
+		result1 models.GitConfigList
+		result2 error
+	}
+	gitConfigsReturnsOnCall map[int]struct {
+		result1 models.GitConfigList
+		result2 error
+	}
 	InfoStub        func() (models.InfoResponse, error)
 	infoMutex       sync.RWMutex
 	infoArgsForCall []struct {
@@ -763,6 +1002,20 @@ type FakeAPIClient struct {
 	serviceUnbindReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StagingCancelStub        func(string, string) (models.Response, error)
+	stagingCancelMutex       sync.RWMutex
+	stagingCancelArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	stagingCancelReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	stagingCancelReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
 	StagingCompleteStub        func(string, string) (models.Response, error)
 	stagingCompleteMutex       sync.RWMutex
 	stagingCompleteArgsForCall []struct {
@@ -777,6 +1030,151 @@ type FakeAPIClient struct {
 		result1 models.Response
 		result2 error
 	}
+	StagingStatusStub        func(string, string) (models.StagingCompleteResponse, error)
+	stagingStatusMutex       sync.RWMutex
+	stagingStatusArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	stagingStatusReturns struct {
+		result1 models.StagingCompleteResponse
+		result2 error
+	}
+	stagingStatusReturnsOnCall map[int]struct {
+		result1 models.StagingCompleteResponse
+		result2 error
+	}
+	TokenCreateStub        func(models.TokenCreateRequest) (models.TokenCreateResponse, error)
+	tokenCreateMutex       sync.RWMutex
+	tokenCreateArgsForCall []struct {
+		arg1 models.TokenCreateRequest
+	}
+	tokenCreateReturns struct {
+		result1 models.TokenCreateResponse
+		result2 error
+	}
+	tokenCreateReturnsOnCall map[int]struct {
+		result1 models.TokenCreateResponse
+		result2 error
+	}
+	TokenDeleteStub        func(string) (models.Response, error)
+	tokenDeleteMutex       sync.RWMutex
+	tokenDeleteArgsForCall []struct {
+		arg1 string
+	}
+	tokenDeleteReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	tokenDeleteReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	TokensStub        func() (models.PersonalAccessTokenList, error)
+	tokensMutex       sync.RWMutex
+	tokensArgsForCall []struct {
+	}
+	tokensReturns struct {
+		result1 models.PersonalAccessTokenList
+		result2 error
+	}
+	tokensReturnsOnCall map[int]struct {
+		result1 models.PersonalAccessTokenList
+		result2 error
+	}
+	UserCreateStub        func(models.UserCreateRequest) (models.Response, error)
+	userCreateMutex       sync.RWMutex
+	userCreateArgsForCall []struct {
+		arg1 models.UserCreateRequest
+	}
+	userCreateReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	userCreateReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	UserDeleteStub        func(string) (models.Response, error)
+	userDeleteMutex       sync.RWMutex
+	userDeleteArgsForCall []struct {
+		arg1 string
+	}
+	userDeleteReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	userDeleteReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	UserNamespaceGrantStub        func(string, models.UserNamespaceGrantRequest) (models.Response, error)
+	userNamespaceGrantMutex       sync.RWMutex
+	userNamespaceGrantArgsForCall []struct {
+		arg1 string
+		arg2 models.UserNamespaceGrantRequest
+	}
+	userNamespaceGrantReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	userNamespaceGrantReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	UserNamespaceRevokeStub        func(string, string) (models.Response, error)
+	userNamespaceRevokeMutex       sync.RWMutex
+	userNamespaceRevokeArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	userNamespaceRevokeReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	userNamespaceRevokeReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	UserPasswordStub        func(string, models.UserPasswordRequest) (models.Response, error)
+	userPasswordMutex       sync.RWMutex
+	userPasswordArgsForCall []struct {
+		arg1 string
+		arg2 models.UserPasswordRequest
+	}
+	userPasswordReturns struct {
+		result1 models.Response
+		result2 error
+	}
+	userPasswordReturnsOnCall map[int]struct {
+		result1 models.Response
+		result2 error
+	}
+	UserShowStub        func(string) (models.User, error)
+	userShowMutex       sync.RWMutex
+	userShowArgsForCall []struct {
+		arg1 string
+	}
+	userShowReturns struct {
+		result1 models.User
+		result2 error
+	}
+	userShowReturnsOnCall map[int]struct {
+		result1 models.User
+		result2 error
+	}
+	UsersStub        func() (models.UserList, error)
+	usersMutex       sync.RWMutex
+	usersArgsForCall []struct {
+	}
+	usersReturns struct {
+		result1 models.UserList
+		result2 error
+	}
+	usersReturnsOnCall map[int]struct {
+		result1 models.UserList
+		result2 error
+	}
 	VersionWarningEnabledStub        func() bool
 	versionWarningEnabledMutex       sync.RWMutex
 	versionWarningEnabledArgsForCall []struct {
@@ -959,17 +1357,17 @@ func (fake *FakeAPIClient) AllServicesReturnsOnCall(i int, result1 models.Servic
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppCreate(arg1 models.ApplicationCreateRequest, arg2 string) (models.Response, error) {
-	fake.appCreateMutex.Lock()
-	ret, specificReturn := fake.appCreateReturnsOnCall[len(fake.appCreateArgsForCall)]
-	fake.appCreateArgsForCall = append(fake.appCreateArgsForCall, struct {
-		arg1 models.ApplicationCreateRequest
+func (fake *FakeAPIClient) AppAbort(arg1 string, arg2 string) (models.CandidateResponse, error) {
+	fake.appAbortMutex.Lock()
+	ret, specificReturn := fake.appAbortReturnsOnCall[len(fake.appAbortArgsForCall)]
+	fake.appAbortArgsForCall = append(fake.appAbortArgsForCall, struct {
+		arg1 string
 		arg2 string
 	}{arg1, arg2})
-	stub := fake.AppCreateStub
-	fakeReturns := fake.appCreateReturns
-	fake.recordInvocation("AppCreate", []interface{}{arg1, arg2})
-	fake.appCreateMutex.Unlock()
+	stub := fake.AppAbortStub
+	fakeReturns := fake.appAbortReturns
+	fake.recordInvocation("AppAbort", []interface{}{arg1, arg2})
+	fake.appAbortMutex.Unlock()
 	if stub != nil {
 		return stub(arg1, arg2)
 	}
@@ -979,58 +1377,318 @@ func (fake *FakeAPIClient) AppCreate(arg1 models.ApplicationCreateRequest, arg2
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) AppCreateCallCount() int {
-	fake.appCreateMutex.RLock()
-	defer fake.appCreateMutex.RUnlock()
-	return len(fake.appCreateArgsForCall)
+func (fake *FakeAPIClient) AppAbortCallCount() int {
+	fake.appAbortMutex.RLock()
+	defer fake.appAbortMutex.RUnlock()
+	return len(fake.appAbortArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppCreateCalls(stub func(models.ApplicationCreateRequest, string) (models.Response, error)) {
-	fake.appCreateMutex.Lock()
-	defer fake.appCreateMutex.Unlock()
-	fake.AppCreateStub = stub
+func (fake *FakeAPIClient) AppAbortCalls(stub func(string, string) (models.CandidateResponse, error)) {
+	fake.appAbortMutex.Lock()
+	defer fake.appAbortMutex.Unlock()
+	fake.AppAbortStub = stub
 }
 
-func (fake *FakeAPIClient) AppCreateArgsForCall(i int) (models.ApplicationCreateRequest, string) {
-	fake.appCreateMutex.RLock()
-	defer fake.appCreateMutex.RUnlock()
-	argsForCall := fake.appCreateArgsForCall[i]
+func (fake *FakeAPIClient) AppAbortArgsForCall(i int) (string, string) {
+	fake.appAbortMutex.RLock()
+	defer fake.appAbortMutex.RUnlock()
+	argsForCall := fake.appAbortArgsForCall[i]
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) AppCreateReturns(result1 models.Response, result2 error) {
-	fake.appCreateMutex.Lock()
-	defer fake.appCreateMutex.Unlock()
-	fake.AppCreateStub = nil
-	fake.appCreateReturns = struct {
-		result1 models.Response
+func (fake *FakeAPIClient) AppAbortReturns(result1 models.CandidateResponse, result2 error) {
+	fake.appAbortMutex.Lock()
+	defer fake.appAbortMutex.Unlock()
+	fake.AppAbortStub = nil
+	fake.appAbortReturns = struct {
+		result1 models.CandidateResponse
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
-	fake.appCreateMutex.Lock()
-	defer fake.appCreateMutex.Unlock()
-	fake.AppCreateStub = nil
-	if fake.appCreateReturnsOnCall == nil {
-		fake.appCreateReturnsOnCall = make(map[int]struct {
-			result1 models.Response
+func (fake *FakeAPIClient) AppAbortReturnsOnCall(i int, result1 models.CandidateResponse, result2 error) {
+	fake.appAbortMutex.Lock()
+	defer fake.appAbortMutex.Unlock()
+	fake.AppAbortStub = nil
+	if fake.appAbortReturnsOnCall == nil {
+		fake.appAbortReturnsOnCall = make(map[int]struct {
+			result1 models.CandidateResponse
 			result2 error
 		})
 	}
-	fake.appCreateReturnsOnCall[i] = struct {
-		result1 models.Response
+	fake.appAbortReturnsOnCall[i] = struct {
+		result1 models.CandidateResponse
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppDelete(arg1 string, arg2 []string) (models.ApplicationDeleteResponse, error) {
-	var arg2Copy []string
-	if arg2 != nil {
-		arg2Copy = make([]string, len(arg2))
-		copy(arg2Copy, arg2)
-	}
-	fake.appDeleteMutex.Lock()
+func (fake *FakeAPIClient) AppBuilds(arg1 string, arg2 string) (models.AppBuildList, error) {
+	fake.appBuildsMutex.Lock()
+	ret, specificReturn := fake.appBuildsReturnsOnCall[len(fake.appBuildsArgsForCall)]
+	fake.appBuildsArgsForCall = append(fake.appBuildsArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppBuildsStub
+	fakeReturns := fake.appBuildsReturns
+	fake.recordInvocation("AppBuilds", []interface{}{arg1, arg2})
+	fake.appBuildsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppBuildsCallCount() int {
+	fake.appBuildsMutex.RLock()
+	defer fake.appBuildsMutex.RUnlock()
+	return len(fake.appBuildsArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppBuildsCalls(stub func(string, string) (models.AppBuildList, error)) {
+	fake.appBuildsMutex.Lock()
+	defer fake.appBuildsMutex.Unlock()
+	fake.AppBuildsStub = stub
+}
+
+func (fake *FakeAPIClient) AppBuildsArgsForCall(i int) (string, string) {
+	fake.appBuildsMutex.RLock()
+	defer fake.appBuildsMutex.RUnlock()
+	argsForCall := fake.appBuildsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppBuildsReturns(result1 models.AppBuildList, result2 error) {
+	fake.appBuildsMutex.Lock()
+	defer fake.appBuildsMutex.Unlock()
+	fake.AppBuildsStub = nil
+	fake.appBuildsReturns = struct {
+		result1 models.AppBuildList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppBuildsReturnsOnCall(i int, result1 models.AppBuildList, result2 error) {
+	fake.appBuildsMutex.Lock()
+	defer fake.appBuildsMutex.Unlock()
+	fake.AppBuildsStub = nil
+	if fake.appBuildsReturnsOnCall == nil {
+		fake.appBuildsReturnsOnCall = make(map[int]struct {
+			result1 models.AppBuildList
+			result2 error
+		})
+	}
+	fake.appBuildsReturnsOnCall[i] = struct {
+		result1 models.AppBuildList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCache(arg1 string, arg2 string) (models.AppCache, error) {
+	fake.appCacheMutex.Lock()
+	ret, specificReturn := fake.appCacheReturnsOnCall[len(fake.appCacheArgsForCall)]
+	fake.appCacheArgsForCall = append(fake.appCacheArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppCacheStub
+	fakeReturns := fake.appCacheReturns
+	fake.recordInvocation("AppCache", []interface{}{arg1, arg2})
+	fake.appCacheMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppCacheCallCount() int {
+	fake.appCacheMutex.RLock()
+	defer fake.appCacheMutex.RUnlock()
+	return len(fake.appCacheArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppCacheCalls(stub func(string, string) (models.AppCache, error)) {
+	fake.appCacheMutex.Lock()
+	defer fake.appCacheMutex.Unlock()
+	fake.AppCacheStub = stub
+}
+
+func (fake *FakeAPIClient) AppCacheArgsForCall(i int) (string, string) {
+	fake.appCacheMutex.RLock()
+	defer fake.appCacheMutex.RUnlock()
+	argsForCall := fake.appCacheArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppCacheReturns(result1 models.AppCache, result2 error) {
+	fake.appCacheMutex.Lock()
+	defer fake.appCacheMutex.Unlock()
+	fake.AppCacheStub = nil
+	fake.appCacheReturns = struct {
+		result1 models.AppCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCacheReturnsOnCall(i int, result1 models.AppCache, result2 error) {
+	fake.appCacheMutex.Lock()
+	defer fake.appCacheMutex.Unlock()
+	fake.AppCacheStub = nil
+	if fake.appCacheReturnsOnCall == nil {
+		fake.appCacheReturnsOnCall = make(map[int]struct {
+			result1 models.AppCache
+			result2 error
+		})
+	}
+	fake.appCacheReturnsOnCall[i] = struct {
+		result1 models.AppCache
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCacheClear(arg1 string, arg2 string) (models.Response, error) {
+	fake.appCacheClearMutex.Lock()
+	ret, specificReturn := fake.appCacheClearReturnsOnCall[len(fake.appCacheClearArgsForCall)]
+	fake.appCacheClearArgsForCall = append(fake.appCacheClearArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppCacheClearStub
+	fakeReturns := fake.appCacheClearReturns
+	fake.recordInvocation("AppCacheClear", []interface{}{arg1, arg2})
+	fake.appCacheClearMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppCacheClearCallCount() int {
+	fake.appCacheClearMutex.RLock()
+	defer fake.appCacheClearMutex.RUnlock()
+	return len(fake.appCacheClearArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppCacheClearCalls(stub func(string, string) (models.Response, error)) {
+	fake.appCacheClearMutex.Lock()
+	defer fake.appCacheClearMutex.Unlock()
+	fake.AppCacheClearStub = stub
+}
+
+func (fake *FakeAPIClient) AppCacheClearArgsForCall(i int) (string, string) {
+	fake.appCacheClearMutex.RLock()
+	defer fake.appCacheClearMutex.RUnlock()
+	argsForCall := fake.appCacheClearArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppCacheClearReturns(result1 models.Response, result2 error) {
+	fake.appCacheClearMutex.Lock()
+	defer fake.appCacheClearMutex.Unlock()
+	fake.AppCacheClearStub = nil
+	fake.appCacheClearReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCacheClearReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.appCacheClearMutex.Lock()
+	defer fake.appCacheClearMutex.Unlock()
+	fake.AppCacheClearStub = nil
+	if fake.appCacheClearReturnsOnCall == nil {
+		fake.appCacheClearReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.appCacheClearReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCreate(arg1 models.ApplicationCreateRequest, arg2 string) (models.Response, error) {
+	fake.appCreateMutex.Lock()
+	ret, specificReturn := fake.appCreateReturnsOnCall[len(fake.appCreateArgsForCall)]
+	fake.appCreateArgsForCall = append(fake.appCreateArgsForCall, struct {
+		arg1 models.ApplicationCreateRequest
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppCreateStub
+	fakeReturns := fake.appCreateReturns
+	fake.recordInvocation("AppCreate", []interface{}{arg1, arg2})
+	fake.appCreateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppCreateCallCount() int {
+	fake.appCreateMutex.RLock()
+	defer fake.appCreateMutex.RUnlock()
+	return len(fake.appCreateArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppCreateCalls(stub func(models.ApplicationCreateRequest, string) (models.Response, error)) {
+	fake.appCreateMutex.Lock()
+	defer fake.appCreateMutex.Unlock()
+	fake.AppCreateStub = stub
+}
+
+func (fake *FakeAPIClient) AppCreateArgsForCall(i int) (models.ApplicationCreateRequest, string) {
+	fake.appCreateMutex.RLock()
+	defer fake.appCreateMutex.RUnlock()
+	argsForCall := fake.appCreateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppCreateReturns(result1 models.Response, result2 error) {
+	fake.appCreateMutex.Lock()
+	defer fake.appCreateMutex.Unlock()
+	fake.AppCreateStub = nil
+	fake.appCreateReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.appCreateMutex.Lock()
+	defer fake.appCreateMutex.Unlock()
+	fake.AppCreateStub = nil
+	if fake.appCreateReturnsOnCall == nil {
+		fake.appCreateReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.appCreateReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppDelete(arg1 string, arg2 []string) (models.ApplicationDeleteResponse, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.appDeleteMutex.Lock()
 	ret, specificReturn := fake.appDeleteReturnsOnCall[len(fake.appDeleteArgsForCall)]
 	fake.appDeleteArgsForCall = append(fake.appDeleteArgsForCall, struct {
 		arg1 string
@@ -1158,6 +1816,70 @@ func (fake *FakeAPIClient) AppDeployReturnsOnCall(i int, result1 *models.DeployR
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppDeployDryRun(arg1 models.DeployRequest) (models.DeployDryRunResponse, error) {
+	fake.appDeployDryRunMutex.Lock()
+	ret, specificReturn := fake.appDeployDryRunReturnsOnCall[len(fake.appDeployDryRunArgsForCall)]
+	fake.appDeployDryRunArgsForCall = append(fake.appDeployDryRunArgsForCall, struct {
+		arg1 models.DeployRequest
+	}{arg1})
+	stub := fake.AppDeployDryRunStub
+	fakeReturns := fake.appDeployDryRunReturns
+	fake.recordInvocation("AppDeployDryRun", []interface{}{arg1})
+	fake.appDeployDryRunMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppDeployDryRunCallCount() int {
+	fake.appDeployDryRunMutex.RLock()
+	defer fake.appDeployDryRunMutex.RUnlock()
+	return len(fake.appDeployDryRunArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppDeployDryRunCalls(stub func(models.DeployRequest) (models.DeployDryRunResponse, error)) {
+	fake.appDeployDryRunMutex.Lock()
+	defer fake.appDeployDryRunMutex.Unlock()
+	fake.AppDeployDryRunStub = stub
+}
+
+func (fake *FakeAPIClient) AppDeployDryRunArgsForCall(i int) models.DeployRequest {
+	fake.appDeployDryRunMutex.RLock()
+	defer fake.appDeployDryRunMutex.RUnlock()
+	argsForCall := fake.appDeployDryRunArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) AppDeployDryRunReturns(result1 models.DeployDryRunResponse, result2 error) {
+	fake.appDeployDryRunMutex.Lock()
+	defer fake.appDeployDryRunMutex.Unlock()
+	fake.AppDeployDryRunStub = nil
+	fake.appDeployDryRunReturns = struct {
+		result1 models.DeployDryRunResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppDeployDryRunReturnsOnCall(i int, result1 models.DeployDryRunResponse, result2 error) {
+	fake.appDeployDryRunMutex.Lock()
+	defer fake.appDeployDryRunMutex.Unlock()
+	fake.AppDeployDryRunStub = nil
+	if fake.appDeployDryRunReturnsOnCall == nil {
+		fake.appDeployDryRunReturnsOnCall = make(map[int]struct {
+			result1 models.DeployDryRunResponse
+			result2 error
+		})
+	}
+	fake.appDeployDryRunReturnsOnCall[i] = struct {
+		result1 models.DeployDryRunResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppExec(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 term.TTY) error {
 	fake.appExecMutex.Lock()
 	ret, specificReturn := fake.appExecReturnsOnCall[len(fake.appExecArgsForCall)]
@@ -1546,78 +2268,342 @@ func (fake *FakeAPIClient) AppPortForwardReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeAPIClient) AppRestart(arg1 string, arg2 string) error {
-	fake.appRestartMutex.Lock()
-	ret, specificReturn := fake.appRestartReturnsOnCall[len(fake.appRestartArgsForCall)]
-	fake.appRestartArgsForCall = append(fake.appRestartArgsForCall, struct {
+func (fake *FakeAPIClient) AppPromote(arg1 string, arg2 string, arg3 models.PromoteRequest) (models.CandidateResponse, error) {
+	fake.appPromoteMutex.Lock()
+	ret, specificReturn := fake.appPromoteReturnsOnCall[len(fake.appPromoteArgsForCall)]
+	fake.appPromoteArgsForCall = append(fake.appPromoteArgsForCall, struct {
 		arg1 string
 		arg2 string
-	}{arg1, arg2})
-	stub := fake.AppRestartStub
-	fakeReturns := fake.appRestartReturns
-	fake.recordInvocation("AppRestart", []interface{}{arg1, arg2})
-	fake.appRestartMutex.Unlock()
+		arg3 models.PromoteRequest
+	}{arg1, arg2, arg3})
+	stub := fake.AppPromoteStub
+	fakeReturns := fake.appPromoteReturns
+	fake.recordInvocation("AppPromote", []interface{}{arg1, arg2, arg3})
+	fake.appPromoteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) AppRestartCallCount() int {
-	fake.appRestartMutex.RLock()
-	defer fake.appRestartMutex.RUnlock()
-	return len(fake.appRestartArgsForCall)
+func (fake *FakeAPIClient) AppPromoteCallCount() int {
+	fake.appPromoteMutex.RLock()
+	defer fake.appPromoteMutex.RUnlock()
+	return len(fake.appPromoteArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppRestartCalls(stub func(string, string) error) {
-	fake.appRestartMutex.Lock()
-	defer fake.appRestartMutex.Unlock()
-	fake.AppRestartStub = stub
+func (fake *FakeAPIClient) AppPromoteCalls(stub func(string, string, models.PromoteRequest) (models.CandidateResponse, error)) {
+	fake.appPromoteMutex.Lock()
+	defer fake.appPromoteMutex.Unlock()
+	fake.AppPromoteStub = stub
 }
 
-func (fake *FakeAPIClient) AppRestartArgsForCall(i int) (string, string) {
-	fake.appRestartMutex.RLock()
-	defer fake.appRestartMutex.RUnlock()
-	argsForCall := fake.appRestartArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+func (fake *FakeAPIClient) AppPromoteArgsForCall(i int) (string, string, models.PromoteRequest) {
+	fake.appPromoteMutex.RLock()
+	defer fake.appPromoteMutex.RUnlock()
+	argsForCall := fake.appPromoteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeAPIClient) AppRestartReturns(result1 error) {
-	fake.appRestartMutex.Lock()
-	defer fake.appRestartMutex.Unlock()
-	fake.AppRestartStub = nil
-	fake.appRestartReturns = struct {
-		result1 error
-	}{result1}
+func (fake *FakeAPIClient) AppPromoteReturns(result1 models.CandidateResponse, result2 error) {
+	fake.appPromoteMutex.Lock()
+	defer fake.appPromoteMutex.Unlock()
+	fake.AppPromoteStub = nil
+	fake.appPromoteReturns = struct {
+		result1 models.CandidateResponse
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppRestartReturnsOnCall(i int, result1 error) {
-	fake.appRestartMutex.Lock()
-	defer fake.appRestartMutex.Unlock()
-	fake.AppRestartStub = nil
-	if fake.appRestartReturnsOnCall == nil {
-		fake.appRestartReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *FakeAPIClient) AppPromoteReturnsOnCall(i int, result1 models.CandidateResponse, result2 error) {
+	fake.appPromoteMutex.Lock()
+	defer fake.appPromoteMutex.Unlock()
+	fake.AppPromoteStub = nil
+	if fake.appPromoteReturnsOnCall == nil {
+		fake.appPromoteReturnsOnCall = make(map[int]struct {
+			result1 models.CandidateResponse
+			result2 error
 		})
 	}
-	fake.appRestartReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.appPromoteReturnsOnCall[i] = struct {
+		result1 models.CandidateResponse
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppRunning(arg1 models.AppRef) (models.Response, error) {
-	fake.appRunningMutex.Lock()
-	ret, specificReturn := fake.appRunningReturnsOnCall[len(fake.appRunningArgsForCall)]
-	fake.appRunningArgsForCall = append(fake.appRunningArgsForCall, struct {
-		arg1 models.AppRef
-	}{arg1})
-	stub := fake.AppRunningStub
-	fakeReturns := fake.appRunningReturns
-	fake.recordInvocation("AppRunning", []interface{}{arg1})
-	fake.appRunningMutex.Unlock()
+func (fake *FakeAPIClient) AppReleaseDiff(arg1 string, arg2 string, arg3 int, arg4 int) (models.AppReleaseDiff, error) {
+	fake.appReleaseDiffMutex.Lock()
+	ret, specificReturn := fake.appReleaseDiffReturnsOnCall[len(fake.appReleaseDiffArgsForCall)]
+	fake.appReleaseDiffArgsForCall = append(fake.appReleaseDiffArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.AppReleaseDiffStub
+	fakeReturns := fake.appReleaseDiffReturns
+	fake.recordInvocation("AppReleaseDiff", []interface{}{arg1, arg2, arg3, arg4})
+	fake.appReleaseDiffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppReleaseDiffCallCount() int {
+	fake.appReleaseDiffMutex.RLock()
+	defer fake.appReleaseDiffMutex.RUnlock()
+	return len(fake.appReleaseDiffArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppReleaseDiffCalls(stub func(string, string, int, int) (models.AppReleaseDiff, error)) {
+	fake.appReleaseDiffMutex.Lock()
+	defer fake.appReleaseDiffMutex.Unlock()
+	fake.AppReleaseDiffStub = stub
+}
+
+func (fake *FakeAPIClient) AppReleaseDiffArgsForCall(i int) (string, string, int, int) {
+	fake.appReleaseDiffMutex.RLock()
+	defer fake.appReleaseDiffMutex.RUnlock()
+	argsForCall := fake.appReleaseDiffArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAPIClient) AppReleaseDiffReturns(result1 models.AppReleaseDiff, result2 error) {
+	fake.appReleaseDiffMutex.Lock()
+	defer fake.appReleaseDiffMutex.Unlock()
+	fake.AppReleaseDiffStub = nil
+	fake.appReleaseDiffReturns = struct {
+		result1 models.AppReleaseDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppReleaseDiffReturnsOnCall(i int, result1 models.AppReleaseDiff, result2 error) {
+	fake.appReleaseDiffMutex.Lock()
+	defer fake.appReleaseDiffMutex.Unlock()
+	fake.AppReleaseDiffStub = nil
+	if fake.appReleaseDiffReturnsOnCall == nil {
+		fake.appReleaseDiffReturnsOnCall = make(map[int]struct {
+			result1 models.AppReleaseDiff
+			result2 error
+		})
+	}
+	fake.appReleaseDiffReturnsOnCall[i] = struct {
+		result1 models.AppReleaseDiff
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppReleases(arg1 string, arg2 string) (models.AppReleaseList, error) {
+	fake.appReleasesMutex.Lock()
+	ret, specificReturn := fake.appReleasesReturnsOnCall[len(fake.appReleasesArgsForCall)]
+	fake.appReleasesArgsForCall = append(fake.appReleasesArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppReleasesStub
+	fakeReturns := fake.appReleasesReturns
+	fake.recordInvocation("AppReleases", []interface{}{arg1, arg2})
+	fake.appReleasesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppReleasesCallCount() int {
+	fake.appReleasesMutex.RLock()
+	defer fake.appReleasesMutex.RUnlock()
+	return len(fake.appReleasesArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppReleasesCalls(stub func(string, string) (models.AppReleaseList, error)) {
+	fake.appReleasesMutex.Lock()
+	defer fake.appReleasesMutex.Unlock()
+	fake.AppReleasesStub = stub
+}
+
+func (fake *FakeAPIClient) AppReleasesArgsForCall(i int) (string, string) {
+	fake.appReleasesMutex.RLock()
+	defer fake.appReleasesMutex.RUnlock()
+	argsForCall := fake.appReleasesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppReleasesReturns(result1 models.AppReleaseList, result2 error) {
+	fake.appReleasesMutex.Lock()
+	defer fake.appReleasesMutex.Unlock()
+	fake.AppReleasesStub = nil
+	fake.appReleasesReturns = struct {
+		result1 models.AppReleaseList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppReleasesReturnsOnCall(i int, result1 models.AppReleaseList, result2 error) {
+	fake.appReleasesMutex.Lock()
+	defer fake.appReleasesMutex.Unlock()
+	fake.AppReleasesStub = nil
+	if fake.appReleasesReturnsOnCall == nil {
+		fake.appReleasesReturnsOnCall = make(map[int]struct {
+			result1 models.AppReleaseList
+			result2 error
+		})
+	}
+	fake.appReleasesReturnsOnCall[i] = struct {
+		result1 models.AppReleaseList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppRestart(arg1 string, arg2 string) error {
+	fake.appRestartMutex.Lock()
+	ret, specificReturn := fake.appRestartReturnsOnCall[len(fake.appRestartArgsForCall)]
+	fake.appRestartArgsForCall = append(fake.appRestartArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppRestartStub
+	fakeReturns := fake.appRestartReturns
+	fake.recordInvocation("AppRestart", []interface{}{arg1, arg2})
+	fake.appRestartMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAPIClient) AppRestartCallCount() int {
+	fake.appRestartMutex.RLock()
+	defer fake.appRestartMutex.RUnlock()
+	return len(fake.appRestartArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppRestartCalls(stub func(string, string) error) {
+	fake.appRestartMutex.Lock()
+	defer fake.appRestartMutex.Unlock()
+	fake.AppRestartStub = stub
+}
+
+func (fake *FakeAPIClient) AppRestartArgsForCall(i int) (string, string) {
+	fake.appRestartMutex.RLock()
+	defer fake.appRestartMutex.RUnlock()
+	argsForCall := fake.appRestartArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppRestartReturns(result1 error) {
+	fake.appRestartMutex.Lock()
+	defer fake.appRestartMutex.Unlock()
+	fake.AppRestartStub = nil
+	fake.appRestartReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAPIClient) AppRestartReturnsOnCall(i int, result1 error) {
+	fake.appRestartMutex.Lock()
+	defer fake.appRestartMutex.Unlock()
+	fake.AppRestartStub = nil
+	if fake.appRestartReturnsOnCall == nil {
+		fake.appRestartReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.appRestartReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAPIClient) AppRollback(arg1 string, arg2 string, arg3 models.RollbackRequest) (models.RollbackResponse, error) {
+	fake.appRollbackMutex.Lock()
+	ret, specificReturn := fake.appRollbackReturnsOnCall[len(fake.appRollbackArgsForCall)]
+	fake.appRollbackArgsForCall = append(fake.appRollbackArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 models.RollbackRequest
+	}{arg1, arg2, arg3})
+	stub := fake.AppRollbackStub
+	fakeReturns := fake.appRollbackReturns
+	fake.recordInvocation("AppRollback", []interface{}{arg1, arg2, arg3})
+	fake.appRollbackMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppRollbackCallCount() int {
+	fake.appRollbackMutex.RLock()
+	defer fake.appRollbackMutex.RUnlock()
+	return len(fake.appRollbackArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppRollbackCalls(stub func(string, string, models.RollbackRequest) (models.RollbackResponse, error)) {
+	fake.appRollbackMutex.Lock()
+	defer fake.appRollbackMutex.Unlock()
+	fake.AppRollbackStub = stub
+}
+
+func (fake *FakeAPIClient) AppRollbackArgsForCall(i int) (string, string, models.RollbackRequest) {
+	fake.appRollbackMutex.RLock()
+	defer fake.appRollbackMutex.RUnlock()
+	argsForCall := fake.appRollbackArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) AppRollbackReturns(result1 models.RollbackResponse, result2 error) {
+	fake.appRollbackMutex.Lock()
+	defer fake.appRollbackMutex.Unlock()
+	fake.AppRollbackStub = nil
+	fake.appRollbackReturns = struct {
+		result1 models.RollbackResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppRollbackReturnsOnCall(i int, result1 models.RollbackResponse, result2 error) {
+	fake.appRollbackMutex.Lock()
+	defer fake.appRollbackMutex.Unlock()
+	fake.AppRollbackStub = nil
+	if fake.appRollbackReturnsOnCall == nil {
+		fake.appRollbackReturnsOnCall = make(map[int]struct {
+			result1 models.RollbackResponse
+			result2 error
+		})
+	}
+	fake.appRollbackReturnsOnCall[i] = struct {
+		result1 models.RollbackResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppRunning(arg1 models.AppRef) (models.Response, error) {
+	fake.appRunningMutex.Lock()
+	ret, specificReturn := fake.appRunningReturnsOnCall[len(fake.appRunningArgsForCall)]
+	fake.appRunningArgsForCall = append(fake.appRunningArgsForCall, struct {
+		arg1 models.AppRef
+	}{arg1})
+	stub := fake.AppRunningStub
+	fakeReturns := fake.appRunningReturns
+	fake.recordInvocation("AppRunning", []interface{}{arg1})
+	fake.appRunningMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
 	}
@@ -1933,6 +2919,72 @@ func (fake *FakeAPIClient) AppUploadReturnsOnCall(i int, result1 models.UploadRe
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) AppUploadChunked(arg1 string, arg2 string, arg3 string) (models.UploadResponse, error) {
+	fake.appUploadChunkedMutex.Lock()
+	ret, specificReturn := fake.appUploadChunkedReturnsOnCall[len(fake.appUploadChunkedArgsForCall)]
+	fake.appUploadChunkedArgsForCall = append(fake.appUploadChunkedArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.AppUploadChunkedStub
+	fakeReturns := fake.appUploadChunkedReturns
+	fake.recordInvocation("AppUploadChunked", []interface{}{arg1, arg2, arg3})
+	fake.appUploadChunkedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedCallCount() int {
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
+	return len(fake.appUploadChunkedArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedCalls(stub func(string, string, string) (models.UploadResponse, error)) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = stub
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedArgsForCall(i int) (string, string, string) {
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
+	argsForCall := fake.appUploadChunkedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedReturns(result1 models.UploadResponse, result2 error) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = nil
+	fake.appUploadChunkedReturns = struct {
+		result1 models.UploadResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppUploadChunkedReturnsOnCall(i int, result1 models.UploadResponse, result2 error) {
+	fake.appUploadChunkedMutex.Lock()
+	defer fake.appUploadChunkedMutex.Unlock()
+	fake.AppUploadChunkedStub = nil
+	if fake.appUploadChunkedReturnsOnCall == nil {
+		fake.appUploadChunkedReturnsOnCall = make(map[int]struct {
+			result1 models.UploadResponse
+			result2 error
+		})
+	}
+	fake.appUploadChunkedReturnsOnCall[i] = struct {
+		result1 models.UploadResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) AppValidateCV(arg1 string, arg2 string) (models.Response, error) {
 	fake.appValidateCVMutex.Lock()
 	ret, specificReturn := fake.appValidateCVReturnsOnCall[len(fake.appValidateCVArgsForCall)]
@@ -1998,18 +3050,19 @@ func (fake *FakeAPIClient) AppValidateCVReturnsOnCall(i int, result1 models.Resp
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
-	fake.appsMutex.Lock()
-	ret, specificReturn := fake.appsReturnsOnCall[len(fake.appsArgsForCall)]
-	fake.appsArgsForCall = append(fake.appsArgsForCall, struct {
+func (fake *FakeAPIClient) AppWebhook(arg1 string, arg2 string) (models.AppWebhook, error) {
+	fake.appWebhookMutex.Lock()
+	ret, specificReturn := fake.appWebhookReturnsOnCall[len(fake.appWebhookArgsForCall)]
+	fake.appWebhookArgsForCall = append(fake.appWebhookArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	stub := fake.AppsStub
-	fakeReturns := fake.appsReturns
-	fake.recordInvocation("Apps", []interface{}{arg1})
-	fake.appsMutex.Unlock()
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookStub
+	fakeReturns := fake.appWebhookReturns
+	fake.recordInvocation("AppWebhook", []interface{}{arg1, arg2})
+	fake.appWebhookMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -2017,62 +3070,64 @@ func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) AppsCallCount() int {
-	fake.appsMutex.RLock()
-	defer fake.appsMutex.RUnlock()
-	return len(fake.appsArgsForCall)
+func (fake *FakeAPIClient) AppWebhookCallCount() int {
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	return len(fake.appWebhookArgsForCall)
 }
 
-func (fake *FakeAPIClient) AppsCalls(stub func(string) (models.AppList, error)) {
-	fake.appsMutex.Lock()
-	defer fake.appsMutex.Unlock()
-	fake.AppsStub = stub
+func (fake *FakeAPIClient) AppWebhookCalls(stub func(string, string) (models.AppWebhook, error)) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = stub
 }
 
-func (fake *FakeAPIClient) AppsArgsForCall(i int) string {
-	fake.appsMutex.RLock()
-	defer fake.appsMutex.RUnlock()
-	argsForCall := fake.appsArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeAPIClient) AppWebhookArgsForCall(i int) (string, string) {
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	argsForCall := fake.appWebhookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) AppsReturns(result1 models.AppList, result2 error) {
-	fake.appsMutex.Lock()
-	defer fake.appsMutex.Unlock()
-	fake.AppsStub = nil
-	fake.appsReturns = struct {
-		result1 models.AppList
+func (fake *FakeAPIClient) AppWebhookReturns(result1 models.AppWebhook, result2 error) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = nil
+	fake.appWebhookReturns = struct {
+		result1 models.AppWebhook
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AppsReturnsOnCall(i int, result1 models.AppList, result2 error) {
-	fake.appsMutex.Lock()
-	defer fake.appsMutex.Unlock()
-	fake.AppsStub = nil
-	if fake.appsReturnsOnCall == nil {
-		fake.appsReturnsOnCall = make(map[int]struct {
-			result1 models.AppList
+func (fake *FakeAPIClient) AppWebhookReturnsOnCall(i int, result1 models.AppWebhook, result2 error) {
+	fake.appWebhookMutex.Lock()
+	defer fake.appWebhookMutex.Unlock()
+	fake.AppWebhookStub = nil
+	if fake.appWebhookReturnsOnCall == nil {
+		fake.appWebhookReturnsOnCall = make(map[int]struct {
+			result1 models.AppWebhook
 			result2 error
 		})
 	}
-	fake.appsReturnsOnCall[i] = struct {
-		result1 models.AppList
+	fake.appWebhookReturnsOnCall[i] = struct {
+		result1 models.AppWebhook
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) AuthToken() (string, error) {
-	fake.authTokenMutex.Lock()
-	ret, specificReturn := fake.authTokenReturnsOnCall[len(fake.authTokenArgsForCall)]
-	fake.authTokenArgsForCall = append(fake.authTokenArgsForCall, struct {
-	}{})
-	stub := fake.AuthTokenStub
-	fakeReturns := fake.authTokenReturns
-	fake.recordInvocation("AuthToken", []interface{}{})
-	fake.authTokenMutex.Unlock()
+func (fake *FakeAPIClient) AppWebhookDisable(arg1 string, arg2 string) (models.Response, error) {
+	fake.appWebhookDisableMutex.Lock()
+	ret, specificReturn := fake.appWebhookDisableReturnsOnCall[len(fake.appWebhookDisableArgsForCall)]
+	fake.appWebhookDisableArgsForCall = append(fake.appWebhookDisableArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookDisableStub
+	fakeReturns := fake.appWebhookDisableReturns
+	fake.recordInvocation("AppWebhookDisable", []interface{}{arg1, arg2})
+	fake.appWebhookDisableMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -2080,10 +3135,264 @@ func (fake *FakeAPIClient) AuthToken() (string, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) AuthTokenCallCount() int {
-	fake.authTokenMutex.RLock()
-	defer fake.authTokenMutex.RUnlock()
-	return len(fake.authTokenArgsForCall)
+func (fake *FakeAPIClient) AppWebhookDisableCallCount() int {
+	fake.appWebhookDisableMutex.RLock()
+	defer fake.appWebhookDisableMutex.RUnlock()
+	return len(fake.appWebhookDisableArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookDisableCalls(stub func(string, string) (models.Response, error)) {
+	fake.appWebhookDisableMutex.Lock()
+	defer fake.appWebhookDisableMutex.Unlock()
+	fake.AppWebhookDisableStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookDisableArgsForCall(i int) (string, string) {
+	fake.appWebhookDisableMutex.RLock()
+	defer fake.appWebhookDisableMutex.RUnlock()
+	argsForCall := fake.appWebhookDisableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookDisableReturns(result1 models.Response, result2 error) {
+	fake.appWebhookDisableMutex.Lock()
+	defer fake.appWebhookDisableMutex.Unlock()
+	fake.AppWebhookDisableStub = nil
+	fake.appWebhookDisableReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookDisableReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.appWebhookDisableMutex.Lock()
+	defer fake.appWebhookDisableMutex.Unlock()
+	fake.AppWebhookDisableStub = nil
+	if fake.appWebhookDisableReturnsOnCall == nil {
+		fake.appWebhookDisableReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.appWebhookDisableReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookEnable(arg1 string, arg2 string) (models.AppWebhook, error) {
+	fake.appWebhookEnableMutex.Lock()
+	ret, specificReturn := fake.appWebhookEnableReturnsOnCall[len(fake.appWebhookEnableArgsForCall)]
+	fake.appWebhookEnableArgsForCall = append(fake.appWebhookEnableArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookEnableStub
+	fakeReturns := fake.appWebhookEnableReturns
+	fake.recordInvocation("AppWebhookEnable", []interface{}{arg1, arg2})
+	fake.appWebhookEnableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableCallCount() int {
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	return len(fake.appWebhookEnableArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableCalls(stub func(string, string) (models.AppWebhook, error)) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableArgsForCall(i int) (string, string) {
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	argsForCall := fake.appWebhookEnableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableReturns(result1 models.AppWebhook, result2 error) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = nil
+	fake.appWebhookEnableReturns = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookEnableReturnsOnCall(i int, result1 models.AppWebhook, result2 error) {
+	fake.appWebhookEnableMutex.Lock()
+	defer fake.appWebhookEnableMutex.Unlock()
+	fake.AppWebhookEnableStub = nil
+	if fake.appWebhookEnableReturnsOnCall == nil {
+		fake.appWebhookEnableReturnsOnCall = make(map[int]struct {
+			result1 models.AppWebhook
+			result2 error
+		})
+	}
+	fake.appWebhookEnableReturnsOnCall[i] = struct {
+		result1 models.AppWebhook
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppWebhookURL(arg1 string, arg2 string) string {
+	fake.appWebhookURLMutex.Lock()
+	ret, specificReturn := fake.appWebhookURLReturnsOnCall[len(fake.appWebhookURLArgsForCall)]
+	fake.appWebhookURLArgsForCall = append(fake.appWebhookURLArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AppWebhookURLStub
+	fakeReturns := fake.appWebhookURLReturns
+	fake.recordInvocation("AppWebhookURL", []interface{}{arg1, arg2})
+	fake.appWebhookURLMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAPIClient) AppWebhookURLCallCount() int {
+	fake.appWebhookURLMutex.RLock()
+	defer fake.appWebhookURLMutex.RUnlock()
+	return len(fake.appWebhookURLArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppWebhookURLCalls(stub func(string, string) string) {
+	fake.appWebhookURLMutex.Lock()
+	defer fake.appWebhookURLMutex.Unlock()
+	fake.AppWebhookURLStub = stub
+}
+
+func (fake *FakeAPIClient) AppWebhookURLArgsForCall(i int) (string, string) {
+	fake.appWebhookURLMutex.RLock()
+	defer fake.appWebhookURLMutex.RUnlock()
+	argsForCall := fake.appWebhookURLArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) AppWebhookURLReturns(result1 string) {
+	fake.appWebhookURLMutex.Lock()
+	defer fake.appWebhookURLMutex.Unlock()
+	fake.AppWebhookURLStub = nil
+	fake.appWebhookURLReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeAPIClient) AppWebhookURLReturnsOnCall(i int, result1 string) {
+	fake.appWebhookURLMutex.Lock()
+	defer fake.appWebhookURLMutex.Unlock()
+	fake.AppWebhookURLStub = nil
+	if fake.appWebhookURLReturnsOnCall == nil {
+		fake.appWebhookURLReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.appWebhookURLReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeAPIClient) Apps(arg1 string) (models.AppList, error) {
+	fake.appsMutex.Lock()
+	ret, specificReturn := fake.appsReturnsOnCall[len(fake.appsArgsForCall)]
+	fake.appsArgsForCall = append(fake.appsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.AppsStub
+	fakeReturns := fake.appsReturns
+	fake.recordInvocation("Apps", []interface{}{arg1})
+	fake.appsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AppsCallCount() int {
+	fake.appsMutex.RLock()
+	defer fake.appsMutex.RUnlock()
+	return len(fake.appsArgsForCall)
+}
+
+func (fake *FakeAPIClient) AppsCalls(stub func(string) (models.AppList, error)) {
+	fake.appsMutex.Lock()
+	defer fake.appsMutex.Unlock()
+	fake.AppsStub = stub
+}
+
+func (fake *FakeAPIClient) AppsArgsForCall(i int) string {
+	fake.appsMutex.RLock()
+	defer fake.appsMutex.RUnlock()
+	argsForCall := fake.appsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) AppsReturns(result1 models.AppList, result2 error) {
+	fake.appsMutex.Lock()
+	defer fake.appsMutex.Unlock()
+	fake.AppsStub = nil
+	fake.appsReturns = struct {
+		result1 models.AppList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AppsReturnsOnCall(i int, result1 models.AppList, result2 error) {
+	fake.appsMutex.Lock()
+	defer fake.appsMutex.Unlock()
+	fake.AppsStub = nil
+	if fake.appsReturnsOnCall == nil {
+		fake.appsReturnsOnCall = make(map[int]struct {
+			result1 models.AppList
+			result2 error
+		})
+	}
+	fake.appsReturnsOnCall[i] = struct {
+		result1 models.AppList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) AuthToken() (string, error) {
+	fake.authTokenMutex.Lock()
+	ret, specificReturn := fake.authTokenReturnsOnCall[len(fake.authTokenArgsForCall)]
+	fake.authTokenArgsForCall = append(fake.authTokenArgsForCall, struct {
+	}{})
+	stub := fake.AuthTokenStub
+	fakeReturns := fake.authTokenReturns
+	fake.recordInvocation("AuthToken", []interface{}{})
+	fake.authTokenMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) AuthTokenCallCount() int {
+	fake.authTokenMutex.RLock()
+	defer fake.authTokenMutex.RUnlock()
+	return len(fake.authTokenArgsForCall)
 }
 
 func (fake *FakeAPIClient) AuthTokenCalls(stub func() (string, error)) {
@@ -3248,17 +4557,19 @@ func (fake *FakeAPIClient) EnvUnsetReturnsOnCall(i int, result1 models.Response,
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) Info() (models.InfoResponse, error) {
-	fake.infoMutex.Lock()
-	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
-	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
-	}{})
-	stub := fake.InfoStub
-	fakeReturns := fake.infoReturns
-	fake.recordInvocation("Info", []interface{}{})
-	fake.infoMutex.Unlock()
+func (fake *FakeAPIClient) GitConfigCreate(arg1 models.GitConfigCreateRequest, arg2 string) (models.Response, error) {
+	fake.gitConfigCreateMutex.Lock()
+	ret, specificReturn := fake.gitConfigCreateReturnsOnCall[len(fake.gitConfigCreateArgsForCall)]
+	fake.gitConfigCreateArgsForCall = append(fake.gitConfigCreateArgsForCall, struct {
+		arg1 models.GitConfigCreateRequest
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GitConfigCreateStub
+	fakeReturns := fake.gitConfigCreateReturns
+	fake.recordInvocation("GitConfigCreate", []interface{}{arg1, arg2})
+	fake.gitConfigCreateMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3266,56 +4577,64 @@ func (fake *FakeAPIClient) Info() (models.InfoResponse, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) InfoCallCount() int {
-	fake.infoMutex.RLock()
-	defer fake.infoMutex.RUnlock()
-	return len(fake.infoArgsForCall)
+func (fake *FakeAPIClient) GitConfigCreateCallCount() int {
+	fake.gitConfigCreateMutex.RLock()
+	defer fake.gitConfigCreateMutex.RUnlock()
+	return len(fake.gitConfigCreateArgsForCall)
 }
 
-func (fake *FakeAPIClient) InfoCalls(stub func() (models.InfoResponse, error)) {
-	fake.infoMutex.Lock()
-	defer fake.infoMutex.Unlock()
-	fake.InfoStub = stub
+func (fake *FakeAPIClient) GitConfigCreateCalls(stub func(models.GitConfigCreateRequest, string) (models.Response, error)) {
+	fake.gitConfigCreateMutex.Lock()
+	defer fake.gitConfigCreateMutex.Unlock()
+	fake.GitConfigCreateStub = stub
 }
 
-func (fake *FakeAPIClient) InfoReturns(result1 models.InfoResponse, result2 error) {
-	fake.infoMutex.Lock()
-	defer fake.infoMutex.Unlock()
-	fake.InfoStub = nil
-	fake.infoReturns = struct {
-		result1 models.InfoResponse
+func (fake *FakeAPIClient) GitConfigCreateArgsForCall(i int) (models.GitConfigCreateRequest, string) {
+	fake.gitConfigCreateMutex.RLock()
+	defer fake.gitConfigCreateMutex.RUnlock()
+	argsForCall := fake.gitConfigCreateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) GitConfigCreateReturns(result1 models.Response, result2 error) {
+	fake.gitConfigCreateMutex.Lock()
+	defer fake.gitConfigCreateMutex.Unlock()
+	fake.GitConfigCreateStub = nil
+	fake.gitConfigCreateReturns = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) InfoReturnsOnCall(i int, result1 models.InfoResponse, result2 error) {
-	fake.infoMutex.Lock()
-	defer fake.infoMutex.Unlock()
-	fake.InfoStub = nil
-	if fake.infoReturnsOnCall == nil {
-		fake.infoReturnsOnCall = make(map[int]struct {
-			result1 models.InfoResponse
+func (fake *FakeAPIClient) GitConfigCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.gitConfigCreateMutex.Lock()
+	defer fake.gitConfigCreateMutex.Unlock()
+	fake.GitConfigCreateStub = nil
+	if fake.gitConfigCreateReturnsOnCall == nil {
+		fake.gitConfigCreateReturnsOnCall = make(map[int]struct {
+			result1 models.Response
 			result2 error
 		})
 	}
-	fake.infoReturnsOnCall[i] = struct {
-		result1 models.InfoResponse
+	fake.gitConfigCreateReturnsOnCall[i] = struct {
+		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceCreate(arg1 models.NamespaceCreateRequest) (models.Response, error) {
-	fake.namespaceCreateMutex.Lock()
-	ret, specificReturn := fake.namespaceCreateReturnsOnCall[len(fake.namespaceCreateArgsForCall)]
-	fake.namespaceCreateArgsForCall = append(fake.namespaceCreateArgsForCall, struct {
-		arg1 models.NamespaceCreateRequest
-	}{arg1})
-	stub := fake.NamespaceCreateStub
-	fakeReturns := fake.namespaceCreateReturns
-	fake.recordInvocation("NamespaceCreate", []interface{}{arg1})
-	fake.namespaceCreateMutex.Unlock()
+func (fake *FakeAPIClient) GitConfigDelete(arg1 string, arg2 string) (models.Response, error) {
+	fake.gitConfigDeleteMutex.Lock()
+	ret, specificReturn := fake.gitConfigDeleteReturnsOnCall[len(fake.gitConfigDeleteArgsForCall)]
+	fake.gitConfigDeleteArgsForCall = append(fake.gitConfigDeleteArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GitConfigDeleteStub
+	fakeReturns := fake.gitConfigDeleteReturns
+	fake.recordInvocation("GitConfigDelete", []interface{}{arg1, arg2})
+	fake.gitConfigDeleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -3323,59 +4642,243 @@ func (fake *FakeAPIClient) NamespaceCreate(arg1 models.NamespaceCreateRequest) (
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAPIClient) NamespaceCreateCallCount() int {
-	fake.namespaceCreateMutex.RLock()
-	defer fake.namespaceCreateMutex.RUnlock()
-	return len(fake.namespaceCreateArgsForCall)
+func (fake *FakeAPIClient) GitConfigDeleteCallCount() int {
+	fake.gitConfigDeleteMutex.RLock()
+	defer fake.gitConfigDeleteMutex.RUnlock()
+	return len(fake.gitConfigDeleteArgsForCall)
 }
 
-func (fake *FakeAPIClient) NamespaceCreateCalls(stub func(models.NamespaceCreateRequest) (models.Response, error)) {
-	fake.namespaceCreateMutex.Lock()
-	defer fake.namespaceCreateMutex.Unlock()
-	fake.NamespaceCreateStub = stub
+func (fake *FakeAPIClient) GitConfigDeleteCalls(stub func(string, string) (models.Response, error)) {
+	fake.gitConfigDeleteMutex.Lock()
+	defer fake.gitConfigDeleteMutex.Unlock()
+	fake.GitConfigDeleteStub = stub
 }
 
-func (fake *FakeAPIClient) NamespaceCreateArgsForCall(i int) models.NamespaceCreateRequest {
-	fake.namespaceCreateMutex.RLock()
-	defer fake.namespaceCreateMutex.RUnlock()
-	argsForCall := fake.namespaceCreateArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeAPIClient) GitConfigDeleteArgsForCall(i int) (string, string) {
+	fake.gitConfigDeleteMutex.RLock()
+	defer fake.gitConfigDeleteMutex.RUnlock()
+	argsForCall := fake.gitConfigDeleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAPIClient) NamespaceCreateReturns(result1 models.Response, result2 error) {
-	fake.namespaceCreateMutex.Lock()
-	defer fake.namespaceCreateMutex.Unlock()
-	fake.NamespaceCreateStub = nil
-	fake.namespaceCreateReturns = struct {
+func (fake *FakeAPIClient) GitConfigDeleteReturns(result1 models.Response, result2 error) {
+	fake.gitConfigDeleteMutex.Lock()
+	defer fake.gitConfigDeleteMutex.Unlock()
+	fake.GitConfigDeleteStub = nil
+	fake.gitConfigDeleteReturns = struct {
 		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
-	fake.namespaceCreateMutex.Lock()
-	defer fake.namespaceCreateMutex.Unlock()
-	fake.NamespaceCreateStub = nil
-	if fake.namespaceCreateReturnsOnCall == nil {
-		fake.namespaceCreateReturnsOnCall = make(map[int]struct {
+func (fake *FakeAPIClient) GitConfigDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.gitConfigDeleteMutex.Lock()
+	defer fake.gitConfigDeleteMutex.Unlock()
+	fake.GitConfigDeleteStub = nil
+	if fake.gitConfigDeleteReturnsOnCall == nil {
+		fake.gitConfigDeleteReturnsOnCall = make(map[int]struct {
 			result1 models.Response
 			result2 error
 		})
 	}
-	fake.namespaceCreateReturnsOnCall[i] = struct {
+	fake.gitConfigDeleteReturnsOnCall[i] = struct {
 		result1 models.Response
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeAPIClient) NamespaceDelete(arg1 string) (models.Response, error) {
-	fake.namespaceDeleteMutex.Lock()
-	ret, specificReturn := fake.namespaceDeleteReturnsOnCall[len(fake.namespaceDeleteArgsForCall)]
-	fake.namespaceDeleteArgsForCall = append(fake.namespaceDeleteArgsForCall, struct {
+func (fake *FakeAPIClient) GitConfigs(arg1 string) (models.GitConfigList, error) {
+	fake.gitConfigsMutex.Lock()
+	ret, specificReturn := fake.gitConfigsReturnsOnCall[len(fake.gitConfigsArgsForCall)]
+	fake.gitConfigsArgsForCall = append(fake.gitConfigsArgsForCall, struct {
 		arg1 string
 	}{arg1})
-	stub := fake.NamespaceDeleteStub
-	fakeReturns := fake.namespaceDeleteReturns
+	stub := fake.GitConfigsStub
+	fakeReturns := fake.gitConfigsReturns
+	fake.recordInvocation("GitConfigs", []interface{}{arg1})
+	fake.gitConfigsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) GitConfigsCallCount() int {
+	fake.gitConfigsMutex.RLock()
+	defer fake.gitConfigsMutex.RUnlock()
+	return len(fake.gitConfigsArgsForCall)
+}
+
+func (fake *FakeAPIClient) GitConfigsCalls(stub func(string) (models.GitConfigList, error)) {
+	fake.gitConfigsMutex.Lock()
+	defer fake.gitConfigsMutex.Unlock()
+	fake.GitConfigsStub = stub
+}
+
+func (fake *FakeAPIClient) GitConfigsArgsForCall(i int) string {
+	fake.gitConfigsMutex.RLock()
+	defer fake.gitConfigsMutex.RUnlock()
+	argsForCall := fake.gitConfigsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) GitConfigsReturns(result1 models.GitConfigList, result2 error) {
+	fake.gitConfigsMutex.Lock()
+	defer fake.gitConfigsMutex.Unlock()
+	fake.GitConfigsStub = nil
+	fake.gitConfigsReturns = struct {
+		result1 models.GitConfigList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) GitConfigsReturnsOnCall(i int, result1 models.GitConfigList, result2 error) {
+	fake.gitConfigsMutex.Lock()
+	defer fake.gitConfigsMutex.Unlock()
+	fake.GitConfigsStub = nil
+	if fake.gitConfigsReturnsOnCall == nil {
+		fake.gitConfigsReturnsOnCall = make(map[int]struct {
+			result1 models.GitConfigList
+			result2 error
+		})
+	}
+	fake.gitConfigsReturnsOnCall[i] = struct {
+		result1 models.GitConfigList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Info() (models.InfoResponse, error) {
+	fake.infoMutex.Lock()
+	ret, specificReturn := fake.infoReturnsOnCall[len(fake.infoArgsForCall)]
+	fake.infoArgsForCall = append(fake.infoArgsForCall, struct {
+	}{})
+	stub := fake.InfoStub
+	fakeReturns := fake.infoReturns
+	fake.recordInvocation("Info", []interface{}{})
+	fake.infoMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) InfoCallCount() int {
+	fake.infoMutex.RLock()
+	defer fake.infoMutex.RUnlock()
+	return len(fake.infoArgsForCall)
+}
+
+func (fake *FakeAPIClient) InfoCalls(stub func() (models.InfoResponse, error)) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = stub
+}
+
+func (fake *FakeAPIClient) InfoReturns(result1 models.InfoResponse, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	fake.infoReturns = struct {
+		result1 models.InfoResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) InfoReturnsOnCall(i int, result1 models.InfoResponse, result2 error) {
+	fake.infoMutex.Lock()
+	defer fake.infoMutex.Unlock()
+	fake.InfoStub = nil
+	if fake.infoReturnsOnCall == nil {
+		fake.infoReturnsOnCall = make(map[int]struct {
+			result1 models.InfoResponse
+			result2 error
+		})
+	}
+	fake.infoReturnsOnCall[i] = struct {
+		result1 models.InfoResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceCreate(arg1 models.NamespaceCreateRequest) (models.Response, error) {
+	fake.namespaceCreateMutex.Lock()
+	ret, specificReturn := fake.namespaceCreateReturnsOnCall[len(fake.namespaceCreateArgsForCall)]
+	fake.namespaceCreateArgsForCall = append(fake.namespaceCreateArgsForCall, struct {
+		arg1 models.NamespaceCreateRequest
+	}{arg1})
+	stub := fake.NamespaceCreateStub
+	fakeReturns := fake.namespaceCreateReturns
+	fake.recordInvocation("NamespaceCreate", []interface{}{arg1})
+	fake.namespaceCreateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) NamespaceCreateCallCount() int {
+	fake.namespaceCreateMutex.RLock()
+	defer fake.namespaceCreateMutex.RUnlock()
+	return len(fake.namespaceCreateArgsForCall)
+}
+
+func (fake *FakeAPIClient) NamespaceCreateCalls(stub func(models.NamespaceCreateRequest) (models.Response, error)) {
+	fake.namespaceCreateMutex.Lock()
+	defer fake.namespaceCreateMutex.Unlock()
+	fake.NamespaceCreateStub = stub
+}
+
+func (fake *FakeAPIClient) NamespaceCreateArgsForCall(i int) models.NamespaceCreateRequest {
+	fake.namespaceCreateMutex.RLock()
+	defer fake.namespaceCreateMutex.RUnlock()
+	argsForCall := fake.namespaceCreateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) NamespaceCreateReturns(result1 models.Response, result2 error) {
+	fake.namespaceCreateMutex.Lock()
+	defer fake.namespaceCreateMutex.Unlock()
+	fake.NamespaceCreateStub = nil
+	fake.namespaceCreateReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.namespaceCreateMutex.Lock()
+	defer fake.namespaceCreateMutex.Unlock()
+	fake.NamespaceCreateStub = nil
+	if fake.namespaceCreateReturnsOnCall == nil {
+		fake.namespaceCreateReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.namespaceCreateReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) NamespaceDelete(arg1 string) (models.Response, error) {
+	fake.namespaceDeleteMutex.Lock()
+	ret, specificReturn := fake.namespaceDeleteReturnsOnCall[len(fake.namespaceDeleteArgsForCall)]
+	fake.namespaceDeleteArgsForCall = append(fake.namespaceDeleteArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.NamespaceDeleteStub
+	fakeReturns := fake.namespaceDeleteReturns
 	fake.recordInvocation("NamespaceDelete", []interface{}{arg1})
 	fake.namespaceDeleteMutex.Unlock()
 	if stub != nil {
@@ -4254,6 +5757,71 @@ func (fake *FakeAPIClient) ServiceUnbindReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAPIClient) StagingCancel(arg1 string, arg2 string) (models.Response, error) {
+	fake.stagingCancelMutex.Lock()
+	ret, specificReturn := fake.stagingCancelReturnsOnCall[len(fake.stagingCancelArgsForCall)]
+	fake.stagingCancelArgsForCall = append(fake.stagingCancelArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StagingCancelStub
+	fakeReturns := fake.stagingCancelReturns
+	fake.recordInvocation("StagingCancel", []interface{}{arg1, arg2})
+	fake.stagingCancelMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) StagingCancelCallCount() int {
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
+	return len(fake.stagingCancelArgsForCall)
+}
+
+func (fake *FakeAPIClient) StagingCancelCalls(stub func(string, string) (models.Response, error)) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = stub
+}
+
+func (fake *FakeAPIClient) StagingCancelArgsForCall(i int) (string, string) {
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
+	argsForCall := fake.stagingCancelArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) StagingCancelReturns(result1 models.Response, result2 error) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = nil
+	fake.stagingCancelReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) StagingCancelReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.stagingCancelMutex.Lock()
+	defer fake.stagingCancelMutex.Unlock()
+	fake.StagingCancelStub = nil
+	if fake.stagingCancelReturnsOnCall == nil {
+		fake.stagingCancelReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.stagingCancelReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) StagingComplete(arg1 string, arg2 string) (models.Response, error) {
 	fake.stagingCompleteMutex.Lock()
 	ret, specificReturn := fake.stagingCompleteReturnsOnCall[len(fake.stagingCompleteArgsForCall)]
@@ -4319,6 +5887,698 @@ func (fake *FakeAPIClient) StagingCompleteReturnsOnCall(i int, result1 models.Re
 	}{result1, result2}
 }
 
+func (fake *FakeAPIClient) StagingStatus(arg1 string, arg2 string) (models.StagingCompleteResponse, error) {
+	fake.stagingStatusMutex.Lock()
+	ret, specificReturn := fake.stagingStatusReturnsOnCall[len(fake.stagingStatusArgsForCall)]
+	fake.stagingStatusArgsForCall = append(fake.stagingStatusArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.StagingStatusStub
+	fakeReturns := fake.stagingStatusReturns
+	fake.recordInvocation("StagingStatus", []interface{}{arg1, arg2})
+	fake.stagingStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) StagingStatusCallCount() int {
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
+	return len(fake.stagingStatusArgsForCall)
+}
+
+func (fake *FakeAPIClient) StagingStatusCalls(stub func(string, string) (models.StagingCompleteResponse, error)) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = stub
+}
+
+func (fake *FakeAPIClient) StagingStatusArgsForCall(i int) (string, string) {
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
+	argsForCall := fake.stagingStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) StagingStatusReturns(result1 models.StagingCompleteResponse, result2 error) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = nil
+	fake.stagingStatusReturns = struct {
+		result1 models.StagingCompleteResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) StagingStatusReturnsOnCall(i int, result1 models.StagingCompleteResponse, result2 error) {
+	fake.stagingStatusMutex.Lock()
+	defer fake.stagingStatusMutex.Unlock()
+	fake.StagingStatusStub = nil
+	if fake.stagingStatusReturnsOnCall == nil {
+		fake.stagingStatusReturnsOnCall = make(map[int]struct {
+			result1 models.StagingCompleteResponse
+			result2 error
+		})
+	}
+	fake.stagingStatusReturnsOnCall[i] = struct {
+		result1 models.StagingCompleteResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) TokenCreate(arg1 models.TokenCreateRequest) (models.TokenCreateResponse, error) {
+	fake.tokenCreateMutex.Lock()
+	ret, specificReturn := fake.tokenCreateReturnsOnCall[len(fake.tokenCreateArgsForCall)]
+	fake.tokenCreateArgsForCall = append(fake.tokenCreateArgsForCall, struct {
+		arg1 models.TokenCreateRequest
+	}{arg1})
+	stub := fake.TokenCreateStub
+	fakeReturns := fake.tokenCreateReturns
+	fake.recordInvocation("TokenCreate", []interface{}{arg1})
+	fake.tokenCreateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) TokenCreateCallCount() int {
+	fake.tokenCreateMutex.RLock()
+	defer fake.tokenCreateMutex.RUnlock()
+	return len(fake.tokenCreateArgsForCall)
+}
+
+func (fake *FakeAPIClient) TokenCreateCalls(stub func(models.TokenCreateRequest) (models.TokenCreateResponse, error)) {
+	fake.tokenCreateMutex.Lock()
+	defer fake.tokenCreateMutex.Unlock()
+	fake.TokenCreateStub = stub
+}
+
+func (fake *FakeAPIClient) TokenCreateArgsForCall(i int) models.TokenCreateRequest {
+	fake.tokenCreateMutex.RLock()
+	defer fake.tokenCreateMutex.RUnlock()
+	argsForCall := fake.tokenCreateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) TokenCreateReturns(result1 models.TokenCreateResponse, result2 error) {
+	fake.tokenCreateMutex.Lock()
+	defer fake.tokenCreateMutex.Unlock()
+	fake.TokenCreateStub = nil
+	fake.tokenCreateReturns = struct {
+		result1 models.TokenCreateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) TokenCreateReturnsOnCall(i int, result1 models.TokenCreateResponse, result2 error) {
+	fake.tokenCreateMutex.Lock()
+	defer fake.tokenCreateMutex.Unlock()
+	fake.TokenCreateStub = nil
+	if fake.tokenCreateReturnsOnCall == nil {
+		fake.tokenCreateReturnsOnCall = make(map[int]struct {
+			result1 models.TokenCreateResponse
+			result2 error
+		})
+	}
+	fake.tokenCreateReturnsOnCall[i] = struct {
+		result1 models.TokenCreateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) TokenDelete(arg1 string) (models.Response, error) {
+	fake.tokenDeleteMutex.Lock()
+	ret, specificReturn := fake.tokenDeleteReturnsOnCall[len(fake.tokenDeleteArgsForCall)]
+	fake.tokenDeleteArgsForCall = append(fake.tokenDeleteArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.TokenDeleteStub
+	fakeReturns := fake.tokenDeleteReturns
+	fake.recordInvocation("TokenDelete", []interface{}{arg1})
+	fake.tokenDeleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) TokenDeleteCallCount() int {
+	fake.tokenDeleteMutex.RLock()
+	defer fake.tokenDeleteMutex.RUnlock()
+	return len(fake.tokenDeleteArgsForCall)
+}
+
+func (fake *FakeAPIClient) TokenDeleteCalls(stub func(string) (models.Response, error)) {
+	fake.tokenDeleteMutex.Lock()
+	defer fake.tokenDeleteMutex.Unlock()
+	fake.TokenDeleteStub = stub
+}
+
+func (fake *FakeAPIClient) TokenDeleteArgsForCall(i int) string {
+	fake.tokenDeleteMutex.RLock()
+	defer fake.tokenDeleteMutex.RUnlock()
+	argsForCall := fake.tokenDeleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) TokenDeleteReturns(result1 models.Response, result2 error) {
+	fake.tokenDeleteMutex.Lock()
+	defer fake.tokenDeleteMutex.Unlock()
+	fake.TokenDeleteStub = nil
+	fake.tokenDeleteReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) TokenDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.tokenDeleteMutex.Lock()
+	defer fake.tokenDeleteMutex.Unlock()
+	fake.TokenDeleteStub = nil
+	if fake.tokenDeleteReturnsOnCall == nil {
+		fake.tokenDeleteReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.tokenDeleteReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Tokens() (models.PersonalAccessTokenList, error) {
+	fake.tokensMutex.Lock()
+	ret, specificReturn := fake.tokensReturnsOnCall[len(fake.tokensArgsForCall)]
+	fake.tokensArgsForCall = append(fake.tokensArgsForCall, struct {
+	}{})
+	stub := fake.TokensStub
+	fakeReturns := fake.tokensReturns
+	fake.recordInvocation("Tokens", []interface{}{})
+	fake.tokensMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) TokensCallCount() int {
+	fake.tokensMutex.RLock()
+	defer fake.tokensMutex.RUnlock()
+	return len(fake.tokensArgsForCall)
+}
+
+func (fake *FakeAPIClient) TokensCalls(stub func() (models.PersonalAccessTokenList, error)) {
+	fake.tokensMutex.Lock()
+	defer fake.tokensMutex.Unlock()
+	fake.TokensStub = stub
+}
+
+func (fake *FakeAPIClient) TokensReturns(result1 models.PersonalAccessTokenList, result2 error) {
+	fake.tokensMutex.Lock()
+	defer fake.tokensMutex.Unlock()
+	fake.TokensStub = nil
+	fake.tokensReturns = struct {
+		result1 models.PersonalAccessTokenList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) TokensReturnsOnCall(i int, result1 models.PersonalAccessTokenList, result2 error) {
+	fake.tokensMutex.Lock()
+	defer fake.tokensMutex.Unlock()
+	fake.TokensStub = nil
+	if fake.tokensReturnsOnCall == nil {
+		fake.tokensReturnsOnCall = make(map[int]struct {
+			result1 models.PersonalAccessTokenList
+			result2 error
+		})
+	}
+	fake.tokensReturnsOnCall[i] = struct {
+		result1 models.PersonalAccessTokenList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserCreate(arg1 models.UserCreateRequest) (models.Response, error) {
+	fake.userCreateMutex.Lock()
+	ret, specificReturn := fake.userCreateReturnsOnCall[len(fake.userCreateArgsForCall)]
+	fake.userCreateArgsForCall = append(fake.userCreateArgsForCall, struct {
+		arg1 models.UserCreateRequest
+	}{arg1})
+	stub := fake.UserCreateStub
+	fakeReturns := fake.userCreateReturns
+	fake.recordInvocation("UserCreate", []interface{}{arg1})
+	fake.userCreateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserCreateCallCount() int {
+	fake.userCreateMutex.RLock()
+	defer fake.userCreateMutex.RUnlock()
+	return len(fake.userCreateArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserCreateCalls(stub func(models.UserCreateRequest) (models.Response, error)) {
+	fake.userCreateMutex.Lock()
+	defer fake.userCreateMutex.Unlock()
+	fake.UserCreateStub = stub
+}
+
+func (fake *FakeAPIClient) UserCreateArgsForCall(i int) models.UserCreateRequest {
+	fake.userCreateMutex.RLock()
+	defer fake.userCreateMutex.RUnlock()
+	argsForCall := fake.userCreateArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) UserCreateReturns(result1 models.Response, result2 error) {
+	fake.userCreateMutex.Lock()
+	defer fake.userCreateMutex.Unlock()
+	fake.UserCreateStub = nil
+	fake.userCreateReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserCreateReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.userCreateMutex.Lock()
+	defer fake.userCreateMutex.Unlock()
+	fake.UserCreateStub = nil
+	if fake.userCreateReturnsOnCall == nil {
+		fake.userCreateReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.userCreateReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserDelete(arg1 string) (models.Response, error) {
+	fake.userDeleteMutex.Lock()
+	ret, specificReturn := fake.userDeleteReturnsOnCall[len(fake.userDeleteArgsForCall)]
+	fake.userDeleteArgsForCall = append(fake.userDeleteArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UserDeleteStub
+	fakeReturns := fake.userDeleteReturns
+	fake.recordInvocation("UserDelete", []interface{}{arg1})
+	fake.userDeleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserDeleteCallCount() int {
+	fake.userDeleteMutex.RLock()
+	defer fake.userDeleteMutex.RUnlock()
+	return len(fake.userDeleteArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserDeleteCalls(stub func(string) (models.Response, error)) {
+	fake.userDeleteMutex.Lock()
+	defer fake.userDeleteMutex.Unlock()
+	fake.UserDeleteStub = stub
+}
+
+func (fake *FakeAPIClient) UserDeleteArgsForCall(i int) string {
+	fake.userDeleteMutex.RLock()
+	defer fake.userDeleteMutex.RUnlock()
+	argsForCall := fake.userDeleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) UserDeleteReturns(result1 models.Response, result2 error) {
+	fake.userDeleteMutex.Lock()
+	defer fake.userDeleteMutex.Unlock()
+	fake.UserDeleteStub = nil
+	fake.userDeleteReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserDeleteReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.userDeleteMutex.Lock()
+	defer fake.userDeleteMutex.Unlock()
+	fake.UserDeleteStub = nil
+	if fake.userDeleteReturnsOnCall == nil {
+		fake.userDeleteReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.userDeleteReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrant(arg1 string, arg2 models.UserNamespaceGrantRequest) (models.Response, error) {
+	fake.userNamespaceGrantMutex.Lock()
+	ret, specificReturn := fake.userNamespaceGrantReturnsOnCall[len(fake.userNamespaceGrantArgsForCall)]
+	fake.userNamespaceGrantArgsForCall = append(fake.userNamespaceGrantArgsForCall, struct {
+		arg1 string
+		arg2 models.UserNamespaceGrantRequest
+	}{arg1, arg2})
+	stub := fake.UserNamespaceGrantStub
+	fakeReturns := fake.userNamespaceGrantReturns
+	fake.recordInvocation("UserNamespaceGrant", []interface{}{arg1, arg2})
+	fake.userNamespaceGrantMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrantCallCount() int {
+	fake.userNamespaceGrantMutex.RLock()
+	defer fake.userNamespaceGrantMutex.RUnlock()
+	return len(fake.userNamespaceGrantArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrantCalls(stub func(string, models.UserNamespaceGrantRequest) (models.Response, error)) {
+	fake.userNamespaceGrantMutex.Lock()
+	defer fake.userNamespaceGrantMutex.Unlock()
+	fake.UserNamespaceGrantStub = stub
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrantArgsForCall(i int) (string, models.UserNamespaceGrantRequest) {
+	fake.userNamespaceGrantMutex.RLock()
+	defer fake.userNamespaceGrantMutex.RUnlock()
+	argsForCall := fake.userNamespaceGrantArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrantReturns(result1 models.Response, result2 error) {
+	fake.userNamespaceGrantMutex.Lock()
+	defer fake.userNamespaceGrantMutex.Unlock()
+	fake.UserNamespaceGrantStub = nil
+	fake.userNamespaceGrantReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserNamespaceGrantReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.userNamespaceGrantMutex.Lock()
+	defer fake.userNamespaceGrantMutex.Unlock()
+	fake.UserNamespaceGrantStub = nil
+	if fake.userNamespaceGrantReturnsOnCall == nil {
+		fake.userNamespaceGrantReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.userNamespaceGrantReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevoke(arg1 string, arg2 string) (models.Response, error) {
+	fake.userNamespaceRevokeMutex.Lock()
+	ret, specificReturn := fake.userNamespaceRevokeReturnsOnCall[len(fake.userNamespaceRevokeArgsForCall)]
+	fake.userNamespaceRevokeArgsForCall = append(fake.userNamespaceRevokeArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.UserNamespaceRevokeStub
+	fakeReturns := fake.userNamespaceRevokeReturns
+	fake.recordInvocation("UserNamespaceRevoke", []interface{}{arg1, arg2})
+	fake.userNamespaceRevokeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevokeCallCount() int {
+	fake.userNamespaceRevokeMutex.RLock()
+	defer fake.userNamespaceRevokeMutex.RUnlock()
+	return len(fake.userNamespaceRevokeArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevokeCalls(stub func(string, string) (models.Response, error)) {
+	fake.userNamespaceRevokeMutex.Lock()
+	defer fake.userNamespaceRevokeMutex.Unlock()
+	fake.UserNamespaceRevokeStub = stub
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevokeArgsForCall(i int) (string, string) {
+	fake.userNamespaceRevokeMutex.RLock()
+	defer fake.userNamespaceRevokeMutex.RUnlock()
+	argsForCall := fake.userNamespaceRevokeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevokeReturns(result1 models.Response, result2 error) {
+	fake.userNamespaceRevokeMutex.Lock()
+	defer fake.userNamespaceRevokeMutex.Unlock()
+	fake.UserNamespaceRevokeStub = nil
+	fake.userNamespaceRevokeReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserNamespaceRevokeReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.userNamespaceRevokeMutex.Lock()
+	defer fake.userNamespaceRevokeMutex.Unlock()
+	fake.UserNamespaceRevokeStub = nil
+	if fake.userNamespaceRevokeReturnsOnCall == nil {
+		fake.userNamespaceRevokeReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.userNamespaceRevokeReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserPassword(arg1 string, arg2 models.UserPasswordRequest) (models.Response, error) {
+	fake.userPasswordMutex.Lock()
+	ret, specificReturn := fake.userPasswordReturnsOnCall[len(fake.userPasswordArgsForCall)]
+	fake.userPasswordArgsForCall = append(fake.userPasswordArgsForCall, struct {
+		arg1 string
+		arg2 models.UserPasswordRequest
+	}{arg1, arg2})
+	stub := fake.UserPasswordStub
+	fakeReturns := fake.userPasswordReturns
+	fake.recordInvocation("UserPassword", []interface{}{arg1, arg2})
+	fake.userPasswordMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserPasswordCallCount() int {
+	fake.userPasswordMutex.RLock()
+	defer fake.userPasswordMutex.RUnlock()
+	return len(fake.userPasswordArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserPasswordCalls(stub func(string, models.UserPasswordRequest) (models.Response, error)) {
+	fake.userPasswordMutex.Lock()
+	defer fake.userPasswordMutex.Unlock()
+	fake.UserPasswordStub = stub
+}
+
+func (fake *FakeAPIClient) UserPasswordArgsForCall(i int) (string, models.UserPasswordRequest) {
+	fake.userPasswordMutex.RLock()
+	defer fake.userPasswordMutex.RUnlock()
+	argsForCall := fake.userPasswordArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAPIClient) UserPasswordReturns(result1 models.Response, result2 error) {
+	fake.userPasswordMutex.Lock()
+	defer fake.userPasswordMutex.Unlock()
+	fake.UserPasswordStub = nil
+	fake.userPasswordReturns = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserPasswordReturnsOnCall(i int, result1 models.Response, result2 error) {
+	fake.userPasswordMutex.Lock()
+	defer fake.userPasswordMutex.Unlock()
+	fake.UserPasswordStub = nil
+	if fake.userPasswordReturnsOnCall == nil {
+		fake.userPasswordReturnsOnCall = make(map[int]struct {
+			result1 models.Response
+			result2 error
+		})
+	}
+	fake.userPasswordReturnsOnCall[i] = struct {
+		result1 models.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserShow(arg1 string) (models.User, error) {
+	fake.userShowMutex.Lock()
+	ret, specificReturn := fake.userShowReturnsOnCall[len(fake.userShowArgsForCall)]
+	fake.userShowArgsForCall = append(fake.userShowArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UserShowStub
+	fakeReturns := fake.userShowReturns
+	fake.recordInvocation("UserShow", []interface{}{arg1})
+	fake.userShowMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UserShowCallCount() int {
+	fake.userShowMutex.RLock()
+	defer fake.userShowMutex.RUnlock()
+	return len(fake.userShowArgsForCall)
+}
+
+func (fake *FakeAPIClient) UserShowCalls(stub func(string) (models.User, error)) {
+	fake.userShowMutex.Lock()
+	defer fake.userShowMutex.Unlock()
+	fake.UserShowStub = stub
+}
+
+func (fake *FakeAPIClient) UserShowArgsForCall(i int) string {
+	fake.userShowMutex.RLock()
+	defer fake.userShowMutex.RUnlock()
+	argsForCall := fake.userShowArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAPIClient) UserShowReturns(result1 models.User, result2 error) {
+	fake.userShowMutex.Lock()
+	defer fake.userShowMutex.Unlock()
+	fake.UserShowStub = nil
+	fake.userShowReturns = struct {
+		result1 models.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UserShowReturnsOnCall(i int, result1 models.User, result2 error) {
+	fake.userShowMutex.Lock()
+	defer fake.userShowMutex.Unlock()
+	fake.UserShowStub = nil
+	if fake.userShowReturnsOnCall == nil {
+		fake.userShowReturnsOnCall = make(map[int]struct {
+			result1 models.User
+			result2 error
+		})
+	}
+	fake.userShowReturnsOnCall[i] = struct {
+		result1 models.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) Users() (models.UserList, error) {
+	fake.usersMutex.Lock()
+	ret, specificReturn := fake.usersReturnsOnCall[len(fake.usersArgsForCall)]
+	fake.usersArgsForCall = append(fake.usersArgsForCall, struct {
+	}{})
+	stub := fake.UsersStub
+	fakeReturns := fake.usersReturns
+	fake.recordInvocation("Users", []interface{}{})
+	fake.usersMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAPIClient) UsersCallCount() int {
+	fake.usersMutex.RLock()
+	defer fake.usersMutex.RUnlock()
+	return len(fake.usersArgsForCall)
+}
+
+func (fake *FakeAPIClient) UsersCalls(stub func() (models.UserList, error)) {
+	fake.usersMutex.Lock()
+	defer fake.usersMutex.Unlock()
+	fake.UsersStub = stub
+}
+
+func (fake *FakeAPIClient) UsersReturns(result1 models.UserList, result2 error) {
+	fake.usersMutex.Lock()
+	defer fake.usersMutex.Unlock()
+	fake.UsersStub = nil
+	fake.usersReturns = struct {
+		result1 models.UserList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAPIClient) UsersReturnsOnCall(i int, result1 models.UserList, result2 error) {
+	fake.usersMutex.Lock()
+	defer fake.usersMutex.Unlock()
+	fake.UsersStub = nil
+	if fake.usersReturnsOnCall == nil {
+		fake.usersReturnsOnCall = make(map[int]struct {
+			result1 models.UserList
+			result2 error
+		})
+	}
+	fake.usersReturnsOnCall[i] = struct {
+		result1 models.UserList
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAPIClient) VersionWarningEnabled() bool {
 	fake.versionWarningEnabledMutex.Lock()
 	ret, specificReturn := fake.versionWarningEnabledReturnsOnCall[len(fake.versionWarningEnabledArgsForCall)]
@@ -4381,12 +6641,22 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.allConfigurationsMutex.RUnlock()
 	fake.allServicesMutex.RLock()
 	defer fake.allServicesMutex.RUnlock()
+	fake.appAbortMutex.RLock()
+	defer fake.appAbortMutex.RUnlock()
+	fake.appBuildsMutex.RLock()
+	defer fake.appBuildsMutex.RUnlock()
+	fake.appCacheMutex.RLock()
+	defer fake.appCacheMutex.RUnlock()
+	fake.appCacheClearMutex.RLock()
+	defer fake.appCacheClearMutex.RUnlock()
 	fake.appCreateMutex.RLock()
 	defer fake.appCreateMutex.RUnlock()
 	fake.appDeleteMutex.RLock()
 	defer fake.appDeleteMutex.RUnlock()
 	fake.appDeployMutex.RLock()
 	defer fake.appDeployMutex.RUnlock()
+	fake.appDeployDryRunMutex.RLock()
+	defer fake.appDeployDryRunMutex.RUnlock()
 	fake.appExecMutex.RLock()
 	defer fake.appExecMutex.RUnlock()
 	fake.appGetPartMutex.RLock()
@@ -4399,8 +6669,16 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.appMatchMutex.RUnlock()
 	fake.appPortForwardMutex.RLock()
 	defer fake.appPortForwardMutex.RUnlock()
+	fake.appPromoteMutex.RLock()
+	defer fake.appPromoteMutex.RUnlock()
+	fake.appReleaseDiffMutex.RLock()
+	defer fake.appReleaseDiffMutex.RUnlock()
+	fake.appReleasesMutex.RLock()
+	defer fake.appReleasesMutex.RUnlock()
 	fake.appRestartMutex.RLock()
 	defer fake.appRestartMutex.RUnlock()
+	fake.appRollbackMutex.RLock()
+	defer fake.appRollbackMutex.RUnlock()
 	fake.appRunningMutex.RLock()
 	defer fake.appRunningMutex.RUnlock()
 	fake.appShowMutex.RLock()
@@ -4411,8 +6689,18 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.appUpdateMutex.RUnlock()
 	fake.appUploadMutex.RLock()
 	defer fake.appUploadMutex.RUnlock()
+	fake.appUploadChunkedMutex.RLock()
+	defer fake.appUploadChunkedMutex.RUnlock()
 	fake.appValidateCVMutex.RLock()
 	defer fake.appValidateCVMutex.RUnlock()
+	fake.appWebhookMutex.RLock()
+	defer fake.appWebhookMutex.RUnlock()
+	fake.appWebhookDisableMutex.RLock()
+	defer fake.appWebhookDisableMutex.RUnlock()
+	fake.appWebhookEnableMutex.RLock()
+	defer fake.appWebhookEnableMutex.RUnlock()
+	fake.appWebhookURLMutex.RLock()
+	defer fake.appWebhookURLMutex.RUnlock()
 	fake.appsMutex.RLock()
 	defer fake.appsMutex.RUnlock()
 	fake.authTokenMutex.RLock()
@@ -4453,6 +6741,12 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.envShowMutex.RUnlock()
 	fake.envUnsetMutex.RLock()
 	defer fake.envUnsetMutex.RUnlock()
+	fake.gitConfigCreateMutex.RLock()
+	defer fake.gitConfigCreateMutex.RUnlock()
+	fake.gitConfigDeleteMutex.RLock()
+	defer fake.gitConfigDeleteMutex.RUnlock()
+	fake.gitConfigsMutex.RLock()
+	defer fake.gitConfigsMutex.RUnlock()
 	fake.infoMutex.RLock()
 	defer fake.infoMutex.RUnlock()
 	fake.namespaceCreateMutex.RLock()
@@ -4485,8 +6779,32 @@ func (fake *FakeAPIClient) Invocations() map[string][][]interface{} {
 	defer fake.serviceShowMutex.RUnlock()
 	fake.serviceUnbindMutex.RLock()
 	defer fake.serviceUnbindMutex.RUnlock()
+	fake.stagingCancelMutex.RLock()
+	defer fake.stagingCancelMutex.RUnlock()
 	fake.stagingCompleteMutex.RLock()
 	defer fake.stagingCompleteMutex.RUnlock()
+	fake.stagingStatusMutex.RLock()
+	defer fake.stagingStatusMutex.RUnlock()
+	fake.tokenCreateMutex.RLock()
+	defer fake.tokenCreateMutex.RUnlock()
+	fake.tokenDeleteMutex.RLock()
+	defer fake.tokenDeleteMutex.RUnlock()
+	fake.tokensMutex.RLock()
+	defer fake.tokensMutex.RUnlock()
+	fake.userCreateMutex.RLock()
+	defer fake.userCreateMutex.RUnlock()
+	fake.userDeleteMutex.RLock()
+	defer fake.userDeleteMutex.RUnlock()
+	fake.userNamespaceGrantMutex.RLock()
+	defer fake.userNamespaceGrantMutex.RUnlock()
+	fake.userNamespaceRevokeMutex.RLock()
+	defer fake.userNamespaceRevokeMutex.RUnlock()
+	fake.userPasswordMutex.RLock()
+	defer fake.userPasswordMutex.RUnlock()
+	fake.userShowMutex.RLock()
+	defer fake.userShowMutex.RUnlock()
+	fake.usersMutex.RLock()
+	defer fake.usersMutex.RUnlock()
 	fake.versionWarningEnabledMutex.RLock()
 	defer fake.versionWarningEnabledMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
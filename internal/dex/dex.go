@@ -144,3 +144,21 @@ func (pg *ProviderGroups) GetRolesFromGroups(groupIDs ...string) []string {
 
 	return roles
 }
+
+// GetNamespacesFromGroups returns the namespaces granted by the provided groups, without duplicates
+func (pg *ProviderGroups) GetNamespacesFromGroups(groupIDs ...string) []string {
+	namespaces := []string{}
+
+	for _, g := range pg.Groups {
+		if !slices.Contains(groupIDs, g.ID) {
+			continue
+		}
+		for _, ns := range g.Namespaces {
+			if ns != "" && !slices.Contains(namespaces, ns) {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+
+	return namespaces
+}
@@ -0,0 +1,389 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitconfig manages the credentials used to clone private git repositories.
+// A git configuration is a Secret in the namespace of the applications using it, labeled
+// as such. It applies to all repositories on the host of the configuration's url, at or
+// below its path.
+package gitconfig
+
+import (
+	"context"
+	neturl "net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// GitConfigLabelKey marks the secrets holding git configurations.
+const GitConfigLabelKey = "epinio.io/gitconfig"
+
+// The keys of the secret data.
+const (
+	urlKey        = "url"
+	usernameKey   = "username"
+	passwordKey   = "password"
+	privateKeyKey = "privatekey"
+	knownHostsKey = "knownhosts"
+)
+
+// defaultUsername is used when a git configuration specifies no username. It is the user
+// expected by the ssh servers of the common git hosting services, and accepted by them
+// together with an access token over https.
+const defaultUsername = "git"
+
+var (
+	// ErrNotFound is returned when the named git configuration does not exist.
+	ErrNotFound = errors.New("git configuration not found")
+	// ErrExists is returned when the name of a new git configuration is already in use.
+	ErrExists = errors.New("git configuration already exists")
+)
+
+// GitConfig holds the credentials for the git repositories at or below URL.
+type GitConfig struct {
+	Name       string
+	Namespace  string
+	URL        string
+	Username   string
+	Password   string
+	PrivateKey string
+	KnownHosts string
+	CreatedBy  string
+	CreatedAt  metav1.Time
+}
+
+// Kind returns the kind of access the configuration provides, https or ssh.
+func (g GitConfig) Kind() string {
+	if g.PrivateKey != "" {
+		return models.GitConfigSSH
+	}
+	return models.GitConfigHTTPS
+}
+
+// Model returns the API representation of the configuration, without the secret parts.
+func (g GitConfig) Model() models.GitConfig {
+	return models.GitConfig{
+		Meta: models.Meta{
+			Name:      g.Name,
+			Namespace: g.Namespace,
+			CreatedAt: g.CreatedAt,
+		},
+		URL:       g.URL,
+		Kind:      g.Kind(),
+		Username:  g.Username,
+		CreatedBy: g.CreatedBy,
+	}
+}
+
+// Auth returns the go-git authentication method for the configuration. The host key of the
+// ssh server is verified against the known hosts, ssh access fails without them.
+func (g GitConfig) Auth() (transport.AuthMethod, error) {
+	username := g.Username
+	if username == "" {
+		username = defaultUsername
+	}
+
+	if g.Kind() == models.GitConfigHTTPS {
+		return &http.BasicAuth{
+			Username: username,
+			Password: g.Password,
+		}, nil
+	}
+
+	auth, err := gitssh.NewPublicKeys(username, []byte(g.PrivateKey), "")
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the private key")
+	}
+
+	if g.KnownHosts == "" {
+		return nil, errors.New("ssh access requires known hosts")
+	}
+
+	callback, err := knownHostsCallback(g.KnownHosts)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing the known hosts")
+	}
+	auth.HostKeyCallback = callback
+
+	return auth, nil
+}
+
+// Validate checks that the request describes a usable git configuration.
+func Validate(req models.GitConfigCreateRequest) error {
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if req.URL == "" {
+		return errors.New("url is required")
+	}
+	if req.Password == "" && req.PrivateKey == "" {
+		return errors.New("either a password or a private key is required")
+	}
+	if req.Password != "" && req.PrivateKey != "" {
+		return errors.New("a password and a private key cannot be used together")
+	}
+	if req.KnownHosts != "" && req.PrivateKey == "" {
+		return errors.New("known hosts require a private key")
+	}
+	if req.PrivateKey != "" && req.KnownHosts == "" {
+		return errors.New("a private key requires known hosts")
+	}
+	if _, ok := parseURL(req.URL); !ok {
+		return errors.Errorf("url '%s' is not a repository url", req.URL)
+	}
+
+	_, err := fromRequest("", "", req).Auth()
+	return err
+}
+
+// Match returns the configuration whose url best matches the url, or nil if there is none.
+// A configuration matches a repository url with the same scheme and host, port included,
+// whose path is the path of the configuration, or below it. The configuration with the
+// longest path is the best match.
+func Match(configs []GitConfig, url string) *GitConfig {
+	repository, ok := parseURL(url)
+	if !ok {
+		return nil
+	}
+
+	var match *GitConfig
+	var matchPath string
+
+	for i, config := range configs {
+		prefix, ok := parseURL(config.URL)
+		if !ok || prefix.scheme != repository.scheme || prefix.host != repository.host {
+			continue
+		}
+		if prefix.path != "" && repository.path != prefix.path &&
+			!strings.HasPrefix(repository.path, prefix.path+"/") {
+			continue
+		}
+		if match == nil || len(prefix.path) > len(matchPath) {
+			match = &configs[i]
+			matchPath = prefix.path
+		}
+	}
+
+	return match
+}
+
+// SameHost returns true if both urls have the same scheme and host, port included.
+func SameHost(url, other string) bool {
+	a, ok := parseURL(url)
+	if !ok {
+		return false
+	}
+	b, ok := parseURL(other)
+	if !ok {
+		return false
+	}
+
+	return a.scheme == b.scheme && a.host == b.host
+}
+
+// repositoryURL is the part of a repository url relevant to matching git configurations.
+type repositoryURL struct {
+	scheme string
+	host   string // Includes the port, if any.
+	path   string // Without leading and trailing slashes, and without `.git` suffix.
+}
+
+// parseURL splits the url into scheme, host, and path. Besides urls it understands the
+// scp-like syntax of ssh, i.e. `user@host:path`, using the scheme `ssh` for it.
+func parseURL(raw string) (repositoryURL, bool) {
+	var result repositoryURL
+
+	if u, err := neturl.Parse(raw); err == nil && u.Scheme != "" && u.Host != "" {
+		result = repositoryURL{scheme: strings.ToLower(u.Scheme), host: strings.ToLower(u.Host), path: u.Path}
+	} else {
+		rest := raw
+		if at := strings.Index(rest, "@"); at >= 0 {
+			rest = rest[at+1:]
+		}
+		colon := strings.Index(rest, ":")
+		if colon <= 0 || strings.Contains(rest[:colon], "/") {
+			return result, false
+		}
+		result = repositoryURL{scheme: "ssh", host: strings.ToLower(rest[:colon]), path: rest[colon+1:]}
+	}
+
+	result.path = strings.TrimSuffix(strings.Trim(result.path, "/"), ".git")
+
+	return result, true
+}
+
+// AuthFor returns the authentication method for cloning the repository at the url, from
+// the best matching git configuration of the namespace. It returns nil if no
+// configuration matches.
+func AuthFor(ctx context.Context, cluster *kubernetes.Cluster, namespace, url string) (transport.AuthMethod, error) {
+	configs, err := List(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	match := Match(configs, url)
+	if match == nil {
+		return nil, nil
+	}
+
+	auth, err := match.Auth()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git configuration %s", match.Name)
+	}
+
+	return auth, nil
+}
+
+// List returns the git configurations of the namespace, sorted by name.
+func List(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]GitConfig, error) {
+	selector := labels.Set(map[string]string{
+		GitConfigLabelKey: "true",
+	}).AsSelector()
+
+	secrets, err := cluster.Kubectl.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configs := []GitConfig{}
+	for _, secret := range secrets.Items {
+		configs = append(configs, fromSecret(secret))
+	}
+
+	sort.SliceStable(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+
+	return configs, nil
+}
+
+// Lookup returns the named git configuration of the namespace.
+func Lookup(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) (*GitConfig, error) {
+	secret, err := cluster.GetSecret(ctx, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if secret.Labels[GitConfigLabelKey] != "true" {
+		return nil, ErrNotFound
+	}
+
+	config := fromSecret(*secret)
+	return &config, nil
+}
+
+// Create creates a new git configuration in the namespace from the request.
+func Create(ctx context.Context, cluster *kubernetes.Cluster, namespace, username string, req models.GitConfigCreateRequest) error {
+	_, err := cluster.GetSecret(ctx, namespace, req.Name)
+	if err == nil {
+		return ErrExists
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	config := fromRequest(namespace, username, req)
+
+	data := map[string][]byte{
+		urlKey:        []byte(config.URL),
+		usernameKey:   []byte(config.Username),
+		passwordKey:   []byte(config.Password),
+		privateKeyKey: []byte(config.PrivateKey),
+		knownHostsKey: []byte(config.KnownHosts),
+	}
+
+	secretLabels := map[string]string{
+		GitConfigLabelKey:              "true",
+		"app.kubernetes.io/name":       "epinio",
+		"app.kubernetes.io/managed-by": "epinio",
+	}
+
+	annotations := map[string]string{
+		models.EpinioCreatedByAnnotation: username,
+	}
+
+	return cluster.CreateLabeledSecret(ctx, namespace, req.Name, data, secretLabels, annotations)
+}
+
+// Delete removes the named git configuration from the namespace.
+func Delete(ctx context.Context, cluster *kubernetes.Cluster, namespace, name string) error {
+	_, err := Lookup(ctx, cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	return cluster.DeleteSecret(ctx, namespace, name)
+}
+
+func fromRequest(namespace, username string, req models.GitConfigCreateRequest) GitConfig {
+	return GitConfig{
+		Name:       req.Name,
+		Namespace:  namespace,
+		URL:        req.URL,
+		Username:   req.Username,
+		Password:   req.Password,
+		PrivateKey: req.PrivateKey,
+		KnownHosts: req.KnownHosts,
+		CreatedBy:  username,
+	}
+}
+
+func fromSecret(secret v1.Secret) GitConfig {
+	return GitConfig{
+		Name:       secret.Name,
+		Namespace:  secret.Namespace,
+		URL:        string(secret.Data[urlKey]),
+		Username:   string(secret.Data[usernameKey]),
+		Password:   string(secret.Data[passwordKey]),
+		PrivateKey: string(secret.Data[privateKeyKey]),
+		KnownHosts: string(secret.Data[knownHostsKey]),
+		CreatedBy:  secret.Annotations[models.EpinioCreatedByAnnotation],
+		CreatedAt:  secret.CreationTimestamp,
+	}
+}
+
+// knownHostsCallback returns a host key callback verifying against the known hosts, given
+// in the format of an ssh known_hosts file.
+func knownHostsCallback(knownHosts string) (ssh.HostKeyCallback, error) {
+	file, err := os.CreateTemp("", "epinio-known-hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(knownHosts)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: The callback reads the file now, and does not need it afterwards.
+	return gitssh.NewKnownHostsCallback(file.Name())
+}
@@ -0,0 +1,171 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitconfig_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/epinio/epinio/internal/gitconfig"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitConfig", func() {
+	var privateKey, knownHosts string
+
+	BeforeEach(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		privateKey = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}))
+
+		publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+		Expect(err).ToNot(HaveOccurred())
+		knownHosts = knownhosts.Line([]string{"github.com"}, publicKey)
+	})
+
+	Describe("Match", func() {
+		configs := []gitconfig.GitConfig{
+			{Name: "host", URL: "https://github.com/"},
+			{Name: "org", URL: "https://github.com/epinio/"},
+			{Name: "ssh", URL: "git@github.com:epinio/"},
+		}
+
+		It("returns the configuration with the longest matching url", func() {
+			match := gitconfig.Match(configs, "https://github.com/epinio/epinio.git")
+			Expect(match).ToNot(BeNil())
+			Expect(match.Name).To(Equal("org"))
+
+			match = gitconfig.Match(configs, "https://github.com/other/repo.git")
+			Expect(match).ToNot(BeNil())
+			Expect(match.Name).To(Equal("host"))
+
+			match = gitconfig.Match(configs, "git@github.com:epinio/epinio.git")
+			Expect(match).ToNot(BeNil())
+			Expect(match.Name).To(Equal("ssh"))
+		})
+
+		It("returns nil when no configuration matches", func() {
+			Expect(gitconfig.Match(configs, "https://gitlab.com/epinio/epinio.git")).To(BeNil())
+			Expect(gitconfig.Match(nil, "https://github.com/epinio/epinio.git")).To(BeNil())
+		})
+
+		It("requires the same scheme and host", func() {
+			Expect(gitconfig.Match(configs, "https://github.com.evil.io/epinio/epinio.git")).To(BeNil())
+			Expect(gitconfig.Match(configs, "https://github.com:8443/epinio/epinio.git")).To(BeNil())
+			Expect(gitconfig.Match(configs, "http://github.com/epinio/epinio.git")).To(BeNil())
+			Expect(gitconfig.Match(configs, "git@github.com.evil.io:epinio/epinio.git")).To(BeNil())
+		})
+
+		It("requires a path boundary after the path of the configuration", func() {
+			match := gitconfig.Match(configs, "https://github.com/epinio-evil/epinio.git")
+			Expect(match).ToNot(BeNil())
+			Expect(match.Name).To(Equal("host"))
+
+			Expect(gitconfig.Match(configs, "git@github.com:epinio-evil/epinio.git")).To(BeNil())
+
+			repo := []gitconfig.GitConfig{{Name: "repo", URL: "https://github.com/epinio/epinio"}}
+			Expect(gitconfig.Match(repo, "https://github.com/epinio/epinio.git")).ToNot(BeNil())
+			Expect(gitconfig.Match(repo, "https://github.com/epinio/epinio-evil.git")).To(BeNil())
+		})
+	})
+
+	Describe("SameHost", func() {
+		It("compares scheme and host", func() {
+			Expect(gitconfig.SameHost("https://github.com/epinio/a.git", "https://github.com/other/b")).To(BeTrue())
+			Expect(gitconfig.SameHost("git@github.com:epinio/a.git", "ssh://git@github.com/other/b")).To(BeTrue())
+
+			Expect(gitconfig.SameHost("https://github.com/epinio/a.git", "https://evil.io/epinio/a.git")).To(BeFalse())
+			Expect(gitconfig.SameHost("https://github.com/epinio/a.git", "http://github.com/epinio/a.git")).To(BeFalse())
+			Expect(gitconfig.SameHost("https://github.com/epinio/a.git", "../b.git")).To(BeFalse())
+		})
+	})
+
+	Describe("Auth", func() {
+		It("returns basic auth for a password", func() {
+			auth, err := gitconfig.GitConfig{URL: "https://github.com/", Password: "token"}.Auth()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(auth).To(Equal(&http.BasicAuth{Username: "git", Password: "token"}))
+
+			auth, err = gitconfig.GitConfig{URL: "https://github.com/", Username: "me", Password: "token"}.Auth()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(auth).To(Equal(&http.BasicAuth{Username: "me", Password: "token"}))
+		})
+
+		It("returns public key auth for a private key", func() {
+			auth, err := gitconfig.GitConfig{URL: "git@github.com:", PrivateKey: privateKey, KnownHosts: knownHosts}.Auth()
+			Expect(err).ToNot(HaveOccurred())
+
+			keys, ok := auth.(*gitssh.PublicKeys)
+			Expect(ok).To(BeTrue())
+			Expect(keys.User).To(Equal("git"))
+			Expect(keys.HostKeyCallback).ToNot(BeNil())
+		})
+
+		It("fails for a bad private key", func() {
+			_, err := gitconfig.GitConfig{URL: "git@github.com:", PrivateKey: "bogus", KnownHosts: knownHosts}.Auth()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails for a private key without known hosts", func() {
+			_, err := gitconfig.GitConfig{URL: "git@github.com:", PrivateKey: privateKey}.Auth()
+			Expect(err).To(MatchError(ContainSubstring("requires known hosts")))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts https and ssh credentials", func() {
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "https://github.com/", Password: "token",
+			})).To(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "git@github.com:", PrivateKey: privateKey, KnownHosts: knownHosts,
+			})).To(Succeed())
+		})
+
+		It("rejects incomplete or ambiguous credentials", func() {
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				URL: "https://github.com/", Password: "token",
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", Password: "token",
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "https://github.com/",
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "git@github.com:", Password: "token", PrivateKey: privateKey,
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "https://github.com/", Password: "token", KnownHosts: "github.com ssh-rsa AAAA",
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "git@github.com:", PrivateKey: privateKey,
+			})).ToNot(Succeed())
+			Expect(gitconfig.Validate(models.GitConfigCreateRequest{
+				Name: "a", URL: "github", Password: "token",
+			})).ToNot(Succeed())
+		})
+	})
+})
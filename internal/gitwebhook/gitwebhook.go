@@ -0,0 +1,274 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitwebhook verifies and decodes the push events sent by Git hosting services.
+// Supported are GitHub, GitLab, and Gitea.
+package gitwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Providers, i.e. the Git hosting services sending the events.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+)
+
+var (
+	// ErrUnknownProvider is returned for requests not coming from a supported provider.
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// ErrBadSignature is returned for requests which are not signed with the secret.
+	ErrBadSignature = errors.New("bad webhook signature")
+	// ErrNotPush is returned for events other than pushes, e.g. the pings sent when a
+	// webhook is set up.
+	ErrNotPush = errors.New("not a push event")
+)
+
+// zeroCommit is the commit id reported by the providers for a deleted branch.
+const zeroCommit = "0000000000000000000000000000000000000000"
+
+// Push is the provider-independent description of a push event.
+type Push struct {
+	Provider      string
+	Branch        string // The pushed branch. Empty if a tag was pushed.
+	DefaultBranch string // The default branch of the repository.
+	Commit        string // The commit the branch points to after the push.
+	Repository    string // The clone url of the repository.
+	Pusher        string // The name of the user who pushed.
+	Deleted       bool   // True if the push deleted the branch.
+}
+
+// Matches returns true if the push updated the branch tracked by an application. An
+// empty revision tracks the default branch of the repository.
+func (p Push) Matches(revision string) bool {
+	if p.Deleted || p.Branch == "" {
+		return false
+	}
+	if revision == "" {
+		return p.Branch == p.DefaultBranch
+	}
+
+	return p.Branch == revision
+}
+
+// FromRepository returns true if the push was made to the repository with the given url.
+// The urls are compared by host and path, so that the https clone url of a push matches
+// the ssh url of the same repository.
+func (p Push) FromRepository(url string) bool {
+	pushed, ok := repositoryID(p.Repository)
+	if !ok {
+		return false
+	}
+	origin, ok := repositoryID(url)
+	if !ok {
+		return false
+	}
+
+	return pushed == origin
+}
+
+// repositoryID reduces a repository url to its host and path, without user, trailing
+// slash and `.git` suffix. Both urls and the scp-like syntax of ssh, i.e.
+// `user@host:path`, are understood.
+func repositoryID(repository string) (string, bool) {
+	var host, path string
+
+	if u, err := url.Parse(repository); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Host, u.Path
+	} else {
+		rest := repository
+		if at := strings.Index(rest, "@"); at >= 0 {
+			rest = rest[at+1:]
+		}
+		colon := strings.Index(rest, ":")
+		if colon <= 0 || strings.Contains(rest[:colon], "/") {
+			return "", false
+		}
+		host, path = rest[:colon], rest[colon+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", false
+	}
+
+	return strings.ToLower(host) + "/" + path, true
+}
+
+// Parse determines the provider from the request headers, verifies the request against
+// the secret, and decodes the push event from the body.
+func Parse(header http.Header, body []byte, secret string) (Push, error) {
+	provider := Provider(header)
+	if provider == "" {
+		return Push{}, ErrUnknownProvider
+	}
+
+	if secret == "" || !verify(provider, header, body, secret) {
+		return Push{}, ErrBadSignature
+	}
+
+	switch provider {
+	case ProviderGitHub:
+		if header.Get("X-GitHub-Event") != "push" {
+			return Push{}, ErrNotPush
+		}
+		return parseGitHub(provider, body)
+	case ProviderGitea:
+		if header.Get("X-Gitea-Event") != "push" {
+			return Push{}, ErrNotPush
+		}
+		// Gitea's payload mirrors the one of GitHub
+		return parseGitHub(provider, body)
+	case ProviderGitLab:
+		if header.Get("X-Gitlab-Event") != "Push Hook" {
+			return Push{}, ErrNotPush
+		}
+		return parseGitLab(body)
+	}
+
+	return Push{}, ErrUnknownProvider
+}
+
+// Provider returns the provider sending the request, or the empty string if the request
+// does not come from a supported provider.
+func Provider(header http.Header) string {
+	// Note: Gitea is checked first, as it sends the GitHub headers too.
+	switch {
+	case header.Get("X-Gitea-Event") != "":
+		return ProviderGitea
+	case header.Get("X-GitHub-Event") != "":
+		return ProviderGitHub
+	case header.Get("X-Gitlab-Event") != "":
+		return ProviderGitLab
+	}
+
+	return ""
+}
+
+// verify checks that the request was made with knowledge of the secret. GitHub and Gitea
+// sign the body with it (HMAC-SHA256). GitLab sends the secret itself as token.
+func verify(provider string, header http.Header, body []byte, secret string) bool {
+	var signature string
+
+	switch provider {
+	case ProviderGitHub:
+		value := header.Get("X-Hub-Signature-256")
+		if !strings.HasPrefix(value, "sha256=") {
+			return false
+		}
+		signature = strings.TrimPrefix(value, "sha256=")
+	case ProviderGitea:
+		signature = header.Get("X-Gitea-Signature")
+	case ProviderGitLab:
+		token := header.Get("X-Gitlab-Token")
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	default:
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(expected, Sign([]byte(secret), body))
+}
+
+// Sign returns the HMAC-SHA256 of the body, keyed by the secret.
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+type githubPush struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		CloneURL      string `json:"clone_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Pusher struct {
+		Name     string `json:"name"`
+		Username string `json:"username"` // Gitea
+	} `json:"pusher"`
+}
+
+func parseGitHub(provider string, body []byte) (Push, error) {
+	var payload githubPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Push{}, errors.Wrap(err, "decoding push event")
+	}
+
+	pusher := payload.Pusher.Name
+	if pusher == "" {
+		pusher = payload.Pusher.Username
+	}
+
+	return Push{
+		Provider:      provider,
+		Branch:        branch(payload.Ref),
+		DefaultBranch: payload.Repository.DefaultBranch,
+		Commit:        payload.After,
+		Repository:    payload.Repository.CloneURL,
+		Pusher:        pusher,
+		Deleted:       payload.Deleted || payload.After == zeroCommit,
+	}, nil
+}
+
+type gitlabPush struct {
+	Ref          string `json:"ref"`
+	After        string `json:"after"`
+	UserUsername string `json:"user_username"`
+	Project      struct {
+		GitHTTPURL    string `json:"git_http_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+}
+
+func parseGitLab(body []byte) (Push, error) {
+	var payload gitlabPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Push{}, errors.Wrap(err, "decoding push event")
+	}
+
+	return Push{
+		Provider:      ProviderGitLab,
+		Branch:        branch(payload.Ref),
+		DefaultBranch: payload.Project.DefaultBranch,
+		Commit:        payload.After,
+		Repository:    payload.Project.GitHTTPURL,
+		Pusher:        payload.UserUsername,
+		Deleted:       payload.After == zeroCommit,
+	}, nil
+}
+
+// branch returns the branch name of a pushed ref, or the empty string if the ref is not a
+// branch, e.g. a tag.
+func branch(ref string) string {
+	if !strings.HasPrefix(ref, "refs/heads/") {
+		return ""
+	}
+
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
@@ -0,0 +1,168 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitwebhook_test
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/epinio/epinio/internal/gitwebhook"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	const secret = "s3cret"
+
+	githubBody := []byte(`{
+  "ref": "refs/heads/main",
+  "after": "1234567890abcdef1234567890abcdef12345678",
+  "repository": {"clone_url": "https://github.com/org/app.git", "default_branch": "main"},
+  "pusher": {"name": "alice"}
+}`)
+
+	giteaBody := []byte(`{
+  "ref": "refs/heads/dev",
+  "after": "1234567890abcdef1234567890abcdef12345678",
+  "repository": {"clone_url": "https://gitea.example.com/org/app.git", "default_branch": "main"},
+  "pusher": {"username": "bob"}
+}`)
+
+	gitlabBody := []byte(`{
+  "ref": "refs/heads/main",
+  "after": "1234567890abcdef1234567890abcdef12345678",
+  "user_username": "carol",
+  "project": {"git_http_url": "https://gitlab.com/org/app.git", "default_branch": "main"}
+}`)
+
+	sign := func(body []byte, key string) string {
+		return hex.EncodeToString(gitwebhook.Sign([]byte(key), body))
+	}
+
+	It("decodes GitHub push events", func() {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Hub-Signature-256", "sha256="+sign(githubBody, secret))
+
+		push, err := gitwebhook.Parse(header, githubBody, secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(push).To(Equal(gitwebhook.Push{
+			Provider:      gitwebhook.ProviderGitHub,
+			Branch:        "main",
+			DefaultBranch: "main",
+			Commit:        "1234567890abcdef1234567890abcdef12345678",
+			Repository:    "https://github.com/org/app.git",
+			Pusher:        "alice",
+		}))
+	})
+
+	It("decodes Gitea push events", func() {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Gitea-Event", "push")
+		header.Set("X-Gitea-Signature", sign(giteaBody, secret))
+
+		push, err := gitwebhook.Parse(header, giteaBody, secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(push.Provider).To(Equal(gitwebhook.ProviderGitea))
+		Expect(push.Branch).To(Equal("dev"))
+		Expect(push.Pusher).To(Equal("bob"))
+	})
+
+	It("decodes GitLab push events", func() {
+		header := http.Header{}
+		header.Set("X-Gitlab-Event", "Push Hook")
+		header.Set("X-Gitlab-Token", secret)
+
+		push, err := gitwebhook.Parse(header, gitlabBody, secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(push.Provider).To(Equal(gitwebhook.ProviderGitLab))
+		Expect(push.Branch).To(Equal("main"))
+		Expect(push.Repository).To(Equal("https://gitlab.com/org/app.git"))
+		Expect(push.Pusher).To(Equal("carol"))
+	})
+
+	It("rejects bad signatures", func() {
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "push")
+		header.Set("X-Hub-Signature-256", "sha256="+sign(githubBody, "other"))
+		_, err := gitwebhook.Parse(header, githubBody, secret)
+		Expect(err).To(MatchError(gitwebhook.ErrBadSignature))
+
+		header = http.Header{}
+		header.Set("X-Gitlab-Event", "Push Hook")
+		header.Set("X-Gitlab-Token", "other")
+		_, err = gitwebhook.Parse(header, gitlabBody, secret)
+		Expect(err).To(MatchError(gitwebhook.ErrBadSignature))
+	})
+
+	It("rejects everything without a secret", func() {
+		header := http.Header{}
+		header.Set("X-Gitlab-Event", "Push Hook")
+		_, err := gitwebhook.Parse(header, gitlabBody, "")
+		Expect(err).To(MatchError(gitwebhook.ErrBadSignature))
+	})
+
+	It("rejects unknown providers", func() {
+		_, err := gitwebhook.Parse(http.Header{}, githubBody, secret)
+		Expect(err).To(MatchError(gitwebhook.ErrUnknownProvider))
+	})
+
+	It("ignores events other than pushes", func() {
+		body := []byte(`{"zen": "Keep it logically awesome."}`)
+		header := http.Header{}
+		header.Set("X-GitHub-Event", "ping")
+		header.Set("X-Hub-Signature-256", "sha256="+sign(body, secret))
+
+		_, err := gitwebhook.Parse(header, body, secret)
+		Expect(err).To(MatchError(gitwebhook.ErrNotPush))
+	})
+})
+
+var _ = Describe("Push", func() {
+	push := gitwebhook.Push{Branch: "main", DefaultBranch: "main"}
+
+	It("matches the tracked branch", func() {
+		Expect(push.Matches("main")).To(BeTrue())
+		Expect(push.Matches("dev")).To(BeFalse())
+	})
+
+	It("matches the default branch without a revision", func() {
+		Expect(push.Matches("")).To(BeTrue())
+
+		other := gitwebhook.Push{Branch: "dev", DefaultBranch: "main"}
+		Expect(other.Matches("")).To(BeFalse())
+	})
+
+	It("does not match deleted branches and tags", func() {
+		deleted := gitwebhook.Push{Branch: "main", DefaultBranch: "main", Deleted: true}
+		Expect(deleted.Matches("main")).To(BeFalse())
+
+		tag := gitwebhook.Push{DefaultBranch: "main"}
+		Expect(tag.Matches("")).To(BeFalse())
+	})
+
+	It("checks the repository", func() {
+		push := gitwebhook.Push{Repository: "https://github.com/epinio/sample.git"}
+
+		Expect(push.FromRepository("https://github.com/epinio/sample")).To(BeTrue())
+		Expect(push.FromRepository("https://GitHub.com/epinio/sample/")).To(BeTrue())
+		Expect(push.FromRepository("git@github.com:epinio/sample.git")).To(BeTrue())
+		Expect(push.FromRepository("ssh://git@github.com/epinio/sample.git")).To(BeTrue())
+
+		Expect(push.FromRepository("https://github.com/epinio/other")).To(BeFalse())
+		Expect(push.FromRepository("https://github.com.evil.io/epinio/sample")).To(BeFalse())
+		Expect(push.FromRepository("")).To(BeFalse())
+		Expect(gitwebhook.Push{}.FromRepository("https://github.com/epinio/sample")).To(BeFalse())
+	})
+})
@@ -0,0 +1,54 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+)
+
+// The ingress annotations of ingress-nginx splitting the traffic of a route between the
+// regular ingress of the live release and the canary ingress of a candidate.
+const (
+	CanaryAnnotation       = "nginx.ingress.kubernetes.io/canary"
+	CanaryWeightAnnotation = "nginx.ingress.kubernetes.io/canary-weight"
+)
+
+// The canary marking is handed to the app chart as the `epinio.ingressAnnotations` section
+// of the `values.yaml`. The ingresses of a canary release are thus created as canary, and
+// never compete with the regular ingresses of the live release for their routes. The chart
+// adds the annotations having a value to the ingresses of the routes:
+//
+//	{{- range $key, $value := .Values.epinio.ingressAnnotations }}
+//	{{- if $value }}
+//	    {{ $key }}: {{ $value | quote }}
+//	{{- end }}
+//	{{- end }}
+//
+// Like the probes, see probes.go, all annotations are written, with null for a regular
+// release, for the removal of the marking to survive the merge with the values of the
+// previous revision.
+
+// canaryValues returns the ingress annotations marking the ingresses as canary, receiving
+// the given percentage of the traffic of their routes. A zero weight is a regular release.
+func canaryValues(weight int32) map[string]*string {
+	annotations := map[string]*string{
+		CanaryAnnotation:       nil,
+		CanaryWeightAnnotation: nil,
+	}
+	if weight > 0 {
+		canary, share := "true", fmt.Sprintf("%d", weight)
+		annotations[CanaryAnnotation] = &canary
+		annotations[CanaryWeightAnnotation] = &share
+	}
+
+	return annotations
+}
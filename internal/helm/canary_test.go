@@ -0,0 +1,61 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("canaryValues()", func() {
+
+	It("marks the ingresses of a canary", func() {
+		data, err := yaml.Marshal(canaryValues(10))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`nginx.ingress.kubernetes.io/canary: "true"
+nginx.ingress.kubernetes.io/canary-weight: "10"
+`))
+	})
+
+	It("writes null for a regular release", func() {
+		data, err := yaml.Marshal(canaryValues(0))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`nginx.ingress.kubernetes.io/canary: null
+nginx.ingress.kubernetes.io/canary-weight: null
+`))
+	})
+
+	It("removes the marking of the deployed release", func() {
+		deployed := map[string]interface{}{
+			"epinio": map[string]interface{}{
+				"imageURL": "image",
+				"ingressAnnotations": map[string]interface{}{
+					CanaryAnnotation:       "true",
+					CanaryWeightAnnotation: "10",
+				},
+			},
+		}
+
+		data, err := yaml.Marshal(map[string]interface{}{
+			"epinio": map[string]interface{}{"ingressAnnotations": canaryValues(0)},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		merged, err := reuseValues(string(data), deployed)
+		Expect(err).ToNot(HaveOccurred())
+		normalized, err := normalizeYAML(merged)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(normalized).To(Equal("epinio:\n  imageURL: image\n  ingressAnnotations: {}\n"))
+	})
+})
@@ -0,0 +1,168 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a diff.
+const diffContext = 3
+
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// Diff returns the line-based unified diff turning `from` into `to`. The labels name the
+// two sides in the header of the diff. The result is empty if there is no difference.
+func Diff(fromLabel, from, toLabel, to string) string {
+	// Diff the texts line by line, by encoding each distinct line as a single rune. The
+	// encoding skips the surrogates, these are not valid in the strings of the diff.
+	lines := map[rune]string{}
+	index := map[string]rune{}
+	encode := func(text string) []rune {
+		encoded := []rune{}
+		for _, line := range strings.SplitAfter(text, "\n") {
+			if line == "" {
+				continue
+			}
+			r, ok := index[line]
+			if !ok {
+				r = rune(len(lines))
+				if r >= 0xD800 {
+					r += 0x800
+				}
+				index[line] = r
+				lines[r] = line
+			}
+			encoded = append(encoded, r)
+		}
+		return encoded
+	}
+
+	a, b := encode(from), encode(to)
+	diffs := diffmatchpatch.New().DiffMainRunes(a, b, false)
+
+	all := []diffLine{}
+	changed := false
+	for _, d := range diffs {
+		if d.Type != diffmatchpatch.DiffEqual {
+			changed = true
+		}
+		for _, r := range d.Text {
+			all = append(all, diffLine{op: d.Type, text: strings.TrimSuffix(lines[r], "\n")})
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	// Line numbers, 1-based, of the first line of `all` in either side.
+	fromLine, toLine := 1, 1
+
+	for start := 0; start < len(all); {
+		// Find the next change, and the end of the hunk around it. A hunk extends as
+		// long as changes are closer than twice the context.
+		first := start
+		for first < len(all) && all[first].op == diffmatchpatch.DiffEqual {
+			first++
+		}
+		if first == len(all) {
+			break
+		}
+
+		last := first
+		for i := first; i < len(all); i++ {
+			if all[i].op != diffmatchpatch.DiffEqual {
+				last = i
+			} else if i-last > 2*diffContext {
+				break
+			}
+		}
+
+		begin := first - diffContext
+		if begin < start {
+			begin = start
+		}
+		end := last + diffContext + 1
+		if end > len(all) {
+			end = len(all)
+		}
+
+		// Advance the line numbers to the beginning of the hunk.
+		for _, l := range all[start:begin] {
+			fromLine, toLine = advance(l.op, fromLine, toLine)
+		}
+
+		fromCount, toCount := 0, 0
+		for _, l := range all[begin:end] {
+			switch l.op {
+			case diffmatchpatch.DiffEqual:
+				fromCount++
+				toCount++
+			case diffmatchpatch.DiffDelete:
+				fromCount++
+			case diffmatchpatch.DiffInsert:
+				toCount++
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+		for _, l := range all[begin:end] {
+			switch l.op {
+			case diffmatchpatch.DiffEqual:
+				out.WriteString(" ")
+			case diffmatchpatch.DiffDelete:
+				out.WriteString("-")
+			case diffmatchpatch.DiffInsert:
+				out.WriteString("+")
+			}
+			out.WriteString(l.text)
+			out.WriteString("\n")
+			fromLine, toLine = advance(l.op, fromLine, toLine)
+		}
+
+		start = end
+	}
+
+	return out.String()
+}
+
+func advance(op diffmatchpatch.Operation, fromLine, toLine int) (int, int) {
+	switch op {
+	case diffmatchpatch.DiffEqual:
+		return fromLine + 1, toLine + 1
+	case diffmatchpatch.DiffDelete:
+		return fromLine + 1, toLine
+	default:
+		return fromLine, toLine + 1
+	}
+}
+
+// hunkRange formats the range of a hunk for one side of the diff. An empty range refers
+// to the line before it, per the unified format.
+func hunkRange(line, count int) string {
+	if count == 0 {
+		line--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff()", func() {
+
+	It("is empty for identical texts", func() {
+		Expect(Diff("a", "x\ny\n", "b", "x\ny\n")).To(BeEmpty())
+	})
+
+	It("shows changed lines with their context", func() {
+		from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+		to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+
+		Expect(Diff("a", from, "b", to)).To(Equal(`--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`))
+	})
+
+	It("splits distant changes into separate hunks", func() {
+		from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+		to := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\ntwelve\n"
+
+		Expect(Diff("a", from, "b", to)).To(Equal(`--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++twelve
+`))
+	})
+
+	It("diffs against an empty text", func() {
+		Expect(Diff("a", "", "b", "x\n")).To(Equal(`--- a
++++ b
+@@ -0,0 +1 @@
++x
+`))
+	})
+})
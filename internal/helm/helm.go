@@ -33,6 +33,8 @@ import (
 	hc "github.com/mittwald/go-helm-client"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
 	helmrelease "helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
 	helmdriver "helm.sh/helm/v3/pkg/storage/driver"
@@ -69,9 +71,19 @@ type ChartParameters struct {
 	Domains        domain.DomainMap      // Map of domains with secrets covering them
 	Start          *int64                // Nano-epoch of deployment. Optional. Used to force a restart, even when nothing else has changed.
 	Settings       models.AppSettings
+	ReleaseName    string                  // Helm release to deploy to. Optional. Defaults to the release named after the application.
+	HealthChecks   *models.AppHealthChecks // Probes of the application. Optional. Defaults to the probes of the app chart.
+	Resources      *models.AppResources    // Compute resources of the application. Optional. Defaults to the resources of the app chart.
+	CanaryWeight   int32                   // Percentage of the traffic of the routes for a canary release. Optional. Defaults to a regular release.
 }
 
-func Values(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef) ([]byte, error) {
+// DeployedRelease describes the build running in a deployed application release.
+type DeployedRelease struct {
+	StageID  string
+	ImageURL string
+}
+
+func Values(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef, releaseName string) ([]byte, error) {
 	none := []byte{}
 
 	client, err := GetHelmClient(cluster.RestConfig, logger, app.Namespace)
@@ -79,7 +91,7 @@ func Values(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef)
 		return none, err
 	}
 
-	values, err := client.GetReleaseValues(names.ReleaseName(app.Name), false)
+	values, err := client.GetReleaseValues(releaseName, false)
 	if err != nil {
 		return none, err
 	}
@@ -92,15 +104,83 @@ func Values(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef)
 	return yaml, nil
 }
 
+// Remove uninstalls the releases of the application. Besides the release named after the
+// application this is the alternate release used by blue/green and canary deployments.
+// Like for the main release the absence of the alternate release is not an error.
 func Remove(cluster *kubernetes.Cluster, logger logr.Logger, app models.AppRef) error {
 	client, err := GetHelmClient(cluster.RestConfig, logger, app.Namespace)
 	if err != nil {
 		return err
 	}
 
+	err = client.UninstallReleaseByName(names.AlternateReleaseName(app.Name))
+	if err != nil && !strings.Contains(err.Error(), "release: not found") {
+		return err
+	}
+
 	return client.UninstallReleaseByName(names.ReleaseName(app.Name))
 }
 
+// RemoveRelease uninstalls the named application release. The absence of the release is
+// not an error.
+func RemoveRelease(cluster *kubernetes.Cluster, logger logr.Logger, namespace, releaseName string) error {
+	client, err := GetHelmClient(cluster.RestConfig, logger, namespace)
+	if err != nil {
+		return errors.Wrap(err, "create a helm client")
+	}
+
+	err = client.UninstallRelease(&hc.ChartSpec{
+		ReleaseName: releaseName,
+		Namespace:   namespace,
+		Wait:        true,
+		Timeout:     duration.ToDeployment(),
+	})
+	if err != nil && !strings.Contains(err.Error(), "release: not found") {
+		return err
+	}
+
+	return nil
+}
+
+// Deployed returns the build running in the named application release. The result is nil
+// if the release does not exist or is not in state "deployed".
+func Deployed(cluster *kubernetes.Cluster, logger logr.Logger, namespace, releaseName string) (*DeployedRelease, error) {
+	client, err := GetHelmClient(cluster.RestConfig, logger, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "create a helm client")
+	}
+
+	r, err := client.GetRelease(releaseName)
+	if err != nil {
+		if err == helmdriver.ErrReleaseNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "getting the helm release")
+	}
+
+	if r.Info == nil || r.Info.Status != helmrelease.StatusDeployed {
+		return nil, nil
+	}
+
+	return deployedFromValues(r.Config), nil
+}
+
+// deployedFromValues extracts the build information from the values of an application
+// release. See the `epinioParam` structure in `Deploy`.
+func deployedFromValues(values map[string]interface{}) *DeployedRelease {
+	deployed := &DeployedRelease{}
+
+	epinio, ok := values["epinio"].(map[string]interface{})
+	if !ok {
+		return deployed
+	}
+
+	deployed.StageID, _ = epinio["stageID"].(string)
+	deployed.ImageURL, _ = epinio["imageURL"].(string)
+
+	return deployed
+}
+
 func RemoveService(logger logr.Logger, cluster *kubernetes.Cluster, app models.AppRef) error {
 	client, err := GetHelmClient(cluster.RestConfig, logger, app.Namespace)
 	if err != nil {
@@ -164,13 +244,126 @@ func DeployService(logger logr.Logger, parameters ServiceParameters) error {
 }
 
 func Deploy(logger logr.Logger, parameters ChartParameters) error {
+	client, chartSpec, err := appChartSpec(logger, parameters, false)
+	if err != nil {
+		return err
+	}
+
+	err = cleanupReleaseIfNeeded(logger, client, chartSpec.ReleaseName)
+	if err != nil {
+		return errors.Wrap(err, "cleaning up release")
+	}
+
+	_, err = client.InstallOrUpgradeChart(context.Background(), chartSpec, nil)
+
+	return err
+}
+
+// Render renders the application chart with the values computed from the chart parameters,
+// without deploying it. Like Deploy it merges in the values of the deployed release. The
+// result is compared against the currently deployed release.
+func Render(logger logr.Logger, parameters ChartParameters) (models.DeployDryRunResponse, error) {
+	result := models.DeployDryRunResponse{}
+
+	client, chartSpec, err := appChartSpec(logger, parameters, true)
+	if err != nil {
+		return result, err
+	}
+
+	currentValues, currentManifests := "", ""
+
+	r, err := client.GetRelease(chartSpec.ReleaseName)
+	if err != nil && err != helmdriver.ErrReleaseNotFound {
+		return result, errors.Wrap(err, "getting the helm release")
+	}
+	if err == nil {
+		result.Deployed = r.Info != nil && r.Info.Status == helmrelease.StatusDeployed
+		result.Revision = r.Version
+
+		currentManifests = r.Manifest
+		currentValues, err = releaseValues(r)
+		if err != nil {
+			return result, err
+		}
+
+		// Deploy removes a release not deployed, and installs it afresh. Else the
+		// upgrade merges the values of the release into the new ones.
+		if result.Deployed {
+			chartSpec.ValuesYaml, err = reuseValues(chartSpec.ValuesYaml, r.Config)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	manifests, err := client.TemplateChart(chartSpec, nil)
+	if err != nil {
+		return result, errors.Wrap(err, "rendering the application chart")
+	}
+
+	values, err := normalizeYAML(chartSpec.ValuesYaml)
+	if err != nil {
+		return result, errors.Wrap(err, "normalizing the values")
+	}
+
+	result.Release = chartSpec.ReleaseName
+	result.Values = values
+	result.Manifests = string(manifests)
+
+	result.ValuesDiff = Diff("deployed/values.yaml", currentValues, "dry-run/values.yaml", result.Values)
+	result.ManifestDiff = Diff("deployed/manifests.yaml", strings.TrimSpace(currentManifests)+"\n",
+		"dry-run/manifests.yaml", strings.TrimSpace(result.Manifests)+"\n")
+
+	return result, nil
+}
+
+// reuseValues merges the values of the current release into the new values, as the upgrade
+// of a release does with `ReuseValues`. The new values take precedence. A null removes the
+// current value.
+func reuseValues(valuesYaml string, current map[string]interface{}) (string, error) {
+	values, err := chartutil.ReadValues([]byte(valuesYaml))
+	if err != nil {
+		return "", errors.Wrap(err, "reading the values")
+	}
+
+	merged := chartutil.CoalesceTables(values, current)
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling the values")
+	}
+
+	return string(data), nil
+}
+
+// normalizeYAML re-marshals the yaml document through a generic map, for a key order
+// matching the values stored with helm releases.
+func normalizeYAML(document string) (string, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(document), &values); err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// appChartSpec assembles the specification for deploying the application chart, with the
+// `values.yaml` computed from the chart parameters. It further returns the helm client to
+// deploy with. For a dry run the chart of a helm repository is fetched without adding the
+// repository to the configuration of the server.
+func appChartSpec(logger logr.Logger, parameters ChartParameters, dryRun bool) (hc.Client, *hc.ChartSpec, error) {
 	// Find the app chart to use for the deployment.
 	appChart, err := appchart.Lookup(parameters.Context, parameters.Cluster, parameters.Chart)
 	if err != nil {
-		return errors.Wrap(err, "looking up application chart")
+		return nil, nil, errors.Wrap(err, "looking up application chart")
 	}
 	if appChart == nil {
-		return fmt.Errorf("Unable to deploy, chart %s not found", parameters.Chart)
+		return nil, nil, fmt.Errorf("Unable to deploy, chart %s not found", parameters.Chart)
 	}
 
 	// Local type definitions for proper marshalling of the
@@ -183,18 +376,21 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 		Secret string `yaml:"secret,omitempty"`
 	}
 	type epinioParam struct {
-		AppName        string               `yaml:"appName"`
-		Configurations []string             `yaml:"configurations"`
-		ConfigPaths    []ConfigParameter    `yaml:"configpaths"`
-		Env            []models.EnvVariable `yaml:"env"`
-		ImageUrl       string               `yaml:"imageURL"`
-		Ingress        string               `yaml:"ingress,omitempty"`
-		ReplicaCount   int32                `yaml:"replicaCount"`
-		Routes         []routeParam         `yaml:"routes"`
-		StageID        string               `yaml:"stageID"`
-		Start          string               `yaml:"start,omitempty"`
-		TlsIssuer      string               `yaml:"tlsIssuer"`
-		Username       string               `yaml:"username"`
+		AppName            string               `yaml:"appName"`
+		Configurations     []string             `yaml:"configurations"`
+		ConfigPaths        []ConfigParameter    `yaml:"configpaths"`
+		Env                []models.EnvVariable `yaml:"env"`
+		ImageUrl           string               `yaml:"imageURL"`
+		Ingress            string               `yaml:"ingress,omitempty"`
+		IngressAnnotations map[string]*string   `yaml:"ingressAnnotations"`
+		Probes             *probesParam         `yaml:"probes"`
+		ReplicaCount       int32                `yaml:"replicaCount"`
+		Resources          *resourcesParam      `yaml:"resources"`
+		Routes             []routeParam         `yaml:"routes"`
+		StageID            string               `yaml:"stageID"`
+		Start              string               `yaml:"start,omitempty"`
+		TlsIssuer          string               `yaml:"tlsIssuer"`
+		Username           string               `yaml:"username"`
 	}
 	type chartParam struct {
 		Epinio epinioParam            `yaml:"epinio"`
@@ -220,15 +416,18 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 
 	params := chartParam{
 		Epinio: epinioParam{
-			AppName:        parameters.Name,
-			Env:            parameters.Environment.List(),
-			ImageUrl:       parameters.ImageURL,
-			ReplicaCount:   parameters.Instances,
-			Configurations: configurationNames,
-			ConfigPaths:    parameters.Configurations,
-			StageID:        parameters.StageID,
-			TlsIssuer:      viper.GetString("tls-issuer"),
-			Username:       parameters.Username,
+			AppName:            parameters.Name,
+			Env:                parameters.Environment.List(),
+			ImageUrl:           parameters.ImageURL,
+			ReplicaCount:       parameters.Instances,
+			Configurations:     configurationNames,
+			ConfigPaths:        parameters.Configurations,
+			StageID:            parameters.StageID,
+			TlsIssuer:          viper.GetString("tls-issuer"),
+			Username:           parameters.Username,
+			Probes:             probeValues(parameters.HealthChecks),
+			Resources:          resourceValues(parameters.Resources),
+			IngressAnnotations: canaryValues(parameters.CanaryWeight),
 			// Ingress, Start, Routes: see below
 		},
 		// Chart, User: see below
@@ -287,7 +486,7 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 		for field, value := range parameters.Settings {
 			spec, found := appChart.Settings[field]
 			if !found {
-				return fmt.Errorf("Unable to deploy. Setting '%s' unknown", field)
+				return nil, nil, fmt.Errorf("Unable to deploy. Setting '%s' unknown", field)
 			}
 
 			// Note: Here the interface{} result of the properly typed value is
@@ -296,7 +495,7 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 
 			v, err := ValidateField(field, value, spec)
 			if err != nil {
-				return fmt.Errorf(`Unable to deploy. %s`, err.Error())
+				return nil, nil, fmt.Errorf(`Unable to deploy. %s`, err.Error())
 			}
 			params.User[field] = v
 		}
@@ -310,14 +509,14 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 
 	yamlParameters, err := yaml.Marshal(params)
 	if err != nil {
-		return errors.Wrap(err, "marshalling the parameters")
+		return nil, nil, errors.Wrap(err, "marshalling the parameters")
 	}
 
 	logger.Info("app helm setup", "parameters-as-yaml", string(yamlParameters))
 
 	client, err := GetHelmClient(parameters.Cluster.RestConfig, logger, parameters.Namespace)
 	if err != nil {
-		return errors.Wrap(err, "create a helm client")
+		return nil, nil, errors.Wrap(err, "create a helm client")
 	}
 
 	helmChart := appChart.HelmChart
@@ -325,31 +524,42 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 
 	// See also part.go, fetchAppChart
 	if appChart.HelmRepo != "" {
-		name := names.GenerateResourceName("hr-" + base64.StdEncoding.EncodeToString([]byte(appChart.HelmRepo)))
-		if err := client.AddOrUpdateChartRepo(repo.Entry{
-			Name: name,
-			URL:  appChart.HelmRepo,
-		}); err != nil {
-			return errors.Wrap(err, "creating the chart repository")
-		}
-
 		pieces := strings.SplitN(helmChart, ":", 2)
 		if len(pieces) == 2 {
 			helmVersion = pieces[1]
 			helmChart = pieces[0]
 		}
 
-		helmChart = fmt.Sprintf("%s/%s", name, helmChart)
-	}
+		if dryRun {
+			// Fetch the chart from the repository directly, leaving the repository
+			// configuration of the server untouched.
+			_, chartPath, err := client.GetChart(helmChart, &action.ChartPathOptions{
+				RepoURL: appChart.HelmRepo,
+				Version: helmVersion,
+			})
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "fetching the application chart")
+			}
+			helmChart, helmVersion = chartPath, ""
+		} else {
+			name := names.GenerateResourceName("hr-" + base64.StdEncoding.EncodeToString([]byte(appChart.HelmRepo)))
+			if err := client.AddOrUpdateChartRepo(repo.Entry{
+				Name: name,
+				URL:  appChart.HelmRepo,
+			}); err != nil {
+				return nil, nil, errors.Wrap(err, "creating the chart repository")
+			}
 
-	releaseName := names.ReleaseName(parameters.Name)
+			helmChart = fmt.Sprintf("%s/%s", name, helmChart)
+		}
+	}
 
-	err = cleanupReleaseIfNeeded(logger, client, releaseName)
-	if err != nil {
-		return errors.Wrap(err, "cleaning up release")
+	releaseName := parameters.ReleaseName
+	if releaseName == "" {
+		releaseName = names.ReleaseName(parameters.Name)
 	}
 
-	chartSpec := hc.ChartSpec{
+	chartSpec := &hc.ChartSpec{
 		ReleaseName: releaseName,
 		ChartName:   helmChart,
 		Version:     helmVersion,
@@ -361,9 +571,7 @@ func Deploy(logger logr.Logger, parameters ChartParameters) error {
 		ReuseValues: true,
 	}
 
-	_, err = client.InstallOrUpgradeChart(context.Background(), &chartSpec, nil)
-
-	return err
+	return client, chartSpec, nil
 }
 
 func Status(ctx context.Context, logger logr.Logger, cluster *kubernetes.Cluster, namespace, releaseName string) (helmrelease.Status, error) {
@@ -220,3 +220,82 @@ var _ = Describe("ValidateField()", func() {
 		Expect(err.Error()).To(Equal(`Setting "field": Expected boolean, got "hound"`))
 	})
 })
+
+var _ = Describe("deployedFromValues()", func() {
+
+	It("extracts the build from the release values", func() {
+		deployed := deployedFromValues(map[string]interface{}{
+			"epinio": map[string]interface{}{
+				"stageID":  "s1",
+				"imageURL": "registry/app:s1",
+			},
+		})
+		Expect(deployed).To(Equal(&DeployedRelease{
+			StageID:  "s1",
+			ImageURL: "registry/app:s1",
+		}))
+	})
+
+	It("is empty for values without epinio section", func() {
+		Expect(deployedFromValues(map[string]interface{}{})).To(Equal(&DeployedRelease{}))
+	})
+})
+
+var _ = Describe("normalizeYAML()", func() {
+
+	It("sorts the keys, making documents of equal values comparable", func() {
+		a, err := normalizeYAML("b: 1\na:\n  d: x\n  c: w\n")
+		Expect(err).ToNot(HaveOccurred())
+		b, err := normalizeYAML("a:\n    c: w\n    d: x\nb: 1\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(a).To(Equal(b))
+		Expect(a).To(Equal("a:\n  c: w\n  d: x\nb: 1\n"))
+	})
+
+	It("fails for invalid yaml", func() {
+		_, err := normalizeYAML("a: [")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("reuseValues()", func() {
+	current := map[string]interface{}{
+		"epinio": map[string]interface{}{
+			"imageURL": "old",
+			"start":    "42",
+			"probes": map[string]interface{}{
+				"readiness": map[string]interface{}{"path": "/healthz"},
+			},
+		},
+		"userConfig": map[string]interface{}{"a": "b"},
+	}
+
+	It("merges the values of the current release, the new ones taking precedence", func() {
+		values, err := reuseValues("epinio:\n  imageURL: new\n", current)
+		Expect(err).ToNot(HaveOccurred())
+
+		normalized, err := normalizeYAML(values)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(normalized).To(Equal(`epinio:
+  imageURL: new
+  probes:
+    readiness:
+      path: /healthz
+  start: "42"
+userConfig:
+  a: b
+`))
+	})
+
+	It("removes the current values set to null", func() {
+		values, err := reuseValues("epinio:\n  imageURL: new\n  probes: null\nuserConfig: null\n", current)
+		Expect(err).ToNot(HaveOccurred())
+
+		normalized, err := normalizeYAML(values)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(normalized).To(Equal(`epinio:
+  imageURL: new
+  start: "42"
+`))
+	})
+})
@@ -0,0 +1,167 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v2"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	helmdriver "helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrRevisionNotFound is returned when a requested revision is not in the release history.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// History returns the revisions of the named application release, most recent first. A
+// release which does not exist has no history.
+func History(cluster *kubernetes.Cluster, logger logr.Logger, namespace, releaseName string) (models.AppReleaseList, error) {
+	releases, err := releaseHistory(cluster, logger, namespace, releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	history := models.AppReleaseList{}
+	for _, r := range releases {
+		history = append(history, appRelease(r))
+	}
+
+	return history, nil
+}
+
+// ReleaseDiff compares the values and manifests of two revisions of the named application
+// release. ErrRevisionNotFound is returned for revisions missing from the history.
+func ReleaseDiff(cluster *kubernetes.Cluster, logger logr.Logger, namespace, releaseName string, from, to int) (models.AppReleaseDiff, error) {
+	releases, err := releaseHistory(cluster, logger, namespace, releaseName)
+	if err != nil {
+		return models.AppReleaseDiff{}, err
+	}
+
+	return revisionDiff(releases, releaseName, from, to)
+}
+
+// releaseHistory returns the revisions of the named release, most recent first.
+func releaseHistory(cluster *kubernetes.Cluster, logger logr.Logger, namespace, releaseName string) ([]*helmrelease.Release, error) {
+	client, err := GetHelmClient(cluster.RestConfig, logger, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "create a helm client")
+	}
+
+	// Note: The helm history action ignores its `max` setting, and returns all revisions.
+	releases, err := client.ListReleaseHistory(releaseName, 0)
+	if err != nil {
+		if errors.Is(err, helmdriver.ErrReleaseNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "listing the helm release history")
+	}
+
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Version > releases[j].Version
+	})
+
+	return releases, nil
+}
+
+// revisionDiff computes the differences between the two identified revisions of the
+// release history.
+func revisionDiff(releases []*helmrelease.Release, releaseName string, from, to int) (models.AppReleaseDiff, error) {
+	result := models.AppReleaseDiff{
+		Release: releaseName,
+		From:    from,
+		To:      to,
+	}
+
+	fromRelease := findRevision(releases, from)
+	if fromRelease == nil {
+		return result, errors.Wrapf(ErrRevisionNotFound, "revision %d of release %s", from, releaseName)
+	}
+	toRelease := findRevision(releases, to)
+	if toRelease == nil {
+		return result, errors.Wrapf(ErrRevisionNotFound, "revision %d of release %s", to, releaseName)
+	}
+
+	fromValues, err := releaseValues(fromRelease)
+	if err != nil {
+		return result, err
+	}
+	toValues, err := releaseValues(toRelease)
+	if err != nil {
+		return result, err
+	}
+
+	fromLabel := releaseName + "/" + revisionLabel(from)
+	toLabel := releaseName + "/" + revisionLabel(to)
+
+	result.ValuesDiff = Diff(fromLabel+"/values.yaml", fromValues, toLabel+"/values.yaml", toValues)
+	result.ManifestDiff = Diff(fromLabel+"/manifests.yaml", strings.TrimSpace(fromRelease.Manifest)+"\n",
+		toLabel+"/manifests.yaml", strings.TrimSpace(toRelease.Manifest)+"\n")
+
+	return result, nil
+}
+
+// findRevision returns the identified revision of the release history, or nil.
+func findRevision(releases []*helmrelease.Release, revision int) *helmrelease.Release {
+	for _, r := range releases {
+		if r.Version == revision {
+			return r
+		}
+	}
+	return nil
+}
+
+func revisionLabel(revision int) string {
+	return "rev" + strconv.Itoa(revision)
+}
+
+// releaseValues returns the user-supplied values of the release as yaml document.
+func releaseValues(r *helmrelease.Release) (string, error) {
+	data, err := yaml.Marshal(r.Config)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling the release values")
+	}
+	return string(data), nil
+}
+
+// appRelease converts a helm release revision into its API representation.
+func appRelease(r *helmrelease.Release) models.AppRelease {
+	deployed := deployedFromValues(r.Config)
+
+	result := models.AppRelease{
+		Release:  r.Name,
+		Revision: r.Version,
+		StageID:  deployed.StageID,
+		ImageURL: deployed.ImageURL,
+		Status:   helmrelease.StatusUnknown.String(),
+	}
+
+	if r.Chart != nil && r.Chart.Metadata != nil {
+		result.Chart = r.Chart.Metadata.Name
+		result.ChartVersion = r.Chart.Metadata.Version
+	}
+
+	if r.Info != nil {
+		result.Status = r.Info.Status.String()
+		result.Description = r.Info.Description
+		result.Updated = metav1.NewTime(r.Info.LastDeployed.Time)
+	}
+
+	return result
+}
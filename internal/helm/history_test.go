@@ -0,0 +1,95 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+var _ = Describe("History", func() {
+
+	revision := func(version int, stageID string) *helmrelease.Release {
+		return &helmrelease.Release{
+			Name:    "rapp",
+			Version: version,
+			Config: map[string]interface{}{
+				"epinio": map[string]interface{}{
+					"stageID":  stageID,
+					"imageURL": "registry/app:" + stageID,
+				},
+			},
+			Manifest: "kind: Deployment\nimage: registry/app:" + stageID + "\n",
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: "epinio-application", Version: "0.1.26"},
+			},
+			Info: &helmrelease.Info{
+				Status:       helmrelease.StatusSuperseded,
+				Description:  "Upgrade complete",
+				LastDeployed: helmtime.Time{Time: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)},
+			},
+		}
+	}
+
+	Describe("appRelease()", func() {
+		It("converts a release revision", func() {
+			r := appRelease(revision(2, "s2"))
+			Expect(r.Release).To(Equal("rapp"))
+			Expect(r.Revision).To(Equal(2))
+			Expect(r.Chart).To(Equal("epinio-application"))
+			Expect(r.ChartVersion).To(Equal("0.1.26"))
+			Expect(r.Status).To(Equal("superseded"))
+			Expect(r.StageID).To(Equal("s2"))
+			Expect(r.ImageURL).To(Equal("registry/app:s2"))
+			Expect(r.Updated.Time).To(Equal(time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)))
+		})
+
+		It("tolerates a revision without chart and info", func() {
+			r := appRelease(&helmrelease.Release{Name: "rapp", Version: 1})
+			Expect(r.Status).To(Equal("unknown"))
+			Expect(r.Chart).To(BeEmpty())
+		})
+	})
+
+	Describe("revisionDiff()", func() {
+		releases := []*helmrelease.Release{revision(2, "s2"), revision(1, "s1")}
+
+		It("diffs values and manifests of two revisions", func() {
+			diff, err := revisionDiff(releases, "rapp", 1, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.From).To(Equal(1))
+			Expect(diff.To).To(Equal(2))
+			Expect(diff.ValuesDiff).To(ContainSubstring("--- rapp/rev1/values.yaml\n+++ rapp/rev2/values.yaml\n"))
+			Expect(diff.ValuesDiff).To(ContainSubstring("\n-  stageID: s1\n"))
+			Expect(diff.ValuesDiff).To(ContainSubstring("\n+  stageID: s2\n"))
+			Expect(diff.ManifestDiff).To(ContainSubstring("-image: registry/app:s1\n+image: registry/app:s2\n"))
+		})
+
+		It("is empty for a revision compared to itself", func() {
+			diff, err := revisionDiff(releases, "rapp", 2, 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diff.ValuesDiff).To(BeEmpty())
+			Expect(diff.ManifestDiff).To(BeEmpty())
+		})
+
+		It("fails for an unknown revision", func() {
+			_, err := revisionDiff(releases, "rapp", 1, 3)
+			Expect(errors.Is(err, ErrRevisionNotFound)).To(BeTrue())
+		})
+	})
+})
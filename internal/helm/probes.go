@@ -0,0 +1,114 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// appPort is the port the application container listens on, and the default port of HTTP
+// health checks.
+const appPort = 8080
+
+// Local type definitions for the marshalling of the health checks into the `epinio.probes`
+// section of the `values.yaml` handed to the app chart. The probes are shaped like the
+// kubernetes container probes, for the chart to place them as is:
+//
+//	livenessProbe: {{ toYaml .Values.epinio.probes.liveness | nindent 12 }}
+//
+// A probe missing from the section leaves the choice to the chart.
+//
+// The upgrade of a release merges the values of the previous revision into the new ones.
+// All keys are therefore written, with null for the missing ones, for the removal of a probe
+// or of one of its settings to reach the chart.
+
+type probesParam struct {
+	Liveness  *probeParam `yaml:"liveness"`
+	Readiness *probeParam `yaml:"readiness"`
+	Startup   *probeParam `yaml:"startup"`
+}
+
+type probeParam struct {
+	HTTPGet             *httpGetParam   `yaml:"httpGet"`
+	TCPSocket           *tcpSocketParam `yaml:"tcpSocket"`
+	Exec                *execParam      `yaml:"exec"`
+	InitialDelaySeconds *int32          `yaml:"initialDelaySeconds"`
+	PeriodSeconds       *int32          `yaml:"periodSeconds"`
+	TimeoutSeconds      *int32          `yaml:"timeoutSeconds"`
+	SuccessThreshold    *int32          `yaml:"successThreshold"`
+	FailureThreshold    *int32          `yaml:"failureThreshold"`
+}
+
+type httpGetParam struct {
+	Path string `yaml:"path"`
+	Port int32  `yaml:"port"`
+}
+
+type tcpSocketParam struct {
+	Port int32 `yaml:"port"`
+}
+
+type execParam struct {
+	Command []string `yaml:"command"`
+}
+
+// probeValues converts the health checks of the application into chart values. The result
+// is nil if there are no health checks, written as null to remove any previous probes.
+func probeValues(checks *models.AppHealthChecks) *probesParam {
+	if checks == nil || checks.IsEmpty() {
+		return nil
+	}
+
+	return &probesParam{
+		Liveness:  probeValue(checks.Liveness),
+		Readiness: probeValue(checks.Readiness),
+		Startup:   probeValue(checks.Startup),
+	}
+}
+
+func probeValue(probe *models.AppProbe) *probeParam {
+	if probe == nil {
+		return nil
+	}
+
+	result := &probeParam{
+		InitialDelaySeconds: optional(probe.InitialDelaySeconds),
+		PeriodSeconds:       optional(probe.PeriodSeconds),
+		TimeoutSeconds:      optional(probe.TimeoutSeconds),
+		SuccessThreshold:    optional(probe.SuccessThreshold),
+		FailureThreshold:    optional(probe.FailureThreshold),
+	}
+
+	switch {
+	case probe.HTTPPath != "":
+		port := probe.Port
+		if port == 0 {
+			port = appPort
+		}
+		result.HTTPGet = &httpGetParam{Path: probe.HTTPPath, Port: port}
+	case probe.TCPPort != 0:
+		result.TCPSocket = &tcpSocketParam{Port: probe.TCPPort}
+	default:
+		result.Exec = &execParam{Command: probe.Command}
+	}
+
+	return result
+}
+
+// optional returns nil for a zero, i.e. unset, timing or threshold, leaving it to the
+// kubernetes defaults.
+func optional(value int32) *int32 {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,131 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("probeValues()", func() {
+
+	It("is nil without health checks", func() {
+		Expect(probeValues(nil)).To(BeNil())
+		Expect(probeValues(&models.AppHealthChecks{})).To(BeNil())
+	})
+
+	It("shapes the health checks like kubernetes probes", func() {
+		values := probeValues(&models.AppHealthChecks{
+			Liveness: &models.AppProbe{
+				TCPPort:             8080,
+				InitialDelaySeconds: 10,
+			},
+			Readiness: &models.AppProbe{
+				HTTPPath:         "/healthz",
+				FailureThreshold: 5,
+			},
+			Startup: &models.AppProbe{
+				Command: []string{"cat", "/tmp/started"},
+			},
+		})
+
+		data, err := yaml.Marshal(values)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`liveness:
+  httpGet: null
+  tcpSocket:
+    port: 8080
+  exec: null
+  initialDelaySeconds: 10
+  periodSeconds: null
+  timeoutSeconds: null
+  successThreshold: null
+  failureThreshold: null
+readiness:
+  httpGet:
+    path: /healthz
+    port: 8080
+  tcpSocket: null
+  exec: null
+  initialDelaySeconds: null
+  periodSeconds: null
+  timeoutSeconds: null
+  successThreshold: null
+  failureThreshold: 5
+startup:
+  httpGet: null
+  tcpSocket: null
+  exec:
+    command:
+    - cat
+    - /tmp/started
+  initialDelaySeconds: null
+  periodSeconds: null
+  timeoutSeconds: null
+  successThreshold: null
+  failureThreshold: null
+`))
+	})
+
+	It("removes the probes of the deployed release", func() {
+		deployed := map[string]interface{}{
+			"epinio": map[string]interface{}{
+				"imageURL": "image",
+				"probes": map[string]interface{}{
+					"liveness": map[string]interface{}{
+						"httpGet":       map[string]interface{}{"path": "/healthz", "port": 8080},
+						"periodSeconds": 5,
+					},
+					"startup": map[string]interface{}{
+						"exec": map[string]interface{}{"command": []interface{}{"true"}},
+					},
+				},
+			},
+		}
+
+		values := func(checks *models.AppHealthChecks) string {
+			data, err := yaml.Marshal(map[string]interface{}{
+				"epinio": map[string]interface{}{"probes": probeValues(checks)},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			merged, err := reuseValues(string(data), deployed)
+			Expect(err).ToNot(HaveOccurred())
+			normalized, err := normalizeYAML(merged)
+			Expect(err).ToNot(HaveOccurred())
+			return normalized
+		}
+
+		Expect(values(nil)).To(Equal("epinio:\n  imageURL: image\n"))
+
+		// The nulls not matching a value of the release reach the chart as is, and
+		// are the same as missing values.
+		Expect(values(&models.AppHealthChecks{
+			Liveness: &models.AppProbe{TCPPort: 8080},
+		})).To(Equal(`epinio:
+  imageURL: image
+  probes:
+    liveness:
+      exec: null
+      failureThreshold: null
+      initialDelaySeconds: null
+      successThreshold: null
+      tcpSocket:
+        port: 8080
+      timeoutSeconds: null
+    readiness: null
+`))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+)
+
+// Local type definitions for the marshalling of the compute resources into the
+// `epinio.resources` section of the `values.yaml` handed to the app chart. The section is
+// shaped like the resources of a kubernetes container, for the chart to place it as is:
+//
+//	resources: {{ toYaml .Values.epinio.resources | nindent 12 }}
+//
+// Without the section the choice is left to the chart.
+//
+// Like the probes, see probes.go, all keys are written, with null for the missing ones, for
+// their removal to survive the merge with the values of the previous revision.
+
+type resourcesParam struct {
+	Requests *quantitiesParam `yaml:"requests"`
+	Limits   *quantitiesParam `yaml:"limits"`
+}
+
+type quantitiesParam struct {
+	CPU    *string `yaml:"cpu"`
+	Memory *string `yaml:"memory"`
+}
+
+// resourceValues converts the compute resources of the application into chart values. The
+// result is nil if there are no resources, written as null to remove any previous resources.
+func resourceValues(resources *models.AppResources) *resourcesParam {
+	if resources == nil || resources.IsEmpty() {
+		return nil
+	}
+
+	result := &resourcesParam{}
+	if resources.CPURequest != "" || resources.MemoryRequest != "" {
+		result.Requests = &quantitiesParam{
+			CPU:    quantity(resources.CPURequest),
+			Memory: quantity(resources.MemoryRequest),
+		}
+	}
+	if resources.CPULimit != "" || resources.MemoryLimit != "" {
+		result.Limits = &quantitiesParam{
+			CPU:    quantity(resources.CPULimit),
+			Memory: quantity(resources.MemoryLimit),
+		}
+	}
+
+	return result
+}
+
+// quantity returns nil for an empty, i.e. unset, quantity.
+func quantity(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,80 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("resourceValues()", func() {
+
+	It("is nil without resources", func() {
+		Expect(resourceValues(nil)).To(BeNil())
+		Expect(resourceValues(&models.AppResources{})).To(BeNil())
+	})
+
+	It("shapes the resources like kubernetes container resources", func() {
+		values := resourceValues(&models.AppResources{
+			CPURequest:    "250m",
+			MemoryRequest: "512Mi",
+			MemoryLimit:   "512Mi",
+		})
+
+		data, err := yaml.Marshal(values)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`requests:
+  cpu: 250m
+  memory: 512Mi
+limits:
+  cpu: null
+  memory: 512Mi
+`))
+	})
+
+	It("removes the resources of the deployed release", func() {
+		deployed := map[string]interface{}{
+			"epinio": map[string]interface{}{
+				"imageURL": "image",
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"cpu": "250m"},
+					"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+				},
+			},
+		}
+
+		values := func(resources *models.AppResources) string {
+			data, err := yaml.Marshal(map[string]interface{}{
+				"epinio": map[string]interface{}{"resources": resourceValues(resources)},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			merged, err := reuseValues(string(data), deployed)
+			Expect(err).ToNot(HaveOccurred())
+			normalized, err := normalizeYAML(merged)
+			Expect(err).ToNot(HaveOccurred())
+			return normalized
+		}
+
+		Expect(values(nil)).To(Equal("epinio:\n  imageURL: image\n"))
+
+		Expect(values(&models.AppResources{MemoryLimit: "512Mi"})).To(Equal(`epinio:
+  imageURL: image
+  resources:
+    limits:
+      memory: 512Mi
+`))
+	})
+})
@@ -118,8 +118,84 @@ func UpdateAppChart(manifest models.ApplicationManifest, cmd *cobra.Command) (mo
 	return manifest, nil
 }
 
+// UpdateDeployment updates the incoming manifest with information pulled from the
+// --deployment-strategy and --canary-weight options
+func UpdateDeployment(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
+	strategy, err := cmd.Flags().GetString("deployment-strategy")
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read option --deployment-strategy")
+	}
+	weight, err := cmd.Flags().GetInt32("canary-weight")
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read option --canary-weight")
+	}
+
+	// Deployment - Replace, a weight alone adjusts the weight of the manifest's canary
+
+	if strategy == "" && weight == 0 {
+		return manifest, nil
+	}
+
+	deployment := models.AppDeploymentStrategy{}
+	if manifest.Configuration.Deployment != nil {
+		deployment = *manifest.Configuration.Deployment
+	}
+	if strategy != "" {
+		deployment.Strategy = strategy
+		deployment.Weight = 0
+	}
+	if weight != 0 {
+		deployment.Weight = weight
+	}
+
+	if err := deployment.Validate(); err != nil {
+		return manifest, err
+	}
+
+	manifest.Configuration.Deployment = &deployment
+
+	return manifest, nil
+}
+
+// UpdateResources updates the incoming manifest with information pulled from the --memory
+// and --cpu options
+func UpdateResources(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
+	memory, err := cmd.Flags().GetString("memory")
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read option --memory")
+	}
+	cpu, err := cmd.Flags().GetString("cpu")
+	if err != nil {
+		return manifest, errors.Wrap(err, "could not read option --cpu")
+	}
+
+	// Resources - Merge. The memory is both request and limit, the cpu only a request.
+
+	if memory == "" && cpu == "" {
+		return manifest, nil
+	}
+
+	resources := models.AppResources{}
+	if manifest.Configuration.Resources != nil {
+		resources = *manifest.Configuration.Resources
+	}
+	resources = resources.Merge(models.AppResources{
+		CPURequest:    cpu,
+		MemoryRequest: memory,
+		MemoryLimit:   memory,
+	})
+
+	if err := resources.Validate(); err != nil {
+		return manifest, err
+	}
+
+	manifest.Configuration.Resources = &resources
+
+	return manifest, nil
+}
+
 // UpdateSources updates the incoming manifest with information pulled from the sources
-// (--path, --git, and --container-imageurl) options
+// (--path, --git, --git-subdir, --git-submodules, and --container-imageurl) options
 func UpdateSources(manifest models.ApplicationManifest, cmd *cobra.Command) (models.ApplicationManifest, error) {
 	path, err := cmd.Flags().GetString("path")
 	if err != nil {
@@ -198,6 +274,31 @@ func UpdateSources(manifest models.ApplicationManifest, cmd *cobra.Command) (mod
 		}
 	}
 
+	// G:it details - Replace, for git origins only
+
+	subdir, err := cmd.Flags().GetString("git-subdir")
+	if err != nil {
+		return manifest, errors.Wrap(err, "failed to read option --git-subdir")
+	}
+
+	submodules, err := cmd.Flags().GetBool("git-submodules")
+	if err != nil {
+		return manifest, errors.Wrap(err, "failed to read option --git-submodules")
+	}
+
+	if subdir != "" || submodules {
+		if manifest.Origin.Kind != models.OriginGit || manifest.Origin.Git == nil {
+			return manifest, errors.New("Cannot use `--git-subdir` and `--git-submodules` without a git origin")
+		}
+
+		if subdir != "" {
+			manifest.Origin.Git.Subdir = subdir
+		}
+		if submodules {
+			manifest.Origin.Git.Submodules = true
+		}
+	}
+
 	return manifest, nil
 }
 
@@ -414,6 +515,33 @@ func Get(manifestPath string) (models.ApplicationManifest, error) {
 		return empty, errors.New("Cannot use `path`, `git`, and `container` keys together")
 	}
 
+	switch manifest.Staging.Strategy {
+	case "", models.StagingStrategyBuildpacks, models.StagingStrategyDockerfile:
+	default:
+		return empty, errors.Errorf("Bad staging strategy `%s`, expected one of `%s`, or `%s`",
+			manifest.Staging.Strategy,
+			models.StagingStrategyBuildpacks,
+			models.StagingStrategyDockerfile)
+	}
+
+	if manifest.Configuration.Deployment != nil {
+		if err := manifest.Configuration.Deployment.Validate(); err != nil {
+			return empty, errors.Wrap(err, "Bad deployment")
+		}
+	}
+
+	if manifest.Configuration.HealthChecks != nil {
+		if err := manifest.Configuration.HealthChecks.Validate(); err != nil {
+			return empty, errors.Wrap(err, "Bad health checks")
+		}
+	}
+
+	if manifest.Configuration.Resources != nil {
+		if err := manifest.Configuration.Resources.Validate(); err != nil {
+			return empty, errors.Wrap(err, "Bad resources")
+		}
+	}
+
 	// Add default location (manifest directory) back, if needed
 	if origins == 0 {
 		manifest.Origin = defaultOrigin
@@ -151,5 +151,210 @@ configuration:
 
 			})
 		})
+
+		When("the desired manifest file selects a staging strategy", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("strategy.yml", []byte(`name: foo
+staging:
+  strategy: dockerfile
+  dockerfile: build/Dockerfile
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("strategy.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("works", func() {
+				m, err := manifest.Get("strategy.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Staging).To(Equal(models.ApplicationStage{
+					Strategy:   models.StagingStrategyDockerfile,
+					Dockerfile: "build/Dockerfile",
+				}))
+			})
+		})
+
+		When("the desired manifest file sets staging resources", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("resources.yml", []byte(`name: foo
+staging:
+  resources:
+    cpulimit: "2"
+    memoryrequest: 1Gi
+    nodeselector:
+      pool: build
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("resources.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("works", func() {
+				m, err := manifest.Get("resources.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Staging.Resources).To(Equal(&models.StagingResources{
+					CPULimit:      "2",
+					MemoryRequest: "1Gi",
+					NodeSelector:  map[string]string{"pool": "build"},
+				}))
+			})
+		})
+
+		When("the desired manifest file selects an unknown staging strategy", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("badstrategy.yml", []byte(`name: foo
+staging:
+  strategy: magic
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("badstrategy.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("fails with an error", func() {
+				_, err := manifest.Get("badstrategy.yml")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Bad staging strategy `magic`"))
+			})
+		})
+
+		When("the desired manifest file selects a canary deployment", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("canary.yml", []byte(`name: foo
+configuration:
+  deployment:
+    strategy: canary
+    weight: 10
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("canary.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("works", func() {
+				m, err := manifest.Get("canary.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Configuration.Deployment).To(Equal(&models.AppDeploymentStrategy{
+					Strategy: models.DeploymentStrategyCanary,
+					Weight:   10,
+				}))
+			})
+		})
+
+		When("the desired manifest file sets a weight for a blue/green deployment", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("badweight.yml", []byte(`name: foo
+configuration:
+  deployment:
+    strategy: bluegreen
+    weight: 10
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("badweight.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("fails with an error", func() {
+				_, err := manifest.Get("badweight.yml")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("weight is only supported by the `canary` deployment strategy"))
+			})
+		})
+
+		When("the desired manifest file sets health checks", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("probes.yml", []byte(`name: foo
+configuration:
+  healthchecks:
+    liveness:
+      tcpPort: 8080
+    readiness:
+      httpPath: /healthz
+      initialDelaySeconds: 5
+      failureThreshold: 3
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("probes.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("works", func() {
+				m, err := manifest.Get("probes.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.Configuration.HealthChecks).To(Equal(&models.AppHealthChecks{
+					Liveness: &models.AppProbe{TCPPort: 8080},
+					Readiness: &models.AppProbe{
+						HTTPPath:            "/healthz",
+						InitialDelaySeconds: 5,
+						FailureThreshold:    3,
+					},
+				}))
+			})
+		})
+
+		When("the desired manifest file sets a probe with two checks", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("badprobe.yml", []byte(`name: foo
+configuration:
+  healthchecks:
+    startup:
+      httpPath: /healthz
+      command: [ "true" ]
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("badprobe.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("fails with an error", func() {
+				_, err := manifest.Get("badprobe.yml")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("startup probe: expected exactly one of httpPath, tcpPort, or command"))
+			})
+		})
+
+		When("the desired manifest file sets a request above its limit", func() {
+			BeforeEach(func() {
+				err := os.WriteFile("badresources.yml", []byte(`name: foo
+configuration:
+  resources:
+    memoryrequest: 1Gi
+    memorylimit: 512Mi
+`), 0600)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := os.Remove("badresources.yml")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("fails with an error", func() {
+				_, err := manifest.Get("badresources.yml")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("memory request '1Gi' exceeds its limit '512Mi'"))
+			})
+		})
 	})
 })
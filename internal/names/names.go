@@ -96,6 +96,13 @@ func ReleaseName(base string) string {
 	return GenerateResourceNameTruncated(base, 53)
 }
 
+// AlternateReleaseName returns the name of the second helm release of an application, as
+// used by blue/green and canary deployments. The underscore cannot occur in application
+// names, thus the name cannot clash with the release of another application.
+func AlternateReleaseName(base string) string {
+	return ReleaseName(base + "_alt")
+}
+
 // ServiceReleaseName returns the name of a helm release derived from the base string.
 func ServiceReleaseName(base string) string {
 	// The integral helm client deploying the chart generates derived names for secrets and pods
@@ -94,4 +94,15 @@ var _ = Describe("Names", func() {
 			Expect(Truncate(originalName, 8)).To(Equal("this-is-"))
 		})
 	})
+
+	Describe("AlternateReleaseName", func() {
+		It("differs from the release of the application", func() {
+			Expect(AlternateReleaseName("foo")).ToNot(Equal(ReleaseName("foo")))
+		})
+
+		It("does not clash with the release of a similarly named application", func() {
+			Expect(AlternateReleaseName("foo")).ToNot(Equal(ReleaseName("foo-alt")))
+			Expect(AlternateReleaseName("foo")).ToNot(Equal(AlternateReleaseName("foo-alt")))
+		})
+	})
 })
@@ -20,6 +20,7 @@ import (
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/duration"
 	"github.com/epinio/epinio/internal/registry"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -88,6 +89,18 @@ func Get(ctx context.Context, kubeClient *kubernetes.Cluster, lookupNamespace st
 	return nil, nil
 }
 
+// AppResourceMaximums returns the caps on the cpu and memory of the applications in the
+// namespace, as recorded in its annotations. Empty values impose no cap.
+func AppResourceMaximums(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string) (string, string, error) {
+	ns, err := kubeClient.Kubectl.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	annotations := ns.GetAnnotations()
+	return annotations[models.EpinioAppMaxCPUAnnotation], annotations[models.EpinioAppMaxMemoryAnnotation], nil
+}
+
 // Create generates a new epinio-controlled namespace, i.e. a kube
 // namespace plus a service account.
 func Create(ctx context.Context, kubeClient *kubernetes.Cluster, namespace string) error {
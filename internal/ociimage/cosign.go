@@ -0,0 +1,175 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ociimage
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The signatures made by cosign are stored in the repository of the image, as an image
+// tagged after the digest of the signed manifest. Each layer of the signature image is a
+// signed payload naming the digest of the signed manifest. The signature of the payload
+// is in an annotation of the layer.
+
+const (
+	// SignatureAnnotation is the layer annotation holding the base64 encoded
+	// signature of the payload.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// signaturePayloadType is the type of the payloads signing container images.
+	signaturePayloadType = "cosign container image signature"
+)
+
+var (
+	// ErrNotSigned is returned when the image has no signatures.
+	ErrNotSigned = errors.New("image is not signed")
+	// ErrSignatureInvalid is returned when no signature of the image verifies against
+	// the keys.
+	ErrSignatureInvalid = errors.New("no valid signature for the image")
+)
+
+// signatureManifest is the part of an image manifest holding the signatures.
+type signatureManifest struct {
+	Layers []struct {
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"layers"`
+}
+
+// signaturePayload is the part of a signed payload identifying the signed image.
+type signaturePayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// VerifySignature checks that the image carries a cosign signature made with one of the
+// keys.
+func (c *Client) VerifySignature(ctx context.Context, image Image, keys []crypto.PublicKey) error {
+	if len(keys) == 0 {
+		return errors.New("no keys to verify the signature with")
+	}
+
+	_, data, err := c.manifest(ctx, image, SignatureTag(image.Digest), imageManifestMediaTypes, http.MethodGet)
+	if errors.Is(err, ErrNotFound) {
+		return ErrNotSigned
+	}
+	if err != nil {
+		return errors.Wrap(err, "fetching the signatures")
+	}
+
+	var manifest signatureManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return errors.Wrap(err, "decoding the signatures")
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[SignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		payload, err := c.blob(ctx, image, layer.Digest)
+		if err != nil {
+			return errors.Wrap(err, "fetching the signed payload")
+		}
+
+		if verifyPayload(payload, signature, image.Digest, keys) {
+			return nil
+		}
+	}
+
+	return ErrSignatureInvalid
+}
+
+// SignatureTag returns the tag of the cosign signatures of the manifest with the digest.
+func SignatureTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1) + ".sig"
+}
+
+// verifyPayload checks that the payload names the digest, and that the signature of the
+// payload verifies against one of the keys.
+func verifyPayload(payload []byte, signature, digest string, keys []crypto.PublicKey) bool {
+	var content signaturePayload
+	if err := json.Unmarshal(payload, &content); err != nil {
+		return false
+	}
+	if content.Critical.Type != signaturePayloadType ||
+		content.Critical.Image.DockerManifestDigest != digest {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	hash := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch key := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, hash[:], sig) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, payload, sig) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ParsePublicKeys returns the public keys of the PEM data, e.g. the contents of a
+// cosign.pub file.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing the public key")
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
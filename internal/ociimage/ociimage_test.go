@@ -0,0 +1,238 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ociimage_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/epinio/epinio/internal/ociimage"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeRegistry serves manifests and blobs of the repository "app", behind token
+// authentication.
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte // by tag and digest
+	blobs     map[string][]byte // by digest
+}
+
+func newFakeRegistry() *fakeRegistry {
+	r := &fakeRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+
+	r.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if req.URL.Query().Get("scope") != "repository:app:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="fake",scope="repository:app:pull"`, r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var content []byte
+		var ok bool
+		switch {
+		case strings.HasPrefix(req.URL.Path, "/v2/app/manifests/"):
+			content, ok = r.manifests[strings.TrimPrefix(req.URL.Path, "/v2/app/manifests/")]
+		case strings.HasPrefix(req.URL.Path, "/v2/app/blobs/"):
+			content, ok = r.blobs[strings.TrimPrefix(req.URL.Path, "/v2/app/blobs/")]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Docker-Content-Digest", digestOf(content))
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(content)
+		}
+	}))
+
+	return r
+}
+
+// push stores the manifest under the tag, and returns its digest.
+func (r *fakeRegistry) push(tag string, manifest []byte) string {
+	digest := digestOf(manifest)
+	r.manifests[tag] = manifest
+	r.manifests[digest] = manifest
+	return digest
+}
+
+// sign stores a cosign signature of the digest, made with the key.
+func (r *fakeRegistry) sign(digest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"app"},`+
+		`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, digest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	Expect(err).ToNot(HaveOccurred())
+
+	payloadDigest := digestOf(payload)
+	r.blobs[payloadDigest] = payload
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"layers": []map[string]interface{}{{
+			"mediaType": "application/vnd.dev.cosign.simplesigning.v1+json",
+			"digest":    payloadDigest,
+			"size":      len(payload),
+			"annotations": map[string]string{
+				ociimage.SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+			},
+		}},
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	r.manifests[ociimage.SignatureTag(digest)] = manifest
+}
+
+func (r *fakeRegistry) host() string {
+	return strings.TrimPrefix(r.server.URL, "https://")
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	return key
+}
+
+var _ = Describe("Client", func() {
+	var registry *fakeRegistry
+	var client *ociimage.Client
+	var digest string
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		client = ociimage.NewClient(registry.server.Client(), nil)
+		digest = registry.push("v1", []byte(`{"schemaVersion":2}`))
+	})
+
+	AfterEach(func() {
+		registry.server.Close()
+	})
+
+	Describe("Resolve", func() {
+		It("resolves tags to the digest of the manifest", func() {
+			image, err := client.Resolve(ctx, registry.host()+"/app:v1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(image.Digest).To(Equal(digest))
+			Expect(image.String()).To(Equal(registry.host() + "/app@" + digest))
+		})
+
+		It("keeps references by digest", func() {
+			image, err := client.Resolve(ctx, registry.host()+"/app@"+digest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(image.Digest).To(Equal(digest))
+		})
+
+		It("fails for unknown tags", func() {
+			_, err := client.Resolve(ctx, registry.host()+"/app:v2")
+			Expect(err).To(MatchError(ContainSubstring("not found")))
+		})
+	})
+
+	Describe("VerifySignature", func() {
+		var key *ecdsa.PrivateKey
+		var image ociimage.Image
+
+		BeforeEach(func() {
+			key = newKey()
+
+			var err error
+			image, err = client.Resolve(ctx, registry.host()+"/app:v1")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("accepts images signed with one of the keys", func() {
+			registry.sign(digest, key)
+
+			err := client.VerifySignature(ctx, image, []crypto.PublicKey{&newKey().PublicKey, &key.PublicKey})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("rejects images signed with other keys", func() {
+			registry.sign(digest, newKey())
+
+			err := client.VerifySignature(ctx, image, []crypto.PublicKey{&key.PublicKey})
+			Expect(err).To(MatchError(ociimage.ErrSignatureInvalid))
+		})
+
+		It("rejects signatures of other images", func() {
+			other := registry.push("v2", []byte(`{"schemaVersion":2,"other":true}`))
+			registry.sign(other, key)
+			registry.manifests[ociimage.SignatureTag(digest)] = registry.manifests[ociimage.SignatureTag(other)]
+
+			err := client.VerifySignature(ctx, image, []crypto.PublicKey{&key.PublicKey})
+			Expect(err).To(MatchError(ociimage.ErrSignatureInvalid))
+		})
+
+		It("rejects unsigned images", func() {
+			err := client.VerifySignature(ctx, image, []crypto.PublicKey{&key.PublicKey})
+			Expect(err).To(MatchError(ociimage.ErrNotSigned))
+		})
+	})
+})
+
+var _ = Describe("ParsePublicKeys", func() {
+	It("parses the PEM encoded public keys", func() {
+		data := []byte{}
+		for i := 0; i < 2; i++ {
+			der, err := x509.MarshalPKIXPublicKey(&newKey().PublicKey)
+			Expect(err).ToNot(HaveOccurred())
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...)
+		}
+
+		keys, err := ociimage.ParsePublicKeys(data)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys).To(HaveLen(2))
+	})
+
+	It("rejects broken keys", func() {
+		data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("broken")})
+
+		_, err := ociimage.ParsePublicKeys(data)
+		Expect(err).To(HaveOccurred())
+	})
+})
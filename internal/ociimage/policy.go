@@ -0,0 +1,112 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ociimage
+
+import (
+	"context"
+	"crypto"
+	"sort"
+	"strings"
+
+	"github.com/epinio/epinio/helpers/kubernetes"
+	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/internal/registry"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// The signature policy is configured by the operator, with secrets in the Epinio
+// namespace. Each secret labeled as signing keys holds PEM encoded public keys, and names
+// the namespaces it applies to in an annotation. Container images deployed into these
+// namespaces must carry a signature made with one of the keys.
+
+const (
+	// SigningKeysLabelKey marks the secrets holding image signing keys.
+	SigningKeysLabelKey = "epinio.io/image-signing-keys"
+	// SigningNamespacesAnnotation lists the namespaces the keys apply to, separated
+	// by commas. A "*" applies the keys to all namespaces.
+	SigningNamespacesAnnotation = "epinio.io/image-signing-namespaces"
+)
+
+// SigningKeys returns the public keys the images deployed into the namespace have to be
+// signed with. No keys mean that signatures are not checked.
+func SigningKeys(ctx context.Context, cluster *kubernetes.Cluster, namespace string) ([]crypto.PublicKey, error) {
+	selector := labels.Set(map[string]string{
+		SigningKeysLabelKey: "true",
+	}).AsSelector()
+
+	secrets, err := cluster.Kubectl.CoreV1().Secrets(helmchart.Namespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []crypto.PublicKey{}
+	for _, secret := range secrets.Items {
+		if !appliesTo(secret, namespace) {
+			continue
+		}
+
+		// Sorted, for a stable order of the keys
+		names := make([]string, 0, len(secret.Data))
+		for name := range secret.Data {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			parsed, err := ParsePublicKeys(secret.Data[name])
+			if err != nil {
+				return nil, errors.Wrapf(err, "signing keys %s/%s", secret.Name, name)
+			}
+			keys = append(keys, parsed...)
+		}
+	}
+
+	return keys, nil
+}
+
+// appliesTo returns true if the signing keys of the secret apply to the namespace.
+func appliesTo(secret v1.Secret, namespace string) bool {
+	for _, name := range strings.Split(secret.Annotations[SigningNamespacesAnnotation], ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || name == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistryCredentials returns the credentials of the registries Epinio is configured
+// with, by registry host.
+func RegistryCredentials(ctx context.Context, cluster *kubernetes.Cluster) (map[string]Credentials, error) {
+	details, err := registry.GetConnectionDetails(ctx, cluster, helmchart.Namespace(), registry.CredentialsSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := map[string]Credentials{}
+	for _, r := range details.RegistryCredentials {
+		host := strings.TrimPrefix(strings.TrimPrefix(r.URL, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+
+		credentials[host] = Credentials{
+			Username: r.Username,
+			Password: r.Password,
+		}
+	}
+
+	return credentials, nil
+}
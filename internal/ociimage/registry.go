@@ -0,0 +1,324 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ociimage resolves container images to the immutable digests of their
+// manifests, and verifies their cosign signatures. It talks to the registries through the
+// OCI distribution API.
+package ociimage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	parser "github.com/novln/docker-parser"
+	"github.com/pkg/errors"
+)
+
+// imageManifestMediaTypes are the types of single image manifests.
+var imageManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// manifestMediaTypes are the manifest types accepted when resolving images. The digest of
+// a multi-platform image is the digest of its index.
+var manifestMediaTypes = append([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}, imageManifestMediaTypes...)
+
+// maxManifestSize limits the size of the manifests and signature payloads read from the
+// registries.
+const maxManifestSize = 4 * 1024 * 1024
+
+// ErrNotFound is returned when the registry does not know the requested manifest.
+var ErrNotFound = errors.New("not found in the registry")
+
+// Image is a container image pinned to the digest of its manifest.
+type Image struct {
+	// Registry is the host of the registry, e.g. "ghcr.io"
+	Registry string
+	// Name is the name of the image in the registry, e.g. "epinio/sample-app"
+	Name string
+	// Digest is the digest of the manifest, e.g. "sha256:..."
+	Digest string
+}
+
+// String returns the reference to the image by digest.
+func (i Image) String() string {
+	return fmt.Sprintf("%s/%s@%s", i.Registry, i.Name, i.Digest)
+}
+
+// Credentials are the username and password for a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Client accesses the registries.
+type Client struct {
+	http        *http.Client
+	credentials map[string]Credentials
+}
+
+// NewClient returns a client using the credentials for the registries, by registry host.
+// Registries without credentials are accessed anonymously.
+func NewClient(httpClient *http.Client, credentials map[string]Credentials) *Client {
+	if credentials == nil {
+		credentials = map[string]Credentials{}
+	}
+
+	return &Client{
+		http:        httpClient,
+		credentials: credentials,
+	}
+}
+
+// Resolve returns the image the reference points to, pinned to the digest of its
+// manifest. References by digest are returned as they are.
+func (c *Client) Resolve(ctx context.Context, reference string) (Image, error) {
+	ref, err := parser.Parse(reference)
+	if err != nil {
+		return Image{}, errors.Wrapf(err, "parsing image reference '%s'", reference)
+	}
+
+	image := Image{
+		Registry: ref.Registry(),
+		Name:     ref.ShortName(),
+	}
+
+	if strings.HasPrefix(ref.Tag(), "sha256:") {
+		image.Digest = ref.Tag()
+		return image, nil
+	}
+
+	digest, _, err := c.manifest(ctx, image, ref.Tag(), manifestMediaTypes, http.MethodHead)
+	if err != nil {
+		return Image{}, errors.Wrapf(err, "resolving image '%s'", reference)
+	}
+
+	image.Digest = digest
+	return image, nil
+}
+
+// manifest requests the manifest of the image with the tag or digest, and returns its
+// digest, and for GET requests its content.
+func (c *Client) manifest(ctx context.Context, image Image, tag string, mediaTypes []string, method string) (string, []byte, error) {
+	response, err := c.request(ctx, image, method, "manifests/"+tag, mediaTypes)
+	if err != nil {
+		return "", nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxManifestSize))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "reading the manifest")
+	}
+
+	digest := response.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		if method == http.MethodHead {
+			// Registries are not required to return the digest. Compute it.
+			return c.manifest(ctx, image, tag, mediaTypes, http.MethodGet)
+		}
+		sum := sha256.Sum256(body)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", nil, errors.Errorf("unsupported digest '%s'", digest)
+	}
+
+	return digest, body, nil
+}
+
+// blob returns the content of the blob of the image, verified against its digest.
+func (c *Client) blob(ctx context.Context, image Image, digest string) ([]byte, error) {
+	response, err := c.request(ctx, image, http.MethodGet, "blobs/"+digest, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxManifestSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading the blob")
+	}
+
+	sum := sha256.Sum256(body)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, errors.Errorf("blob %s does not match its digest", digest)
+	}
+
+	return body, nil
+}
+
+// request sends the request for the path below the repository of the image. It
+// authenticates as challenged by the registry.
+func (c *Client) request(ctx context.Context, image Image, method, path string, accept []string) (*http.Response, error) {
+	uri := fmt.Sprintf("https://%s/v2/%s/%s", registryHost(image.Registry), image.Name, path)
+
+	newRequest := func() (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, method, uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, mediaType := range accept {
+			request.Header.Add("Accept", mediaType)
+		}
+		return request, nil
+	}
+
+	request, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		response.Body.Close()
+
+		request, err = newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.authorize(ctx, request, image, challenge); err != nil {
+			return nil, err
+		}
+
+		response, err = c.http.Do(request)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, ErrNotFound
+	default:
+		response.Body.Close()
+		return nil, errors.Errorf("registry responded with %s", response.Status)
+	}
+}
+
+// authorize adds the authorization requested by the challenge to the request.
+func (c *Client) authorize(ctx context.Context, request *http.Request, image Image, challenge string) error {
+	credentials, hasCredentials := c.credentials[image.Registry]
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if !hasCredentials {
+			return errors.New("registry requires credentials")
+		}
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+		return nil
+	case "bearer":
+		token, err := c.token(ctx, params, credentials, hasCredentials)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	default:
+		return errors.Errorf("unsupported registry authentication '%s'", challenge)
+	}
+}
+
+// token requests a bearer token from the authorization service named by the challenge.
+func (c *Client) token(ctx context.Context, params map[string]string, credentials Credentials, hasCredentials bool) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", errors.Errorf("bad token realm '%s'", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value := params[key]; value != "" {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredentials {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "requesting a registry token")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errors.Errorf("registry token service responded with %s", response.Status)
+	}
+
+	var tokens struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxManifestSize)).Decode(&tokens); err != nil {
+		return "", errors.Wrap(err, "decoding the registry token")
+	}
+
+	if tokens.Token != "" {
+		return tokens.Token, nil
+	}
+	if tokens.AccessToken != "" {
+		return tokens.AccessToken, nil
+	}
+
+	return "", errors.New("registry token service returned no token")
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseChallenge returns the lowercased scheme and the parameters of a WWW-Authenticate
+// challenge.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+// registryHost returns the host serving the registry API. The Docker Hub is served by a
+// host other than the one in image references.
+func registryHost(registry string) string {
+	if registry == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return registry
+}
@@ -14,15 +14,21 @@
 package s3manager
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/epinio/epinio/helpers/kubernetes"
 	"github.com/epinio/epinio/internal/helmchart"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
 	"github.com/google/uuid"
 	minio "github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -269,3 +275,347 @@ func (m *Manager) DeleteObject(ctx context.Context, objectID string) error {
 	return m.minioClient.RemoveObject(ctx, m.connectionDetails.Bucket, objectID,
 		minio.RemoveObjectOptions{})
 }
+
+// ListBlobs returns all the blobs of the bucket. A missing bucket holds no blobs.
+func (m *Manager) ListBlobs(ctx context.Context) ([]models.BlobInfo, error) {
+	exists, err := m.minioClient.BucketExists(ctx, m.connectionDetails.Bucket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "checking bucket %s exists", m.connectionDetails.Bucket)
+	}
+	if !exists {
+		return []models.BlobInfo{}, nil
+	}
+
+	blobs := []models.BlobInfo{}
+	for object := range m.minioClient.ListObjects(ctx, m.connectionDetails.Bucket,
+		minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return nil, errors.Wrap(object.Err, "listing the objects")
+		}
+		if strings.HasPrefix(object.Key, uploadMarkerPrefix) {
+			continue
+		}
+
+		blobs = append(blobs, models.BlobInfo{
+			BlobUID:      object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return blobs, nil
+}
+
+// The parts of a multipart upload have to be at least 5 MiB large, except for the last.
+// UploadPartSize is the size of the parts requested from clients.
+const UploadPartSize = 8 * 1024 * 1024
+
+// ErrChecksumMismatch is returned when the checksum of a completed multipart upload does
+// not match the checksum announced when the upload started.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrUploadNotFound is returned for multipart uploads which do not exist, or whose upload
+// id does not match.
+var ErrUploadNotFound = errors.New("upload not found")
+
+// checksumMetaKey is the user metadata key holding the expected sha256 checksum of a
+// multipart upload.
+const checksumMetaKey = "sha256"
+
+// uploadIDMetaKey is the user metadata key holding the upload id in the marker of a
+// multipart upload.
+const uploadIDMetaKey = "upload-id"
+
+// uploadMarkerPrefix is the prefix of the markers of the multipart uploads in progress.
+// The metadata of an upload is not accessible before it completes. The marker object
+// records it until then. Markers are not blobs.
+const uploadMarkerPrefix = "epinio-uploads/"
+
+func uploadMarker(blobUID string) string {
+	return uploadMarkerPrefix + blobUID
+}
+
+// StartUpload begins a multipart upload of a new blob, with the expected sha256 checksum
+// of the complete blob. It returns the blobUID of the new blob, and the id of the upload.
+func (m *Manager) StartUpload(ctx context.Context, sha256Hex string, metadata map[string]string) (string, string, error) {
+	if err := m.EnsureBucket(ctx); err != nil {
+		return "", "", errors.Wrap(err, "ensuring bucket")
+	}
+
+	userMetadata := map[string]string{checksumMetaKey: sha256Hex}
+	for key, value := range metadata {
+		userMetadata[key] = value
+	}
+
+	objectName := uuid.New().String()
+	uploadID, err := m.core().NewMultipartUpload(ctx, m.connectionDetails.Bucket, objectName,
+		minio.PutObjectOptions{
+			ContentType:  "application/tar",
+			UserMetadata: userMetadata,
+		})
+	if err != nil {
+		return "", "", errors.Wrap(err, "starting the multipart upload")
+	}
+
+	markerMetadata := map[string]string{uploadIDMetaKey: uploadID}
+	for key, value := range userMetadata {
+		markerMetadata[key] = value
+	}
+
+	_, err = m.minioClient.PutObject(ctx, m.connectionDetails.Bucket, uploadMarker(objectName),
+		bytes.NewReader(nil), 0, minio.PutObjectOptions{UserMetadata: markerMetadata})
+	if err != nil {
+		_ = m.core().AbortMultipartUpload(ctx, m.connectionDetails.Bucket, objectName, uploadID)
+		return "", "", errors.Wrap(err, "recording the multipart upload")
+	}
+
+	return objectName, uploadID, nil
+}
+
+// UploadMeta returns the metadata given to the multipart upload when it started. The keys
+// are in canonical form, e.g. "App". It returns ErrUploadNotFound when no such upload is in
+// progress.
+func (m *Manager) UploadMeta(ctx context.Context, blobUID, uploadID string) (map[string]string, error) {
+	info, err := m.minioClient.StatObject(ctx, m.connectionDetails.Bucket, uploadMarker(blobUID),
+		minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrUploadNotFound
+		}
+		return nil, errors.Wrap(err, "reading the upload meta data")
+	}
+
+	meta := info.UserMetadata
+	if meta["Upload-Id"] != uploadID {
+		return nil, ErrUploadNotFound
+	}
+	delete(meta, "Upload-Id")
+
+	return meta, nil
+}
+
+// UploadPart stores a part of a multipart upload. The store verifies the part against
+// its sha256 checksum.
+func (m *Manager) UploadPart(ctx context.Context, blobUID, uploadID string, number int, data io.Reader, size int64, sha256Hex string) (models.UploadPart, error) {
+	part, err := m.core().PutObjectPart(ctx, m.connectionDetails.Bucket, blobUID, uploadID,
+		number, data, size, "", sha256Hex, nil)
+	if err != nil {
+		return models.UploadPart{}, errors.Wrapf(err, "writing part %d", number)
+	}
+
+	return models.UploadPart{
+		Number: part.PartNumber,
+		Size:   part.Size,
+		ETag:   part.ETag,
+	}, nil
+}
+
+// UploadedParts returns the parts of the multipart upload stored so far, ordered by
+// number.
+func (m *Manager) UploadedParts(ctx context.Context, blobUID, uploadID string) ([]models.UploadPart, error) {
+	parts := []models.UploadPart{}
+
+	marker := 0
+	for {
+		result, err := m.core().ListObjectParts(ctx, m.connectionDetails.Bucket, blobUID, uploadID, marker, 1000)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing the uploaded parts")
+		}
+
+		for _, part := range result.ObjectParts {
+			parts = append(parts, models.UploadPart{
+				Number: part.PartNumber,
+				Size:   part.Size,
+				ETag:   part.ETag,
+			})
+		}
+
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// CompleteUpload assembles the blob from the given parts of the multipart upload, and
+// verifies it against the checksum announced when the upload started. A blob failing the
+// verification is removed.
+func (m *Manager) CompleteUpload(ctx context.Context, blobUID, uploadID string, parts []models.UploadPart) error {
+	if len(parts) == 0 {
+		return errors.New("no parts uploaded")
+	}
+
+	complete := []minio.CompletePart{}
+	for _, part := range parts {
+		complete = append(complete, minio.CompletePart{
+			PartNumber: part.Number,
+			ETag:       part.ETag,
+		})
+	}
+
+	_, err := m.core().CompleteMultipartUpload(ctx, m.connectionDetails.Bucket, blobUID, uploadID,
+		complete, minio.PutObjectOptions{})
+	if err != nil {
+		return errors.Wrap(err, "completing the multipart upload")
+	}
+
+	if err := m.DeleteObject(ctx, uploadMarker(blobUID)); err != nil {
+		return errors.Wrap(err, "removing the upload marker")
+	}
+
+	meta, err := m.Meta(ctx, blobUID)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := m.Checksum(ctx, blobUID)
+	if err != nil {
+		return err
+	}
+
+	// Note: The user metadata keys are returned in canonical form.
+	if expected := meta["Sha256"]; expected != checksum {
+		if err := m.DeleteObject(ctx, blobUID); err != nil {
+			return errors.Wrap(err, "removing the corrupted blob")
+		}
+		return errors.Wrapf(ErrChecksumMismatch, "expected %s, got %s", expected, checksum)
+	}
+
+	return nil
+}
+
+// AbortUpload cancels the multipart upload, and removes its stored parts.
+func (m *Manager) AbortUpload(ctx context.Context, blobUID, uploadID string) error {
+	err := m.core().AbortMultipartUpload(ctx, m.connectionDetails.Bucket, blobUID, uploadID)
+	if err != nil {
+		return errors.Wrap(err, "aborting the multipart upload")
+	}
+
+	return errors.Wrap(m.DeleteObject(ctx, uploadMarker(blobUID)), "removing the upload marker")
+}
+
+// RemoveStaleUploads aborts the multipart uploads last written before the given time, e.g.
+// those of clients which never completed them, and removes their markers. Markers left
+// over without an upload are removed as well. It returns the number of removed uploads.
+func (m *Manager) RemoveStaleUploads(ctx context.Context, before time.Time) (int, error) {
+	exists, err := m.minioClient.BucketExists(ctx, m.connectionDetails.Bucket)
+	if err != nil {
+		return 0, errors.Wrapf(err, "checking bucket %s exists", m.connectionDetails.Bucket)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	uploads, err := m.incompleteUploads(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	active, aborted := map[string]bool{}, map[string]bool{}
+	for _, upload := range uploads {
+		written, err := m.lastWritten(ctx, upload)
+		if err != nil {
+			return removed, err
+		}
+		// An upload in progress keeps adding parts.
+		if !written.Before(before) {
+			active[upload.Key] = true
+			continue
+		}
+
+		err = m.AbortUpload(ctx, upload.Key, upload.UploadID)
+		if err != nil && minio.ToErrorResponse(errors.Cause(err)).Code != "NoSuchUpload" {
+			return removed, err
+		}
+		aborted[upload.Key] = true
+		removed++
+	}
+
+	for object := range m.minioClient.ListObjects(ctx, m.connectionDetails.Bucket,
+		minio.ListObjectsOptions{Prefix: uploadMarkerPrefix, Recursive: true}) {
+		if object.Err != nil {
+			return removed, errors.Wrap(object.Err, "listing the upload markers")
+		}
+		blobUID := strings.TrimPrefix(object.Key, uploadMarkerPrefix)
+		if active[blobUID] || !object.LastModified.Before(before) {
+			continue
+		}
+
+		if err := m.DeleteObject(ctx, object.Key); err != nil {
+			return removed, errors.Wrap(err, "removing the upload marker")
+		}
+		if !aborted[blobUID] {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// incompleteUploads returns the multipart uploads in progress, i.e. neither completed nor
+// aborted.
+func (m *Manager) incompleteUploads(ctx context.Context) ([]minio.ObjectMultipartInfo, error) {
+	uploads := []minio.ObjectMultipartInfo{}
+
+	keyMarker, uploadIDMarker := "", ""
+	for {
+		result, err := m.core().ListMultipartUploads(ctx, m.connectionDetails.Bucket, "",
+			keyMarker, uploadIDMarker, "", 1000)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing the multipart uploads")
+		}
+
+		uploads = append(uploads, result.Uploads...)
+
+		if !result.IsTruncated {
+			return uploads, nil
+		}
+		keyMarker, uploadIDMarker = result.NextKeyMarker, result.NextUploadIDMarker
+	}
+}
+
+// lastWritten returns the time the multipart upload last stored a part, or the time it
+// started, without parts.
+func (m *Manager) lastWritten(ctx context.Context, upload minio.ObjectMultipartInfo) (time.Time, error) {
+	written := upload.Initiated
+
+	marker := 0
+	for {
+		result, err := m.core().ListObjectParts(ctx, m.connectionDetails.Bucket, upload.Key, upload.UploadID, marker, 1000)
+		if err != nil {
+			return written, errors.Wrap(err, "listing the uploaded parts")
+		}
+
+		for _, part := range result.ObjectParts {
+			if part.LastModified.After(written) {
+				written = part.LastModified
+			}
+		}
+
+		if !result.IsTruncated {
+			return written, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// Checksum returns the sha256 checksum of the blob, in hex.
+func (m *Manager) Checksum(ctx context.Context, blobUID string) (string, error) {
+	object, err := m.minioClient.GetObject(ctx, m.connectionDetails.Bucket, blobUID, minio.GetObjectOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "reading the object")
+	}
+	defer object.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, object); err != nil {
+		return "", errors.Wrap(err, "reading the object")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (m *Manager) core() *minio.Core {
+	return &minio.Core{Client: m.minioClient}
+}
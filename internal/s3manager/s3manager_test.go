@@ -12,6 +12,15 @@
 package s3manager_test
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/epinio/epinio/internal/s3manager"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -85,3 +94,127 @@ var _ = Describe("ConnectionDetails", func() {
 		})
 	})
 })
+
+// fakeUpload is a multipart upload in progress in the fakeS3 bucket.
+type fakeUpload struct {
+	id        string
+	initiated time.Time
+	parts     []time.Time
+}
+
+// fakeS3 serves the few S3 requests needed to collect the stale multipart uploads of a
+// single bucket.
+type fakeS3 struct {
+	mu      sync.Mutex
+	uploads map[string]fakeUpload
+	objects map[string]time.Time
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"), "bucket")
+	key = strings.TrimPrefix(key, "/")
+	query := r.URL.Query()
+	stamp := func(t time.Time) string { return t.UTC().Format(time.RFC3339) }
+
+	switch {
+	case r.Method == http.MethodHead && key == "":
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && key == "" && query.Has("uploads"):
+		body := "<ListMultipartUploadsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>"
+		for name, upload := range f.uploads {
+			body += fmt.Sprintf("<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>%s</Initiated></Upload>",
+				name, upload.id, stamp(upload.initiated))
+		}
+		fmt.Fprint(w, body+"</ListMultipartUploadsResult>")
+	case r.Method == http.MethodGet && key == "":
+		names := []string{}
+		for name := range f.objects {
+			if strings.HasPrefix(name, query.Get("prefix")) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		body := fmt.Sprintf("<ListBucketResult><Name>bucket</Name><KeyCount>%d</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>", len(names))
+		for _, name := range names {
+			body += fmt.Sprintf(`<Contents><Key>%s</Key><LastModified>%s</LastModified><Size>0</Size><ETag>"e"</ETag></Contents>`,
+				name, stamp(f.objects[name]))
+		}
+		fmt.Fprint(w, body+"</ListBucketResult>")
+	case r.Method == http.MethodGet && query.Has("uploadId"):
+		body := "<ListPartsResult><Bucket>bucket</Bucket><IsTruncated>false</IsTruncated>"
+		for i, written := range f.uploads[key].parts {
+			body += fmt.Sprintf(`<Part><PartNumber>%d</PartNumber><LastModified>%s</LastModified><ETag>"e"</ETag><Size>1</Size></Part>`,
+				i+1, stamp(written))
+		}
+		fmt.Fprint(w, body+"</ListPartsResult>")
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+var _ = Describe("Manager", func() {
+	Describe("RemoveStaleUploads", func() {
+		var store *fakeS3
+		var server *httptest.Server
+		var manager *s3manager.Manager
+		now := time.Now().Truncate(time.Second)
+
+		BeforeEach(func() {
+			store = &fakeS3{
+				uploads: map[string]fakeUpload{
+					"stale":  {id: "u1", initiated: now.Add(-3 * time.Hour)},
+					"active": {id: "u2", initiated: now.Add(-3 * time.Hour), parts: []time.Time{now.Add(-time.Minute)}},
+					"fresh":  {id: "u3", initiated: now.Add(-time.Minute)},
+				},
+				objects: map[string]time.Time{
+					"blob":                   now.Add(-3 * time.Hour),
+					"epinio-uploads/stale":   now.Add(-3 * time.Hour),
+					"epinio-uploads/active":  now.Add(-3 * time.Hour),
+					"epinio-uploads/fresh":   now.Add(-time.Minute),
+					"epinio-uploads/orphan":  now.Add(-3 * time.Hour),
+					"epinio-uploads/orphan2": now.Add(-time.Minute),
+				},
+			}
+			server = httptest.NewServer(store)
+
+			var err error
+			manager, err = s3manager.New(s3manager.ConnectionDetails{
+				Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+				AccessKeyID:     "key",
+				SecretAccessKey: "secret",
+				Bucket:          "bucket",
+				Location:        "us-east-1",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("aborts the uploads last written before the given time, and removes their markers", func() {
+			removed, err := manager.RemoveStaleUploads(context.Background(), now.Add(-time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal(2))
+
+			Expect(store.uploads).To(HaveLen(2))
+			Expect(store.uploads).To(HaveKey("active"))
+			Expect(store.uploads).To(HaveKey("fresh"))
+
+			Expect(store.objects).To(HaveLen(4))
+			Expect(store.objects).To(HaveKey("blob"))
+			Expect(store.objects).To(HaveKey("epinio-uploads/active"))
+			Expect(store.objects).To(HaveKey("epinio-uploads/fresh"))
+			Expect(store.objects).To(HaveKey("epinio-uploads/orphan2"))
+		})
+	})
+})
@@ -328,6 +328,12 @@ func (c *Client) AppImportGit(app models.AppRef, gitRef models.GitRef) (*models.
 	data := url.Values{}
 	data.Set("giturl", gitRef.URL)
 	data.Set("gitrev", gitRef.Revision)
+	if gitRef.Subdir != "" {
+		data.Set("gitsubdir", gitRef.Subdir)
+	}
+	if gitRef.Submodules {
+		data.Set("gitsubmodules", "true")
+	}
 
 	url := fmt.Sprintf("%s%s/%s", c.Settings.API, api.Root, api.Routes.Path("AppImportGit", app.Namespace, app.Name))
 	request, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
@@ -413,6 +419,31 @@ func (c *Client) AppDeploy(req models.DeployRequest) (*models.DeployResponse, er
 	return resp, nil
 }
 
+// AppDeployDryRun renders the app chart for the deploy request, without deploying anything
+func (c *Client) AppDeployDryRun(req models.DeployRequest) (models.DeployDryRunResponse, error) {
+	resp := models.DeployDryRunResponse{}
+
+	out, err := json.Marshal(req)
+	if err != nil {
+		return resp, errors.Wrap(err, "can't marshal deploy request")
+	}
+
+	endpoint := fmt.Sprintf("%s?dryRun=true", api.Routes.Path("AppDeploy", req.App.Namespace, req.App.Name))
+
+	data, err := c.post(endpoint, string(out))
+	if err != nil {
+		return resp, errors.Wrap(err, "can't dry-run app deployment")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
 // AppLogs streams the logs of all the application instances, in the targeted namespace
 // If stageID is an empty string, runtime application logs are streamed. If stageID
 // is set, then the matching staging logs are streamed.
@@ -477,6 +508,42 @@ func (c *Client) AppLogs(namespace, appName, stageID string, follow bool, printC
 func (c *Client) StagingComplete(namespace string, id string) (models.Response, error) {
 	resp := models.Response{}
 
+	data, err := c.stagingComplete(api.Routes.Path("StagingComplete", namespace, id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// StagingStatus checks if the staging process is complete. Unlike StagingComplete it
+// returns early while the staging is waiting in the build queue, with the position in
+// that queue.
+func (c *Client) StagingStatus(namespace string, id string) (models.StagingCompleteResponse, error) {
+	resp := models.StagingCompleteResponse{}
+
+	data, err := c.stagingComplete(fmt.Sprintf("%s?queue=true", api.Routes.Path("StagingComplete", namespace, id)))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// stagingComplete queries the staging status endpoint, retrying on transient errors.
+func (c *Client) stagingComplete(endpoint string) ([]byte, error) {
 	details := c.log.V(1)
 	var (
 		data []byte
@@ -484,7 +551,7 @@ func (c *Client) StagingComplete(namespace string, id string) (models.Response,
 	)
 	err = retry.Do(
 		func() error {
-			data, err = c.get(api.Routes.Path("StagingComplete", namespace, id))
+			data, err = c.get(endpoint)
 			return err
 		},
 		retry.RetryIf(func(err error) bool {
@@ -509,17 +576,8 @@ func (c *Client) StagingComplete(namespace string, id string) (models.Response,
 		retry.Delay(time.Second),
 		retry.Attempts(duration.RetryMax),
 	)
-	if err != nil {
-		return resp, err
-	}
-
-	if err := json.Unmarshal(data, &resp); err != nil {
-		return resp, err
-	}
-
-	c.log.V(1).Info("response decoded", "response", resp)
 
-	return resp, nil
+	return data, err
 }
 
 // AppRunning checks if the app is running
@@ -704,6 +762,237 @@ func (c *Client) AppRestart(namespace string, appName string) error {
 	return nil
 }
 
+// StagingCancel cancels a running staging job
+func (c *Client) StagingCancel(namespace string, id string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("StagingCancel", namespace, id))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppRollback rolls an app back to an earlier build
+func (c *Client) AppRollback(namespace string, appName string, req models.RollbackRequest) (models.RollbackResponse, error) {
+	resp := models.RollbackResponse{}
+
+	out, err := json.Marshal(req)
+	if err != nil {
+		return resp, errors.Wrap(err, "can't marshal rollback request")
+	}
+
+	data, err := c.post(api.Routes.Path("AppRollback", namespace, appName), string(out))
+	if err != nil {
+		return resp, errors.Wrap(err, "can't roll back app")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppPromote promotes the candidate of an app, or shifts the traffic split of its canary
+func (c *Client) AppPromote(namespace string, appName string, req models.PromoteRequest) (models.CandidateResponse, error) {
+	resp := models.CandidateResponse{}
+
+	out, err := json.Marshal(req)
+	if err != nil {
+		return resp, errors.Wrap(err, "can't marshal promote request")
+	}
+
+	data, err := c.post(api.Routes.Path("AppPromote", namespace, appName), string(out))
+	if err != nil {
+		return resp, errors.Wrap(err, "can't promote app")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppAbort removes the candidate of an app
+func (c *Client) AppAbort(namespace string, appName string) (models.CandidateResponse, error) {
+	resp := models.CandidateResponse{}
+
+	data, err := c.post(api.Routes.Path("AppAbort", namespace, appName), "")
+	if err != nil {
+		return resp, errors.Wrap(err, "can't abort app candidate")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppBuilds returns the build history of an app
+func (c *Client) AppBuilds(namespace string, appName string) (models.AppBuildList, error) {
+	resp := models.AppBuildList{}
+
+	data, err := c.get(api.Routes.Path("AppBuilds", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppReleases returns the revisions of the helm release of an app
+func (c *Client) AppReleases(namespace string, appName string) (models.AppReleaseList, error) {
+	resp := models.AppReleaseList{}
+
+	data, err := c.get(api.Routes.Path("AppReleases", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppReleaseDiff returns the differences between two revisions of the helm release of an app
+func (c *Client) AppReleaseDiff(namespace string, appName string, from, to int) (models.AppReleaseDiff, error) {
+	resp := models.AppReleaseDiff{}
+
+	data, err := c.get(api.Routes.Path("AppReleaseDiff", namespace, appName, strconv.Itoa(from), strconv.Itoa(to)))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppCache returns information about the build cache of an app
+func (c *Client) AppCache(namespace string, appName string) (models.AppCache, error) {
+	var resp models.AppCache
+
+	data, err := c.get(api.Routes.Path("AppCache", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppCacheClear removes the build cache of an app
+func (c *Client) AppCacheClear(namespace string, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("AppCacheClear", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppWebhook returns the state of the Git webhook of an app
+func (c *Client) AppWebhook(namespace string, appName string) (models.AppWebhook, error) {
+	var resp models.AppWebhook
+
+	data, err := c.get(api.Routes.Path("AppWebhook", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppWebhookEnable enables the Git webhook of an app, with a new secret
+func (c *Client) AppWebhookEnable(namespace string, appName string) (models.AppWebhook, error) {
+	var resp models.AppWebhook
+
+	data, err := c.post(api.Routes.Path("AppWebhookEnable", namespace, appName), "")
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppWebhookDisable disables the Git webhook of an app
+func (c *Client) AppWebhookDisable(namespace string, appName string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("AppWebhookDisable", namespace, appName))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, err
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// AppWebhookURL returns the url Git hosting services have to call for the webhook of an app
+func (c *Client) AppWebhookURL(namespace string, appName string) string {
+	return fmt.Sprintf("%s%s/%s", c.Settings.API, api.WebhookRoot, api.WebhookRoutes.Path("GitWebhook", namespace, appName))
+}
+
 func constructApplicationBatchDeleteURL(namespace string, names []string) string {
 	q := url.Values{}
 	for _, c := range names {
@@ -45,8 +45,8 @@ func New(ctx context.Context, settings *epiniosettings.Settings) *Client {
 	var tokenSource oauth2.TokenSource
 
 	// we have to initialize the tokenSource (for the refresh) only if there is already a token to refresh
-	// otherwise we could hit an untrusted CA
-	if settings.API != "" && settings.Token.AccessToken != "" {
+	// otherwise we could hit an untrusted CA. Personal access tokens are not refreshed.
+	if settings.API != "" && settings.Token.AccessToken != "" && !auth.IsPersonalAccessToken(settings.Token.AccessToken) {
 		dexURL := regexp.MustCompile(`epinio\.(.*)`).ReplaceAllString(settings.API, "auth.$1")
 		token := &oauth2.Token{
 			AccessToken:  settings.Token.AccessToken,
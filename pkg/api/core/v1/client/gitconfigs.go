@@ -0,0 +1,80 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+// GitConfigs returns the git configurations of a namespace
+func (c *Client) GitConfigs(namespace string) (models.GitConfigList, error) {
+	resp := models.GitConfigList{}
+
+	data, err := c.get(api.Routes.Path("GitConfigs", namespace))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// GitConfigCreate creates a git configuration in a namespace
+func (c *Client) GitConfigCreate(req models.GitConfigCreateRequest, namespace string) (models.Response, error) {
+	resp := models.Response{}
+
+	// Note: The request is not logged, as it contains the credentials.
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("GitConfigCreate", namespace), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// GitConfigDelete deletes a git configuration of a namespace
+func (c *Client) GitConfigDelete(namespace string, name string) (models.Response, error) {
+	resp := models.Response{}
+
+	data, err := c.delete(api.Routes.Path("GitConfigDelete", namespace, name))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
@@ -70,6 +70,10 @@ func (c *Client) patch(endpoint string, data string) ([]byte, error) {
 	return c.do(endpoint, "PATCH", data)
 }
 
+func (c *Client) put(endpoint string, data string) ([]byte, error) {
+	return c.do(endpoint, "PUT", data)
+}
+
 func (c *Client) delete(endpoint string) ([]byte, error) {
 	return c.do(endpoint, "DELETE", "")
 }
@@ -137,6 +141,39 @@ func (c *Client) upload(endpoint string, path string) ([]byte, error) {
 	return bodyBytes, nil
 }
 
+// putPart sends a part of a chunked upload, with its sha256 checksum.
+func (c *Client) putPart(endpoint string, data []byte, checksum string) ([]byte, error) {
+	uri := fmt.Sprintf("%s%s/%s", c.Settings.API, api.Root, endpoint)
+
+	request, err := http.NewRequest("PUT", uri, bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+
+	request.Header.Add("Content-Type", "application/octet-stream")
+	request.Header.Add(models.UploadChecksumHeader, checksum)
+
+	err = c.handleAuthorization(request)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	response, err := c.HttpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to PUT the part")
+	}
+	defer response.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK {
+		return nil, wrapResponseError(fmt.Errorf("server status code: %s\n%s",
+			http.StatusText(response.StatusCode), string(bodyBytes)),
+			response.StatusCode)
+	}
+
+	return bodyBytes, nil
+}
+
 func (c *Client) do(endpoint, method, requestBody string) ([]byte, error) {
 	uri := fmt.Sprintf("%s%s/%s", c.Settings.API, api.Root, endpoint)
 	c.log.Info(fmt.Sprintf("%s %s", method, uri))
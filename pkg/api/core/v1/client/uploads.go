@@ -0,0 +1,210 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/epinio/epinio/helpers"
+	api "github.com/epinio/epinio/internal/api/v1"
+	"github.com/epinio/epinio/internal/duration"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	"github.com/pkg/errors"
+)
+
+// AppUploadChunked uploads the tarball of an app in parts. Failed parts are retried, and
+// an interrupted upload resumes with the parts the server does not have yet. The server
+// verifies every part, and the assembled tarball, against their sha256 checksums.
+func (c *Client) AppUploadChunked(namespace string, name string, tarball string) (models.UploadResponse, error) {
+	resp := models.UploadResponse{}
+	details := c.log.V(1)
+
+	file, err := os.Open(tarball)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to open tarball")
+	}
+	defer file.Close()
+
+	size, checksum, err := fileChecksum(file)
+	if err != nil {
+		return resp, errors.Wrap(err, "failed to checksum tarball")
+	}
+
+	upload, err := c.appUploadInit(namespace, name, models.UploadInitRequest{
+		Size:   size,
+		SHA256: checksum,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	query := "?upload=" + url.QueryEscape(upload.UploadID)
+	parts := int((size + upload.PartSize - 1) / upload.PartSize)
+
+	// sent records the etags of the parts confirmed by the server.
+	sent := map[int]string{}
+
+	err = retry.Do(
+		func() error {
+			stored, err := c.appUploadStatus(namespace, name, upload.BlobUID, query)
+			if err != nil {
+				return err
+			}
+
+			for number := 1; number <= parts; number++ {
+				if etag, ok := sent[number]; ok && stored[number] == etag {
+					continue
+				}
+
+				part, err := c.appUploadPart(namespace, name, upload.BlobUID, query, file, number, upload.PartSize)
+				if err != nil {
+					return err
+				}
+				sent[number] = part.ETag
+
+				details.Info("uploaded part", "part", fmt.Sprintf("%d/%d", number, parts))
+			}
+
+			return nil
+		},
+		retry.RetryIf(func(err error) bool {
+			if r, ok := err.(interface{ StatusCode() int }); ok {
+				return helpers.RetryableCode(r.StatusCode())
+			}
+			return true
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			details.WithValues(
+				"tries", fmt.Sprintf("%d/%d", n, duration.RetryMax),
+				"error", err.Error(),
+			).Info("Resuming upload")
+		}),
+		retry.Delay(time.Second),
+		retry.Attempts(duration.RetryMax),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		// Best effort. Do not leave the parts behind.
+		_, _ = c.delete(api.Routes.Path("AppUploadAbort", namespace, name, upload.BlobUID) + query)
+		return resp, errors.Wrap(err, "can't upload archive")
+	}
+
+	data, err := c.post(api.Routes.Path("AppUploadComplete", namespace, name, upload.BlobUID)+query, "")
+	if err != nil {
+		return resp, errors.Wrap(err, "can't complete upload")
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+func (c *Client) appUploadInit(namespace, name string, req models.UploadInitRequest) (models.UploadInitResponse, error) {
+	resp := models.UploadInitResponse{}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	data, err := c.post(api.Routes.Path("AppUploadInit", namespace, name), string(b))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+	if resp.PartSize <= 0 {
+		return resp, errors.New("bad part size")
+	}
+
+	c.log.V(1).Info("response decoded", "response", resp)
+
+	return resp, nil
+}
+
+// appUploadStatus returns the etags of the stored parts of the upload, by part number.
+func (c *Client) appUploadStatus(namespace, name, blobUID, query string) (map[int]string, error) {
+	resp := models.UploadStatusResponse{}
+
+	data, err := c.get(api.Routes.Path("AppUploadStatus", namespace, name, blobUID) + query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errors.Wrap(err, "response body is not JSON")
+	}
+
+	stored := map[int]string{}
+	for _, part := range resp.Parts {
+		stored[part.Number] = part.ETag
+	}
+
+	return stored, nil
+}
+
+func (c *Client) appUploadPart(namespace, name, blobUID, query string, file io.ReaderAt, number int, partSize int64) (models.UploadPart, error) {
+	resp := models.UploadPart{}
+
+	buf := make([]byte, partSize)
+	n, err := file.ReadAt(buf, int64(number-1)*partSize)
+	if err != nil && err != io.EOF {
+		return resp, errors.Wrapf(err, "failed to read part %d", number)
+	}
+	buf = buf[:n]
+
+	sum := sha256.Sum256(buf)
+
+	data, err := c.putPart(api.Routes.Path("AppUploadPart", namespace, name, blobUID, strconv.Itoa(number))+query,
+		buf, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return resp, err
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return resp, errors.Wrap(err, "response body is not JSON")
+	}
+
+	return resp, nil
+}
+
+// fileChecksum returns the size and sha256 checksum of the file, in hex.
+func fileChecksum(file io.ReadSeeker) (int64, string, error) {
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,145 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/epinio/epinio/internal/cli/settings"
+	"github.com/epinio/epinio/pkg/api/core/v1/client"
+	"github.com/epinio/epinio/pkg/api/core/v1/models"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client chunked uploads", func() {
+	const uploads = "/api/v1/namespaces/workspace/applications/app/uploads"
+
+	var epinioClient *client.Client
+	var tarball string
+	var mutex sync.Mutex
+	var stored map[int]string
+	var puts []int
+	var failures map[int]int
+	var completed, aborted bool
+
+	BeforeEach(func() {
+		stored = map[int]string{}
+		puts = []int{}
+		failures = map[int]int{}
+		completed, aborted = false, false
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			path := r.URL.Path
+			switch {
+			case r.Method == "POST" && path == uploads:
+				_ = json.NewEncoder(w).Encode(models.UploadInitResponse{BlobUID: "blob", UploadID: "upload", PartSize: 4})
+
+			case r.Method == "GET" && path == uploads+"/blob":
+				status := models.UploadStatusResponse{}
+				for number, content := range stored {
+					status.Parts = append(status.Parts, models.UploadPart{Number: number, ETag: content})
+				}
+				_ = json.NewEncoder(w).Encode(status)
+
+			case r.Method == "PUT" && strings.HasPrefix(path, uploads+"/blob/parts/"):
+				number, err := strconv.Atoi(strings.TrimPrefix(path, uploads+"/blob/parts/"))
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				puts = append(puts, number)
+
+				if failures[number] > 0 {
+					failures[number]--
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				content, _ := io.ReadAll(r.Body)
+				stored[number] = string(content)
+				_ = json.NewEncoder(w).Encode(models.UploadPart{Number: number, ETag: string(content)})
+
+			case r.Method == "POST" && path == uploads+"/blob/complete":
+				completed = true
+				_ = json.NewEncoder(w).Encode(models.UploadResponse{BlobUID: "blob"})
+
+			case r.Method == "DELETE" && path == uploads+"/blob":
+				aborted = true
+				_ = json.NewEncoder(w).Encode(models.Response{Status: "ok"})
+
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(srv.Close)
+
+		epinioClient = client.New(context.Background(), &settings.Settings{API: srv.URL})
+
+		dir, err := os.MkdirTemp("", "epinio-upload")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+
+		tarball = filepath.Join(dir, "app.tar")
+		Expect(os.WriteFile(tarball, []byte("0123456789"), 0600)).To(Succeed())
+	})
+
+	assembled := func() string {
+		numbers := []int{}
+		for number := range stored {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+
+		content := ""
+		for _, number := range numbers {
+			content += stored[number]
+		}
+		return content
+	}
+
+	It("uploads the tarball in parts", func() {
+		resp, err := epinioClient.AppUploadChunked("workspace", "app", tarball)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.BlobUID).To(Equal("blob"))
+
+		Expect(puts).To(Equal([]int{1, 2, 3}))
+		Expect(assembled()).To(Equal("0123456789"))
+		Expect(completed).To(BeTrue())
+	})
+
+	It("resumes with the parts the server does not have", func() {
+		failures[2] = 1
+
+		resp, err := epinioClient.AppUploadChunked("workspace", "app", tarball)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.BlobUID).To(Equal("blob"))
+
+		Expect(puts).To(Equal([]int{1, 2, 2, 3}))
+		Expect(assembled()).To(Equal("0123456789"))
+		Expect(completed).To(BeTrue())
+		Expect(aborted).To(BeFalse())
+	})
+})
@@ -13,6 +13,7 @@ package models
 
 import (
 	"github.com/epinio/epinio/internal/names"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -22,6 +23,18 @@ const (
 
 	EpinioCreatedByAnnotation = "epinio.io/created-by"
 
+	EpinioStagingStrategyAnnotation   = "epinio.io/staging-strategy"
+	EpinioStagingDockerfileAnnotation = "epinio.io/staging-dockerfile"
+	EpinioStagingResourcesAnnotation  = "epinio.io/staging-resources"
+	EpinioRollbackAnnotation          = "epinio.io/rollback"
+
+	EpinioDeploymentStrategyAnnotation = "epinio.io/deployment-strategy"
+	EpinioCandidateAnnotation          = "epinio.io/deployment-candidate"
+	EpinioLiveReleaseAnnotation        = "epinio.io/live-release"
+
+	EpinioHealthChecksAnnotation = "epinio.io/health-checks"
+	EpinioResourcesAnnotation    = "epinio.io/resources"
+
 	ApplicationCreated = "created"
 	ApplicationStaging = "staging"
 	ApplicationRunning = "running"
@@ -33,6 +46,11 @@ type ApplicationStatus string
 type GitRef struct {
 	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
 	URL      string `json:"repository"         yaml:"url"`
+	// Subdir is the directory of the repository holding the application sources.
+	// Only that directory is imported.
+	Subdir string `json:"subdir,omitempty" yaml:"subdir,omitempty"`
+	// Submodules requests the import of the submodules of the repository.
+	Submodules bool `json:"submodules,omitempty" yaml:"submodules,omitempty"`
 }
 
 // App has all the application's properties, for at rest (Configuration), and active (Workload).
@@ -49,6 +67,8 @@ type App struct {
 	StatusMessage string                   `json:"statusmessage"`
 	StageID       string                   `json:"stage_id,omitempty"` // staging id, last run
 	ImageURL      string                   `json:"image_url"`
+	Rollback      *AppRollback             `json:"rollback,omitempty"`  // set while rolled back
+	Candidate     *AppCandidate            `json:"candidate,omitempty"` // set while a new build awaits promotion
 }
 
 type PodInfo struct {
@@ -63,7 +83,6 @@ type PodInfo struct {
 // AppDeployment contains all the information specific to an active
 // application, i.e. one with a deployment in the cluster.
 type AppDeployment struct {
-	// TODO: Readiness and Liveness fields?
 	Name            string              `json:"name,omitempty"`
 	Active          bool                `json:"active,omitempty"` // app is > 0 replicas
 	CreatedAt       string              `json:"createdAt,omitempty"`
@@ -72,7 +91,7 @@ type AppDeployment struct {
 	Replicas        map[string]*PodInfo `json:"replicas"`
 	Username        string              `json:"username,omitempty"` // app creator
 	StageID         string              `json:"stage_id,omitempty"` // staging id, running app
-	Status          string              `json:"status,omitempty"`   // app replica status
+	Status          string              `json:"status,omitempty"`   // app replica status, and failing probes, if any
 	Routes          []string            `json:"routes,omitempty"`   // app routes
 }
 
@@ -176,6 +195,18 @@ func (ar *AppRef) MakeScaleSecretName() string {
 	return names.GenerateResourceName(ar.Name + "-scale")
 }
 
+// MakeBuildsSecretName returns the name of the kube secret holding the build history
+// of the referenced application
+func (ar *AppRef) MakeBuildsSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-builds")
+}
+
+// MakeWebhookSecretName returns the name of the kube secret holding the secret of the
+// application's Git webhook
+func (ar *AppRef) MakeWebhookSecretName() string {
+	return names.GenerateResourceName(ar.Name + "-webhook")
+}
+
 // MakePVCName returns the name of the kube pvc to use with/for the referenced application.
 func (ar *AppRef) MakePVCName() string {
 	return names.GenerateResourceName(ar.Namespace, ar.Name)
@@ -201,3 +232,95 @@ type ImageRef struct {
 func NewImage(id string) ImageRef {
 	return ImageRef{id}
 }
+
+// AppCache describes the build cache of an application, i.e. the PVC mounted into the
+// staging jobs as the buildpack cache.
+type AppCache struct {
+	Name       string      `json:"name"`
+	Exists     bool        `json:"exists"`
+	Phase      string      `json:"phase,omitempty"`
+	Capacity   string      `json:"capacity,omitempty"`
+	CreatedAt  metav1.Time `json:"createdAt,omitempty"`
+	LastStaged metav1.Time `json:"lastStaged,omitempty"`
+}
+
+// Results of an application build, i.e. of a staging run. A queued build waits for the
+// admission of its staging job.
+const (
+	BuildQueued    = "queued"
+	BuildRunning   = "running"
+	BuildSucceeded = "succeeded"
+	BuildFailed    = "failed"
+	BuildCancelled = "cancelled"
+	BuildUnknown   = "unknown"
+)
+
+// AppBuild is a single entry in the build history of an application. It records the
+// inputs and the outcome of a staging run.
+type AppBuild struct {
+	StageID      string            `json:"stage_id"`
+	BlobUID      string            `json:"blobuid,omitempty"`
+	BuilderImage string            `json:"builderimage,omitempty"`
+	Strategy     string            `json:"strategy,omitempty"`
+	Origin       ApplicationOrigin `json:"origin"`
+	ImageURL     string            `json:"image,omitempty"`
+	Username     string            `json:"username,omitempty"`
+	Status       string            `json:"status"`
+	StartedAt    metav1.Time       `json:"startedAt,omitempty"`
+	FinishedAt   *metav1.Time      `json:"finishedAt,omitempty"`
+}
+
+// AppBuildList is a collection of application builds, most recent first
+type AppBuildList []AppBuild
+
+// AppRelease is a single revision in the history of the helm release of an application.
+type AppRelease struct {
+	Release      string      `json:"release"`
+	Revision     int         `json:"revision"`
+	Chart        string      `json:"chart,omitempty"`
+	ChartVersion string      `json:"chart_version,omitempty"`
+	Status       string      `json:"status"`
+	StageID      string      `json:"stage_id,omitempty"`
+	ImageURL     string      `json:"image,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Updated      metav1.Time `json:"updated,omitempty"`
+}
+
+// AppReleaseList is a collection of application release revisions, most recent first
+type AppReleaseList []AppRelease
+
+// AppReleaseDiff holds the differences in values and manifests between two revisions of
+// the helm release of an application, as unified diffs. Empty diffs mean no change.
+type AppReleaseDiff struct {
+	Release      string `json:"release"`
+	From         int    `json:"from"`
+	To           int    `json:"to"`
+	ValuesDiff   string `json:"values_diff,omitempty"`
+	ManifestDiff string `json:"manifest_diff,omitempty"`
+}
+
+// AppRollback records the rollback of an application to the image of an earlier build.
+// It stays with the application until a newer build is deployed.
+type AppRollback struct {
+	StageID     string      `json:"stage_id"`                // build rolled back to
+	FromStageID string      `json:"from_stage_id,omitempty"` // build deployed before
+	ImageURL    string      `json:"image,omitempty"`
+	Username    string      `json:"username,omitempty"`
+	Time        metav1.Time `json:"time,omitempty"`
+}
+
+// AppCandidate records a new build of an application deployed next to the live one by
+// the blue/green or canary strategies. It stays with the application until it is
+// promoted or aborted.
+type AppCandidate struct {
+	Strategy     string      `json:"strategy"`
+	StageID      string      `json:"stage_id"`                // build of the candidate
+	ImageURL     string      `json:"image,omitempty"`         // image of the candidate
+	Release      string      `json:"release"`                 // helm release of the candidate
+	Weight       int32       `json:"weight,omitempty"`        // percentage of traffic, canary only
+	FromStageID  string      `json:"from_stage_id,omitempty"` // build of the live release
+	FromImageURL string      `json:"from_image,omitempty"`    // image of the live release
+	FromRelease  string      `json:"from_release"`            // live helm release
+	Username     string      `json:"username,omitempty"`
+	Time         metav1.Time `json:"time,omitempty"`
+}
@@ -0,0 +1,30 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// AuditRecord is the record of a mutating request handled by the API server
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId"`
+	User      string    `json:"user"`
+	Role      string    `json:"role"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Namespace string    `json:"namespace,omitempty"`
+	Resource  string    `json:"resource,omitempty"`
+	Status    int       `json:"status"`
+}
+
+// AuditRecordList is a list of audit records, most recent first
+type AuditRecordList []AuditRecord
@@ -0,0 +1,33 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// BlobInfo describes a blob holding application sources, and why it is kept or removed
+type BlobInfo struct {
+	BlobUID      string    `json:"blobuid"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+	Reason       string    `json:"reason"`
+}
+
+// BlobCollectionReport lists the blobs kept and removed by a garbage collection run.
+// In a dry run nothing is removed.
+type BlobCollectionReport struct {
+	DryRun       bool       `json:"dryRun"`
+	KeepStages   int        `json:"keepStages"`
+	MaxAge       string     `json:"maxAge"`
+	Kept         []BlobInfo `json:"kept"`
+	Removed      []BlobInfo `json:"removed"`
+	RemovedBytes int64      `json:"removedBytes"`
+}
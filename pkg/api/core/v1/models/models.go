@@ -19,8 +19,10 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/epinio/epinio/helpers"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	helmrelease "helm.sh/helm/v3/pkg/release"
@@ -117,11 +119,35 @@ type ApplicationManifest struct {
 	Namespace                string            `yaml:"namespace,omitempty"`
 }
 
+// Staging strategies. The buildpacks strategy is the default, building the sources with
+// the Paketo builder image. The dockerfile strategy builds the image from a Dockerfile
+// found in the sources.
+const (
+	StagingStrategyBuildpacks = "buildpacks"
+	StagingStrategyDockerfile = "dockerfile"
+)
+
 // ApplicationStage is the part of the manifest holding information
-// relevant to staging the application's sources. This is, currently,
-// only the reference to the Paketo builder image to use.
+// relevant to staging the application's sources. This is the staging
+// strategy, and the reference to the Paketo builder image, or the
+// location of the Dockerfile to use. It further holds the resources
+// of the staging job, overriding the server's defaults.
 type ApplicationStage struct {
-	Builder string `yaml:"builder,omitempty"`
+	Builder    string            `yaml:"builder,omitempty"`
+	Strategy   string            `yaml:"strategy,omitempty"`
+	Dockerfile string            `yaml:"dockerfile,omitempty"`
+	Resources  *StagingResources `yaml:"resources,omitempty"`
+}
+
+// StagingResources describes the compute resources of a staging job, and the nodes it
+// may run on. The quantities use the kubernetes notation, e.g. `500m` cpu, or `1Gi`
+// memory. Empty fields fall back to the server's defaults.
+type StagingResources struct {
+	CPURequest    string            `json:"cpu_request,omitempty"    yaml:"cpurequest,omitempty"`
+	CPULimit      string            `json:"cpu_limit,omitempty"      yaml:"cpulimit,omitempty"`
+	MemoryRequest string            `json:"memory_request,omitempty" yaml:"memoryrequest,omitempty"`
+	MemoryLimit   string            `json:"memory_limit,omitempty"   yaml:"memorylimit,omitempty"`
+	NodeSelector  map[string]string `json:"node_selector,omitempty"  yaml:"nodeselector,omitempty"`
 }
 
 // ApplicationOrigin is the part of the manifest describing the origin of the application
@@ -135,6 +161,9 @@ type ApplicationOrigin struct {
 	Container string  `yaml:"container,omitempty" json:"container,omitempty"`
 	Git       *GitRef `yaml:"git,omitempty"       json:"git,omitempty"`
 	Path      string  `yaml:"path,omitempty"      json:"path,omitempty"`
+	// Digest of the container image manifest, resolved at deploy time. Not part of
+	// the manifest, the container reference stays as given.
+	Digest string `yaml:"-" json:"digest,omitempty"`
 }
 
 // manifest origin codes for `Kind`.
@@ -150,11 +179,21 @@ func (o *ApplicationOrigin) String() string {
 	case OriginPath:
 		return helpers.AbsPath(o.Path)
 	case OriginGit:
-		if o.Git.Revision == "" {
-			return o.Git.URL
+		origin := o.Git.URL
+		if o.Git.Subdir != "" {
+			origin = fmt.Sprintf("%s (%s)", origin, o.Git.Subdir)
+		}
+		if o.Git.Revision != "" {
+			origin = fmt.Sprintf("%s @ %s", origin, o.Git.Revision)
 		}
-		return fmt.Sprintf("%s @ %s", o.Git.URL, o.Git.Revision)
+		if o.Git.Submodules {
+			origin = fmt.Sprintf("%s, with submodules", origin)
+		}
+		return origin
 	case OriginContainer:
+		if o.Digest != "" && !strings.HasSuffix(o.Container, "@"+o.Digest) {
+			return fmt.Sprintf("%s (%s)", o.Container, o.Digest)
+		}
 		return o.Container
 	default:
 		// Nothing
@@ -175,18 +214,281 @@ type ApplicationCreateRequest struct {
 // Note: Instances is a pointer to give us a nil value separate from
 // actual integers, as means of communicating `default`/`no change`.
 type ApplicationUpdateRequest struct {
-	Instances      *int32         `json:"instances"          yaml:"instances,omitempty"`
-	Configurations []string       `json:"configurations"     yaml:"configurations,omitempty"`
-	Environment    EnvVariableMap `json:"environment"        yaml:"environment,omitempty"`
-	Routes         []string       `json:"routes"             yaml:"routes,omitempty"`
-	AppChart       string         `json:"appchart,omitempty" yaml:"appchart,omitempty"`
-	Settings       AppSettings    `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Instances      *int32                 `json:"instances"          yaml:"instances,omitempty"`
+	Configurations []string               `json:"configurations"     yaml:"configurations,omitempty"`
+	Environment    EnvVariableMap         `json:"environment"        yaml:"environment,omitempty"`
+	Routes         []string               `json:"routes"             yaml:"routes,omitempty"`
+	AppChart       string                 `json:"appchart,omitempty" yaml:"appchart,omitempty"`
+	Settings       AppSettings            `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Deployment     *AppDeploymentStrategy `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	HealthChecks   *AppHealthChecks       `json:"healthchecks,omitempty" yaml:"healthchecks,omitempty"`
+	Resources      *AppResources          `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// The strategies for deploying a new build of an application
+const (
+	DeploymentStrategyRolling   = "rolling"
+	DeploymentStrategyBlueGreen = "bluegreen"
+	DeploymentStrategyCanary    = "canary"
+)
+
+// AppDeploymentStrategy describes how a new build replaces the running application.
+// `rolling` upgrades the running workload in place. `bluegreen` deploys the new build
+// next to the running one, without routes, and switches the routes over on promotion.
+// `canary` routes `Weight` percent of the traffic to the new build until promotion.
+type AppDeploymentStrategy struct {
+	Strategy string `json:"strategy"         yaml:"strategy"`
+	Weight   int32  `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// Validate checks that the strategy is known, and that a weight is given only for, and
+// is sensible for a canary.
+func (s AppDeploymentStrategy) Validate() error {
+	switch s.Strategy {
+	case DeploymentStrategyRolling, DeploymentStrategyBlueGreen:
+		if s.Weight != 0 {
+			return fmt.Errorf("weight is only supported by the `%s` deployment strategy", DeploymentStrategyCanary)
+		}
+	case DeploymentStrategyCanary:
+		if s.Weight < 1 || s.Weight > 99 {
+			return fmt.Errorf("canary weight %d is out of range, expected 1 to 99", s.Weight)
+		}
+	default:
+		return fmt.Errorf("bad deployment strategy `%s`, expected one of `%s`, `%s`, or `%s`",
+			s.Strategy,
+			DeploymentStrategyRolling,
+			DeploymentStrategyBlueGreen,
+			DeploymentStrategyCanary)
+	}
+	return nil
+}
+
+// AppResources describes the compute resources of the application's workload. The
+// quantities use the kubernetes notation, e.g. `500m` cpu, or `1Gi` memory. Empty fields
+// leave the choice to the app chart.
+type AppResources struct {
+	CPURequest    string `json:"cpu_request,omitempty"    yaml:"cpurequest,omitempty"`
+	CPULimit      string `json:"cpu_limit,omitempty"      yaml:"cpulimit,omitempty"`
+	MemoryRequest string `json:"memory_request,omitempty" yaml:"memoryrequest,omitempty"`
+	MemoryLimit   string `json:"memory_limit,omitempty"   yaml:"memorylimit,omitempty"`
+}
+
+// IsEmpty returns true if no resource is set.
+func (r AppResources) IsEmpty() bool {
+	return r.CPURequest == "" && r.CPULimit == "" && r.MemoryRequest == "" && r.MemoryLimit == ""
+}
+
+// Merge returns the resources with the fields set in the overrides replacing their
+// counterparts.
+func (r AppResources) Merge(overrides AppResources) AppResources {
+	if overrides.CPURequest != "" {
+		r.CPURequest = overrides.CPURequest
+	}
+	if overrides.CPULimit != "" {
+		r.CPULimit = overrides.CPULimit
+	}
+	if overrides.MemoryRequest != "" {
+		r.MemoryRequest = overrides.MemoryRequest
+	}
+	if overrides.MemoryLimit != "" {
+		r.MemoryLimit = overrides.MemoryLimit
+	}
+	return r
+}
+
+// Validate checks that all quantities parse, are positive, and that no request exceeds
+// its limit.
+func (r AppResources) Validate() error {
+	quantities := map[string]*resource.Quantity{}
+
+	for _, q := range []struct{ name, value string }{
+		{"cpu request", r.CPURequest},
+		{"cpu limit", r.CPULimit},
+		{"memory request", r.MemoryRequest},
+		{"memory limit", r.MemoryLimit},
+	} {
+		if q.value == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return fmt.Errorf("bad %s '%s': %s", q.name, q.value, err.Error())
+		}
+		if quantity.Sign() <= 0 {
+			return fmt.Errorf("bad %s '%s': has to be positive", q.name, q.value)
+		}
+		quantities[q.name] = &quantity
+	}
+
+	for _, kind := range []string{"cpu", "memory"} {
+		request, limit := quantities[kind+" request"], quantities[kind+" limit"]
+		if request != nil && limit != nil && request.Cmp(*limit) > 0 {
+			return fmt.Errorf("%s request '%s' exceeds its limit '%s'",
+				kind, request.String(), limit.String())
+		}
+	}
+
+	return nil
+}
+
+// AppHealthChecks holds the probes checking the health of the application's workload. A
+// probe which is not set is not used. Without any probes the app chart decides. The server
+// accepts health checks only with the option `app-health-checks`, for app charts supporting
+// them.
+type AppHealthChecks struct {
+	Liveness  *AppProbe `json:"liveness,omitempty"  yaml:"liveness,omitempty"`
+	Readiness *AppProbe `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	Startup   *AppProbe `json:"startup,omitempty"   yaml:"startup,omitempty"`
+}
+
+// AppProbe describes a single health check. Exactly one of `HTTPPath`, `TCPPort`, and
+// `Command` has to be set, choosing between a HTTP GET request, opening a TCP connection,
+// and running a command in the container. `Port` is the port of the HTTP check, and
+// defaults to the application port. Zero timings and thresholds use the kubernetes
+// defaults.
+type AppProbe struct {
+	HTTPPath            string   `json:"httpPath,omitempty"            yaml:"httpPath,omitempty"`
+	Port                int32    `json:"port,omitempty"                yaml:"port,omitempty"`
+	TCPPort             int32    `json:"tcpPort,omitempty"             yaml:"tcpPort,omitempty"`
+	Command             []string `json:"command,omitempty"             yaml:"command,omitempty"`
+	InitialDelaySeconds int32    `json:"initialDelaySeconds,omitempty" yaml:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32    `json:"periodSeconds,omitempty"       yaml:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32    `json:"timeoutSeconds,omitempty"      yaml:"timeoutSeconds,omitempty"`
+	SuccessThreshold    int32    `json:"successThreshold,omitempty"    yaml:"successThreshold,omitempty"`
+	FailureThreshold    int32    `json:"failureThreshold,omitempty"    yaml:"failureThreshold,omitempty"`
+}
+
+// IsEmpty returns true if no probe is set.
+func (h AppHealthChecks) IsEmpty() bool {
+	return h.Liveness == nil && h.Readiness == nil && h.Startup == nil
+}
+
+// Validate checks all probes which are set.
+func (h AppHealthChecks) Validate() error {
+	if h.Liveness != nil {
+		if err := h.Liveness.validate("liveness", true); err != nil {
+			return err
+		}
+	}
+	if h.Readiness != nil {
+		if err := h.Readiness.validate("readiness", false); err != nil {
+			return err
+		}
+	}
+	if h.Startup != nil {
+		if err := h.Startup.validate("startup", true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that the probe has exactly one kind of check, with sensible ports and
+// timings. Kubernetes requires liveness and startup probes to succeed after one success.
+func (p AppProbe) validate(name string, singleSuccess bool) error {
+	kinds := 0
+	if p.HTTPPath != "" {
+		kinds++
+		if !strings.HasPrefix(p.HTTPPath, "/") {
+			return fmt.Errorf("%s probe: http path `%s` has to start with `/`", name, p.HTTPPath)
+		}
+		if p.Port < 0 || p.Port > 65535 {
+			return fmt.Errorf("%s probe: port %d is out of range, expected 1 to 65535", name, p.Port)
+		}
+	} else if p.Port != 0 {
+		return fmt.Errorf("%s probe: port is only supported by http checks, use tcpPort for tcp checks", name)
+	}
+	if p.TCPPort != 0 {
+		kinds++
+		if p.TCPPort < 0 || p.TCPPort > 65535 {
+			return fmt.Errorf("%s probe: tcp port %d is out of range, expected 1 to 65535", name, p.TCPPort)
+		}
+	}
+	if len(p.Command) > 0 {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%s probe: expected exactly one of httpPath, tcpPort, or command", name)
+	}
+
+	if p.InitialDelaySeconds < 0 || p.PeriodSeconds < 0 || p.TimeoutSeconds < 0 ||
+		p.SuccessThreshold < 0 || p.FailureThreshold < 0 {
+		return fmt.Errorf("%s probe: timings and thresholds cannot be negative", name)
+	}
+	if singleSuccess && p.SuccessThreshold > 1 {
+		return fmt.Errorf("%s probe: success threshold has to be 1", name)
+	}
+
+	return nil
 }
 
 type ImportGitResponse struct {
 	BlobUID string `json:"blobuid,omitempty"`
 }
 
+// The kinds of git configurations, by the credentials they hold
+const (
+	GitConfigHTTPS = "https"
+	GitConfigSSH   = "ssh"
+)
+
+// GitConfig describes the credentials used to access the git repositories whose url
+// starts with the URL of the configuration. The secret parts of the credentials are
+// never returned.
+type GitConfig struct {
+	Meta      Meta   `json:"meta"`
+	URL       string `json:"url"`
+	Kind      string `json:"kind"`
+	Username  string `json:"username,omitempty"`
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// GitConfigList is a collection of git configurations
+type GitConfigList []GitConfig
+
+// GitConfigCreateRequest contains the data needed to create a git configuration. It
+// holds either a password or token for https access, or a private key for ssh access.
+type GitConfigCreateRequest struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Username   string `json:"username,omitempty"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"privatekey,omitempty"`
+	KnownHosts string `json:"knownhosts,omitempty"`
+}
+
+// UploadChecksumHeader is the header carrying the sha256 checksum, in hex, of a part of a
+// chunked upload.
+const UploadChecksumHeader = "X-Epinio-Checksum-Sha256"
+
+// UploadInitRequest starts a chunked upload of application sources. It announces the
+// size and the sha256 checksum, in hex, of the complete archive.
+type UploadInitRequest struct {
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// UploadInitResponse identifies the new chunked upload, and the size of the parts to
+// send. Only the last part may be smaller.
+type UploadInitResponse struct {
+	BlobUID  string `json:"blobuid"`
+	UploadID string `json:"uploadid"`
+	PartSize int64  `json:"partsize"`
+}
+
+// UploadPart describes a stored part of a chunked upload
+type UploadPart struct {
+	Number int    `json:"number"`
+	Size   int64  `json:"size"`
+	ETag   string `json:"etag"`
+}
+
+// UploadStatusResponse lists the stored parts of a chunked upload, ordered by number
+type UploadStatusResponse struct {
+	Parts []UploadPart `json:"parts"`
+}
+
 // UploadRequest is a multipart form
 
 // UploadResponse represents the server's response to a successful app sources upload
@@ -199,12 +501,79 @@ type StageRequest struct {
 	App          AppRef `json:"app,omitempty"`
 	BlobUID      string `json:"blobuid,omitempty"`
 	BuilderImage string `json:"builderimage,omitempty"`
+	NoCache      bool   `json:"nocache,omitempty"`
+	Strategy     string `json:"strategy,omitempty"`
+	Dockerfile   string `json:"dockerfile,omitempty"`
+	// Resources of the staging job. Without them the resources of the previous
+	// staging are used, if any.
+	Resources *StagingResources `json:"resources,omitempty"`
 }
 
 // StageResponse represents the server's response to a successful app staging
 type StageResponse struct {
 	Stage    StageRef `json:"stage,omitempty"`
 	ImageURL string   `json:"image,omitempty"`
+	// QueuePosition is the position of the staging in the build queue, starting at 1.
+	// It is 0 if the staging started right away.
+	QueuePosition int `json:"queue_position,omitempty"`
+}
+
+// StagingQueued is the status reported for a staging waiting in the build queue
+const StagingQueued = "queued"
+
+// StagingCompleteResponse represents the server's response to a staging status request
+// made with the `queue` parameter. For a queued staging it reports the position in the
+// build queue. Else the staging is complete, with status "ok".
+type StagingCompleteResponse struct {
+	Response
+	QueuePosition int `json:"queue_position,omitempty"`
+}
+
+// RollbackRequest represents and contains the data needed to roll an application back to
+// an earlier build. Without a stage id the build before the deployed one is used.
+type RollbackRequest struct {
+	StageID string `json:"stage_id,omitempty"`
+}
+
+// RollbackResponse represents the server's response to a successful app rollback
+type RollbackResponse struct {
+	Rollback AppRollback `json:"rollback"`
+	Routes   []string    `json:"routes,omitempty"`
+}
+
+// PromoteRequest represents and contains the data needed to promote the candidate of an
+// application. For a canary a weight below 100 only shifts the traffic split. Without a
+// weight the candidate replaces the live release.
+type PromoteRequest struct {
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// CandidateResponse represents the server's response to a successful promotion or abort
+// of an application's candidate.
+type CandidateResponse struct {
+	Candidate AppCandidate `json:"candidate"`
+	Promoted  bool         `json:"promoted,omitempty"`
+	Routes    []string     `json:"routes,omitempty"`
+}
+
+// AppWebhook describes the Git webhook of an application, and the secret the Git hosting
+// service has to sign its calls with.
+type AppWebhook struct {
+	Enabled bool   `json:"enabled"`
+	Secret  string `json:"secret,omitempty"`
+}
+
+// Git webhook outcomes
+const (
+	WebhookTriggered = "triggered"
+	WebhookIgnored   = "ignored"
+)
+
+// GitWebhookResponse represents the server's response to a Git webhook call. A triggered
+// webhook imports, stages, and deploys the application in the background.
+type GitWebhookResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
 }
 
 // DeployRequest represents and contains the data needed to deploy an application
@@ -224,6 +593,19 @@ type DeployResponse struct {
 	Routes []string `json:"routes,omitempty"`
 }
 
+// DeployDryRunResponse represents the server's response to a deployment dry-run. It holds
+// the values handed to the app chart, the rendered manifests, and the differences of both
+// to the currently deployed release. Nothing was deployed.
+type DeployDryRunResponse struct {
+	Release      string `json:"release"`
+	Deployed     bool   `json:"deployed"`           // release is currently deployed
+	Revision     int    `json:"revision,omitempty"` // revision of the current release
+	Values       string `json:"values"`
+	Manifests    string `json:"manifests"`
+	ValuesDiff   string `json:"values_diff,omitempty"`
+	ManifestDiff string `json:"manifest_diff,omitempty"`
+}
+
 // ApplicationDeleteResponse represents the server's response to a successful app deletion
 type ApplicationDeleteResponse struct {
 	UnboundConfigurations []string `json:"unboundconfigurations"`
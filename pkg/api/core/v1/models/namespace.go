@@ -11,6 +11,15 @@
 
 package models
 
+// The annotations of a namespace capping the cpu and memory an application of the
+// namespace may request, or be limited to. The values use the kubernetes quantity
+// notation. Without annotation there is no cap. With an annotation the applications not
+// declaring the limit are limited to the maximum.
+const (
+	EpinioAppMaxCPUAnnotation    = "epinio.io/app-max-cpu"
+	EpinioAppMaxMemoryAnnotation = "epinio.io/app-max-memory"
+)
+
 // Namespace has all the namespace properties, i.e. name, app names, and configuration names
 // It is used in the CLI and API responses.
 type Namespace struct {
@@ -0,0 +1,41 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// PersonalAccessToken describes a personal access token. Its value is only returned at creation.
+type PersonalAccessToken struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Username   string      `json:"username"`
+	Namespaces []string    `json:"namespaces,omitempty"`
+	ReadOnly   bool        `json:"readOnly"`
+	CreatedAt  metav1.Time `json:"createdAt,omitempty"`
+}
+
+// PersonalAccessTokenList is a list of personal access tokens
+type PersonalAccessTokenList []PersonalAccessToken
+
+// TokenCreateRequest contains the data needed to create a personal access token.
+// An empty list of namespaces doesn't restrict the namespaces accessible with the token.
+type TokenCreateRequest struct {
+	Name       string   `json:"name"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	ReadOnly   bool     `json:"readOnly,omitempty"`
+}
+
+// TokenCreateResponse contains the created personal access token, and its value
+type TokenCreateResponse struct {
+	Token PersonalAccessToken `json:"token"`
+	Value string              `json:"value"`
+}
@@ -0,0 +1,51 @@
+// Copyright © 2021 - 2023 SUSE LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// User describes an Epinio user. The password is never returned.
+type User struct {
+	Username   string          `json:"username"`
+	Role       string          `json:"role"`
+	Namespaces []UserNamespace `json:"namespaces"`
+	CreatedAt  metav1.Time     `json:"createdAt,omitempty"`
+}
+
+// UserList is a list of users
+type UserList []User
+
+// UserNamespace is a namespace accessible by a user, with the role of the user in it
+type UserNamespace struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// UserCreateRequest contains the data needed to create a user
+type UserCreateRequest struct {
+	Username   string          `json:"username"`
+	Password   string          `json:"password"`
+	Role       string          `json:"role,omitempty"`
+	Namespaces []UserNamespace `json:"namespaces,omitempty"`
+}
+
+// UserPasswordRequest contains the new password of a user
+type UserPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// UserNamespaceGrantRequest contains the namespace to grant to a user. An empty role grants
+// the default namespace-admin role.
+type UserNamespaceGrantRequest struct {
+	Namespace string `json:"namespace"`
+	Role      string `json:"role,omitempty"`
+}